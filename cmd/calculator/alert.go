@@ -0,0 +1,76 @@
+package main
+
+import (
+    "fmt"
+    "net/http"
+    "os"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/alert"
+    "github.com/eth-rewards-calculator/internal/beacon"
+    "github.com/eth-rewards-calculator/internal/config"
+
+    "github.com/fatih/color"
+)
+
+func handleAlert() {
+    thresholds := alert.Thresholds{
+        MinAPY:             alertAPY,
+        MinParticipation:   alertParticipation,
+        MaxActivationQueue: alertActivationQueue,
+        MaxExitQueue:       alertExitQueue,
+    }
+    if thresholds == (alert.Thresholds{}) {
+        fmt.Println("Error: Set at least one threshold (--alert-apy, --alert-participation, --alert-activation-queue, --alert-exit-queue)")
+        os.Exit(1)
+    }
+    if alertAPY < 0 || alertParticipation < 0 || alertParticipation > 1 || alertActivationQueue < 0 || alertExitQueue < 0 {
+        fmt.Println("Error: Alert thresholds must not be negative, and participation must be between 0.0 and 1.0")
+        os.Exit(1)
+    }
+    requireStreamFormat("alert")
+
+    source := stateProvider()
+    monitor := alert.NewMonitor(thresholds)
+    webhookClient := &http.Client{Timeout: beacon.DefaultTimeout}
+    warning := color.New(color.FgRed, color.Bold)
+    pollInterval := time.Duration(config.SECONDS_PER_SLOT) * time.Second
+
+    var lastEpoch uint64
+    for checked := false; !checked || follow; checked = true {
+        snapshot, err := source.Snapshot()
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "Error fetching network snapshot: %v\n", err)
+            os.Exit(1)
+        }
+        // Only check each finalized epoch once
+        if checked && snapshot.Epoch == lastEpoch {
+            time.Sleep(pollInterval)
+            continue
+        }
+        lastEpoch = snapshot.Epoch
+
+        if snapshot.ActiveValidators > 0 {
+            snapshot.APY = projectRewardsAt(createNetworkState(snapshot.ActiveValidators), snapshot.Participation).APY
+        }
+
+        if !printFormatted(snapshot) {
+            fmt.Printf("Epoch %d: %s active validators, %s participation, %s APY, queues %d in / %d out\n",
+                snapshot.Epoch, formatNumber(uint64(snapshot.ActiveValidators)), formatPercent(snapshot.Participation*100),
+                formatPercent(snapshot.APY), snapshot.ActivationQueue, snapshot.ExitQueue)
+        }
+
+        for _, fired := range monitor.Check(*snapshot) {
+            if !printFormatted(&fired) {
+                warning.Printf("ALERT: %s\n", fired.Message)
+            }
+            if webhookURL == "" {
+                continue
+            }
+            // Keep monitoring if the webhook is unavailable
+            if err := alert.SendWebhook(webhookClient, webhookURL, fired); err != nil {
+                fmt.Fprintf(os.Stderr, "Error sending alert: %v\n", err)
+            }
+        }
+    }
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/config"
+
+    "github.com/fatih/color"
+)
+
+// reportedEffectiveBalances lists every effective balance a 0x01 validator can
+// have before ejection, then a selection of the larger ones 0x02 validators
+// can compound to
+func reportedEffectiveBalances() []uint64 {
+    var balances []uint64
+    for b := config.EJECTION_BALANCE + config.EFFECTIVE_BALANCE_INCREMENT; b <= config.MAX_EFFECTIVE_BALANCE; b += config.EFFECTIVE_BALANCE_INCREMENT {
+        balances = append(balances, b)
+    }
+
+    balances = append(balances, config.MAX_EFFECTIVE_BALANCE+config.EFFECTIVE_BALANCE_INCREMENT)
+    for b := 2 * config.MAX_EFFECTIVE_BALANCE; b < config.MAX_EFFECTIVE_BALANCE_ELECTRA; b *= 2 {
+        balances = append(balances, b)
+    }
+    return append(balances, config.MAX_EFFECTIVE_BALANCE_ELECTRA)
+}
+
+func handleBalances() {
+    requireValidators("balances")
+
+    results := projectRewards(createNetworkState(validatorCount))
+    rates := calculator.EffectiveBalanceRates(results, reportedEffectiveBalances())
+    recordRun(rates)
+
+    if printFormatted(rates) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+
+    header.Println("\n=== Rewards by Effective Balance ===")
+
+    fmt.Printf("\nNetwork Validators: %s\n", formatNumber(uint64(validatorCount)))
+
+    printHeader := func() {
+        fmt.Printf("%-12s %-20s %-14s %-14s %-10s %-12s %-12s\n",
+            "Effective", "Balance Range", "Daily ETH", "Annual ETH", "APY", "vs 32 ETH", "Next Step")
+        fmt.Println(strings.Repeat("-", 100))
+    }
+
+    subheader.Println("\nAll Validators (ejected at 16 ETH):")
+    printHeader()
+    for i, rate := range rates {
+        if rate.Compounding && (i == 0 || !rates[i-1].Compounding) {
+            subheader.Println("\nCompounding (0x02) Validators:")
+            printHeader()
+        }
+
+        balanceRange := fmt.Sprintf("%s+", formatDecimal(rate.MinBalance))
+        nextStep := "-"
+        if rate.MaxBalance > 0 {
+            balanceRange = fmt.Sprintf("%s-%s", formatDecimal(rate.MinBalance), formatDecimal(rate.MaxBalance))
+            nextStep = formatDecimal(rate.DaysToNextStep) + " days"
+        }
+        fmt.Printf("%-12s %-20s %-14s %-14s %-10s %-12s %-12s\n",
+            formatDecimal(rate.EffectiveBalance), balanceRange, formatETH(rate.DailyRewards),
+            formatETH(rate.AnnualRewards), formatPercent(rate.APY), formatPercent(rate.RelativeRewards), nextStep)
+    }
+
+    fmt.Println("\nRewards scale with the effective balance, which moves in 1 ETH steps. It drops once the")
+    fmt.Println("balance falls 0.25 ETH below it and rises once the balance is 1.25 ETH above it.")
+    fmt.Println()
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+    "fmt"
+    "net/http"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/bot"
+)
+
+// newChatBot creates the bot behind chat commands. Commands without a
+// validator count use the live network with --beacon-url or --explorer-url,
+// otherwise -v, or 1M validators.
+func newChatBot() *bot.Bot {
+    count := validatorCount
+    if count == 0 {
+        count = 1000000
+    }
+    chatBot := &bot.Bot{Validators: count, Participation: participation}
+    if liveSource() {
+        chatBot.Source = stateProvider()
+    }
+    return chatBot
+}
+
+func handleBot() {
+    token := os.Getenv("TELEGRAM_BOT_TOKEN")
+    if token == "" && discordPublicKey == "" {
+        fmt.Println("Error: Set TELEGRAM_BOT_TOKEN or --discord-public-key to connect the bot to a chat service")
+        os.Exit(1)
+    }
+
+    chatBot := newChatBot()
+
+    errs := make(chan error, 2)
+    if token != "" {
+        fmt.Println("Answering Telegram commands")
+        go func() { errs <- bot.NewTelegram(token, chatBot).Run() }()
+    }
+    if discordPublicKey != "" {
+        handler, err := bot.NewDiscordHandler(discordPublicKey, chatBot)
+        if err != nil {
+            fmt.Printf("Error: %v\n", err)
+            os.Exit(1)
+        }
+        mux := http.NewServeMux()
+        mux.Handle("/discord", handler)
+        fmt.Printf("Answering Discord interactions at http://%s/discord\n", listenAddr)
+        go func() { errs <- http.ListenAndServe(listenAddr, mux) }()
+    }
+
+    if err := <-errs; err != nil {
+        fmt.Fprintf(os.Stderr, "Error running bot: %v\n", err)
+        os.Exit(1)
+    }
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// comparisonColumn is one metric the validator count comparison can show
+type comparisonColumn struct {
+    name   string // as given to --columns
+    key    string // in structured output
+    header string
+    width  int
+    value  func(results *types.RewardResults) interface{}
+    text   func(results *types.RewardResults) string
+}
+
+// percentColumn is a column of a percentage
+func percentColumn(name, key, header string, width int, percent func(*types.RewardResults) float64) comparisonColumn {
+    return comparisonColumn{name, key, header, width,
+        func(r *types.RewardResults) interface{} { return roundTo(percent(r), precision) },
+        func(r *types.RewardResults) string { return formatPercent(percent(r)) }}
+}
+
+// ethColumn is a column of an amount in Gwei, shown in ETH
+func ethColumn(name, key, header string, gwei func(*types.RewardResults) float64) comparisonColumn {
+    return comparisonColumn{name, key, header, 15,
+        func(r *types.RewardResults) interface{} { return roundTo(gwei(r)/1e9, precision) },
+        func(r *types.RewardResults) string { return formatGweiAsETH(gwei(r)) }}
+}
+
+// comparisonColumns are the columns --columns picks from
+var comparisonColumns = []comparisonColumn{
+    {"staked", "total_staked_eth", "Total Staked (ETH)", 20,
+        func(r *types.RewardResults) interface{} { return r.TotalStakedGwei / 1e9 },
+        func(r *types.RewardResults) string { return formatNumber(r.TotalStakedGwei / 1e9) }},
+    {"base_reward", "base_reward_gwei", "Base Reward (Gwei)", 20,
+        func(r *types.RewardResults) interface{} { return r.BaseRewardPerEpochGwei },
+        func(r *types.RewardResults) string { return fmt.Sprint(r.BaseRewardPerEpochGwei) }},
+    ethColumn("annual", "annual_rewards_eth", "Annual ETH", func(r *types.RewardResults) float64 { return r.TotalAnnualRewardsGwei }),
+    percentColumn("apy", "apy_percentage", "APY %", 10, func(r *types.RewardResults) float64 { return r.APY }),
+    ethColumn("daily", "daily_rewards_eth", "Daily ETH", func(r *types.RewardResults) float64 { return r.DailyRewardsGwei }),
+    ethColumn("weekly", "weekly_rewards_eth", "Weekly ETH", func(r *types.RewardResults) float64 { return r.WeeklyRewardsGwei }),
+    ethColumn("monthly", "monthly_rewards_eth", "Monthly ETH", func(r *types.RewardResults) float64 { return r.MonthlyRewardsGwei }),
+    ethColumn("consensus", "consensus_rewards_eth", "CL ETH", func(r *types.RewardResults) float64 { return r.ConsensusRewardsAnnualGwei }),
+    ethColumn("execution", "execution_rewards_eth", "EL ETH", func(r *types.RewardResults) float64 { return r.ExecutionRewardsAnnualGwei }),
+    percentColumn("base_apy", "base_apy_percentage", "Base APY %", 15, func(r *types.RewardResults) float64 { return r.BaseAPY }),
+    {"proposals", "expected_proposals_per_year", "Proposals/Year", 15,
+        func(r *types.RewardResults) interface{} { return roundTo(r.ExpectedProposalsPerYear, precision) },
+        func(r *types.RewardResults) string { return formatDecimal(r.ExpectedProposalsPerYear) }},
+    percentColumn("source_apr", "source_apr_percentage", "Source APR %", 15, func(r *types.RewardResults) float64 { return r.SourceAPR }),
+    percentColumn("target_apr", "target_apr_percentage", "Target APR %", 15, func(r *types.RewardResults) float64 { return r.TargetAPR }),
+    percentColumn("head_apr", "head_apr_percentage", "Head APR %", 15, func(r *types.RewardResults) float64 { return r.HeadAPR }),
+    percentColumn("proposer_apr", "proposer_apr_percentage", "Proposer APR %", 15, func(r *types.RewardResults) float64 { return r.ProposerAPR }),
+    percentColumn("sync_apr", "sync_committee_apr_percentage", "Sync APR %", 15, func(r *types.RewardResults) float64 { return r.SyncAPR }),
+}
+
+// defaultColumns are the comparison's columns without --columns
+const defaultColumns = "staked,base_reward,annual,apy,daily"
+
+// selectedColumns parses --columns, exiting on an unknown or repeated name
+func selectedColumns() []comparisonColumn {
+    var selected []comparisonColumn
+    seen := make(map[string]bool)
+    for _, name := range strings.Split(columns, ",") {
+        name = strings.TrimSpace(name)
+        if seen[name] {
+            fmt.Printf("Error: Column '%s' is given more than once\n", name)
+            os.Exit(1)
+        }
+        seen[name] = true
+
+        found := false
+        for _, column := range comparisonColumns {
+            if column.name == name {
+                selected = append(selected, column)
+                found = true
+                break
+            }
+        }
+        if !found {
+            names := make([]string, len(comparisonColumns))
+            for i, column := range comparisonColumns {
+                names[i] = column.name
+            }
+            fmt.Printf("Error: Unknown column '%s' (use %s)\n", name, strings.Join(names, ", "))
+            os.Exit(1)
+        }
+    }
+    return selected
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strconv"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+// command is a named mode of the calculator selected by the first argument
+type command struct {
+    name        string
+    description string
+    run         func()
+    parquet     bool // whether the command supports --format parquet
+}
+
+var commands []command
+
+func init() {
+    commands = []command{
+        {"forecast", "Project APY decay under validator-set growth models", handleForecast, true},
+        {"issuance", "Compare alternative issuance curves against the sqrt curve", handleIssuance, true},
+        {"equilibrium", "Find the validator count where APY equals an alternative yield", handleEquilibrium, false},
+        {"opportunity", "Compare staking with alternative yields over a horizon, counting the queue and withdrawal delays", handleOpportunity, false},
+        {"skims", "Project the partial withdrawals that skim a 0x01 validator's rewards", handleSkims, true},
+        {"bot", "Answer calculator commands in Telegram or Discord", handleBot, false},
+        {"serve", "Serve a JSON API and a browser calculator on --listen", handleServe, false},
+        {"openapi", "Print the OpenAPI 3 document for the serve API", handleOpenAPI, false},
+        {"grid", "Compute APY over a grid of two parameters", handleGrid, false},
+        {"threshold", "Find the effectiveness and participation at which APY drops to a target", handleThreshold, false},
+        {"target", "Find the stake needed to earn a target income per month", handleTarget, false},
+        {"balances", "Show rewards at each effective balance, for leaked or partially funded validators", handleBalances, false},
+        {"compound", "Project the growth from funding new validators with skimmed rewards", handleCompound, true},
+        {"export", "Export projected or imported reward events as tax software CSV", handleExport, false},
+        {"exit-plan", "Stagger a fleet's voluntary exits to be fully withdrawn by a date", handleExitPlan, false},
+        {"consolidate", "Estimate how long consolidations take and the rewards lost in transit", handleConsolidate, false},
+        {"exit", "Plan an exit requested from the execution layer (EIP-7002)", handleExit, false},
+        {"queue", "Estimate the activation and exit queue waits under their separate churn limits", handleQueue, false},
+        {"forks", "Compare rewards and penalties under Phase 0, Altair, and Bellatrix rules", handleForks, false},
+        {"leak", "Follow online and offline validators' balances through an inactivity leak", handleLeak, false},
+        {"slashing", "Estimate the penalties and network impact of slashing -s validators together", handleSlashing, false},
+        {"insurance", "Check whether a slashing insurance premium is worth paying", handleInsurance, false},
+        {"realized", "Fetch a validator's realized rewards from a beacon node", handleRealized, false},
+        {"cross-check", "Compare the calculator's per-validator rewards for an epoch with a consensus client's", handleCrossCheck, false},
+        {"networks", "Report live rewards across several networks' beacon nodes", handleNetworks, false},
+        {"alert", "Watch live network conditions and post alerts to a webhook", handleAlert, false},
+        {"endpoints", "Check the health of the beacon nodes and explorers live modes fail over between", handleEndpoints, false},
+        {"state", "Save a network state to a file (state export) or analyze a saved one (state import)", handleState, false},
+        {"track", "Track a validator's realized rewards against the ideal as epochs arrive", handleTrack, false},
+        {"simulate", "Simulate a year of rewards and show the distribution of outcomes", handleSimulate, true},
+        {"outage", "Simulate an outage slot by slot and estimate what it costs", handleOutage, false},
+        {"monitor", "Measure attestation effectiveness from Lighthouse or Prysm validator monitoring", handleMonitor, false},
+        {"luck", "Rate a validator's proposal count against the expected count", handleLuck, false},
+        {"csm", "Compare Lido CSM operator economics with solo staking", handleCSM, false},
+        {"restaking", "Stack restaking yield and risk on top of solo staking", handleRestaking, false},
+        {"ssv", "Compare running validators through SSV with solo staking", handleSSV, false},
+        {"config", "Show the effective configuration (config show) or print it as ETHCALC_* variables (config env)", handleConfig, false},
+        {"diff", "Compare the projections of two scenarios field by field", handleDiff, false},
+        {"constants", "Compare rewards under two sets of chain constants, such as alternative reward weights", handleConstants, false},
+        {"history", "List runs recorded with --store, or compare two runs by ID", handleHistory, false},
+    }
+}
+
+func runCommand(name string) {
+    for _, cmd := range commands {
+        if cmd.name == name {
+            if parquetOutput && !cmd.parquet {
+                rejectParquet()
+            }
+            cmd.run()
+            return
+        }
+    }
+
+    fmt.Printf("Error: Unknown command '%s'\n", name)
+    flag.Usage()
+    os.Exit(1)
+}
+
+func usage() {
+    fmt.Fprintf(os.Stderr, "Usage: %s [command] [flags]\n", os.Args[0])
+
+    fmt.Fprintln(os.Stderr, "\nCommands:")
+    for _, cmd := range commands {
+        fmt.Fprintf(os.Stderr, "  %-20s %s\n", cmd.name, cmd.description)
+    }
+
+    fmt.Fprintln(os.Stderr, "\nFlags:")
+    flag.PrintDefaults()
+
+    fmt.Fprintln(os.Stderr, "\nEvery flag can also be set with an ETHCALC_* environment variable named after it,")
+    fmt.Fprintln(os.Stderr, "such as ETHCALC_BEACON_URL for --beacon-url. Flags take precedence over the environment.")
+}
+
+// requireValidators exits with an error when no validator count was given and
+// none can be fetched from a beacon node
+func requireValidators(name string) {
+    if validatorCount <= 0 && !fetchValidatorCount() {
+        fmt.Printf("Error: The %s command requires a validator count (-v)\n", name)
+        os.Exit(1)
+    }
+}
+
+// fetchValidatorCount sets the validator count to the active validators at the
+// beacon node or explorer when --beacon-url or --explorer-url was given,
+// exiting if they cannot be fetched. It reports whether a count was fetched.
+func fetchValidatorCount() bool {
+    if !liveSource() {
+        return false
+    }
+
+    count, err := stateProvider().ActiveValidators()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error fetching active validator count: %v\n", err)
+        os.Exit(1)
+    }
+    if count == 0 {
+        fmt.Fprintf(os.Stderr, "Error fetching active validator count: no active validators at %s\n", sourceName())
+        os.Exit(1)
+    }
+
+    validatorCount = count
+    fmt.Fprintf(os.Stderr, "Using %s active validators from %s\n", formatNumber(uint64(count)), sourceName())
+    return true
+}
+
+// validatorCounts returns the counts given with -c, or the single -v count
+func validatorCounts(name string) []int {
+    if compare == "" {
+        requireValidators(name)
+        return []int{validatorCount}
+    }
+
+    var counts []int
+    for _, countStr := range strings.Split(compare, ",") {
+        count, err := strconv.Atoi(strings.TrimSpace(countStr))
+        if err != nil || count <= 0 {
+            fmt.Printf("Error: Invalid validator count '%s'\n", countStr)
+            os.Exit(1)
+        }
+        counts = append(counts, count)
+    }
+
+    return counts
+}
+
+// outputSetups prints a comparison of staking setups as JSON or a table
+func outputSetups(title string, comparison *types.SetupComparison) {
+    recordRun(comparison)
+
+    if printFormatted(comparison) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    header.Printf("\n=== %s ===\n", title)
+
+    fmt.Printf("\nCapital: %s ETH\n", formatETH(comparison.Capital))
+    fmt.Printf("Network Validators: %s\n\n", formatNumber(uint64(validatorCount)))
+
+    // Table header
+    fmt.Printf("%-12s %-12s %-14s %-12s %-14s %-14s %-14s %-14s %-10s\n",
+        "Setup", "Validators", "Deployed ETH", "Idle ETH", "Gross ETH/yr",
+        "Penalties", "Fees", "Net ETH/yr", "Return %")
+    fmt.Println(strings.Repeat("-", 125))
+
+    for _, setup := range comparison.Setups {
+        fmt.Printf("%-12s %-12d %-14s %-12s %-14s %-14s %-14s %-14s %-10s\n",
+            setup.Name,
+            setup.Validators,
+            formatETH(setup.CapitalDeployed),
+            formatETH(setup.IdleCapital),
+            formatETH(setup.GrossRewards),
+            formatETH(setup.Penalties),
+            formatETH(setup.Fees),
+            formatETH(setup.NetRewards),
+            formatPercent(setup.ReturnOnCapital))
+    }
+
+    fmt.Println()
+}
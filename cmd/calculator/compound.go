@@ -0,0 +1,75 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/config"
+
+    "github.com/fatih/color"
+)
+
+func handleCompound() {
+    requireValidators("compound")
+    if forecastMonths <= 0 {
+        fmt.Println("Error: Months must be greater than 0")
+        os.Exit(1)
+    }
+    if capitalETH*1e9 < float64(config.MAX_EFFECTIVE_BALANCE) {
+        fmt.Printf("Error: At least %s ETH is needed to fund a validator\n",
+            formatGweiAsETH(float64(config.MAX_EFFECTIVE_BALANCE)))
+        os.Exit(1)
+    }
+
+    results := projectRewards(createNetworkState(validatorCount))
+    projection := calculator.ProjectCompounding(results, capitalETH, forecastMonths)
+    recordRun(projection)
+
+    if parquetOutput {
+        rows := make([]compoundingRow, len(projection.Timeline))
+        for i, month := range projection.Timeline {
+            rows[i] = newCompoundingRow(month)
+        }
+        printParquet(rows)
+        return
+    }
+
+    if printFormatted(projection) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+    highlight := color.New(color.FgGreen, color.Bold)
+
+    header.Println("\n=== Rolling Skims into New Validators ===")
+
+    fmt.Printf("\nNetwork Validators: %s\n", formatNumber(uint64(validatorCount)))
+    fmt.Printf("Capital: %s ETH (%d validators)\n", formatETH(projection.Capital), projection.InitialValidators)
+
+    // Show the position at the end of each year, plus the final month
+    subheader.Printf("\nValidator Growth (%d months):\n", forecastMonths)
+    fmt.Printf("%-8s %-12s %-14s %-14s %-16s\n", "Month", "Validators", "Added", "Pool ETH", "Total Value ETH")
+    fmt.Println(strings.Repeat("-", 68))
+    added := 0
+    for _, month := range projection.Timeline {
+        added += month.NewValidators
+        if month.Month%12 != 0 && month.Month != len(projection.Timeline) {
+            continue
+        }
+        fmt.Printf("%-8d %-12d %-14d %-14s %-16s\n",
+            month.Month, month.Validators, added, formatETH(month.Pool), formatETH(month.TotalValue))
+        added = 0
+    }
+
+    subheader.Println("\nResult:")
+    fmt.Printf("- Validators: %d -> %d\n", projection.InitialValidators, projection.FinalValidators)
+    fmt.Printf("- Value if Rewards Are Withdrawn: %s ETH (%s APY)\n",
+        formatETH(projection.WithdrawnValue), formatPercent(projection.WithdrawnAPY))
+    highlight.Printf("- Value with Rewards Restaked: %s ETH (%s APY)\n",
+        formatETH(projection.FinalValue), formatPercent(projection.CompoundedAPY))
+
+    fmt.Println()
+}
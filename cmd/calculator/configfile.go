@@ -0,0 +1,188 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "io/fs"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/config"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+// Where each flag's effective value comes from, in order of precedence
+const (
+    sourceFlag    = "flag"
+    sourceEnv     = "env"
+    sourceFile    = "file"
+    sourceDefault = "default"
+)
+
+// configValue is one flag's effective value, for config show
+type configValue struct {
+    Name   string `json:"name"`
+    Value  string `json:"value"`
+    Source string `json:"source"`
+}
+
+// flagSources records which layer set each flag
+var flagSources = make(map[string]string)
+
+// envName is the environment variable that sets a flag
+func envName(name string) string {
+    return "ETHCALC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// defaultConfigPath is the config file read when --config is not given
+func defaultConfigPath() string {
+    dir, err := os.UserConfigDir()
+    if err != nil {
+        return ""
+    }
+    return filepath.Join(dir, "eth-rewards", "config.yaml")
+}
+
+// applyConfigLayers fills in the flags not given on the command line, first
+// from ETHCALC_* environment variables and then from the config file, so
+// flags override the environment, which overrides the file. The file uses
+// flag names as keys in flat YAML ("beacon-url: http://..."). A missing file
+// is only an error when it was named with --config or ETHCALC_CONFIG.
+func applyConfigLayers() {
+    flag.Visit(func(f *flag.Flag) {
+        flagSources[f.Name] = sourceFlag
+    })
+
+    // The config file's own location can come from the environment
+    if value, ok := os.LookupEnv(envName("config")); ok && configPath == "" {
+        setFromLayer("config", value, sourceEnv, envName("config"))
+    }
+
+    path, explicit := configPath, configPath != ""
+    if !explicit {
+        path = defaultConfigPath()
+    }
+    settings := make(map[string]string)
+    if path != "" {
+        f, err := os.Open(path)
+        switch {
+        case err == nil:
+            settings, err = config.ParseChainConfig(f)
+            f.Close()
+            if err != nil {
+                fmt.Fprintf(os.Stderr, "Error reading config file %s: %v\n", path, err)
+                os.Exit(1)
+            }
+        case explicit || !errors.Is(err, fs.ErrNotExist):
+            fmt.Fprintf(os.Stderr, "Error reading config file: %v\n", err)
+            os.Exit(1)
+        }
+    }
+    for name := range settings {
+        if f := flag.Lookup(name); f == nil || name == "config" {
+            fmt.Printf("Error: Unknown setting '%s' in config file %s\n", name, path)
+            os.Exit(1)
+        }
+    }
+
+    warnUnknownEnv()
+
+    flag.VisitAll(func(f *flag.Flag) {
+        if f.Changed {
+            return
+        }
+        if value, ok := os.LookupEnv(envName(f.Name)); ok {
+            setFromLayer(f.Name, value, sourceEnv, envName(f.Name))
+        } else if value, ok := settings[f.Name]; ok {
+            setFromLayer(f.Name, value, sourceFile, path)
+        } else {
+            flagSources[f.Name] = sourceDefault
+        }
+    })
+}
+
+// warnUnknownEnv warns about ETHCALC_* variables that match no flag, since a
+// misspelled name would otherwise be ignored silently
+func warnUnknownEnv() {
+    known := make(map[string]bool)
+    flag.VisitAll(func(f *flag.Flag) {
+        known[envName(f.Name)] = true
+    })
+    for _, entry := range os.Environ() {
+        name, _, _ := strings.Cut(entry, "=")
+        if strings.HasPrefix(name, "ETHCALC_") && !known[name] {
+            fmt.Fprintf(os.Stderr, "Warning: %s does not match any flag\n", name)
+        }
+    }
+}
+
+// setFromLayer sets a flag from the environment or config file, exiting on an
+// invalid value
+func setFromLayer(name, value, source, origin string) {
+    if err := flag.Set(name, value); err != nil {
+        fmt.Printf("Error: Invalid value '%s' for --%s from %s: %v\n", value, name, origin, err)
+        os.Exit(1)
+    }
+    flagSources[name] = source
+}
+
+func handleConfig() {
+    if flag.NArg() != 2 || (flag.Arg(1) != "show" && flag.Arg(1) != "env") {
+        fmt.Println("Error: Use 'config show' to print the effective configuration or 'config env' to print it as ETHCALC_* variables")
+        os.Exit(1)
+    }
+
+    var values []configValue
+    flag.VisitAll(func(f *flag.Flag) {
+        values = append(values, configValue{Name: f.Name, Value: f.Value.String(), Source: flagSources[f.Name]})
+    })
+
+    if flag.Arg(1) == "env" {
+        printConfigEnv(values)
+        return
+    }
+
+    if printFormatted(values) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    highlight := color.New(color.FgGreen)
+
+    header.Println("\n=== Effective Configuration ===")
+
+    path := configPath
+    if path == "" {
+        path = defaultConfigPath()
+    }
+    fmt.Printf("\nConfig File: %s\n", path)
+    fmt.Println("Precedence: flag > env (ETHCALC_*) > file > default")
+    fmt.Println()
+
+    fmt.Printf("%-28s %-10s %s\n", "Setting", "Source", "Value")
+    fmt.Println(strings.Repeat("-", 80))
+    for _, v := range values {
+        line := fmt.Sprintf("%-28s %-10s %s", v.Name, v.Source, v.Value)
+        if v.Source == sourceDefault {
+            fmt.Println(line)
+        } else {
+            highlight.Println(line)
+        }
+    }
+
+    fmt.Println()
+}
+
+// printConfigEnv prints the settings that differ from the defaults as
+// ETHCALC_* assignments, ready for an env file or a container definition
+func printConfigEnv(values []configValue) {
+    for _, v := range values {
+        if v.Source == sourceDefault || v.Name == "config" {
+            continue
+        }
+        fmt.Printf("%s=%s\n", envName(v.Name), v.Value)
+    }
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/config"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+func handleConsolidate() {
+    requireValidators("consolidate")
+
+    // Without a count, plan to consolidate the portfolio's 0x01 validators
+    requests := consolidations
+    if !flag.CommandLine.Changed("consolidations") && portfolioValidators != nil {
+        requests = 0
+        for _, v := range portfolioValidators {
+            if !v.Compounding() {
+                requests++
+            }
+        }
+    }
+    if requests <= 0 {
+        fmt.Println("Error: The consolidate command requires a number of consolidations (--consolidations)")
+        os.Exit(1)
+    }
+    if consolidationQueue < 0 {
+        fmt.Println("Error: Consolidation queue length must not be negative")
+        os.Exit(1)
+    }
+
+    results := projectRewards(createNetworkState(validatorCount))
+    plan := calculator.PlanConsolidations(results, requests, consolidationQueue)
+    recordRun(plan)
+
+    if printFormatted(plan) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+    highlight := color.New(color.FgGreen, color.Bold)
+    warning := color.New(color.FgRed, color.Bold)
+
+    header.Println("\n=== Consolidation Queue (EIP-7251) ===")
+
+    fmt.Printf("\nNetwork Validators: %s\n", formatNumber(uint64(validatorCount)))
+    fmt.Printf("Consolidations: %d (%s queued ahead)\n", plan.Requests, formatNumber(uint64(plan.QueueAhead)))
+
+    subheader.Println("\nConsolidation Churn:")
+    fmt.Printf("- Churn Limit: %s ETH per epoch\n", formatDecimal(plan.ChurnLimit))
+    if plan.Disabled {
+        warning.Println("- Consolidations are not processed: the churn cannot cover one 32 ETH validator")
+        fmt.Println()
+        return
+    }
+    fmt.Printf("- Throughput: %s consolidations per epoch (%s per day)\n",
+        formatDecimal(plan.PerEpoch), formatDecimal(plan.PerEpoch*float64(config.EPOCHS_PER_DAY)))
+
+    subheader.Println("\nTimeline:")
+    fmt.Printf("- First Consolidation Complete: %s days\n", formatDecimal(plan.FirstDays))
+    fmt.Printf("- Last Consolidation Complete: %s days (%s epochs)\n", formatDecimal(plan.LastDays), formatDecimal(plan.LastEpochs))
+    fmt.Printf("- Each Source Idle Between Exit and Transfer: %s epochs\n", formatDecimal(plan.IdleEpochs))
+
+    subheader.Println("\nRewards Over the Transition:")
+    fmt.Printf("- Without Consolidating: %s ETH\n", formatETH(plan.RewardsKept))
+    fmt.Printf("- While Consolidating: %s ETH\n", formatETH(plan.RewardsEarned))
+    highlight.Printf("- Difference: %s ETH\n", formatETH(plan.RewardsDifference))
+
+    fmt.Println()
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "sort"
+    "strconv"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+// constantsDiff compares the projections of one scenario under two sets of
+// chain constants. Constants lists only the parameters the overlays set
+// differently.
+type constantsDiff struct {
+    OverlayA     string      `json:"overlay_a"`
+    OverlayB     string      `json:"overlay_b"`
+    Constants    []fieldDiff `json:"constants"`
+    Fields       []fieldDiff `json:"fields"`
+    ChangedCount int         `json:"changed_count"`
+}
+
+func handleConstants() {
+    if flag.NArg() != 3 {
+        fmt.Println("Error: Use 'constants <overlay> <overlay>', each an overlay or preset name, KEY=value,... pairs, or a config.yaml file")
+        os.Exit(1)
+    }
+    requireValidators("constants")
+
+    a, paramsA := projectUnderOverlay(flag.Arg(1))
+    b, paramsB := projectUnderOverlay(flag.Arg(2))
+    diff := &constantsDiff{OverlayA: flag.Arg(1), OverlayB: flag.Arg(2), Fields: diffResults(a, b)}
+
+    var keys []string
+    for key, value := range paramsA {
+        if paramsB[key] != value {
+            keys = append(keys, key)
+        }
+    }
+    sort.Strings(keys)
+    for _, key := range keys {
+        d := fieldDiff{Name: key, A: constantValue(paramsA[key]), B: constantValue(paramsB[key])}
+        setDelta(&d)
+        diff.Constants = append(diff.Constants, d)
+    }
+    for _, d := range diff.Fields {
+        if fieldChanged(d) {
+            diff.ChangedCount++
+        }
+    }
+    recordRun(diff)
+
+    if printFormatted(diff) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+
+    header.Println("\n=== Constant Set Comparison ===")
+    fmt.Printf("\nA: %s\nB: %s\n", diff.OverlayA, diff.OverlayB)
+    fmt.Printf("Validators: %s, Participation: %s\n", formatNumber(uint64(validatorCount)), formatPercent(participation*100))
+
+    subheader.Println("\nChanged Constants:")
+    if len(diff.Constants) == 0 {
+        fmt.Println("None: both overlays leave the same chain parameters")
+    } else {
+        printFieldDiffs(diff.Constants)
+    }
+
+    subheader.Println("\nRewards:")
+    printFieldDiffs(diff.Fields)
+    fmt.Printf("\n%d of %d fields changed\n\n", diff.ChangedCount, len(diff.Fields))
+}
+
+// projectUnderOverlay projects rewards at the flags' values with the overlay
+// applied over the current chain parameters, returning the projection and the
+// parameters it ran under. The chain parameters are restored afterwards.
+func projectUnderOverlay(overlay string) (*types.RewardResults, map[string]string) {
+    spec, err := config.LoadOverlay(overlay)
+    if err != nil {
+        fmt.Printf("Error: %v\n", err)
+        os.Exit(1)
+    }
+
+    saved := config.Save()
+    defer saved.Restore()
+    if _, err := config.ApplySpec(spec); err != nil {
+        fmt.Printf("Error: Overlay '%s': %v\n", overlay, err)
+        os.Exit(1)
+    }
+
+    state := createNetworkState(validatorCount)
+    if flag.CommandLine.Changed("fork") {
+        state.CurrentFork = fork
+    }
+    return projectRewards(state), config.Save().Values()
+}
+
+// constantValue returns a chain parameter as a number when it is one, so its
+// change can be computed
+func constantValue(value string) interface{} {
+    if n, err := strconv.ParseUint(value, 10, 64); err == nil {
+        return float64(n)
+    }
+    return value
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/beacon"
+    "github.com/eth-rewards-calculator/internal/crosscheck"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+// mismatchesShown caps the mismatching rewards listed without --detailed
+const mismatchesShown = 20
+
+func handleCrossCheck() {
+    client := beacon.NewClient(primaryBeaconURL())
+
+    // An epoch's rewards are paid at the end of the next, so the latest
+    // finalized payment is for the epoch two before the finalized one
+    epoch := checkEpoch
+    if !flag.CommandLine.Changed("epoch") {
+        finalized, err := client.GetFinalizedEpoch()
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "Error fetching finalized epoch: %v\n", err)
+            os.Exit(1)
+        }
+        if finalized < 2 {
+            fmt.Println("Error: The chain has not finalized an epoch with rewards to compare yet")
+            os.Exit(1)
+        }
+        epoch = finalized - 2
+    }
+
+    var indices []uint64
+    if flag.CommandLine.Changed("validator-index") {
+        indices = append(indices, validatorIndex)
+    }
+
+    check, err := crosscheck.Run(client, epoch, indices...)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error cross-checking rewards: %v\n", err)
+        os.Exit(1)
+    }
+    recordRun(check)
+
+    if !printFormatted(check) {
+        header := color.New(color.FgCyan, color.Bold)
+        subheader := color.New(color.FgYellow, color.Bold)
+
+        header.Println("\n=== Reward Cross-Check ===")
+        fmt.Printf("\nReference: %s\n", check.Client)
+        fmt.Printf("Epoch: %d (%s)\n", check.Epoch, check.Fork)
+        fmt.Printf("Validators Compared: %s\n", formatNumber(uint64(check.Validators)))
+
+        subheader.Println("\nTotals (Gwei):")
+        fmt.Printf("%-12s %-18s %-18s %-12s %-12s\n", "Component", "Calculator", "Client", "Mismatches", "Max Delta")
+        for _, component := range check.Components {
+            mismatches := color.GreenString("%-12d", component.Mismatches)
+            if component.Mismatches > 0 {
+                mismatches = color.RedString("%-12d", component.Mismatches)
+            }
+            fmt.Printf("%-12s %-18d %-18d %s %-12d\n", component.Component,
+                component.Calculated, component.Reference, mismatches, component.MaxAbsDelta)
+        }
+
+        if len(check.Mismatches) > 0 {
+            subheader.Println("\nMismatches (Gwei):")
+            fmt.Printf("%-12s %-12s %-14s %-14s %-10s\n", "Validator", "Component", "Calculator", "Client", "Delta")
+            for i, mismatch := range check.Mismatches {
+                if i == mismatchesShown && !detailed {
+                    fmt.Printf("... and %d more (--detailed lists them all)\n", len(check.Mismatches)-i)
+                    break
+                }
+                fmt.Printf("%-12d %-12s %-14d %-14d %+d\n", mismatch.ValidatorIndex, mismatch.Component,
+                    mismatch.Calculated, mismatch.Reference, mismatch.Calculated-mismatch.Reference)
+            }
+        } else {
+            color.New(color.FgGreen, color.Bold).Println("\nEvery reward matches the client's.")
+        }
+        fmt.Println()
+    }
+
+    if len(check.Mismatches) > 0 {
+        os.Exit(1)
+    }
+}
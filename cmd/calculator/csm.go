@@ -0,0 +1,41 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+func handleCSM() {
+    requireValidators("csm")
+
+    if capitalETH*1e9 < config.CSM_FIRST_BOND {
+        fmt.Printf("Error: At least %s ETH is needed to bond a CSM validator\n",
+            formatGweiAsETH(config.CSM_FIRST_BOND))
+        os.Exit(1)
+    }
+
+    results := projectRewards(createNetworkState(validatorCount))
+
+    comparison := &types.SetupComparison{
+        Capital: capitalETH,
+        Setups: []types.StakingSetup{
+            calculator.SoloStakingSetup(results, capitalETH),
+            calculator.LidoCSMSetup(results, capitalETH),
+        },
+    }
+
+    outputSetups("Lido CSM vs Solo Staking", comparison)
+
+    if textOutput {
+        csm := comparison.Setups[1]
+        bond := float64(calculator.CSMBond(csm.Validators))
+        fmt.Printf("CSM bond for %d validators: %s ETH (%s ETH excess bond earning stETH rewards)\n",
+            csm.Validators, formatGweiAsETH(bond), formatETH(capitalETH-bond/1e9))
+        fmt.Printf("CSM operators earn %s of their validators' rewards; fee rewards are withheld below %s performance\n\n",
+            formatPercent(float64(config.CSM_OPERATOR_FEE_BPS)/100), formatPercent(config.CSM_PERFORMANCE_THRESHOLD*100))
+    }
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "math"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+// fieldDiff is one output field under both scenarios. Delta and PercentChange
+// are B - A and its share of A, for numeric fields only.
+type fieldDiff struct {
+    Name          string      `json:"name"`
+    A             interface{} `json:"a"`
+    B             interface{} `json:"b"`
+    Delta         *float64    `json:"delta,omitempty"`
+    PercentChange *float64    `json:"percent_change,omitempty"`
+}
+
+// scenarioDiff compares the projections of two scenarios field by field
+type scenarioDiff struct {
+    ScenarioA    string      `json:"scenario_a"`
+    ScenarioB    string      `json:"scenario_b"`
+    Fields       []fieldDiff `json:"fields"`
+    ChangedCount int         `json:"changed_count"`
+}
+
+func handleDiff() {
+    if flag.NArg() != 3 {
+        fmt.Println("Error: Use 'diff <scenario> <scenario>', each as name=value,... such as validators=1000000,participation=0.95,effectiveness=0.99,fork=deneb")
+        os.Exit(1)
+    }
+
+    a, b := projectScenario(flag.Arg(1)), projectScenario(flag.Arg(2))
+    diff := &scenarioDiff{ScenarioA: flag.Arg(1), ScenarioB: flag.Arg(2), Fields: diffResults(a, b)}
+    for _, d := range diff.Fields {
+        if fieldChanged(d) {
+            diff.ChangedCount++
+        }
+    }
+    recordRun(diff)
+
+    if printFormatted(diff) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+
+    header.Println("\n=== Scenario Diff ===")
+    fmt.Printf("\nA: %s\nB: %s\n\n", diff.ScenarioA, diff.ScenarioB)
+
+    printFieldDiffs(diff.Fields)
+    fmt.Printf("\n%d of %d fields changed\n\n", diff.ChangedCount, len(diff.Fields))
+}
+
+// diffResults pairs the fields of two projections in a's order. Fields only
+// b has, such as omitted empty ones, go last in name order.
+func diffResults(a, b *types.RewardResults) []fieldDiff {
+    fieldsA, fieldsB := scenarioFields(a), scenarioFields(b)
+    valuesB := make(map[string]interface{}, len(fieldsB))
+    for _, f := range fieldsB {
+        valuesB[f.key] = f.value
+    }
+
+    var diffs []fieldDiff
+    for _, f := range fieldsA {
+        d := fieldDiff{Name: f.key, A: f.value, B: valuesB[f.key]}
+        delete(valuesB, f.key)
+        setDelta(&d)
+        diffs = append(diffs, d)
+    }
+    var extra []string
+    for key := range valuesB {
+        extra = append(extra, key)
+    }
+    sort.Strings(extra)
+    for _, key := range extra {
+        d := fieldDiff{Name: key, B: valuesB[key]}
+        setDelta(&d)
+        diffs = append(diffs, d)
+    }
+    return diffs
+}
+
+// printFieldDiffs prints the fields as a table, highlighting those that changed
+func printFieldDiffs(diffs []fieldDiff) {
+    changed := color.New(color.FgYellow)
+
+    fmt.Printf("%-50s %20s %20s %20s %12s\n", "Field", "A", "B", "Change", "Change %")
+    fmt.Println(strings.Repeat("-", 126))
+    for _, d := range diffs {
+        delta, percent := "", ""
+        if d.Delta != nil {
+            rounded := roundTo(*d.Delta, precision)
+            if rounded == 0 {
+                rounded = 0 // not -0
+            }
+            delta = strconv.FormatFloat(rounded, 'f', -1, 64)
+            if rounded >= 0 {
+                delta = "+" + delta
+            }
+        }
+        if d.PercentChange != nil {
+            percent = formatSignedPercent(*d.PercentChange)
+        }
+        line := fmt.Sprintf("%-50s %20s %20s %20s %12s",
+            d.Name, formatHistoryValue(d.A), formatHistoryValue(d.B), delta, percent)
+        if fieldChanged(d) {
+            changed.Println(line)
+        } else {
+            fmt.Println(line)
+        }
+    }
+}
+
+// projectScenario projects rewards for a scenario given as name=value,...
+// pairs. validators, participation, and the grid parameters set their flags'
+// values; effectiveness is the share of duties performed (1 - --miss-rate)
+// and fork the fork whose rules apply. Anything not given keeps its flag's
+// value, and the flags are restored afterwards.
+func projectScenario(spec string) *types.RewardResults {
+    savedValidators, savedParticipation, savedMissRate := validatorCount, participation, missRate
+    savedSlashingProb, savedPriorityFees, savedMEV := slashingProb, priorityFees, mevReward
+    defer func() {
+        validatorCount, participation, missRate = savedValidators, savedParticipation, savedMissRate
+        slashingProb, priorityFees, mevReward = savedSlashingProb, savedPriorityFees, savedMEV
+    }()
+
+    scenarioFork := ""
+    if flag.CommandLine.Changed("fork") {
+        scenarioFork = fork
+    }
+    given := make(map[string]bool)
+    for _, pair := range strings.Split(spec, ",") {
+        name, value, found := strings.Cut(pair, "=")
+        name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+        if !found || given[name] {
+            fmt.Printf("Error: Invalid scenario '%s' (use name=value,... with each name once)\n", spec)
+            os.Exit(1)
+        }
+        given[name] = true
+
+        switch name {
+        case "fork":
+            if !config.IsKnownFork(value) || value == "phase0" {
+                fmt.Printf("Error: Unknown fork '%s' in scenario (known forks from Altair: %s)\n",
+                    value, strings.Join(config.KnownForks[1:], ", "))
+                os.Exit(1)
+            }
+            scenarioFork = value
+        case "effectiveness":
+            effectiveness, err := strconv.ParseFloat(value, 64)
+            if err != nil || effectiveness < 0 || effectiveness > 1 {
+                fmt.Printf("Error: Invalid effectiveness value '%s'\n", value)
+                os.Exit(1)
+            }
+            missRate = 1 - effectiveness
+        default:
+            parameter, known := gridParameters[name]
+            number, err := strconv.ParseFloat(value, 64)
+            if !known {
+                fmt.Printf("Error: Unknown scenario parameter '%s' (use validators, participation, effectiveness, fork, miss-rate, slashing-prob, priority-fees, or mev)\n", name)
+                os.Exit(1)
+            }
+            if err != nil || !parameter.valid(number) {
+                fmt.Printf("Error: Invalid %s value '%s'\n", name, value)
+                os.Exit(1)
+            }
+            parameter.set(number)
+        }
+    }
+    if given["effectiveness"] && given["miss-rate"] {
+        fmt.Println("Error: A scenario cannot give both effectiveness and miss-rate")
+        os.Exit(1)
+    }
+    requireValidators("diff")
+
+    state := createNetworkState(validatorCount)
+    state.CurrentFork = scenarioFork
+    return projectRewards(state)
+}
+
+// scenarioFields flattens results to their JSON fields in document order,
+// with nested fields named by dotted paths. Numbers keep full precision so
+// small changes are not rounded away.
+func scenarioFields(results *types.RewardResults) []field {
+    data, err := json.Marshal(results)
+    var tree interface{}
+    if err == nil {
+        decoder := json.NewDecoder(bytes.NewReader(data))
+        decoder.UseNumber()
+        tree, err = decodeOrdered(decoder)
+    }
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+        os.Exit(1)
+    }
+
+    var fields []field
+    var walk func(path string, value interface{})
+    walk = func(path string, value interface{}) {
+        switch v := value.(type) {
+        case object:
+            for _, f := range v {
+                if path == "" {
+                    walk(f.key, f.value)
+                } else {
+                    walk(path+"."+f.key, f.value)
+                }
+            }
+        case []interface{}:
+            for i, child := range v {
+                walk(fmt.Sprintf("%s[%d]", path, i), child)
+            }
+        case json.Number:
+            number, _ := v.Float64()
+            fields = append(fields, field{path, number})
+        default:
+            fields = append(fields, field{path, v})
+        }
+    }
+    walk("", tree)
+    return fields
+}
+
+// fieldChanged reports whether the field differs between the scenarios,
+// however little
+func fieldChanged(d fieldDiff) bool {
+    if d.Delta != nil {
+        return *d.Delta != 0
+    }
+    return fmt.Sprint(d.A) != fmt.Sprint(d.B)
+}
+
+// setDelta fills in the change of a numeric field, then rounds both values
+// to --precision
+func setDelta(d *fieldDiff) {
+    a, aOK := d.A.(float64)
+    b, bOK := d.B.(float64)
+    if aOK {
+        d.A = roundTo(a, precision)
+    }
+    if bOK {
+        d.B = roundTo(b, precision)
+    }
+    if !aOK || !bOK {
+        return
+    }
+    delta := b - a
+    d.Delta = &delta
+    if a != 0 {
+        percent := delta / math.Abs(a) * 100
+        d.PercentChange = &percent
+    }
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/provider"
+
+    "github.com/fatih/color"
+)
+
+func handleEndpoints() {
+    statuses := provider.NewFailover(liveEndpoints(), 0, 0).Check()
+    recordRun(statuses)
+
+    healthy := 0
+    for _, status := range statuses {
+        if status.Healthy {
+            healthy++
+        }
+    }
+
+    if !printFormatted(statuses) {
+        header := color.New(color.FgCyan, color.Bold)
+        header.Println("\n=== Live Endpoints ===")
+        fmt.Println("\nIn order of preference:")
+        for i, status := range statuses {
+            if status.Healthy {
+                fmt.Printf("%d. %s: %s, %s active validators (%.0f ms)\n", i+1, status.Name,
+                    color.GreenString("up"), formatNumber(uint64(status.ActiveValidators)), status.LatencyMs)
+            } else {
+                fmt.Printf("%d. %s: %s, %s\n", i+1, status.Name, color.RedString("down"), status.Error)
+            }
+        }
+        fmt.Printf("\n%d of %d endpoints healthy\n\n", healthy, len(statuses))
+    }
+
+    if healthy == 0 {
+        os.Exit(1)
+    }
+}
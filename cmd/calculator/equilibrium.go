@@ -0,0 +1,67 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+
+    "github.com/fatih/color"
+)
+
+func handleEquilibrium() {
+    if alternativeYield <= 0 {
+        fmt.Println("Error: Alternative yield must be greater than 0")
+        os.Exit(1)
+    }
+
+    result := calculator.SolveStakingEquilibrium(alternativeYield, participation)
+    recordRun(result)
+
+    if printFormatted(result) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+    highlight := color.New(color.FgGreen, color.Bold)
+
+    header.Println("\n=== Staking Equilibrium ===")
+
+    subheader.Println("\nInputs:")
+    fmt.Printf("- Alternative Yield: %s\n", formatPercent(result.TargetYield))
+    fmt.Printf("- Participation Rate: %s\n", formatPercent(participation*100))
+
+    subheader.Println("\nEquilibrium:")
+    highlight.Printf("- Validator Count: %s\n", formatNumber(uint64(result.ValidatorCount)))
+    fmt.Printf("- Total Staked: %s ETH\n", formatNumber(result.TotalStaked))
+    fmt.Printf("- Staking Ratio: %s of supply\n", formatPercent(result.StakingRatio))
+    fmt.Printf("- Staking APY: %s\n", formatPercent(result.APY))
+
+    if result.ExceedsSupply {
+        warningColor := color.New(color.FgRed, color.Bold)
+        warningColor.Println("- Staking APY stays above the alternative yield even with the entire supply staked")
+    }
+
+    // Compare against the current network if a validator count was given
+    if validatorCount > 0 {
+        current := calculator.CalculateRewards(createNetworkState(validatorCount), participation)
+
+        subheader.Println("\nCurrent Network:")
+        fmt.Printf("- Validator Count: %s\n", formatNumber(uint64(validatorCount)))
+        fmt.Printf("- Staking APY: %s\n", formatPercent(current.APY))
+
+        switch {
+        case validatorCount < result.ValidatorCount:
+            fmt.Printf("- Stake is expected to grow by ~%s validators\n",
+                formatNumber(uint64(result.ValidatorCount-validatorCount)))
+        case validatorCount > result.ValidatorCount:
+            fmt.Printf("- Stake is expected to shrink by ~%s validators\n",
+                formatNumber(uint64(validatorCount-result.ValidatorCount)))
+        default:
+            fmt.Println("- The network is at equilibrium")
+        }
+    }
+
+    fmt.Println()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+    "fmt"
+    "strconv"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+)
+
+// exitPlanResult is the exit command's JSON output
+type exitPlanResult struct {
+    *types.ExitPlan
+    ExitDelayCost float64 `json:"exit_delay_cost_eth"`
+    TotalCost     float64 `json:"total_cost_eth"`
+}
+
+func handleExit() {
+    requireValidators("exit")
+
+    plan := calculator.PlanExit(validatorCount, exitQueue, pendingRequests, excessRequests)
+    results := projectRewards(createNetworkState(validatorCount))
+    result := exitPlanResult{
+        ExitPlan:      plan,
+        ExitDelayCost: results.ExitDelayCostGwei / 1e9,
+        TotalCost:     plan.RequestFee + results.ExitDelayCostGwei/1e9,
+    }
+    recordRun(result)
+
+    if printFormatted(&result) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+    highlight := color.New(color.FgGreen, color.Bold)
+
+    header.Println("\n=== Exit Plan (Execution Layer Request) ===")
+
+    fmt.Printf("\nNetwork Validators: %s\n", formatNumber(uint64(validatorCount)))
+    fmt.Printf("Validators Ahead in Exit Queue: %s\n", formatNumber(uint64(exitQueue)))
+
+    subheader.Println("\nWithdrawal Request (EIP-7002):")
+    fmt.Printf("- Excess Requests: %d\n", plan.ExcessRequests)
+    fmt.Printf("- Request Fee: %s ETH (%s wei)\n", strconv.FormatFloat(plan.RequestFee, 'g', 6, 64), plan.RequestFeeWei)
+    if plan.BlocksToMinimumFee > 0 {
+        fmt.Printf("- Fee Returns to Minimum After: %d blocks without new requests\n", plan.BlocksToMinimumFee)
+    }
+    fmt.Printf("- Requests Queued Ahead: %d\n", plan.PendingRequests)
+
+    subheader.Println("\nTimeline (from submission):")
+    for _, m := range plan.Milestones {
+        fmt.Printf("- %-40s %10s epochs  %8s days\n", m.Stage+":", formatDecimal(m.Epochs), formatDecimal(m.Days))
+    }
+
+    subheader.Println("\nCost:")
+    fmt.Printf("- Request Fee: %s ETH\n", strconv.FormatFloat(plan.RequestFee, 'g', 6, 64))
+    fmt.Printf("- Exit Delay Cost at %s Alternative Yield: %s ETH\n",
+        formatPercent(alternativeYield), formatETH(result.ExitDelayCost))
+    highlight.Printf("- Total: %s ETH\n", formatETH(result.TotalCost))
+
+    fmt.Println()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+func handleExitPlan() {
+    requireValidators("exit-plan")
+
+    validators := fleetSize
+    if !flag.CommandLine.Changed("fleet") && portfolioValidators != nil {
+        validators = len(portfolioValidators)
+    }
+    if validators <= 0 {
+        fmt.Println("Error: The exit-plan command requires the number of validators to exit (--fleet)")
+        os.Exit(1)
+    }
+    if exitBy == "" {
+        fmt.Println("Error: The exit-plan command requires a date to be fully exited by (--exit-by)")
+        os.Exit(1)
+    }
+    deadline, err := time.Parse("2006-01-02", exitBy)
+    if err != nil {
+        fmt.Printf("Error: Invalid exit date '%s' (use YYYY-MM-DD)\n", exitBy)
+        os.Exit(1)
+    }
+    start := time.Now().UTC()
+    if !deadline.After(start) {
+        fmt.Printf("Error: Exit date %s is not in the future\n", exitBy)
+        os.Exit(1)
+    }
+
+    results := projectRewards(createNetworkState(validatorCount))
+    schedule := calculator.PlanStaggeredExits(results, validators, exitQueue, start, deadline)
+    recordRun(schedule)
+
+    if printFormatted(schedule) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+    highlight := color.New(color.FgGreen, color.Bold)
+    warning := color.New(color.FgRed, color.Bold)
+
+    header.Println("\n=== Staggered Exit Plan ===")
+
+    fmt.Printf("\nNetwork Validators: %s\n", formatNumber(uint64(validatorCount)))
+    fmt.Printf("Validators to Exit: %s\n", formatNumber(uint64(schedule.Validators)))
+    fmt.Printf("Fully Exited By: %s\n", schedule.Deadline)
+    fmt.Printf("Exit Churn: %d validators per epoch (%s ahead in the queue)\n",
+        schedule.ChurnLimit, formatNumber(uint64(schedule.QueueAhead)))
+    fmt.Printf("Exit to Withdrawal: %s days\n", formatDecimal(schedule.WithdrawalDays))
+
+    if !schedule.Feasible {
+        warning.Printf("\nThe deadline cannot be met: submitting every exit today, the fleet is withdrawn by %s\n",
+            schedule.CompletionDate)
+    }
+
+    subheader.Println("\nSchedule:")
+    fmt.Printf("%-12s %-12s %-12s %-16s %-14s\n", "Submit On", "Exits", "Cumulative", "Last Exit On", "Income ETH")
+    fmt.Println(strings.Repeat("-", 70))
+    for _, batch := range schedule.Batches {
+        fmt.Printf("%-12s %-12s %-12s %-16s %-14s\n", batch.Date, formatNumber(uint64(batch.Validators)),
+            formatNumber(uint64(batch.Cumulative)), batch.ExitDate, formatETH(batch.Income))
+    }
+
+    subheader.Println("\nIncome Until Exit:")
+    fmt.Printf("- Exiting Everything Today: %s ETH\n", formatETH(schedule.ImmediateIncome))
+    highlight.Printf("- Following the Schedule: %s ETH\n", formatETH(schedule.ExpectedIncome))
+    fmt.Printf("- Fully Withdrawn By: %s\n", schedule.CompletionDate)
+
+    fmt.Println()
+}
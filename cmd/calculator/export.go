@@ -0,0 +1,82 @@
+package main
+
+import (
+    "bufio"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/export"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+func handleExport() {
+    if _, ok := export.Formats[taxFormat]; !ok {
+        fmt.Printf("Error: Unknown tax format '%s' (use koinly, cointracker, or csv)\n", taxFormat)
+        os.Exit(1)
+    }
+
+    var events []types.RewardEvent
+    if eventsPath != "" {
+        events = readRewardEvents()
+    } else {
+        requireValidators("export")
+        if forecastMonths <= 0 {
+            fmt.Println("Error: Months must be greater than 0")
+            os.Exit(1)
+        }
+        results := projectRewards(createNetworkState(validatorCount))
+        events = calculator.ProjectRewardEvents(results, time.Now().UTC(), float64(forecastMonths)*365.25/12)
+    }
+    recordRun(events)
+
+    labels := map[string]string{types.ConsensusLayer: clLabel, types.ExecutionLayer: elLabel}
+    if err := export.Write(os.Stdout, taxFormat, events, labels); err != nil {
+        fmt.Fprintf(os.Stderr, "Error writing export: %v\n", err)
+        os.Exit(1)
+    }
+}
+
+// readRewardEvents reads reward events, one JSON record per line, such as
+// {"time": "2025-03-01T12:00:00Z", "layer": "consensus", "amount_eth": 0.021}
+func readRewardEvents() []types.RewardEvent {
+    var r io.Reader = os.Stdin
+    if eventsPath != "-" {
+        f, err := os.Open(eventsPath)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "Error reading events: %v\n", err)
+            os.Exit(1)
+        }
+        defer f.Close()
+        r = f
+    }
+
+    var events []types.RewardEvent
+    scanner := bufio.NewScanner(r)
+    for line := 1; scanner.Scan(); line++ {
+        if len(scanner.Bytes()) == 0 {
+            continue
+        }
+
+        var event types.RewardEvent
+        if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+            fmt.Fprintf(os.Stderr, "Error reading events: line %d: %v\n", line, err)
+            os.Exit(1)
+        }
+        if event.Layer != types.ConsensusLayer && event.Layer != types.ExecutionLayer {
+            fmt.Fprintf(os.Stderr, "Error reading events: line %d: layer must be %q or %q\n",
+                line, types.ConsensusLayer, types.ExecutionLayer)
+            os.Exit(1)
+        }
+        events = append(events, event)
+    }
+    if err := scanner.Err(); err != nil {
+        fmt.Fprintf(os.Stderr, "Error reading events: %v\n", err)
+        os.Exit(1)
+    }
+
+    return events
+}
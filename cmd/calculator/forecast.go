@@ -0,0 +1,92 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+)
+
+func handleForecast() {
+    requireValidators("forecast")
+
+    if forecastMonths <= 0 {
+        fmt.Println("Error: Forecast months must be greater than 0")
+        os.Exit(1)
+    }
+
+    capacity := growthCap
+    if capacity == 0 {
+        capacity = validatorCount * 2
+    }
+    if capacity <= validatorCount {
+        fmt.Println("Error: Growth cap must be larger than the current validator count")
+        os.Exit(1)
+    }
+
+    models := []calculator.GrowthModel{
+        calculator.LinearGrowth{Initial: validatorCount, PerMonth: growthRate},
+        calculator.NewLogisticGrowth(validatorCount, capacity, growthRate),
+    }
+
+    forecasts := make([]*types.GrowthForecast, len(models))
+    for i, model := range models {
+        forecasts[i] = calculator.ForecastAPY(model, forecastMonths, participation)
+    }
+
+    recordRun(forecasts)
+
+    if printFormatted(forecasts) {
+        return
+    }
+
+    if parquetOutput {
+        var rows []forecastRow
+        for _, forecast := range forecasts {
+            for _, point := range forecast.Points {
+                rows = append(rows, newForecastRow(forecast.Model, point))
+            }
+        }
+        printParquet(rows)
+        return
+    }
+
+    outputForecast(forecasts, capacity)
+}
+
+func outputForecast(forecasts []*types.GrowthForecast, capacity int) {
+    header := color.New(color.FgCyan, color.Bold)
+    header.Println("\n=== Validator Set Growth Forecast ===")
+
+    fmt.Printf("\nStarting Validators: %s\n", formatNumber(uint64(validatorCount)))
+    fmt.Printf("Growth Rate: %+.0f validators/month\n", growthRate)
+    fmt.Printf("Logistic Capacity: %s validators\n", formatNumber(uint64(capacity)))
+    fmt.Printf("Participation Rate: %s\n\n", formatPercent(participation*100))
+
+    // Table header
+    fmt.Printf("%-8s", "Month")
+    for _, forecast := range forecasts {
+        fmt.Printf(" %-15s %-10s %-10s",
+            strings.ToUpper(forecast.Model[:1])+forecast.Model[1:]+" Vals", "APY %", "Change")
+    }
+    fmt.Println()
+    fmt.Println(strings.Repeat("-", 8+38*len(forecasts)))
+
+    for month := range forecasts[0].Points {
+        fmt.Printf("%-8d", month)
+        for _, forecast := range forecasts {
+            point := forecast.Points[month]
+            fmt.Printf(" %-15s %-10s %-10s",
+                formatNumber(uint64(point.ValidatorCount)),
+                formatPercent(point.APY),
+                formatSignedPercent(point.APYChange))
+        }
+        fmt.Println()
+    }
+
+    fmt.Println()
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+)
+
+// forkNames are the display names for calculator.ComparedForks
+var forkNames = map[string]string{
+    "phase0":    "Phase 0",
+    "altair":    "Altair",
+    "bellatrix": "Bellatrix+",
+}
+
+func handleForks() {
+    requireValidators("forks")
+
+    comparisons := calculator.CompareForks(validatorCount, participation, inactivityEpochs, slashingCount)
+    recordRun(comparisons)
+
+    if printFormatted(comparisons) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+
+    header.Println("\n=== Rewards Across Forks ===")
+
+    fmt.Printf("\nNetwork Validators: %s\n", formatNumber(uint64(validatorCount)))
+    fmt.Printf("Participation Rate: %s\n\n", formatPercent(participation*100))
+
+    row := func(label string, value func(c types.ForkComparison) string) {
+        fmt.Printf("%-38s", label)
+        for _, c := range comparisons {
+            fmt.Printf(" %-16s", value(c))
+        }
+        fmt.Println()
+    }
+
+    row("", func(c types.ForkComparison) string { return forkNames[c.Fork] })
+    fmt.Println(strings.Repeat("-", 38+17*len(comparisons)))
+
+    subheader.Println("Rewards")
+    row("Base Reward (Gwei)", func(c types.ForkComparison) string { return formatNumber(c.BaseRewardGwei) })
+    row("Attestation Reward / Epoch (Gwei)", func(c types.ForkComparison) string {
+        return formatNumber(c.AttestationRewardPerEpochGwei)
+    })
+    row("Proposer Reward / Block (ETH)", func(c types.ForkComparison) string {
+        return formatGweiAsETH(c.ProposerRewardPerBlockGwei)
+    })
+    row("Attestations / Year (ETH)", func(c types.ForkComparison) string {
+        return formatGweiAsETH(c.AttestationRewardsAnnualGwei)
+    })
+    row("Proposals / Year (ETH)", func(c types.ForkComparison) string {
+        return formatGweiAsETH(c.ProposerRewardsAnnualGwei)
+    })
+    row("Sync Committee / Year (ETH)", func(c types.ForkComparison) string {
+        return formatGweiAsETH(c.SyncCommitteeRewardsAnnualGwei)
+    })
+    row("Total / Year (ETH)", func(c types.ForkComparison) string { return formatGweiAsETH(c.TotalAnnualRewardsGwei) })
+    row("APY", func(c types.ForkComparison) string { return formatPercent(c.APY) })
+
+    subheader.Println("\nPenalties")
+    row("Inactivity Penalty Quotient", func(c types.ForkComparison) string {
+        return formatNumber(c.InactivityPenaltyQuotient)
+    })
+    row(fmt.Sprintf("Leak Loss, %d Epochs Offline (ETH)", inactivityEpochs), func(c types.ForkComparison) string {
+        return formatGweiAsETH(c.InactivityLeakLossGwei)
+    })
+    row(fmt.Sprintf("Slashing, %d Slashed (ETH)", max(1, slashingCount)), func(c types.ForkComparison) string {
+        return formatGweiAsETH(float64(c.SlashingPenaltyGwei))
+    })
+
+    fmt.Println()
+}
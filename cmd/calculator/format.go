@@ -0,0 +1,171 @@
+package main
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "math"
+    "os"
+    "reflect"
+    "strconv"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+)
+
+// Rounding rules shared by text and JSON output:
+//   - ETH amounts and probabilities use --precision decimals
+//   - percentages, ratios, and durations use --precision minus 4 decimals (at least 2)
+//   - Gwei amounts are always whole numbers
+//   - values are rounded half away from zero before formatting
+//   - JSON floats are rounded to --precision decimals, keeping at least
+//     --precision significant digits so small probabilities are not lost
+
+// shortPrecision returns the number of decimals used for percentages, ratios, and durations
+func shortPrecision() int {
+    return max(2, precision-4)
+}
+
+// roundTo rounds a value half away from zero to the given number of decimals
+func roundTo(value float64, decimals int) float64 {
+    if math.IsInf(value, 0) || math.IsNaN(value) {
+        return value
+    }
+    scale := math.Pow(10, float64(decimals))
+    return math.Round(value*scale) / scale
+}
+
+// formatETH formats an ETH amount with the configured precision
+func formatETH(eth float64) string {
+    rounded := roundTo(eth, precision)
+    if rounded == 0 {
+        rounded = 0 // not -0
+    }
+    return strconv.FormatFloat(rounded, 'f', precision, 64)
+}
+
+// formatGweiAsETH formats a Gwei amount as ETH with the configured precision
+func formatGweiAsETH(gwei float64) string {
+    return formatETH(gwei / 1e9)
+}
+
+// formatProbability formats a probability (0.0-1.0) as a percentage with the configured precision
+func formatProbability(probability float64) string {
+    return strconv.FormatFloat(roundTo(probability*100, precision), 'f', precision, 64) + "%"
+}
+
+// formatPercent formats a value that is already a percentage
+func formatPercent(percent float64) string {
+    return formatDecimal(percent) + "%"
+}
+
+// formatSignedPercent formats a percentage change with an explicit sign
+func formatSignedPercent(percent float64) string {
+    formatted := formatPercent(percent)
+    if roundTo(percent, shortPrecision()) >= 0 {
+        formatted = "+" + formatted
+    }
+    return formatted
+}
+
+// formatDecimal formats ratios, multipliers, and durations
+func formatDecimal(value float64) string {
+    return strconv.FormatFloat(roundTo(value, shortPrecision()), 'f', shortPrecision(), 64)
+}
+
+// printJSON writes v as indented JSON with every float rounded to the configured precision
+func printJSON(v interface{}) {
+    output, err := marshalJSON(v)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+        os.Exit(1)
+    }
+    fmt.Println(string(output))
+}
+
+// marshalJSON returns v as indented JSON with every float rounded to the
+// configured precision, and big numbers as strings if --big-numbers asks for
+// it. The rounding applies to the encoding, so v itself is left unchanged.
+func marshalJSON(v interface{}) ([]byte, error) {
+    data, err := json.Marshal(v)
+    if err != nil {
+        return nil, err
+    }
+    decoder := json.NewDecoder(bytes.NewReader(data))
+    decoder.UseNumber()
+    tree, err := decodeOrdered(decoder)
+    if err != nil {
+        return nil, err
+    }
+    tree = roundNumbersIn(tree)
+    if bigNumbers == "string" {
+        tree = quoteBigNumbersIn(tree)
+    }
+    return json.MarshalIndent(tree, "", "  ")
+}
+
+// roundOutput rounds a float for JSON and Parquet output: to --precision
+// decimals, or more for a small value so it keeps --precision significant
+// digits
+func roundOutput(value float64) float64 {
+    decimals := precision
+    if value != 0 && !math.IsInf(value, 0) && !math.IsNaN(value) {
+        if magnitude := int(math.Floor(math.Log10(math.Abs(value)))); magnitude < 0 {
+            decimals = min(max(decimals, precision-1-magnitude), 300)
+        }
+    }
+    return roundTo(value, decimals)
+}
+
+// roundNumbersIn rounds the non-integer numbers of a tree from decodeOrdered
+func roundNumbersIn(value interface{}) interface{} {
+    switch value := value.(type) {
+    case object:
+        for i := range value {
+            value[i].value = roundNumbersIn(value[i].value)
+        }
+    case []interface{}:
+        for i := range value {
+            value[i] = roundNumbersIn(value[i])
+        }
+    case json.Number:
+        if !strings.ContainsAny(value.String(), ".eE") {
+            return value
+        }
+        if n, err := value.Float64(); err == nil {
+            return json.Number(strconv.FormatFloat(roundOutput(n), 'f', -1, 64))
+        }
+    }
+    return value
+}
+
+// roundFloats rounds every settable float reachable from value in place, for
+// rows built only to be written
+func roundFloats(value reflect.Value) {
+    switch value.Kind() {
+    case reflect.Ptr, reflect.Interface:
+        if !value.IsNil() {
+            roundFloats(value.Elem())
+        }
+    case reflect.Struct:
+        for i := 0; i < value.NumField(); i++ {
+            if value.Type().Field(i).IsExported() {
+                roundFloats(value.Field(i))
+            }
+        }
+    case reflect.Slice, reflect.Array:
+        for i := 0; i < value.Len(); i++ {
+            roundFloats(value.Index(i))
+        }
+    case reflect.Float32, reflect.Float64:
+        if value.CanSet() {
+            value.SetFloat(roundOutput(value.Float()))
+        }
+    }
+}
+
+// taxTreatmentNames are the display names for calculator.TaxTreatments
+var taxTreatmentNames = map[string]string{
+    calculator.TaxAtReceipt:  "Income at Receipt",
+    calculator.TaxAtDisposal: "Capital Gains at Disposal (zero basis)",
+}
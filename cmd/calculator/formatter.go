@@ -0,0 +1,448 @@
+package main
+
+import (
+    "bytes"
+    "encoding/csv"
+    "encoding/json"
+    "fmt"
+    "io"
+    "math"
+    "os"
+    "strconv"
+    "strings"
+    "unicode/utf8"
+)
+
+// Formatter writes a command's results in one structured output format. Only
+// the structured formats are Formatters: the default text output is still laid
+// out by each command with its own Printf calls, and may show more or fewer
+// fields than the results value holds. The structured formats are all produced
+// from that value, so every command supports every one of them, apart from
+// grid's own CSV layout and the streaming commands limited by
+// requireStreamFormat.
+type Formatter interface {
+    Format(w io.Writer, v interface{}) error
+}
+
+// formatters are the structured output formats by --format name
+var formatters = map[string]Formatter{
+    "json":     jsonFormatter{},
+    "yaml":     yamlFormatter{},
+    "csv":      csvFormatter{},
+    "markdown": markdownFormatter{},
+    "influx":   influxFormatter{},
+    "table":    tableFormatter{},
+}
+
+// printFormatted writes v in the selected structured format and reports
+// whether it did; for text output the caller prints its own layout
+func printFormatted(v interface{}) bool {
+    formatter, ok := formatters[outputFormat]
+    if !ok {
+        return false
+    }
+    if err := formatter.Format(os.Stdout, v); err != nil {
+        fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+        os.Exit(1)
+    }
+    return true
+}
+
+// requireStreamFormat exits with an error unless the output is text, JSON, or
+// influx, the formats whose records can be written one after another as they
+// arrive
+func requireStreamFormat(name string) {
+    if !textOutput && !jsonOutput && outputFormat != "influx" {
+        fmt.Printf("Error: The %s command streams its output and only supports text, json, or influx format\n", name)
+        os.Exit(1)
+    }
+}
+
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(w io.Writer, v interface{}) error {
+    output, err := marshalJSON(v)
+    if err != nil {
+        return err
+    }
+    _, err = fmt.Fprintln(w, string(output))
+    return err
+}
+
+type yamlFormatter struct{}
+
+func (yamlFormatter) Format(w io.Writer, v interface{}) error {
+    tree, err := orderedTree(v)
+    if err != nil {
+        return err
+    }
+    var buf bytes.Buffer
+    writeYAML(&buf, tree, 0)
+    _, err = w.Write(buf.Bytes())
+    return err
+}
+
+type csvFormatter struct{}
+
+func (csvFormatter) Format(w io.Writer, v interface{}) error {
+    header, rows, err := tabulate(v)
+    if err != nil {
+        return err
+    }
+    writer := csv.NewWriter(w)
+    writer.Write(header)
+    writer.WriteAll(rows)
+    return writer.Error()
+}
+
+type markdownFormatter struct{}
+
+func (markdownFormatter) Format(w io.Writer, v interface{}) error {
+    header, rows, err := tabulate(v)
+    if err != nil {
+        return err
+    }
+    escape := strings.NewReplacer("|", "\\|", "\n", " ")
+    writeRow := func(cells []string) {
+        for i, cell := range cells {
+            cells[i] = escape.Replace(cell)
+        }
+        fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+    }
+
+    writeRow(header)
+    separator := make([]string, len(header))
+    for i := range separator {
+        separator[i] = "---"
+    }
+    fmt.Fprintf(w, "|%s|\n", strings.Join(separator, "|"))
+    for _, row := range rows {
+        writeRow(row)
+    }
+    return nil
+}
+
+// tableFormatter lays results out as aligned plain-text columns: the same rows
+// as CSV, for reading in a terminal rather than a spreadsheet
+type tableFormatter struct{}
+
+func (tableFormatter) Format(w io.Writer, v interface{}) error {
+    header, rows, err := tabulate(v)
+    if err != nil {
+        return err
+    }
+    widths := make([]int, len(header))
+    for _, cells := range append([][]string{header}, rows...) {
+        for i, cell := range cells {
+            widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+        }
+    }
+
+    var buf bytes.Buffer
+    writeRow := func(cells []string) {
+        for i, cell := range cells {
+            if i == len(cells)-1 {
+                buf.WriteString(cell)
+                break
+            }
+            buf.WriteString(cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2))
+        }
+        buf.WriteByte('\n')
+    }
+    writeRow(header)
+    separator := make([]string, len(header))
+    for i := range separator {
+        separator[i] = strings.Repeat("-", widths[i])
+    }
+    writeRow(separator)
+    for _, row := range rows {
+        writeRow(row)
+    }
+    _, err = w.Write(buf.Bytes())
+    return err
+}
+
+// field is one key and value of a JSON object, in document order
+type field struct {
+    key   string
+    value interface{}
+}
+
+// object is a JSON object with its fields in document order
+type object []field
+
+// MarshalJSON writes the fields in order
+func (o object) MarshalJSON() ([]byte, error) {
+    var buf bytes.Buffer
+    buf.WriteByte('{')
+    for i, f := range o {
+        if i > 0 {
+            buf.WriteByte(',')
+        }
+        key, err := json.Marshal(f.key)
+        if err != nil {
+            return nil, err
+        }
+        value, err := json.Marshal(f.value)
+        if err != nil {
+            return nil, err
+        }
+        buf.Write(key)
+        buf.WriteByte(':')
+        buf.Write(value)
+    }
+    buf.WriteByte('}')
+    return buf.Bytes(), nil
+}
+
+// maxSafeInteger is the largest integer a JavaScript number holds exactly
+const maxSafeInteger = 1<<53 - 1
+
+// quoteBigNumbersIn rewrites every number of a tree from decodeOrdered beyond
+// maxSafeInteger in magnitude as a string of its digits, so JavaScript's
+// JSON.parse cannot silently round it
+func quoteBigNumbersIn(value interface{}) interface{} {
+    switch value := value.(type) {
+    case object:
+        for i := range value {
+            value[i].value = quoteBigNumbersIn(value[i].value)
+        }
+    case []interface{}:
+        for i := range value {
+            value[i] = quoteBigNumbersIn(value[i])
+        }
+    case json.Number:
+        n, err := value.Float64()
+        if err != nil || math.Abs(n) > maxSafeInteger {
+            return value.String()
+        }
+    }
+    return value
+}
+
+// orderedTree converts v to its rounded JSON form as nested object objects,
+// []interface{} arrays, and scalars, keeping the order of struct fields
+func orderedTree(v interface{}) (interface{}, error) {
+    data, err := marshalJSON(v)
+    if err != nil {
+        return nil, err
+    }
+    decoder := json.NewDecoder(bytes.NewReader(data))
+    decoder.UseNumber()
+    return decodeOrdered(decoder)
+}
+
+func decodeOrdered(decoder *json.Decoder) (interface{}, error) {
+    token, err := decoder.Token()
+    if err != nil {
+        return nil, err
+    }
+    switch token {
+    case json.Delim('{'):
+        object := object{}
+        for decoder.More() {
+            key, err := decoder.Token()
+            if err != nil {
+                return nil, err
+            }
+            value, err := decodeOrdered(decoder)
+            if err != nil {
+                return nil, err
+            }
+            object = append(object, field{key.(string), value})
+        }
+        _, err = decoder.Token()
+        return object, err
+    case json.Delim('['):
+        array := []interface{}{}
+        for decoder.More() {
+            value, err := decodeOrdered(decoder)
+            if err != nil {
+                return nil, err
+            }
+            array = append(array, value)
+        }
+        _, err = decoder.Token()
+        return array, err
+    }
+    return token, nil
+}
+
+// scalarString formats a JSON scalar for CSV and markdown cells
+func scalarString(value interface{}) string {
+    switch value := value.(type) {
+    case nil:
+        return ""
+    case string:
+        return value
+    default:
+        return fmt.Sprint(value)
+    }
+}
+
+// flatten adds the scalars under value to row with dotted keys, such as
+// "timeline.0.month", recording new keys in order
+func flatten(prefix string, value interface{}, row map[string]string, keys *[]string) {
+    join := func(key string) string {
+        if prefix == "" {
+            return key
+        }
+        return prefix + "." + key
+    }
+    switch value := value.(type) {
+    case object:
+        for _, f := range value {
+            flatten(join(f.key), f.value, row, keys)
+        }
+    case []interface{}:
+        for i, element := range value {
+            flatten(join(strconv.Itoa(i)), element, row, keys)
+        }
+    default:
+        if _, seen := row[prefix]; !seen {
+            *keys = append(*keys, prefix)
+        }
+        row[prefix] = scalarString(value)
+    }
+}
+
+// tabulate lays v out as a table. A list becomes one row per element with a
+// column per (flattened) field; anything else becomes field/value rows.
+func tabulate(v interface{}) ([]string, [][]string, error) {
+    tree, err := orderedTree(v)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    list, ok := tree.([]interface{})
+    if !ok {
+        row := make(map[string]string)
+        var keys []string
+        flatten("", tree, row, &keys)
+        rows := make([][]string, len(keys))
+        for i, key := range keys {
+            rows[i] = []string{key, row[key]}
+        }
+        return []string{"field", "value"}, rows, nil
+    }
+
+    var header []string
+    seen := make(map[string]bool)
+    flat := make([]map[string]string, len(list))
+    for i, element := range list {
+        flat[i] = make(map[string]string)
+        var keys []string
+        flatten("", element, flat[i], &keys)
+        for _, key := range keys {
+            if !seen[key] {
+                seen[key] = true
+                header = append(header, key)
+            }
+        }
+    }
+    if len(header) == 1 && header[0] == "" {
+        header[0] = "value"
+        for _, row := range flat {
+            row["value"] = row[""]
+        }
+    }
+
+    rows := make([][]string, len(flat))
+    for i, row := range flat {
+        rows[i] = make([]string, len(header))
+        for j, key := range header {
+            rows[i][j] = row[key]
+        }
+    }
+    return header, rows, nil
+}
+
+// writeYAML writes a tree from orderedTree as block-style YAML
+func writeYAML(buf *bytes.Buffer, value interface{}, indent int) {
+    pad := strings.Repeat("  ", indent)
+    switch value := value.(type) {
+    case object:
+        if len(value) == 0 {
+            buf.WriteString(pad + "{}\n")
+        }
+        for _, f := range value {
+            buf.WriteString(pad + yamlScalar(f.key) + ":")
+            writeYAMLValue(buf, f.value, indent+1)
+        }
+    case []interface{}:
+        if len(value) == 0 {
+            buf.WriteString(pad + "[]\n")
+        }
+        for _, element := range value {
+            if !nonEmptyCollection(element) {
+                buf.WriteString(pad + "-")
+                writeYAMLValue(buf, element, indent+1)
+                continue
+            }
+            // Start the nested block on the dash line, as in "- key: value"
+            var item bytes.Buffer
+            writeYAML(&item, element, indent+1)
+            buf.WriteString(pad + "- ")
+            buf.Write(item.Bytes()[len(pad)+2:])
+        }
+    default:
+        buf.WriteString(pad + yamlScalar(value) + "\n")
+    }
+}
+
+// nonEmptyCollection reports whether value is an object or array with elements
+func nonEmptyCollection(value interface{}) bool {
+    switch value := value.(type) {
+    case object:
+        return len(value) > 0
+    case []interface{}:
+        return len(value) > 0
+    }
+    return false
+}
+
+// writeYAMLValue writes the value after a key or list dash: scalars and empty
+// collections on the same line, everything else indented below
+func writeYAMLValue(buf *bytes.Buffer, value interface{}, indent int) {
+    switch v := value.(type) {
+    case object:
+        if len(v) == 0 {
+            buf.WriteString(" {}\n")
+            return
+        }
+    case []interface{}:
+        if len(v) == 0 {
+            buf.WriteString(" []\n")
+            return
+        }
+    default:
+        buf.WriteString(" " + yamlScalar(value) + "\n")
+        return
+    }
+    buf.WriteString("\n")
+    writeYAML(buf, value, indent)
+}
+
+// yamlScalar formats a scalar, quoting strings that YAML would otherwise read
+// as another type or that contain special characters
+func yamlScalar(value interface{}) string {
+    switch value := value.(type) {
+    case nil:
+        return "null"
+    case string:
+        if value == "" || strings.ContainsAny(value, ":#{}[],&*!|>'\"%@`\n\t") ||
+            strings.TrimSpace(value) != value || strings.HasPrefix(value, "-") || strings.HasPrefix(value, "?") {
+            return strconv.Quote(value)
+        }
+        switch strings.ToLower(value) {
+        case "true", "false", "yes", "no", "on", "off", "null", "~":
+            return strconv.Quote(value)
+        }
+        if _, err := strconv.ParseFloat(value, 64); err == nil {
+            return strconv.Quote(value)
+        }
+        return value
+    default:
+        return fmt.Sprint(value)
+    }
+}
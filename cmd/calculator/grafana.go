@@ -0,0 +1,356 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// grafanaMetric is a value the Grafana JSON datasource can query, computed at
+// a validator count and participation rate
+type grafanaMetric struct {
+    label string
+    value func(q grafanaInputs) (float64, error)
+}
+
+// grafanaInputs are the scenario one target asks for. The state is a template
+// standing in for the validators, shared by the metrics of a request that use
+// the same validator count.
+type grafanaInputs struct {
+    state         *types.NetworkState
+    validators    int
+    participation float64
+    payload       map[string]interface{}
+}
+
+// Bounds on one query: the size of its body, its targets, and the values of
+// one sweep, so a single request cannot tie up the server
+const (
+    maxGrafanaQueryBytes = 1 << 20
+    maxGrafanaTargets    = 20
+    maxGrafanaSweep      = 50
+)
+
+var grafanaMetrics = map[string]grafanaMetric{
+    "apy": {"Projected APY (%)", func(q grafanaInputs) (float64, error) {
+        return projectTemplateRewardsAt(q.state, q.validators, q.participation).APY, nil
+    }},
+    "risk_adjusted_apy": {"Risk-adjusted APY (%)", func(q grafanaInputs) (float64, error) {
+        return projectTemplateRewardsAt(q.state, q.validators, q.participation).RiskAdjustedAPY, nil
+    }},
+    "daily_rewards_eth": {"Daily rewards per validator (ETH)", func(q grafanaInputs) (float64, error) {
+        return projectTemplateRewardsAt(q.state, q.validators, q.participation).DailyRewardsGwei / 1e9, nil
+    }},
+    "annual_rewards_eth": {"Annual rewards per validator (ETH)", func(q grafanaInputs) (float64, error) {
+        return projectTemplateRewardsAt(q.state, q.validators, q.participation).TotalAnnualRewardsGwei / 1e9, nil
+    }},
+    "issuance_eth": {"Annual network issuance (ETH)", func(q grafanaInputs) (float64, error) {
+        return grafanaIssuance(q).AnnualIssuance, nil
+    }},
+    "inflation": {"Annual inflation from issuance (%)", func(q grafanaInputs) (float64, error) {
+        return grafanaIssuance(q).InflationRate, nil
+    }},
+    "attestation_penalty_daily_eth": {"Daily penalty for missing every attestation (ETH)", func(q grafanaInputs) (float64, error) {
+        penalties, err := calculator.CalculatePenalties(q.state, 0, false, false, false)
+        if err != nil {
+            return 0, err
+        }
+        return penalties.DailyAttestationPenalty, nil
+    }},
+    "slashing_penalty_eth": {"Penalty per validator in a correlated slashing (ETH)", func(q grafanaInputs) (float64, error) {
+        slashed, ok := grafanaNumber(q.payload["slashed"])
+        if !ok || slashed < 1 {
+            slashed = 1
+        }
+        return calculator.EstimateSlashingImpact(q.state, int(slashed)).CorrelatedPenalty.Total, nil
+    }},
+    "leak_loss_eth": {"Offline validator's loss in an inactivity leak (ETH)", func(q grafanaInputs) (float64, error) {
+        epochs, _ := grafanaNumber(q.payload["epochs"])
+        leak := calculator.SimulateLeak(q.validators, q.participation, uint64(epochs))
+        return (float64(leak.Offline.StartBalance) - float64(leak.Offline.EndBalance)) / 1e9, nil
+    }},
+}
+
+// grafanaIssuance compares the policy named in the payload (sqrt, capped, or
+// targeted, shaped by --issuance-cap and --issuance-peak)
+func grafanaIssuance(q grafanaInputs) types.IssuanceResult {
+    var policy calculator.IssuancePolicy = calculator.SqrtIssuance{}
+    switch name, _ := q.payload["policy"].(string); name {
+    case "capped":
+        policy = calculator.CappedIssuance{MaxIssuanceETH: issuanceCap}
+    case "targeted":
+        policy = calculator.TargetedIssuance{PeakStakeETH: issuancePeak}
+    }
+    return calculator.CompareTemplateIssuancePolicies(q.state, q.validators, q.participation, policy)[0]
+}
+
+// grafanaMetricNames lists the metrics in a stable order
+func grafanaMetricNames() []string {
+    names := make([]string, 0, len(grafanaMetrics))
+    for name := range grafanaMetrics {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// grafanaNumber reads a payload value given as a number or, as dashboard
+// variables arrive, a string
+func grafanaNumber(value interface{}) (float64, bool) {
+    switch v := value.(type) {
+    case float64:
+        return v, true
+    case string:
+        parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+        return parsed, err == nil
+    }
+    return 0, false
+}
+
+// serveGrafana answers the Grafana JSON datasource under /grafana: the
+// connection test, /search and /metrics to list the metrics, /query to compute
+// them, and an empty /annotations
+func serveGrafana(w http.ResponseWriter, r *http.Request) {
+    path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/grafana"), "/")
+    if path == "" {
+        if r.Method != http.MethodGet {
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        w.Write([]byte("OK\n"))
+        return
+    }
+    if r.Method != http.MethodPost {
+        http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    var response interface{}
+    switch path {
+    case "/search":
+        response = grafanaMetricNames()
+    case "/metrics":
+        option := func(value string) map[string]string { return map[string]string{"label": value, "value": value} }
+        payloads := []map[string]interface{}{
+            {"name": "validators", "label": "Validators", "type": "input"},
+            {"name": "participation", "label": "Participation (0.0-1.0)", "type": "input"},
+            {"name": "sweep", "label": "Sweep", "type": "select",
+                "options": []map[string]string{option("validators"), option("participation")}},
+            {"name": "values", "label": "Sweep values (comma-separated)", "type": "input"},
+            {"name": "policy", "label": "Issuance policy", "type": "select",
+                "options": []map[string]string{option("sqrt"), option("capped"), option("targeted")}},
+            {"name": "slashed", "label": "Validators slashed together", "type": "input"},
+            {"name": "epochs", "label": "Leak epochs (default a year)", "type": "input"},
+        }
+        var metrics []map[string]interface{}
+        for _, name := range grafanaMetricNames() {
+            metrics = append(metrics, map[string]interface{}{
+                "value": name, "label": grafanaMetrics[name].label, "payloads": payloads,
+            })
+        }
+        response = metrics
+    case "/query":
+        var err error
+        if response, err = grafanaQuery(w, r); err != nil {
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
+    case "/annotations":
+        response = []interface{}{}
+    default:
+        http.NotFound(w, r)
+        return
+    }
+
+    output, err := json.Marshal(response)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.Write(output)
+}
+
+// grafanaQuery computes each target of a query. A target with a sweep returns
+// a table of the metric over the swept values; any other returns the metric
+// as a flat time series across the dashboard's range, since projections do
+// not change with time.
+func grafanaQuery(w http.ResponseWriter, r *http.Request) ([]interface{}, error) {
+    var request struct {
+        Range struct {
+            From time.Time `json:"from"`
+            To   time.Time `json:"to"`
+        } `json:"range"`
+        Targets []struct {
+            Target  string                 `json:"target"`
+            Hide    bool                   `json:"hide"`
+            Payload map[string]interface{} `json:"payload"`
+            Data    map[string]interface{} `json:"data"` // the older SimpleJSON name
+        } `json:"targets"`
+    }
+    body := http.MaxBytesReader(w, r.Body, maxGrafanaQueryBytes)
+    if err := json.NewDecoder(body).Decode(&request); err != nil {
+        return nil, fmt.Errorf("invalid query: %v", err)
+    }
+    if len(request.Targets) > maxGrafanaTargets {
+        return nil, fmt.Errorf("a query may have at most %d targets", maxGrafanaTargets)
+    }
+    to := request.Range.To
+    if to.IsZero() {
+        to = time.Now()
+    }
+    from := request.Range.From
+    if from.IsZero() || from.After(to) {
+        from = to
+    }
+
+    states := make(map[int]*types.NetworkState)
+    stateFor := func(count int) (*types.NetworkState, error) {
+        if states[count] == nil {
+            state, err := createTemplateState(count)
+            if err != nil {
+                return nil, err
+            }
+            states[count] = state
+        }
+        return states[count], nil
+    }
+
+    response := []interface{}{}
+    for _, target := range request.Targets {
+        if target.Hide || target.Target == "" {
+            continue
+        }
+        metric, ok := grafanaMetrics[target.Target]
+        if !ok {
+            return nil, fmt.Errorf("unknown metric %q; see /grafana/search", target.Target)
+        }
+        payload := target.Payload
+        if payload == nil {
+            payload = target.Data
+        }
+
+        count, rate, err := grafanaScenario(payload)
+        if err != nil {
+            return nil, err
+        }
+
+        sweep, _ := payload["sweep"].(string)
+        if sweep == "" {
+            state, err := stateFor(count)
+            if err != nil {
+                return nil, err
+            }
+            value, err := metric.value(grafanaInputs{state: state, validators: count, participation: rate, payload: payload})
+            if err != nil {
+                return nil, err
+            }
+            response = append(response, map[string]interface{}{
+                "target": target.Target,
+                "datapoints": [][2]float64{
+                    {value, float64(from.UnixMilli())},
+                    {value, float64(to.UnixMilli())},
+                },
+            })
+            continue
+        }
+
+        values, err := grafanaSweepValues(payload["values"])
+        if err != nil {
+            return nil, err
+        }
+        var rows [][2]float64
+        for _, v := range values {
+            inputs := grafanaInputs{validators: count, participation: rate, payload: payload}
+            switch sweep {
+            case "validators":
+                inputs.validators = int(v)
+            case "participation":
+                if v <= 0 || v > 1 {
+                    return nil, fmt.Errorf("participation must be between 0.0 and 1.0")
+                }
+                inputs.participation = v
+            default:
+                return nil, fmt.Errorf("sweep must be validators or participation")
+            }
+            if inputs.state, err = stateFor(inputs.validators); err != nil {
+                return nil, err
+            }
+            value, err := metric.value(inputs)
+            if err != nil {
+                return nil, err
+            }
+            rows = append(rows, [2]float64{v, value})
+        }
+        response = append(response, map[string]interface{}{
+            "type": "table",
+            "columns": []map[string]string{
+                {"text": sweep, "type": "number"},
+                {"text": target.Target, "type": "number"},
+            },
+            "rows": rows,
+        })
+    }
+    return response, nil
+}
+
+// grafanaScenario reads the validator count and participation of a target,
+// taking what it leaves out from serveSource
+func grafanaScenario(payload map[string]interface{}) (int, float64, error) {
+    count, countGiven := grafanaNumber(payload["validators"])
+    rate, rateGiven := grafanaNumber(payload["participation"])
+    if !countGiven || !rateGiven {
+        snapshot, err := serveSource.Snapshot()
+        if err != nil {
+            return 0, 0, fmt.Errorf("fetching network conditions: %v", err)
+        }
+        if !countGiven {
+            count = float64(snapshot.ActiveValidators)
+        }
+        if !rateGiven {
+            rate = snapshot.Participation
+        }
+    }
+
+    if err := checkServeCount(int(count)); err != nil {
+        return 0, 0, err
+    }
+    if rate <= 0 || rate > 1 {
+        return 0, 0, fmt.Errorf("participation must be between 0.0 and 1.0")
+    }
+    return int(count), rate, nil
+}
+
+// grafanaSweepValues reads the values of a sweep, given as a list or a
+// comma-separated string
+func grafanaSweepValues(value interface{}) ([]float64, error) {
+    var items []interface{}
+    switch v := value.(type) {
+    case []interface{}:
+        items = v
+    case string:
+        for _, item := range strings.Split(v, ",") {
+            items = append(items, item)
+        }
+    }
+
+    var values []float64
+    for _, item := range items {
+        parsed, ok := grafanaNumber(item)
+        if !ok {
+            return nil, fmt.Errorf("invalid sweep value %v", item)
+        }
+        values = append(values, parsed)
+    }
+    if len(values) == 0 || len(values) > maxGrafanaSweep {
+        return nil, fmt.Errorf("a sweep needs 1 to %d values", maxGrafanaSweep)
+    }
+    return values, nil
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+    "encoding/csv"
+    "fmt"
+    "math"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+)
+
+// gridParameter is a command-line input that the grid command can vary
+type gridParameter struct {
+    set   func(value float64)
+    valid func(value float64) bool
+}
+
+// gridParameters are the inputs grid can vary, by name. Each one sets the same
+// global as its command-line flag.
+var gridParameters = map[string]gridParameter{
+    "validators": {
+        set:   func(value float64) { validatorCount = int(value) },
+        valid: func(value float64) bool { return value >= 1 && value == float64(int(value)) },
+    },
+    "participation": {
+        set:   func(value float64) { participation = value },
+        valid: func(value float64) bool { return value > 0 && value <= 1 },
+    },
+    "miss-rate": {
+        set:   func(value float64) { missRate = value },
+        valid: func(value float64) bool { return value >= 0 && value <= 1 },
+    },
+    "slashing-prob": {
+        set:   func(value float64) { slashingProb = value },
+        valid: func(value float64) bool { return value >= 0 && value <= 1 },
+    },
+    "priority-fees": {
+        set:   func(value float64) { priorityFees = value },
+        valid: func(value float64) bool { return value >= 0 },
+    },
+    "mev": {
+        set:   func(value float64) { mevReward = value },
+        valid: func(value float64) bool { return value >= 0 },
+    },
+}
+
+// parseGridAxis parses a name=v1,v2,... grid axis
+func parseGridAxis(axis string) (string, []float64) {
+    name, list, found := strings.Cut(axis, "=")
+    name = strings.TrimSpace(name)
+    parameter, known := gridParameters[name]
+    if !found || !known {
+        names := make([]string, 0, len(gridParameters))
+        for known := range gridParameters {
+            names = append(names, known)
+        }
+        sort.Strings(names)
+        fmt.Printf("Error: Invalid grid axis '%s' (use name=v1,v2,... with name one of %s)\n",
+            axis, strings.Join(names, ", "))
+        os.Exit(1)
+    }
+
+    var values []float64
+    for _, valueStr := range strings.Split(list, ",") {
+        value, err := strconv.ParseFloat(strings.TrimSpace(valueStr), 64)
+        if err != nil || !parameter.valid(value) {
+            fmt.Printf("Error: Invalid %s value '%s'\n", name, valueStr)
+            os.Exit(1)
+        }
+        values = append(values, value)
+    }
+    return name, values
+}
+
+func handleGrid() {
+    rowName, rows := parseGridAxis(gridRows)
+    columnName, columns := parseGridAxis(gridColumns)
+    if rowName == columnName {
+        fmt.Println("Error: Grid rows and columns must vary different parameters")
+        os.Exit(1)
+    }
+    if rowName != "validators" && columnName != "validators" {
+        requireValidators("grid")
+    }
+
+    grid := &types.SensitivityGrid{
+        RowParameter:    rowName,
+        ColumnParameter: columnName,
+        Rows:            rows,
+        Columns:         columns,
+    }
+    bar := newProgress("Computing grid", len(rows)*len(columns))
+    for _, row := range rows {
+        gridParameters[rowName].set(row)
+        apys := make([]float64, len(columns))
+        for j, column := range columns {
+            gridParameters[columnName].set(column)
+            apys[j] = projectRewards(createNetworkState(validatorCount)).RiskAdjustedAPY
+            bar.step(1)
+        }
+        grid.APY = append(grid.APY, apys)
+    }
+    bar.finish()
+    recordRun(grid)
+
+    if csvOutput {
+        printGridCSV(grid)
+        return
+    }
+
+    if printFormatted(grid) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    header.Println("\n=== APY Sensitivity Grid ===")
+    fmt.Printf("\nRisk-adjusted APY by %s (rows) and %s (columns), shaded from lowest (red) to highest (green)\n\n",
+        rowName, columnName)
+
+    fmt.Printf("%-16s", rowName)
+    for _, column := range columns {
+        fmt.Printf(" %10s", formatGridValue(column))
+    }
+    fmt.Println()
+    fmt.Println(strings.Repeat("-", 16+11*len(columns)))
+    low, high := gridRange(grid)
+    for i, row := range rows {
+        fmt.Printf("%-16s", formatGridValue(row))
+        for _, apy := range grid.APY[i] {
+            fmt.Print(" ")
+            heatmapColor(apy, low, high).Printf("%10s", formatPercent(apy))
+        }
+        fmt.Println()
+    }
+
+    // Legend from lowest to highest APY
+    fmt.Print("\nScale: ")
+    for i := range heatmapColors {
+        value := low + (high-low)*(float64(i)+0.5)/float64(len(heatmapColors))
+        heatmapColor(value, low, high).Printf(" %s ", formatPercent(value))
+    }
+    fmt.Println()
+
+    fmt.Println()
+}
+
+// heatmapColors shade grid cells from the lowest APY to the highest
+var heatmapColors = []*color.Color{
+    color.New(color.BgRed, color.FgWhite),
+    color.New(color.BgHiRed, color.FgBlack),
+    color.New(color.BgYellow, color.FgBlack),
+    color.New(color.BgHiGreen, color.FgBlack),
+    color.New(color.BgGreen, color.FgBlack),
+}
+
+// heatmapColor picks the shade for value on a scale from low to high
+func heatmapColor(value, low, high float64) *color.Color {
+    if high <= low {
+        return heatmapColors[len(heatmapColors)/2]
+    }
+    bucket := int((value - low) / (high - low) * float64(len(heatmapColors)))
+    return heatmapColors[min(max(bucket, 0), len(heatmapColors)-1)]
+}
+
+// gridRange returns the lowest and highest APY in the grid
+func gridRange(grid *types.SensitivityGrid) (float64, float64) {
+    low, high := grid.APY[0][0], grid.APY[0][0]
+    for _, apys := range grid.APY {
+        for _, apy := range apys {
+            low = math.Min(low, apy)
+            high = math.Max(high, apy)
+        }
+    }
+    return low, high
+}
+
+// printGridCSV writes the grid as CSV with one row per grid row and the column
+// values as the header
+func printGridCSV(grid *types.SensitivityGrid) {
+    out := csv.NewWriter(os.Stdout)
+    record := []string{grid.RowParameter + `\` + grid.ColumnParameter}
+    for _, column := range grid.Columns {
+        record = append(record, formatGridValue(column))
+    }
+    out.Write(record)
+
+    for i, row := range grid.Rows {
+        record = []string{formatGridValue(row)}
+        for _, apy := range grid.APY[i] {
+            record = append(record, strconv.FormatFloat(roundTo(apy, precision), 'f', -1, 64))
+        }
+        out.Write(record)
+    }
+
+    out.Flush()
+    if err := out.Error(); err != nil {
+        fmt.Fprintf(os.Stderr, "Error writing CSV: %v\n", err)
+        os.Exit(1)
+    }
+}
+
+// formatGridValue formats a parameter value as given on the command line
+func formatGridValue(value float64) string {
+    return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/store"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+// change is one input or result that differs between two runs
+type change struct {
+    Name string      `json:"name"`
+    Old  interface{} `json:"old"`
+    New  interface{} `json:"new"`
+}
+
+// runComparison lists what changed between two recorded runs
+type runComparison struct {
+    OldRun         int64    `json:"old_run"`
+    NewRun         int64    `json:"new_run"`
+    InputChanges   []change `json:"input_changes"`
+    ResultChanges  []change `json:"result_changes"`
+    UnchangedCount int      `json:"unchanged_result_count"`
+}
+
+func handleHistory() {
+    if runStore == nil {
+        fmt.Println("Error: The history command requires a run database (--store)")
+        os.Exit(1)
+    }
+
+    switch flag.NArg() {
+    case 1:
+        listRuns()
+    case 3:
+        compareRuns(parseRunID(flag.Arg(1)), parseRunID(flag.Arg(2)))
+    default:
+        fmt.Println("Error: Use 'history' to list runs or 'history <id> <id>' to compare two runs")
+        os.Exit(1)
+    }
+}
+
+func parseRunID(arg string) int64 {
+    id, err := strconv.ParseInt(arg, 10, 64)
+    if err != nil || id <= 0 {
+        fmt.Printf("Error: Invalid run ID '%s'\n", arg)
+        os.Exit(1)
+    }
+    return id
+}
+
+func listRuns() {
+    runs, err := runStore.Runs()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error reading run history: %v\n", err)
+        os.Exit(1)
+    }
+
+    if printFormatted(runs) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    header.Println("\n=== Run History ===")
+    fmt.Println()
+
+    if len(runs) == 0 {
+        fmt.Printf("No runs recorded in %s\n\n", storePath)
+        return
+    }
+
+    fmt.Printf("%-6s %-22s %-24s %s\n", "ID", "Time (UTC)", "Command", "Arguments")
+    fmt.Println(strings.Repeat("-", 100))
+    for _, run := range runs {
+        fmt.Printf("%-6d %-22s %-24s %s\n",
+            run.ID,
+            run.CreatedAt.Format("2006-01-02 15:04:05"),
+            run.Command,
+            strings.Join(run.Args, " "))
+    }
+    fmt.Println()
+}
+
+func compareRuns(oldID, newID int64) {
+    oldRun, err := runStore.Run(oldID)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error reading run history: %v\n", err)
+        os.Exit(1)
+    }
+    newRun, err := runStore.Run(newID)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error reading run history: %v\n", err)
+        os.Exit(1)
+    }
+
+    outputRunComparison(oldRun, newRun)
+}
+
+func outputRunComparison(oldRun, newRun *store.Run) {
+    comparison := runComparison{OldRun: oldRun.ID, NewRun: newRun.ID}
+
+    // Inputs are flag values; --store itself is never recorded
+    for _, name := range unionKeys(oldRun.Inputs, newRun.Inputs) {
+        oldValue, newValue := oldRun.Inputs[name], newRun.Inputs[name]
+        if oldValue != newValue {
+            comparison.InputChanges = append(comparison.InputChanges, change{name, oldValue, newValue})
+        }
+    }
+
+    oldResults, newResults := flattenResults(oldRun.Results), flattenResults(newRun.Results)
+    for _, name := range unionKeys(oldResults, newResults) {
+        oldValue, oldOK := oldResults[name]
+        newValue, newOK := newResults[name]
+        if oldOK && newOK && fmt.Sprint(oldValue) == fmt.Sprint(newValue) {
+            comparison.UnchangedCount++
+            continue
+        }
+        comparison.ResultChanges = append(comparison.ResultChanges, change{name, oldValue, newValue})
+    }
+
+    if printFormatted(comparison) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+    changed := color.New(color.FgYellow)
+
+    header.Printf("\n=== Run %d vs Run %d ===\n", oldRun.ID, newRun.ID)
+
+    fmt.Printf("\nRun %d: %s  %s %s\n", oldRun.ID, oldRun.CreatedAt.Format("2006-01-02 15:04:05"),
+        oldRun.Command, strings.Join(oldRun.Args, " "))
+    fmt.Printf("Run %d: %s  %s %s\n", newRun.ID, newRun.CreatedAt.Format("2006-01-02 15:04:05"),
+        newRun.Command, strings.Join(newRun.Args, " "))
+    if oldRun.Command != newRun.Command {
+        color.New(color.FgRed, color.Bold).Println("Warning: The runs used different commands, so their results are not directly comparable")
+    }
+
+    subheader.Println("\nChanged Inputs:")
+    if len(comparison.InputChanges) == 0 {
+        fmt.Println("- None")
+    }
+    for _, c := range comparison.InputChanges {
+        changed.Printf("- %s: %q -> %q\n", c.Name, c.Old, c.New)
+    }
+
+    subheader.Println("\nChanged Results:")
+    if len(comparison.ResultChanges) == 0 {
+        fmt.Println("- None")
+    }
+    for _, c := range comparison.ResultChanges {
+        changed.Printf("- %s: %s -> %s%s\n", c.Name, formatHistoryValue(c.Old), formatHistoryValue(c.New),
+            formatHistoryDelta(c.Old, c.New))
+    }
+    fmt.Printf("\n%d results unchanged\n\n", comparison.UnchangedCount)
+}
+
+// flattenResults maps every leaf of a JSON document to a dotted path such as
+// "setups[1].net_rewards_eth"
+func flattenResults(raw json.RawMessage) map[string]interface{} {
+    var doc interface{}
+    if err := json.Unmarshal(raw, &doc); err != nil {
+        return map[string]interface{}{"results": string(raw)}
+    }
+
+    flat := make(map[string]interface{})
+    var walk func(path string, value interface{})
+    walk = func(path string, value interface{}) {
+        switch v := value.(type) {
+        case map[string]interface{}:
+            for key, child := range v {
+                if path == "" {
+                    walk(key, child)
+                } else {
+                    walk(path+"."+key, child)
+                }
+            }
+        case []interface{}:
+            for i, child := range v {
+                walk(fmt.Sprintf("%s[%d]", path, i), child)
+            }
+        default:
+            flat[path] = v
+        }
+    }
+    walk("", doc)
+    return flat
+}
+
+// unionKeys returns the keys of both maps in sorted order
+func unionKeys[V any](a, b map[string]V) []string {
+    seen := make(map[string]bool)
+    for key := range a {
+        seen[key] = true
+    }
+    for key := range b {
+        seen[key] = true
+    }
+
+    keys := make([]string, 0, len(seen))
+    for key := range seen {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    return keys
+}
+
+func formatHistoryValue(value interface{}) string {
+    switch v := value.(type) {
+    case nil:
+        return "(none)"
+    case float64:
+        return strconv.FormatFloat(roundTo(v, precision), 'f', -1, 64)
+    default:
+        return fmt.Sprint(v)
+    }
+}
+
+// formatHistoryDelta shows the difference between two numeric results
+func formatHistoryDelta(oldValue, newValue interface{}) string {
+    oldNumber, oldOK := oldValue.(float64)
+    newNumber, newOK := newValue.(float64)
+    if !oldOK || !newOK {
+        return ""
+    }
+
+    delta := strconv.FormatFloat(roundTo(newNumber-oldNumber, precision), 'f', -1, 64)
+    if newNumber >= oldNumber {
+        delta = "+" + delta
+    }
+    return " (" + delta + ")"
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// influxMeasurement names every line of --format influx; the command and the
+// scenario are tags
+const influxMeasurement = "eth_rewards"
+
+// influxFormatter writes InfluxDB line protocol, one line per result (or per
+// element of a list), with the flattened JSON fields as fields. Numbers are
+// always written as floats, so a field never changes type between runs.
+type influxFormatter struct{}
+
+func (influxFormatter) Format(w io.Writer, v interface{}) error {
+    tree, err := orderedTree(v)
+    if err != nil {
+        return err
+    }
+    now := time.Now()
+
+    list, ok := tree.([]interface{})
+    if !ok {
+        return writeInfluxLine(w, influxTags(), tree, now)
+    }
+    for i, element := range list {
+        // Rows of one run share a timestamp, so a tag tells them apart
+        tags := influxTags()
+        tags["row"] = strconv.Itoa(i)
+        if err := writeInfluxLine(w, tags, element, now); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// influxTags are the tags of the run: the command, the chain's network, and
+// the --scenario name
+func influxTags() map[string]string {
+    return map[string]string{"command": runCommandName(), "network": networkName(), "scenario": scenario}
+}
+
+// writeInfluxLine writes value's scalars as the fields of one line, skipping
+// a value with none
+func writeInfluxLine(w io.Writer, tags map[string]string, value interface{}, at time.Time) error {
+    row := make(map[string]string)
+    var keys []string
+    flattenInflux("", value, row, &keys)
+    if len(keys) == 0 {
+        return nil
+    }
+
+    var line strings.Builder
+    line.WriteString(influxEscape(influxMeasurement, ", "))
+    names := make([]string, 0, len(tags))
+    for name := range tags {
+        names = append(names, name)
+    }
+    sort.Strings(names) // the order InfluxDB stores them in
+    for _, name := range names {
+        if tags[name] != "" {
+            fmt.Fprintf(&line, ",%s=%s", influxEscape(name, ",= "), influxEscape(tags[name], ",= "))
+        }
+    }
+    for i, key := range keys {
+        separator := ","
+        if i == 0 {
+            separator = " "
+        }
+        fmt.Fprintf(&line, "%s%s=%s", separator, influxEscape(key, ",= "), row[key])
+    }
+    fmt.Fprintf(&line, " %d\n", at.UnixNano())
+
+    _, err := io.WriteString(w, line.String())
+    return err
+}
+
+// flattenInflux is flatten with the values encoded as line protocol fields.
+// Nulls have no field value and are left out.
+func flattenInflux(prefix string, value interface{}, row map[string]string, keys *[]string) {
+    join := func(key string) string {
+        if prefix == "" {
+            return key
+        }
+        return prefix + "." + key
+    }
+    var encoded string
+    switch value := value.(type) {
+    case object:
+        for _, f := range value {
+            flattenInflux(join(f.key), f.value, row, keys)
+        }
+        return
+    case []interface{}:
+        for i, element := range value {
+            flattenInflux(join(strconv.Itoa(i)), element, row, keys)
+        }
+        return
+    case nil:
+        return
+    case json.Number:
+        encoded = value.String()
+    case bool:
+        encoded = strconv.FormatBool(value)
+    case string:
+        encoded = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+    default:
+        encoded = fmt.Sprint(value)
+    }
+    if prefix == "" {
+        prefix = "value"
+    }
+    if _, seen := row[prefix]; !seen {
+        *keys = append(*keys, prefix)
+    }
+    row[prefix] = encoded
+}
+
+// influxEscape backslash-escapes the characters of special that line protocol
+// reserves in a measurement, tag, or field key
+func influxEscape(s, special string) string {
+    var escaped strings.Builder
+    for _, c := range s {
+        if c == '\\' || strings.ContainsRune(special, c) {
+            escaped.WriteByte('\\')
+        }
+        escaped.WriteRune(c)
+    }
+    return escaped.String()
+}
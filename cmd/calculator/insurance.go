@@ -0,0 +1,60 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+
+    "github.com/fatih/color"
+)
+
+func handleInsurance() {
+    requireValidators("insurance")
+
+    if premiumBps < 0 {
+        fmt.Println("Error: Premium must not be negative")
+        os.Exit(1)
+    }
+
+    // Without an explicit probability, fall back to the historical base rate
+    probability := slashingProb
+    if probability == 0 {
+        probability = calculator.HistoricalSlashingRate()
+    }
+
+    state := createNetworkState(validatorCount)
+    result := calculator.EvaluateSlashingInsurance(state, premiumBps, probability, slashingCount)
+    recordRun(result)
+
+    if printFormatted(result) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+
+    header.Println("\n=== Slashing Insurance Break-Even ===")
+
+    subheader.Println("\nPremium:")
+    fmt.Printf("- Premium: %s bps of stake per year\n", formatDecimal(result.PremiumBps))
+    fmt.Printf("- Annual Cost: %s ETH\n", formatETH(result.AnnualPremium))
+
+    subheader.Println("\nModeled Losses:")
+    fmt.Printf("- Annual Slashing Probability: %s\n", formatProbability(result.SlashingProbability))
+    fmt.Printf("- Single Slashing Penalty: %s ETH\n", formatETH(result.SinglePenalty))
+    fmt.Printf("- Correlated Slashing Penalty (%d validators): %s ETH\n",
+        result.CorrelatedCount, formatETH(result.CorrelatedPenalty))
+    fmt.Printf("- Expected Annual Loss: %s ETH\n", formatETH(result.ExpectedLoss))
+    fmt.Printf("- Break-Even Probability: %s\n", formatProbability(result.BreakEvenProbability))
+
+    subheader.Println("\nVerdict:")
+    if result.CoverageRational {
+        color.New(color.FgGreen, color.Bold).Println("- Coverage is economically rational: the premium is below the expected loss")
+    } else {
+        color.New(color.FgRed, color.Bold).Printf("- Coverage costs %sx the expected loss; only worthwhile as protection against a %s ETH tail loss\n",
+            formatDecimal(result.AnnualPremium/result.ExpectedLoss), formatETH(result.CorrelatedPenalty))
+    }
+
+    fmt.Println()
+}
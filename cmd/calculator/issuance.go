@@ -0,0 +1,69 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+)
+
+func handleIssuance() {
+    counts := validatorCounts("issuance")
+
+    policies := []calculator.IssuancePolicy{
+        calculator.SqrtIssuance{},
+        calculator.CappedIssuance{MaxIssuanceETH: issuanceCap},
+        calculator.TargetedIssuance{PeakStakeETH: issuancePeak},
+    }
+
+    var results []types.IssuanceResult
+    for _, count := range counts {
+        state := createNetworkState(count)
+        results = append(results, calculator.CompareIssuancePolicies(state, participation, policies...)...)
+    }
+
+    recordRun(results)
+
+    if printFormatted(results) {
+        return
+    }
+
+    if parquetOutput {
+        rows := make([]issuanceRow, len(results))
+        for i, result := range results {
+            rows[i] = newIssuanceRow(result)
+        }
+        printParquet(rows)
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    header.Println("\n=== Issuance Curve Comparison ===")
+
+    fmt.Printf("\nParticipation Rate: %s\n", formatPercent(participation*100))
+    fmt.Printf("Capped Issuance: %s ETH/year\n", formatNumber(uint64(issuanceCap)))
+    fmt.Printf("Targeted Peak Stake: %s ETH\n\n", formatNumber(uint64(issuancePeak)))
+
+    // Table header
+    fmt.Printf("%-15s %-20s %-10s %-10s %-15s %-10s %-20s %-12s\n",
+        "Validators", "Total Staked (ETH)", "Policy", "APY %",
+        "Effective APY %", "vs Sqrt", "Issuance (ETH/yr)", "Inflation %")
+    fmt.Println(strings.Repeat("-", 120))
+
+    for _, result := range results {
+        fmt.Printf("%-15s %-20s %-10s %-10s %-15s %-10s %-20s %-12s\n",
+            formatNumber(uint64(result.ValidatorCount)),
+            formatNumber(result.TotalStaked),
+            result.Policy,
+            formatPercent(result.BaseAPY),
+            formatPercent(result.EffectiveAPY),
+            formatSignedPercent(result.APYChange),
+            formatNumber(uint64(result.AnnualIssuance)),
+            formatPercent(result.InflationRate))
+    }
+
+    fmt.Println()
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+)
+
+// leakTableRows is roughly how many trajectory rows the text output shows
+const leakTableRows = 30
+
+// leakCohortNames are the display names of the cohorts
+var leakCohortNames = map[string]string{
+    "online":  "Online",
+    "offline": "Offline",
+}
+
+func handleLeak() {
+    requireValidators("leak")
+    if participation*3 >= 2 {
+        fmt.Println("Error: Participation must be below 2/3 (-p) for the chain to stop finalizing")
+        os.Exit(1)
+    }
+    if inactivityEpochs < 0 {
+        fmt.Println("Error: Inactivity epochs must not be negative")
+        os.Exit(1)
+    }
+
+    analysis := calculator.SimulateLeak(validatorCount, participation, uint64(inactivityEpochs))
+    recordRun(analysis)
+
+    if printFormatted(analysis) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+    highlight := color.New(color.FgGreen, color.Bold)
+    warning := color.New(color.FgRed, color.Bold)
+
+    header.Println("\n=== Inactivity Leak Cohorts ===")
+
+    fmt.Printf("\nNetwork Validators: %s (%s online, %s offline)\n", formatNumber(uint64(analysis.Validators)),
+        formatNumber(uint64(analysis.Online.Validators)), formatNumber(uint64(analysis.Offline.Validators)))
+    fmt.Printf("Participation Rate: %s\n", formatPercent(analysis.Participation*100))
+
+    subheader.Println("\nBalance per Validator:")
+    fmt.Printf("%-10s %-10s %-14s %-14s %-16s %-14s\n",
+        "Day", "Epoch", "Online ETH", "Offline ETH", "Offline Score", "Online Stake")
+    fmt.Println(strings.Repeat("-", 82))
+    step := (len(analysis.Trajectory) + leakTableRows - 1) / leakTableRows
+    for i, point := range analysis.Trajectory {
+        if i%step != 0 && i != len(analysis.Trajectory)-1 {
+            continue
+        }
+        fmt.Printf("%-10s %-10d %-14s %-14s %-16s %-14s\n",
+            formatDecimal(point.Days), point.Epoch,
+            formatGweiAsETH(float64(point.OnlineBalance)), formatGweiAsETH(float64(point.OfflineBalance)),
+            formatNumber(point.OfflineScore), formatPercent(point.OnlineShare))
+    }
+
+    subheader.Println("\nCohorts:")
+    for _, cohort := range []types.LeakCohort{analysis.Online, analysis.Offline} {
+        fmt.Printf("- %s (%s validators): %s -> %s ETH each, %s ETH in total\n",
+            leakCohortNames[cohort.Name], formatNumber(uint64(cohort.Validators)),
+            formatGweiAsETH(float64(cohort.StartBalance)), formatGweiAsETH(float64(cohort.EndBalance)),
+            formatGweiAsETH(cohort.TotalChange))
+        if cohort.EjectedEpoch > 0 {
+            fmt.Printf("  Ejected after %s days (epoch %d)\n", epochDays(cohort.EjectedEpoch), cohort.EjectedEpoch)
+        }
+    }
+
+    subheader.Println("\nRedistribution:")
+    fmt.Printf("- Stake Burned: %s ETH\n", formatGweiAsETH(analysis.Burned))
+    fmt.Printf("- Online Share of Stake: %s -> %s\n",
+        formatPercent(analysis.StartOnlineShare), formatPercent(analysis.EndOnlineShare))
+    if analysis.FinalityRestored {
+        highlight.Printf("- Finality resumes after %s days (epoch %d)\n\n", epochDays(analysis.Epochs), analysis.Epochs)
+    } else {
+        warning.Printf("- Finality is not restored within %s days (epoch %d)\n\n", epochDays(analysis.Epochs), analysis.Epochs)
+    }
+}
+
+// epochDays formats a number of epochs as days
+func epochDays(epochs uint64) string {
+    return formatDecimal(float64(epochs) / float64(config.EPOCHS_PER_DAY))
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+    "math"
+    "net"
+    "net/http"
+    "strconv"
+    "sync"
+    "time"
+)
+
+// responseCache holds encoded responses by request parameters. Once full, the
+// oldest entry is evicted first.
+type responseCache struct {
+    mu      sync.Mutex
+    size    int
+    entries map[string][]byte
+    order   []string // keys, oldest first
+}
+
+func newResponseCache(size int) *responseCache {
+    return &responseCache{size: size, entries: make(map[string][]byte)}
+}
+
+func (c *responseCache) get(key string) ([]byte, bool) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    body, ok := c.entries[key]
+    return body, ok
+}
+
+func (c *responseCache) put(key string, body []byte) {
+    if c.size <= 0 {
+        return
+    }
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if _, ok := c.entries[key]; ok {
+        return
+    }
+    if len(c.order) >= c.size {
+        delete(c.entries, c.order[0])
+        c.order = c.order[1:]
+    }
+    c.entries[key] = body
+    c.order = append(c.order, key)
+}
+
+// rateLimiter is a token bucket per client address: each client may make burst
+// requests at once, refilled at rate requests per second
+type rateLimiter struct {
+    mu      sync.Mutex
+    rate    float64
+    burst   float64
+    clients map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+    tokens float64
+    last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+    return &rateLimiter{rate: rate, burst: float64(burst), clients: make(map[string]*tokenBucket)}
+}
+
+// allow takes a token for client, or returns how long until one is available
+func (l *rateLimiter) allow(client string, now time.Time) (bool, time.Duration) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    bucket, ok := l.clients[client]
+    if !ok {
+        bucket = &tokenBucket{tokens: l.burst, last: now}
+        l.clients[client] = bucket
+    }
+    bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+    bucket.last = now
+
+    if bucket.tokens < 1 {
+        return false, time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+    }
+    bucket.tokens--
+
+    // Forget clients whose buckets have refilled, so the map stays small
+    if len(l.clients) > 10000 {
+        for key, other := range l.clients {
+            if other.tokens+now.Sub(other.last).Seconds()*l.rate >= l.burst {
+                delete(l.clients, key)
+            }
+        }
+    }
+    return true, 0
+}
+
+// limit wraps next so that clients over the rate limit get 429 Too Many Requests
+func (l *rateLimiter) limit(next http.HandlerFunc) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        client, _, err := net.SplitHostPort(r.RemoteAddr)
+        if err != nil {
+            client = r.RemoteAddr
+        }
+        if ok, wait := l.allow(client, time.Now()); !ok {
+            w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+            http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+            return
+        }
+        next(w, r)
+    }
+}
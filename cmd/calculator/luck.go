@@ -0,0 +1,60 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+func handleLuck() {
+    if !flag.CommandLine.Changed("proposals") || proposals < 0 {
+        fmt.Println("Error: The luck command requires the number of blocks proposed (--proposals)")
+        os.Exit(1)
+    }
+    if !flag.CommandLine.Changed("to-epoch") {
+        fmt.Println("Error: The luck command requires an epoch range (--from-epoch, --to-epoch)")
+        os.Exit(1)
+    }
+    if toEpoch < fromEpoch {
+        fmt.Printf("Error: End epoch %d is before start epoch %d\n", toEpoch, fromEpoch)
+        os.Exit(1)
+    }
+
+    counts := validatorCounts("luck")
+    result := calculator.ProposerLuck(proposals, toEpoch-fromEpoch+1, counts)
+    recordRun(result)
+
+    if printFormatted(result) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+    highlight := color.New(color.FgGreen, color.Bold)
+
+    header.Println("\n=== Proposer Luck ===")
+
+    subheader.Println("\nPeriod:")
+    fmt.Printf("- Epochs: %d - %d (%d epochs, %s days)\n",
+        fromEpoch, toEpoch, result.Epochs, formatDecimal(result.Days))
+    if len(counts) == 1 {
+        fmt.Printf("- Network Validators: %s\n", formatNumber(uint64(counts[0])))
+    } else {
+        fmt.Printf("- Network Validators: %s to %s over %d samples\n",
+            formatNumber(uint64(counts[0])), formatNumber(uint64(counts[len(counts)-1])), len(counts))
+    }
+
+    subheader.Println("\nProposals:")
+    fmt.Printf("- Actual: %d\n", result.Proposals)
+    fmt.Printf("- Expected: %s\n", formatDecimal(result.ExpectedProposals))
+    highlight.Printf("- Luck: %s\n", formatPercent(result.Luck))
+    fmt.Printf("- Luck Percentile: %s\n", formatPercent(result.Percentile))
+    fmt.Printf("- Chance of %d or Fewer: %s\n", result.Proposals, formatPercent(result.ProbabilityAtMost*100))
+    fmt.Printf("- Chance of %d or More: %s\n", result.Proposals, formatPercent(result.ProbabilityAtLeast*100))
+
+    fmt.Println()
+}
@@ -1,14 +1,16 @@
 package main
 
 import (
-    "encoding/json"
     "fmt"
+    "math"
     "os"
     "strconv"
     "strings"
+    "time"
 
     "github.com/eth-rewards-calculator/internal/calculator"
     "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/lightclient"
     "github.com/eth-rewards-calculator/internal/types"
 
     "github.com/fatih/color"
@@ -20,32 +22,287 @@ var (
     participation    float64
     detailed         bool
     jsonOutput       bool
+    outputFormat     string
+    textOutput       bool
+    bigNumbers       string
+    parquetOutput    bool
+    csvOutput        bool
     compare          string
+    columns          string
+    sortBy           string
+    filter           string
     showPenalties    bool
     inactivityEpochs int
     slashingCount    int
+    slashingEvents   string
+    fork             string
     compareParticipation bool
+    forecastMonths   int
+    growthRate       float64
+    growthCap        int
+    issuanceCap      float64
+    issuancePeak     float64
+    alternativeYield float64
+    alternatives     string
+    missRate         float64
+    syncUptime       float64
+    slashingProb     float64
+    historicalSlashing bool
+    premiumBps       float64
+    exitQueue        int
+    priorityFees     float64
+    mevReward        float64
+    beaconURL        string
+    explorerURL      string
+    explorerAPIKey   string
+    checkpoint       string
+    stateTTL         time.Duration
+    retries          int
+    retryBackoff     time.Duration
+    validatorIndex   uint64
+    fromEpoch        uint64
+    toEpoch          uint64
+    checkEpoch       uint64
+    outcomesPath     string
+    follow           bool
+    proposals        int
+    trials           int
+    bins             int
+    seed             int64
+    offline          time.Duration
+    startSlot        uint64
+    pendingRequests  uint64
+    excessRequests   uint64
+    taxFormat        string
+    eventsPath       string
+    clLabel          string
+    elLabel          string
+    listenAddr       string
+    networkList      string
+    every            time.Duration
+    appendPath       string
+    live             bool
+    webhookURL       string
+    discordPublicKey string
+    alertAPY         float64
+    alertParticipation float64
+    alertActivationQueue int
+    alertExitQueue   int
+    cacheSize        int
+    rateLimit        float64
+    rateBurst        int
+    gridRows         string
+    gridColumns      string
+    targetAPY        float64
+    targetETH        float64
+    targetUSD        float64
+    ethPrice         float64
+    taxTreatment     string
+    incomeTaxRate    float64
+    capitalGainsRate float64
+    holdingYears     float64
+    capitalETH       float64
+    restakingAPR     float64
+    restakingSlashProb float64
+    restakingSlashLoss float64
+    ssvOperators     int
+    ssvOperatorFee   float64
+    ssvNetworkFee    float64
+    ssvPrice         float64
+    precision        int
+    storePath        string
+    specFromBeacon   bool
+    preset           string
+    network          string
+    scenario         string
+    publishURL       string
+    publishTopic     string
+    chainConfig      string
+    keystoreDir      string
+    depositDataPath  string
+    activationQueue  int
+    consolidations   int
+    consolidationQueue int
+    fleetSize        int
+    exitBy           string
+    asOf             string
+    monitorPath      string
+    configPath       string
+    noColor          bool
+    noProgress       bool
 )
 
 func init() {
     flag.IntVarP(&validatorCount, "validators", "v", 0, "Number of validators")
     flag.Float64VarP(&participation, "participation", "p", 0.95, "Network participation rate (0.0-1.0)")
     flag.BoolVarP(&detailed, "detailed", "d", false, "Show detailed breakdown")
-    flag.BoolVarP(&jsonOutput, "json", "j", false, "Output results as JSON")
+    flag.BoolVarP(&jsonOutput, "json", "j", false, "Output results as JSON (same as --format json)")
+    flag.StringVarP(&outputFormat, "format", "f", "text", "Output format: text, table, json, yaml, csv, markdown, influx, or parquet (sweeps only)")
+    flag.StringVarP(&scenario, "scenario", "", "", "Scenario name to tag influx output with")
+    flag.StringVarP(&bigNumbers, "big-numbers", "", "number", "Write numbers beyond JavaScript's safe integer range as: number or string")
+    flag.BoolVarP(&noColor, "no-color", "", false, "Disable colored output (also disabled by NO_COLOR or when stdout is not a terminal)")
+    flag.BoolVarP(&noProgress, "no-progress", "", false, "Do not report progress on stderr during long simulations, sweeps, and backtests")
     flag.StringVarP(&compare, "compare", "c", "", "Compare multiple validator counts (comma-separated)")
+    flag.StringVarP(&columns, "columns", "", defaultColumns, "Metrics to show in the -c comparison, comma-separated (e.g. apy,daily,annual,base_reward)")
     flag.BoolVarP(&showPenalties, "penalties", "", false, "Show penalty calculations")
     flag.IntVarP(&inactivityEpochs, "inactivity", "i", 0, "Epochs of inactivity for penalty calculation")
     flag.IntVarP(&slashingCount, "slashing", "s", 0, "Number of validators slashed together")
+    flag.StringVarP(&slashingEvents, "slashing-events", "", "", "Other slashings as days:count pairs relative to -s, such as -10:2000,25:500 (with --penalties)")
+    flag.StringVarP(&fork, "fork", "", "bellatrix", "Fork whose slashing rules apply, such as phase0, altair, or bellatrix (for slashing)")
     flag.BoolVarP(&compareParticipation, "compare-participation", "", false, "Compare rewards at different participation rates")
+    flag.StringVarP(&sortBy, "sort-by", "", "", "Sort -c and --compare-participation rows by a metric, as name or name:desc")
+    flag.StringVarP(&filter, "filter", "", "", "Keep only -c and --compare-participation rows meeting every condition, such as apy>3.0 (comma-separated)")
+    flag.IntVarP(&forecastMonths, "months", "m", 12, "Number of months to forecast")
+    flag.Float64VarP(&growthRate, "growth-rate", "", 10000, "Validators added per month for growth forecasts")
+    flag.IntVarP(&growthCap, "growth-cap", "", 0, "Validator set capacity for logistic growth (default 2x current)")
+    flag.Float64VarP(&issuanceCap, "issuance-cap", "", 1000000, "Maximum annual issuance in ETH for the capped curve")
+    flag.Float64VarP(&issuancePeak, "issuance-peak", "", 32000000, "Total stake in ETH at which the targeted curve's issuance peaks")
+    flag.Float64VarP(&alternativeYield, "yield", "y", 3.5, "Alternative (opportunity-cost) yield in percent")
+    flag.StringVarP(&alternatives, "alternatives", "", "", "Alternative yields in percent as name=yield,... (for opportunity, default: --yield)")
+    flag.Float64VarP(&missRate, "miss-rate", "", 0, "Fraction of duties missed for risk-adjusted return (0.0-1.0)")
+    flag.Float64VarP(&syncUptime, "sync-uptime", "", 0, "Fraction of sync committee duties performed while on the committee (default: 1 - --miss-rate)")
+    flag.Float64VarP(&slashingProb, "slashing-prob", "", 0, "Annual probability of being slashed for risk-adjusted return (0.0-1.0)")
+    flag.BoolVarP(&historicalSlashing, "historical-slashing", "", false, "Use the historical mainnet slashing rate as the slashing probability")
+    flag.Float64VarP(&premiumBps, "premium", "", 10, "Slashing insurance premium in basis points of stake per year")
+    flag.IntVarP(&exitQueue, "exit-queue", "", 0, "Number of validators ahead in the exit queue")
+    flag.Float64VarP(&priorityFees, "priority-fees", "", 0, "Average priority fees per proposed block in ETH")
+    flag.Float64VarP(&mevReward, "mev", "", 0, "Average MEV payment per proposed block in ETH")
+    flag.StringVarP(&beaconURL, "beacon-url", "", "http://localhost:5052", "Beacon node REST API URL, or a comma-separated list to fail over between")
+    flag.StringVarP(&explorerURL, "explorer-url", "", "", "Block explorer with the beaconcha.in API to take live network conditions from before --beacon-url (comma-separated for several)")
+    flag.StringVarP(&explorerAPIKey, "explorer-api-key", "", "", "API key for --explorer-url")
+    flag.StringVarP(&checkpoint, "checkpoint", "", "", "Trusted finalized block root; live conditions from --beacon-url are verified against it with the light-client protocol")
+    flag.DurationVarP(&stateTTL, "state-ttl", "", 384*time.Second, "How long live network conditions are reused by alert, serve, bot, and --every --live (0 fetches every time)")
+    flag.IntVarP(&retries, "retries", "", 2, "Retries of a failed request to a live endpoint before failing over to the next")
+    flag.DurationVarP(&retryBackoff, "retry-backoff", "", time.Second, "Wait before the first retry of a live endpoint, doubling after each")
+    flag.Uint64VarP(&validatorIndex, "validator-index", "", 0, "Validator index to fetch realized rewards for")
+    flag.Uint64VarP(&fromEpoch, "from-epoch", "", 0, "First epoch of the realized rewards range")
+    flag.Uint64VarP(&toEpoch, "to-epoch", "", 0, "Last epoch of the realized rewards range")
+    flag.Uint64VarP(&checkEpoch, "epoch", "", 0, "Epoch whose rewards cross-check compares (default: the latest finalized one already paid)")
+    flag.StringVarP(&outcomesPath, "outcomes", "", "", "JSON Lines file of per-epoch duty outcomes to track instead of a beacon node (- for stdin)")
+    flag.BoolVarP(&follow, "follow", "", false, "Keep tracking newly finalized epochs until interrupted")
+    flag.IntVarP(&proposals, "proposals", "", 0, "Blocks the validator actually proposed over the epoch range (for luck)")
+    flag.IntVarP(&trials, "trials", "", 10000, "Monte Carlo trials for simulate and outage")
+    flag.IntVarP(&bins, "bins", "", 20, "Histogram bins for simulate")
+    flag.Int64VarP(&seed, "seed", "", 0, "Random seed for simulate and outage (default: time-based)")
+    flag.DurationVarP(&offline, "offline", "", 0, "How long the validator is offline (e.g. 45m, for outage)")
+    flag.Uint64VarP(&startSlot, "start-slot", "", 0, "Slot the outage starts at (for outage)")
+    flag.Uint64VarP(&pendingRequests, "withdrawal-requests", "", 0, "Withdrawal requests already queued in the EIP-7002 contract (for exit)")
+    flag.Uint64VarP(&excessRequests, "request-excess", "", 0, "Excess withdrawal requests that set the EIP-7002 fee (for exit)")
+    flag.StringVarP(&taxFormat, "tax-format", "", "csv", "Export format: koinly, cointracker, or csv")
+    flag.StringVarP(&eventsPath, "events", "", "", "JSON Lines file of reward events to export instead of projecting them (- for stdin)")
+    flag.StringVarP(&clLabel, "cl-label", "", "", "Label for consensus layer income in exports (default depends on --tax-format)")
+    flag.StringVarP(&elLabel, "el-label", "", "", "Label for execution layer income in exports (default depends on --tax-format)")
+    flag.StringVarP(&listenAddr, "listen", "", "localhost:8080", "Address for serve to listen on")
+    flag.StringVarP(&networkList, "networks", "", "", "Networks to report on as name=beacon-url,... (for networks)")
+    flag.DurationVarP(&every, "every", "", 0, "Recompute on this schedule (e.g. 1h), writing one CSV row (or influx line) per run")
+    flag.StringVarP(&appendPath, "append", "", "", "File to append scheduled results to (stdout if not set)")
+    flag.StringVarP(&publishURL, "publish", "", "", "Message bus to publish scheduled results to: nats://host:4222 or kafka://host:9092")
+    flag.StringVarP(&publishTopic, "publish-topic", "", "eth-rewards", "Topic prefix for --publish; results go to <prefix>.<network>")
+    flag.BoolVarP(&live, "live", "", false, "Take the validator count and participation from the beacon node for scheduled runs")
+    flag.StringVarP(&discordPublicKey, "discord-public-key", "", "", "Discord application public key (hex) for bot interactions")
+    flag.StringVarP(&webhookURL, "webhook", "", "", "URL that alert POSTs each alert to as JSON")
+    flag.Float64VarP(&alertAPY, "alert-apy", "", 0, "Alert when projected APY falls below this percentage")
+    flag.Float64VarP(&alertParticipation, "alert-participation", "", 0, "Alert when participation falls below this rate (0.0-1.0)")
+    flag.IntVarP(&alertActivationQueue, "alert-activation-queue", "", 0, "Alert when the activation queue exceeds this many validators")
+    flag.IntVarP(&alertExitQueue, "alert-exit-queue", "", 0, "Alert when the exit queue exceeds this many validators")
+    flag.IntVarP(&cacheSize, "cache-size", "", 1024, "Responses serve keeps in memory (0 disables caching)")
+    flag.Float64VarP(&rateLimit, "rate-limit", "", 10, "API requests per second allowed per client in serve (0 disables)")
+    flag.IntVarP(&rateBurst, "rate-burst", "", 20, "API requests a client may make at once before --rate-limit applies")
+    flag.StringVarP(&gridRows, "grid-rows", "", "validators=500000,750000,1000000,1250000,1500000", "Grid row parameter and values as name=v1,v2,... (for grid)")
+    flag.StringVarP(&gridColumns, "grid-cols", "", "participation=0.8,0.85,0.9,0.95,1.0", "Grid column parameter and values as name=v1,v2,... (for grid)")
+    flag.Float64VarP(&targetAPY, "target-apy", "", 0, "APY in percent to find the participation and effectiveness thresholds for (for threshold)")
+    flag.Float64VarP(&targetETH, "target-eth", "", 0, "Net income in ETH per month to solve for (for target)")
+    flag.Float64VarP(&targetUSD, "target-usd", "", 0, "Net income in USD per month to solve for (for target, needs --eth-price)")
+    flag.Float64VarP(&ethPrice, "eth-price", "", 0, "Price of ETH in USD")
+    flag.StringVarP(&taxTreatment, "tax-treatment", "", calculator.TaxAtReceipt, "How rewards are taxed: receipt (income when received) or disposal (zero-basis capital gains when sold)")
+    flag.Float64VarP(&incomeTaxRate, "income-tax-rate", "", 0, "Income tax rate in percent for rewards taxed at receipt")
+    flag.Float64VarP(&capitalGainsRate, "capital-gains-rate", "", 0, "Capital gains tax rate in percent for rewards taxed at disposal")
+    flag.Float64VarP(&holdingYears, "holding-years", "", 1, "Years rewards are held before being sold, for taxes at disposal")
+    flag.Float64VarP(&capitalETH, "eth", "e", 32, "Amount of ETH to stake when comparing staking setups")
+    flag.Float64VarP(&restakingAPR, "restaking-apr", "", 0, "Additional APR in percent earned by restaking")
+    flag.Float64VarP(&restakingSlashProb, "restaking-slashing-prob", "", 0, "Annual probability of a restaking slashing event (0.0-1.0)")
+    flag.Float64VarP(&restakingSlashLoss, "restaking-slashing-loss", "", 0.1, "Fraction of stake lost in a restaking slashing event (0.0-1.0)")
+    flag.IntVarP(&ssvOperators, "ssv-operators", "", 4, "Number of operators in the SSV cluster")
+    flag.Float64VarP(&ssvOperatorFee, "ssv-operator-fee", "", 1.0, "SSV operator fee in SSV per validator per year")
+    flag.Float64VarP(&ssvNetworkFee, "ssv-network-fee", "", 1.0, "SSV network fee in SSV per validator per year")
+    flag.Float64VarP(&ssvPrice, "ssv-price", "", 0.003, "Price of the SSV token in ETH")
+    flag.IntVarP(&precision, "precision", "", 6, "Decimals for ETH amounts and JSON values (percentages use 4 fewer, at least 2)")
+    flag.StringVarP(&preset, "preset", "", "mainnet", "Consensus spec preset: mainnet or minimal")
+    flag.StringVarP(&network, "network", "", "", "Slot time: mainnet, slot-10s, or slot-6s, keeping the preset's epoch length (default: the preset's)")
+    flag.StringVarP(&chainConfig, "chain-config", "", "", "Consensus layer config.yaml (e.g. from ethereum-package) to load chain constants from")
+    flag.BoolVarP(&specFromBeacon, "spec-from-beacon", "", false, "Load chain constants from the beacon node at --beacon-url instead of the mainnet defaults")
+    flag.StringVarP(&keystoreDir, "keystore-dir", "", "", "Directory of EIP-2335 validator keystores to project your own validators' rewards for")
+    flag.StringVarP(&depositDataPath, "deposit-data", "", "", "staking-deposit-cli deposit_data.json to project the activation wait and first-year rewards for")
+    flag.IntVarP(&activationQueue, "activation-queue", "", 0, "Number of validators ahead in the activation queue (for --deposit-data and queue)")
+    flag.IntVarP(&consolidations, "consolidations", "", 0, "Number of 32 ETH validators to consolidate (for consolidate, default: the 0x01 validators given)")
+    flag.IntVarP(&consolidationQueue, "consolidation-queue", "", 0, "Number of consolidations already queued ahead (for consolidate)")
+    flag.IntVarP(&fleetSize, "fleet", "", 0, "Number of your validators to exit (for exit-plan, default: the validators given)")
+    flag.StringVarP(&exitBy, "exit-by", "", "", "Date to be fully exited and withdrawn by, as YYYY-MM-DD (for exit-plan)")
+    flag.StringVarP(&monitorPath, "monitor", "", "", "Lighthouse or Prysm validator monitor log or metrics (file, URL, or - for stdin) to take --miss-rate from")
+    flag.StringVarP(&asOf, "as-of", "", "", "Use the mainnet validator count and participation on a past date, as YYYY-MM-DD")
+    flag.StringVarP(&configPath, "config", "", "", "Config file of flag defaults as flat YAML (default: eth-rewards/config.yaml in the user config directory)")
+    flag.StringVarP(&storePath, "store", "", "", "SQLite database to record this run's inputs and results in")
+
+    flag.Usage = usage
 }
 
 func main() {
     flag.Parse()
+    applyConfigLayers()
 
-    // Validate inputs
-    if validatorCount == 0 && compare == "" && !compareParticipation {
-        fmt.Println("Error: Please specify validator count with -v, use -c for comparison, or use --compare-participation")
-        flag.Usage()
+    // The color package already turns color off for NO_COLOR, TERM=dumb, and
+    // output that is not a terminal
+    if noColor {
+        color.NoColor = true
+    }
+
+    if jsonOutput {
+        outputFormat = "json"
+    }
+    switch outputFormat {
+    case "text", "table", "json", "yaml", "csv", "markdown", "influx", "parquet":
+    default:
+        fmt.Printf("Error: Unknown output format '%s' (use text, table, json, yaml, csv, markdown, influx, or parquet)\n", outputFormat)
+        os.Exit(1)
+    }
+    if bigNumbers != "number" && bigNumbers != "string" {
+        fmt.Printf("Error: Unknown big number encoding '%s' (use number or string)\n", bigNumbers)
+        os.Exit(1)
+    }
+    textOutput = outputFormat == "text"
+    jsonOutput = outputFormat == "json"
+    parquetOutput = outputFormat == "parquet"
+    csvOutput = outputFormat == "csv"
+
+    // Chain constants must be in place before any state is built. The network
+    // overrides the preset's slot time, a config file overrides both, and a
+    // beacon node's spec overrides everything.
+    if err := config.ApplyPreset(preset); err != nil {
+        fmt.Printf("Error: %v\n", err)
+        os.Exit(1)
+    }
+    if network != "" {
+        if err := config.ApplyNetwork(network); err != nil {
+            fmt.Printf("Error: %v\n", err)
+            os.Exit(1)
+        }
+    }
+    if chainConfig != "" {
+        loadChainConfig()
+    }
+    if specFromBeacon {
+        loadBeaconSpec()
+    }
+
+    if asOf != "" {
+        applyAsOf()
+    }
+    if monitorPath != "" {
+        applyMonitor()
+    }
+
+    if precision < 0 || precision > 18 {
+        fmt.Println("Error: Precision must be between 0 and 18")
         os.Exit(1)
     }
 
@@ -54,9 +311,106 @@ func main() {
         os.Exit(1)
     }
 
+    if missRate < 0 || missRate > 1 || slashingProb < 0 || slashingProb > 1 {
+        fmt.Println("Error: Miss rate and slashing probability must be between 0.0 and 1.0")
+        os.Exit(1)
+    }
+
+    if flag.CommandLine.Changed("sync-uptime") && (syncUptime <= 0 || syncUptime > 1) {
+        fmt.Println("Error: Sync committee uptime must be greater than 0.0 and at most 1.0")
+        os.Exit(1)
+    }
+
+    if priorityFees < 0 || mevReward < 0 {
+        fmt.Println("Error: Priority fees and MEV must not be negative")
+        os.Exit(1)
+    }
+
+    if exitQueue < 0 {
+        fmt.Println("Error: Exit queue length must not be negative")
+        os.Exit(1)
+    }
+
+    if !calculator.IsTaxTreatment(taxTreatment) {
+        fmt.Printf("Error: Unknown tax treatment '%s' (use receipt or disposal)\n", taxTreatment)
+        os.Exit(1)
+    }
+
+    if incomeTaxRate < 0 || incomeTaxRate > 100 || capitalGainsRate < 0 || capitalGainsRate > 100 {
+        fmt.Println("Error: Tax rates must be between 0 and 100")
+        os.Exit(1)
+    }
+
+    if holdingYears <= 0 {
+        fmt.Println("Error: Holding period must be greater than 0")
+        os.Exit(1)
+    }
+
+    if historicalSlashing {
+        if slashingProb > 0 {
+            fmt.Println("Error: --historical-slashing cannot be combined with --slashing-prob")
+            os.Exit(1)
+        }
+        slashingProb = calculator.HistoricalSlashingRate()
+    }
+
+    if keystoreDir != "" && depositDataPath != "" {
+        fmt.Println("Error: --keystore-dir cannot be combined with --deposit-data")
+        os.Exit(1)
+    }
+    if activationQueue < 0 {
+        fmt.Println("Error: Activation queue length must not be negative")
+        os.Exit(1)
+    }
+    if stateTTL < 0 {
+        fmt.Println("Error: --state-ttl must not be negative")
+        os.Exit(1)
+    }
+    if retries < 0 || retryBackoff < 0 {
+        fmt.Println("Error: --retries and --retry-backoff must not be negative")
+        os.Exit(1)
+    }
+    if checkpoint != "" {
+        if _, err := lightclient.ParseRoot(checkpoint); err != nil {
+            fmt.Println("Error: --checkpoint must be a 0x-prefixed 32-byte block root")
+            os.Exit(1)
+        }
+        if explorerURL != "" {
+            fmt.Println("Error: --checkpoint verifies beacon nodes; explorers do not serve light-client data")
+            os.Exit(1)
+        }
+    }
+    if keystoreDir != "" {
+        loadKeystores()
+    }
+    if depositDataPath != "" {
+        loadDepositData()
+    }
+
+    openStore()
+
+    // Handle subcommands
+    if flag.NArg() > 0 {
+        runCommand(flag.Arg(0))
+        return
+    }
+
+    // Handle scheduled recomputation
+    if every != 0 || appendPath != "" || live || publishURL != "" {
+        runScheduled()
+        return
+    }
+
+    // Validate inputs
+    if validatorCount == 0 && compare == "" && !compareParticipation && !fetchValidatorCount() {
+        fmt.Println("Error: Please specify validator count with -v or --beacon-url, use -c for comparison, or use --compare-participation")
+        flag.Usage()
+        os.Exit(1)
+    }
+
     // Handle comparison mode
     if compare != "" {
-        handleComparison(compare, participation)
+        handleComparison(participation)
         return
     }
     
@@ -70,12 +424,20 @@ func main() {
     }
 
     // Single validator count calculation
+    if parquetOutput {
+        rejectParquet()
+    }
     state := createNetworkState(validatorCount)
-    results := calculator.CalculateRewards(state, participation)
+    results := projectRewards(state)
+    if portfolioValidators != nil {
+        results.Portfolio = calculator.ProjectPortfolio(results, portfolioValidators, portfolioSource)
+        if portfolioPending {
+            calculator.ProjectActivation(results.Portfolio, results, portfolioValidators, validatorCount, activationQueue)
+        }
+    }
+    recordRun(results)
 
-    if jsonOutput {
-        outputJSON(results)
-    } else {
+    if !printFormatted(results) {
         outputFormatted(results, state, detailed)
     }
 
@@ -85,113 +447,217 @@ func main() {
 }
 
 func createNetworkState(validators int) *types.NetworkState {
-    state := &types.NetworkState{
-        Validators:         make([]types.Validator, validators),
-        TotalActiveBalance: uint64(validators) * config.MAX_EFFECTIVE_BALANCE,
-        CurrentEpoch:       1000,
-        FinalizedEpoch:     998,
-    }
-
-    // Initialize validators
-    for i := range state.Validators {
-        state.Validators[i] = types.Validator{
-            EffectiveBalance: config.MAX_EFFECTIVE_BALANCE,
-            Slashed:          false,
-            InactivityScore:  0,
-        }
-        
-        if inactivityEpochs > 0 {
-            state.Validators[i].InactivityScore = uint64(inactivityEpochs * 4)
-            state.FinalizedEpoch = state.CurrentEpoch - uint64(inactivityEpochs) - 2
-        }
+    state, err := calculator.NewNetworkState(validators)
+    if err != nil {
+        fmt.Printf("Error: Invalid network state: %v\n", err)
+        os.Exit(1)
+    }
+
+    if inactivityEpochs > 0 {
+        // Every validator is offline while the chain stops finalizing
+        calculator.AdvanceWithoutFinality(state, uint64(inactivityEpochs), func(int) bool { return true })
     }
 
     return state
 }
 
-func handleComparison(compareStr string, participation float64) {
-    counts := strings.Split(compareStr, ",")
+// createTemplateState is createNetworkState with one template validator
+// standing in for all of them (see calculator.NewTemplateState), for servers
+// that project any count on request. Invalid counts are returned as errors.
+func createTemplateState(validators int) (*types.NetworkState, error) {
+    state, err := calculator.NewTemplateState(validators)
+    if err != nil {
+        return nil, err
+    }
+    if inactivityEpochs > 0 {
+        calculator.AdvanceWithoutFinality(state, uint64(inactivityEpochs), func(int) bool { return true })
+    }
+    return state, nil
+}
+
+// projectRewards calculates rewards for the state with every adjustment selected
+// on the command line applied
+func projectRewards(state *types.NetworkState) *types.RewardResults {
+    return projectRewardsAt(state, participation)
+}
+
+// projectRewardsAt is projectRewards at the given participation rate instead of
+// the --participation flag
+func projectRewardsAt(state *types.NetworkState, participation float64) *types.RewardResults {
+    return projectTemplateRewardsAt(state, len(state.Validators), participation)
+}
+
+// projectTemplateRewardsAt is projectRewardsAt for a template state standing in
+// for validators validators
+func projectTemplateRewardsAt(state *types.NetworkState, validators int, participation float64) *types.RewardResults {
+    return calculator.ProjectTemplateRewards(state, validators, participation, calculator.Adjustments{
+        PriorityFees:        priorityFees,
+        MEV:                 mevReward,
+        MissRate:            missRate,
+        SyncUptime:          syncUptime,
+        SlashingProbability: slashingProb,
+        ExitQueue:           exitQueue,
+        AlternativeYield:    alternativeYield,
+        TaxTreatment:        taxTreatment,
+        IncomeTaxRate:       incomeTaxRate,
+        CapitalGainsRate:    capitalGainsRate,
+        HoldingYears:        holdingYears,
+    })
+}
+
+func handleComparison(participation float64) {
+    selected := selectedColumns()
+    options := parseSweepOptions()
+    var sweep []*types.RewardResults
+    counts := validatorCounts("comparison")
+    bar := newProgress("Sweeping validator counts", len(counts))
+    for _, count := range counts {
+        results := calculator.CalculateRewards(createNetworkState(count), participation)
+        calculator.ApplyExecutionRewards(results, priorityFees*1e9, mevReward*1e9)
+        sweep = append(sweep, results)
+        bar.step(1)
+    }
+    bar.finish()
+    sweep = options.apply(sweep)
+    recordRun(sweep)
+    
+    if parquetOutput {
+        rows := make([]comparisonRow, len(sweep))
+        for i, results := range sweep {
+            rows[i] = newComparisonRow(results)
+        }
+        printParquet(rows)
+        return
+    }
+    
+    if !textOutput {
+        comparison := make([]object, len(sweep))
+        for i, results := range sweep {
+            comparison[i] = object{{"validator_count", results.ValidatorCount}}
+            for _, column := range selected {
+                comparison[i] = append(comparison[i], field{column.key, column.value(results)})
+            }
+        }
+        printFormatted(comparison)
+        return
+    }
     
     header := color.New(color.FgCyan, color.Bold)
     header.Println("\n=== Ethereum Staking Rewards Comparison ===")
     
-    fmt.Printf("\nParticipation Rate: %.1f%%\n\n", participation*100)
+    fmt.Printf("\nParticipation Rate: %s\n\n", formatPercent(participation*100))
     
     // Table header
-    fmt.Printf("%-15s %-20s %-20s %-15s %-10s %-15s\n", 
-        "Validators", "Total Staked (ETH)", "Base Reward (Gwei)", 
-        "Annual ETH", "APY %", "Daily ETH")
-    fmt.Println(strings.Repeat("-", 100))
+    width := 15
+    fmt.Printf("%-15s", "Validators")
+    for _, column := range selected {
+        fmt.Printf(" %-*s", column.width, column.header)
+        width += column.width + 1
+    }
+    fmt.Println()
+    fmt.Println(strings.Repeat("-", width))
 
-    for _, countStr := range counts {
-        count, err := strconv.Atoi(strings.TrimSpace(countStr))
-        if err != nil {
-            fmt.Printf("Error: Invalid validator count '%s'\n", countStr)
-            continue
+    for _, results := range sweep {
+        fmt.Printf("%-15d", results.ValidatorCount)
+        for _, column := range selected {
+            fmt.Printf(" %-*s", column.width, column.text(results))
         }
-
-        state := createNetworkState(count)
-        results := calculator.CalculateRewards(state, participation)
-        
-        fmt.Printf("%-15d %-20s %-20d %-15.6f %-10.2f%% %-15.6f\n",
-            count,
-            formatNumber(state.TotalActiveBalance/1e9),
-            results.BaseRewardPerEpoch,
-            results.TotalAnnualRewards/1e9,
-            results.APY,
-            results.TotalAnnualRewards/1e9/365.25)
+        fmt.Println()
     }
     
     fmt.Println()
 }
 
 func compareParticipationRates(validatorCount int) {
-    header := color.New(color.FgCyan, color.Bold)
-    header.Println("\n=== Participation Rate Impact Analysis ===")
-    
-    fmt.Printf("\nValidator Count: %s\n\n", formatNumber(uint64(validatorCount)))
+    options := parseSweepOptions()
     
     // Create network state once
     state := createNetworkState(validatorCount)
     
-    // Table header
-    fmt.Printf("%-20s %-15s %-15s %-20s %-15s %-25s\n", 
-        "Participation Rate", "Multiplier", "Base APY %", "Effective APY %", 
-        "Annual ETH", "Network Status")
-    fmt.Println(strings.Repeat("-", 110))
-    
     // Compare different participation rates
     participationRates := []float64{1.0, 0.95, 0.9, 0.8, 0.7, 0.6667, 0.6, 0.5, 0.4, 0.3333}
     
-    for _, rate := range participationRates {
-        results := calculator.CalculateRewards(state, rate)
-        
-        statusColor := color.New(color.FgGreen)
-        status := "Healthy"
-        
-        if rate < 0.3333 {
-            statusColor = color.New(color.FgRed, color.Bold)
-            status = "CRITICAL - No finality"
-        } else if rate < 0.6667 {
-            statusColor = color.New(color.FgRed)
-            status = "Inactivity leak active"
-        } else if rate < 0.8 {
-            statusColor = color.New(color.FgYellow)
-            status = "Reduced security"
+    sweep := make([]*types.RewardResults, len(participationRates))
+    for i, rate := range participationRates {
+        sweep[i] = calculator.CalculateRewards(state, rate)
+        calculator.ApplyExecutionRewards(sweep[i], priorityFees*1e9, mevReward*1e9)
+    }
+    sweep = options.apply(sweep)
+    recordRun(sweep)
+    
+    // Execution income gets its own columns when given
+    withExecution := priorityFees > 0 || mevReward > 0
+    
+    if parquetOutput {
+        rows := make([]participationRow, len(sweep))
+        for i, results := range sweep {
+            rows[i] = newParticipationRow(results)
         }
+        printParquet(rows)
+        return
+    }
+    
+    if !textOutput {
+        comparison := make([]types.ParticipationResult, len(sweep))
+        for i, results := range sweep {
+            status, _ := participationStatus(results.ParticipationRate)
+            comparison[i] = types.ParticipationResult{
+                ParticipationRate:       results.ParticipationRate,
+                ParticipationMultiplier: results.ParticipationMultiplier,
+                BaseAPY:                 results.BaseAPY,
+                EffectiveAPY:            results.EffectiveAPY,
+                ConsensusRewards:        results.ConsensusRewardsAnnualGwei / 1e9,
+                ExecutionRewards:        results.ExecutionRewardsAnnualGwei / 1e9,
+                AnnualRewards:           results.TotalAnnualRewardsGwei / 1e9,
+                InactivityLeakActive:    results.InactivityLeakActive,
+                NetworkStatus:           status,
+            }
+        }
+        printFormatted(comparison)
+        return
+    }
+    
+    header := color.New(color.FgCyan, color.Bold)
+    header.Println("\n=== Participation Rate Impact Analysis ===")
+    
+    fmt.Printf("\nValidator Count: %s\n\n", formatNumber(uint64(validatorCount)))
+    
+    // Table header
+    fmt.Printf("%-20s %-15s %-15s %-20s ", "Participation Rate", "Multiplier", "Base APY %", "Effective APY %")
+    if withExecution {
+        fmt.Printf("%-15s %-15s ", "CL ETH", "EL ETH")
+    }
+    fmt.Printf("%-15s %-25s\n", "Annual ETH", "Network Status")
+    if withExecution {
+        fmt.Println(strings.Repeat("-", 142))
+    } else {
+        fmt.Println(strings.Repeat("-", 110))
+    }
+    
+    for _, results := range sweep {
+        rate := results.ParticipationRate
+        status, statusColor := participationStatus(rate)
         
-        fmt.Printf("%-20s %-15s %-15.2f%% %-20.2f%% %-15.6f ",
-            fmt.Sprintf("%.1f%%", rate*100),
-            fmt.Sprintf("%.2fx", results.ParticipationMultiplier),
-            results.BaseAPY,
-            results.EffectiveAPY,
-            results.TotalAnnualRewards/1e9)
+        fmt.Printf("%-20s %-15s %-15s %-20s ",
+            formatPercent(rate*100),
+            formatDecimal(results.ParticipationMultiplier)+"x",
+            formatPercent(results.BaseAPY),
+            formatPercent(results.EffectiveAPY))
+        if withExecution {
+            fmt.Printf("%-15s %-15s ",
+                formatGweiAsETH(results.ConsensusRewardsAnnualGwei),
+                formatGweiAsETH(results.ExecutionRewardsAnnualGwei))
+        }
+        fmt.Printf("%-15s ", formatGweiAsETH(results.TotalAnnualRewardsGwei))
         
         statusColor.Printf("%-25s\n", status)
     }
     
     fmt.Println("\nNOTE: This model shows how active validators benefit from others being offline.")
     fmt.Println("      At low participation rates, inactivity penalties and network instability become significant factors.")
+    if withExecution {
+        fmt.Println("      Execution rewards come from your own proposals, so they do not scale with participation.")
+    }
 }
 
 func outputFormatted(results *types.RewardResults, state *types.NetworkState, detailed bool) {
@@ -205,50 +671,86 @@ func outputFormatted(results *types.RewardResults, state *types.NetworkState, de
     subheader.Println("\nNetwork Parameters:")
     fmt.Printf("- Validator Count: %s\n", formatNumber(uint64(len(state.Validators))))
     fmt.Printf("- Total Staked: %s ETH\n", formatNumber(state.TotalActiveBalance/1e9))
-    fmt.Printf("- Participation Rate: %.1f%%\n", results.ParticipationRate*100)
-    fmt.Printf("- Effective Balance: %.0f ETH\n", float64(config.MAX_EFFECTIVE_BALANCE)/1e9)
+    fmt.Printf("- Participation Rate: %s\n", formatPercent(results.ParticipationRate*100))
+    fmt.Printf("- Effective Balance: %s ETH\n", formatNumber(config.MAX_EFFECTIVE_BALANCE/1e9))
     
     // Base Reward Calculation
     subheader.Println("\nBase Reward Calculation:")
     fmt.Printf("- Base Reward Factor: %d\n", config.BASE_REWARD_FACTOR)
     fmt.Printf("- Square Root of Total Balance: %s\n", formatNumber(results.SqrtTotalBalance))
-    fmt.Printf("- Base Reward per Epoch: %s Gwei (%.9f ETH)\n", 
-        formatNumber(results.BaseRewardPerEpoch), float64(results.BaseRewardPerEpoch)/1e9)
+    fmt.Printf("- Base Reward per Increment: %s Gwei (× %s increments of 1 ETH)\n",
+        formatNumber(results.BaseRewardPerIncrementGwei), formatNumber(calculator.EffectiveBalanceIncrements(config.MAX_EFFECTIVE_BALANCE)))
+    fmt.Printf("- Base Reward per Epoch: %s Gwei (%s ETH)\n", 
+        formatNumber(results.BaseRewardPerEpochGwei), formatGweiAsETH(float64(results.BaseRewardPerEpochGwei)))
     
     if detailed {
         // Detailed Reward Breakdown
         subheader.Println("\nDetailed Reward Breakdown (per epoch):")
-        fmt.Printf("- Source Vote Reward: %s Gwei (%.2f%%)\n", 
-            formatNumber(results.SourceReward), 
-            float64(config.TIMELY_SOURCE_WEIGHT)/float64(config.WEIGHT_DENOMINATOR)*100)
-        fmt.Printf("- Target Vote Reward: %s Gwei (%.2f%%)\n", 
-            formatNumber(results.TargetReward),
-            float64(config.TIMELY_TARGET_WEIGHT)/float64(config.WEIGHT_DENOMINATOR)*100)
-        fmt.Printf("- Head Vote Reward: %s Gwei (%.2f%%)\n", 
-            formatNumber(results.HeadReward),
-            float64(config.TIMELY_HEAD_WEIGHT)/float64(config.WEIGHT_DENOMINATOR)*100)
+        fmt.Printf("- Source Vote Reward: %s Gwei (%s)\n", 
+            formatNumber(results.SourceRewardGwei), 
+            formatPercent(float64(config.TIMELY_SOURCE_WEIGHT)/float64(config.WEIGHT_DENOMINATOR)*100))
+        fmt.Printf("- Target Vote Reward: %s Gwei (%s)\n", 
+            formatNumber(results.TargetRewardGwei),
+            formatPercent(float64(config.TIMELY_TARGET_WEIGHT)/float64(config.WEIGHT_DENOMINATOR)*100))
+        fmt.Printf("- Head Vote Reward: %s Gwei (%s)\n", 
+            formatNumber(results.HeadRewardGwei),
+            formatPercent(float64(config.TIMELY_HEAD_WEIGHT)/float64(config.WEIGHT_DENOMINATOR)*100))
         fmt.Printf("- Total Attestation Reward: %s Gwei\n", 
-            formatNumber(results.AttestationRewardPerEpoch))
+            formatNumber(results.AttestationRewardPerEpochGwei))
         
         subheader.Println("\nProposer Statistics:")
-        fmt.Printf("- Probability per Epoch: %.4f%%\n", results.ProposerProbability*100)
-        fmt.Printf("- Expected Proposals per Year: %.2f\n", results.ExpectedProposalsPerYear)
+        fmt.Printf("- Probability per Slot: %s\n", formatProbability(results.ProposerProbability))
+        fmt.Printf("- Expected Proposals per Year: %s\n", formatDecimal(results.ExpectedProposalsPerYear))
         fmt.Printf("- Average Proposer Reward per Block: %s Gwei\n", 
-            formatNumber(uint64(results.AvgProposerRewardPerBlock)))
+            formatNumber(uint64(results.AvgProposerRewardPerBlockGwei)))
+        fmt.Printf("- Expected Time to First Proposal: %s days (%s epochs)\n",
+            formatDecimal(results.FirstProposal.ExpectedDays), formatDecimal(results.FirstProposal.ExpectedEpochs))
+        for _, p := range results.FirstProposal.Percentiles {
+            fmt.Printf("  - %s%% of validators propose within %s days\n",
+                strconv.FormatFloat(p.Percentile, 'f', -1, 64), formatDecimal(p.Days))
+        }
+        
+        subheader.Println("\nSync Committee Statistics:")
+        fmt.Printf("- Selection Probability per Period: %s\n", formatProbability(results.SyncCommitteeProbability))
+        fmt.Printf("- Expected Periods per Year: %s\n", formatDecimal(results.SyncCommitteePeriodsPerYear))
+        fmt.Printf("- Income per Period: %s ETH (%d epochs, ~%s days)\n",
+            formatGweiAsETH(results.SyncCommitteeIncomePerPeriodGwei), config.EPOCHS_PER_SYNC_COMMITTEE_PERIOD,
+            formatDecimal(float64(config.EPOCHS_PER_SYNC_COMMITTEE_PERIOD)/float64(config.EPOCHS_PER_DAY)))
+        fmt.Printf("- Penalty per Missed Slot: %s Gwei (the reward it would have earned)\n",
+            formatNumber(calculator.CalculateSyncCommitteeReward(state, 1)))
         
         subheader.Println("\nAttestation Inclusion Details:")
-        fmt.Printf("- Estimated Attestations per Block: %.0f\n", results.EstimatedAttestationsPerBlock)
+        fmt.Printf("- Estimated Attestations per Block: %s\n", formatNumber(uint64(math.Round(results.EstimatedAttestationsPerBlock))))
         fmt.Printf("- Attestation Inclusion Reward: %s Gwei\n", 
-            formatNumber(results.AttestationInclusionReward))
-        fmt.Printf("- Inclusion Effectiveness Rate: %.1f%%\n", results.InclusionEffectivenessRate*100)
+            formatNumber(results.AttestationInclusionRewardGwei))
+        fmt.Printf("- Inclusion Effectiveness Rate: %s\n", formatPercent(results.InclusionEffectivenessRate*100))
+        
+        subheader.Println("\nYield by Duty (APR):")
+        consensusAPR := results.ConsensusRewardsAnnualGwei / float64(config.MAX_EFFECTIVE_BALANCE) * 100
+        for _, duty := range []struct {
+            name string
+            apr  float64
+        }{
+            {"Source Votes", results.SourceAPR},
+            {"Target Votes", results.TargetAPR},
+            {"Head Votes", results.HeadAPR},
+            {"Block Proposals", results.ProposerAPR},
+            {"Sync Committee", results.SyncAPR},
+        } {
+            share := 0.0
+            if consensusAPR > 0 {
+                share = duty.apr / consensusAPR * 100
+            }
+            fmt.Printf("- %s: %s (%s of consensus yield)\n", duty.name, formatPercent(duty.apr), formatPercent(share))
+        }
     }
     
     // Participation Economics
     if results.ParticipationRate < 1.0 {
         subheader.Println("\nParticipation Economics:")
-        fmt.Printf("- Participation Multiplier: %.2fx\n", results.ParticipationMultiplier)
-        fmt.Printf("- Base APY (at 100%% participation): %.2f%%\n", results.BaseAPY)
-        fmt.Printf("- Effective APY (with boost): %.2f%%\n", results.EffectiveAPY)
+        fmt.Printf("- Participation Multiplier: %sx\n", formatDecimal(results.ParticipationMultiplier))
+        fmt.Printf("- Base APY (at 100%% participation): %s\n", formatPercent(results.BaseAPY))
+        fmt.Printf("- Effective APY (with boost): %s\n", formatPercent(results.EffectiveAPY))
         if results.NetworkHealthWarning != "" {
             warningColor := color.New(color.FgRed, color.Bold)
             warningColor.Printf("- %s\n", results.NetworkHealthWarning)
@@ -257,20 +759,124 @@ func outputFormatted(results *types.RewardResults, state *types.NetworkState, de
     
     // Annual Rewards
     subheader.Println("\nAnnual Rewards:")
-    fmt.Printf("- Attestation Rewards: %.6f ETH\n", results.AttestationRewardsAnnual/1e9)
-    fmt.Printf("- Proposer Rewards: %.6f ETH\n", results.ProposerRewardsAnnual/1e9)
-    fmt.Printf("- Total Annual Rewards: %.6f ETH\n", results.TotalAnnualRewards/1e9)
+    fmt.Println("- Consensus Layer:")
+    fmt.Printf("  - Attestation Rewards: %s ETH\n", formatGweiAsETH(results.AttestationRewardsAnnualGwei))
+    fmt.Printf("  - Proposer Rewards: %s ETH\n", formatGweiAsETH(results.ProposerRewardsAnnualGwei))
+    fmt.Printf("  - Sync Committee Rewards: %s ETH\n", formatGweiAsETH(results.SyncCommitteeRewardsAnnualGwei))
+    fmt.Printf("  - Subtotal: %s ETH\n", formatGweiAsETH(results.ConsensusRewardsAnnualGwei))
+    fmt.Println("- Execution Layer:")
+    fmt.Printf("  - Priority Fees: %s ETH\n", formatGweiAsETH(results.PriorityFeesAnnualGwei))
+    fmt.Printf("  - MEV: %s ETH\n", formatGweiAsETH(results.MEVRewardsAnnualGwei))
+    fmt.Printf("  - Subtotal: %s ETH\n", formatGweiAsETH(results.ExecutionRewardsAnnualGwei))
+    fmt.Printf("- Total Annual Rewards: %s ETH (± %s ETH)\n",
+        formatGweiAsETH(results.TotalAnnualRewardsGwei), formatGweiAsETH(results.AnnualRewardsStdDevGwei))
     
-    highlight.Printf("- Annual Percentage Yield (APY): %.2f%%\n", results.APY)
+    highlight.Printf("- Annual Percentage Yield (APY): %s\n", formatPercent(results.APY))
+    fmt.Printf("- Typical Range (± 1 std dev): %s to %s (proposals ± %s ETH, sync committee ± %s ETH)\n",
+        formatPercent(math.Max(0, results.APY-results.APYStdDev)), formatPercent(results.APY+results.APYStdDev),
+        formatGweiAsETH(results.ProposerRewardsStdDevGwei), formatGweiAsETH(results.SyncCommitteeRewardsStdDevGwei))
+    fmt.Printf("- Exit Delay Cost: %s ETH (%s days queued + %s days to withdrawal at %s alternative yield)\n",
+        formatGweiAsETH(results.ExitDelayCostGwei), formatDecimal(results.ExitQueueDays),
+        formatDecimal(results.WithdrawalDelayDays), formatPercent(results.AlternativeYield))
+    
+    // Risk-adjusted return
+    if results.MissRate > 0 || results.SyncMissRate > 0 || results.SlashingProbability > 0 {
+        subheader.Println("\nRisk-Adjusted Return:")
+        fmt.Printf("- Miss Rate: %s\n", formatPercent(results.MissRate*100))
+        fmt.Printf("- Sync Committee Uptime: %s\n", formatPercent((1-results.SyncMissRate)*100))
+        fmt.Printf("- Annual Slashing Probability: %s", formatProbability(results.SlashingProbability))
+        if historicalSlashing {
+            fmt.Print(" (historical mainnet rate)")
+        }
+        fmt.Println()
+        fmt.Printf("- Expected Missed Rewards: %s ETH\n", formatGweiAsETH(results.ExpectedMissedRewardsGwei))
+        fmt.Printf("- Expected Penalties: %s ETH (%s ETH sync committee)\n", formatGweiAsETH(results.ExpectedPenaltiesGwei),
+            formatGweiAsETH(results.ExpectedSyncPenaltiesGwei))
+        fmt.Printf("- Expected Slashing Loss: %s ETH\n", formatGweiAsETH(results.ExpectedSlashingLossGwei))
+        highlight.Printf("- Risk-Adjusted APY: %s\n", formatPercent(results.RiskAdjustedAPY))
+    }
+    
+    // After-tax returns
+    if len(results.TaxResults) > 0 {
+        subheader.Println("\nAfter-Tax Returns:")
+        for _, tax := range results.TaxResults {
+            line := fmt.Sprintf("- %s: %s APY (%s ETH/yr tax at %s)", taxTreatmentNames[tax.Treatment],
+                formatPercent(tax.AfterTaxAPY), formatGweiAsETH(tax.AnnualTaxGwei), formatPercent(tax.TaxRate))
+            if tax.Treatment == results.TaxTreatment {
+                highlight.Println(line + " [selected]")
+            } else {
+                fmt.Println(line)
+            }
+        }
+    }
     
-    // Daily/Monthly projections
-    subheader.Println("\nProjected Earnings:")
-    fmt.Printf("- Daily: %.6f ETH\n", results.TotalAnnualRewards/1e9/365.25)
-    fmt.Printf("- Weekly: %.6f ETH\n", results.TotalAnnualRewards/1e9/52.18)
-    fmt.Printf("- Monthly: %.6f ETH\n", results.TotalAnnualRewards/1e9/12)
+    // Period summary
+    subheader.Println("\nProjected Earnings (ETH):")
+    printPeriods(results.Periods)
+    
+    // The operator's own validators
+    if p := results.Portfolio; p != nil {
+        subheader.Printf("\nYour Validators (%s):\n", p.Source)
+        fmt.Printf("- Validators: %d (%s ETH staked)\n", p.Validators, formatGweiAsETH(float64(p.TotalStaked)))
+        fmt.Printf("- Annual Rewards: %s ETH\n", formatGweiAsETH(p.AnnualRewards))
+        fmt.Printf("- Monthly: %s ETH\n", formatGweiAsETH(p.MonthlyRewards))
+        fmt.Printf("- Daily: %s ETH\n", formatGweiAsETH(p.DailyRewards))
+        if len(p.Groups) == 1 && p.Groups[0].Credentials == "0x01" {
+            highlight.Printf("- APY: %s\n", formatPercent(p.APY))
+        } else {
+            for _, g := range p.Groups {
+                fmt.Printf("- %s %s: %d validators, %s ETH staked, %s ETH/yr, %s APY\n", g.Credentials,
+                    credentialNames[g.Credentials], g.Validators, formatGweiAsETH(float64(g.TotalStaked)),
+                    formatGweiAsETH(g.AnnualRewards), formatPercent(g.APY))
+            }
+            highlight.Printf("- Blended APY: %s\n", formatPercent(p.APY))
+        }
+        
+        if len(p.Deposits) > 0 {
+            fmt.Printf("\nActivation (%s validators ahead in the queue):\n", formatNumber(uint64(p.ActivationQueue)))
+            fmt.Printf("%-16s %-12s %-16s %-18s\n", "Pubkey", "Amount ETH", "Activation Days", "First-Year ETH")
+            fmt.Println(strings.Repeat("-", 65))
+            for _, d := range p.Deposits {
+                fmt.Printf("%-16s %-12s %-16s %-18s\n", shortPubkey(d.Pubkey), formatGweiAsETH(float64(d.Amount)),
+                    formatDecimal(d.ActivationDays), formatGweiAsETH(d.FirstYearRewards))
+            }
+            highlight.Printf("- First-Year Rewards: %s ETH (all active after %s days)\n",
+                formatGweiAsETH(p.FirstYearRewards), formatDecimal(p.ActivationDays))
+        }
+    }
+}
+
+// printPeriods prints a period summary as a table of ETH amounts
+func printPeriods(rows []types.PeriodRow) {
+    fmt.Printf("%-24s %14s %14s %14s %14s %14s\n", "", "Per Epoch", "Daily", "Weekly", "Monthly", "Annual")
+    fmt.Println(strings.Repeat("-", 99))
+    for _, row := range rows {
+        fmt.Printf("%-24s %14s %14s %14s %14s %14s\n", row.Name, formatGweiAsETH(row.PerEpoch),
+            formatGweiAsETH(row.Daily), formatGweiAsETH(row.Weekly), formatGweiAsETH(row.Monthly),
+            formatGweiAsETH(row.Annual))
+    }
+}
+
+// participationStatus describes the network's condition at a participation rate
+func participationStatus(rate float64) (string, *color.Color) {
+    switch {
+    case rate < 0.3333:
+        return "CRITICAL - No finality", color.New(color.FgRed, color.Bold)
+    case rate < 0.6667:
+        return "Inactivity leak active", color.New(color.FgRed)
+    case rate < 0.8:
+        return "Reduced security", color.New(color.FgYellow)
+    default:
+        return "Healthy", color.New(color.FgGreen)
+    }
 }
 
 func showPenaltyExamples(state *types.NetworkState) {
+    var events []types.SlashingEvent
+    if slashingCount > 0 || slashingEvents != "" {
+        events = parseSlashingEvents()
+    }
+
     header := color.New(color.FgRed, color.Bold)
     subheader := color.New(color.FgYellow, color.Bold)
     
@@ -279,46 +885,103 @@ func showPenaltyExamples(state *types.NetworkState) {
     validatorIndex := 0
     
     // Missed attestation
-    penalties := calculator.CalculatePenalties(state, validatorIndex, false, false, false)
+    penalties, err := calculator.CalculatePenalties(state, validatorIndex, false, false, false)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error calculating penalties: %v\n", err)
+        os.Exit(1)
+    }
     subheader.Println("\nMissed Attestation Penalties:")
-    fmt.Printf("- Source Penalty: %s Gwei\n", formatNumber(penalties.SourcePenalty))
-    fmt.Printf("- Target Penalty: %s Gwei\n", formatNumber(penalties.TargetPenalty))
-    fmt.Printf("- Head Penalty: %s Gwei\n", formatNumber(penalties.HeadPenalty))
-    fmt.Printf("- Total per Epoch: %s Gwei\n", formatNumber(penalties.TotalAttestationPenalty))
-    fmt.Printf("- Daily Cost: %.6f ETH\n", float64(penalties.TotalAttestationPenalty*225)/1e9)
+    fmt.Printf("- Source Penalty: %s Gwei\n", formatNumber(penalties.SourcePenaltyGwei))
+    fmt.Printf("- Target Penalty: %s Gwei\n", formatNumber(penalties.TargetPenaltyGwei))
+    fmt.Printf("- Head Penalty: %s Gwei\n", formatNumber(penalties.HeadPenaltyGwei))
+    fmt.Printf("- Total per Epoch: %s Gwei\n", formatNumber(penalties.TotalAttestationPenaltyGwei))
     
     // Inactivity leak
     if inactivityEpochs > 0 {
-        inactivityPenalty := calculator.GetInactivityPenalty(state, validatorIndex)
+        inactivityPenalty, err := calculator.GetInactivityPenalty(state, validatorIndex)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "Error calculating inactivity penalty: %v\n", err)
+            os.Exit(1)
+        }
         subheader.Printf("\nInactivity Leak (%d epochs without finality):\n", inactivityEpochs)
         fmt.Printf("- Inactivity Score: %d\n", state.Validators[validatorIndex].InactivityScore)
-        fmt.Printf("- Penalty per Epoch: %s Gwei (%.6f ETH)\n", 
-            formatNumber(inactivityPenalty), float64(inactivityPenalty)/1e9)
-        fmt.Printf("- Daily Penalty: %.6f ETH\n", float64(inactivityPenalty*225)/1e9)
-        fmt.Printf("- Projected Loss in 30 days: %.6f ETH\n", float64(inactivityPenalty*225*30)/1e9)
+        fmt.Printf("- Penalty per Epoch: %s Gwei (%s ETH)\n", 
+            formatNumber(inactivityPenalty), formatGweiAsETH(float64(inactivityPenalty)))
+    }
+
+    // The inactivity row only applies while the chain is not finalizing
+    subheader.Println("\nPenalties by Period (ETH):")
+    if inactivityEpochs > 0 {
+        printPeriods(penalties.Periods)
+    } else {
+        printPeriods(penalties.Periods[:1])
     }
     
     // Slashing
     if slashingCount > 0 {
         subheader.Printf("\nSlashing Penalties (%d validators slashed together):\n", slashingCount)
-        slashingResults := calculator.CalculateSlashingPenalties(
-            state, validatorIndex, uint64(slashingCount)*config.MAX_EFFECTIVE_BALANCE)
-        
-        fmt.Printf("- Initial Penalty: %.6f ETH\n", float64(slashingResults.InitialPenalty)/1e9)
-        fmt.Printf("- Proportional Penalty: %.6f ETH\n", float64(slashingResults.ProportionalPenalty)/1e9)
-        fmt.Printf("- Total Penalty: %.6f ETH (%.2f%% of stake)\n", 
-            float64(slashingResults.TotalPenalty)/1e9,
-            float64(slashingResults.TotalPenalty)/float64(config.MAX_EFFECTIVE_BALANCE)*100)
+        // The correlation penalty is charged at the midpoint against the
+        // slashings vector as it stands then
+        state.SlashingsPerEpoch = calculator.SlashingsVectorAt(events, calculator.SlashingMidpoint(events[0].Epoch))
+        slashingResults, err := calculator.CalculateWindowedSlashingPenalties(state, validatorIndex)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "Error calculating slashing penalties: %v\n", err)
+            os.Exit(1)
+        }
+
+        if len(events) > 1 {
+            var windowed, total uint64
+            for _, balance := range state.SlashingsPerEpoch {
+                windowed += balance
+            }
+            for _, event := range events {
+                total += event.BalanceGwei
+            }
+            fmt.Printf("- Slashed in Window (±%s days): %s validators (%s outside it)\n",
+                epochDays(config.EPOCHS_PER_SLASHINGS_VECTOR/2),
+                formatNumber(windowed/config.MAX_EFFECTIVE_BALANCE),
+                formatNumber((total-windowed)/config.MAX_EFFECTIVE_BALANCE))
+        }
+        fmt.Printf("- Initial Penalty: %s ETH\n", formatGweiAsETH(float64(slashingResults.InitialPenaltyGwei)))
+        fmt.Printf("- Proportional Penalty: %s ETH\n", formatGweiAsETH(float64(slashingResults.ProportionalPenaltyGwei)))
+        fmt.Printf("- Total Penalty: %s ETH (%s of stake)\n", 
+            formatGweiAsETH(float64(slashingResults.TotalPenaltyGwei)),
+            formatPercent(slashingResults.PercentageOfStake))
     }
 }
 
-func outputJSON(results *types.RewardResults) {
-    output, err := json.MarshalIndent(results, "", "  ")
-    if err != nil {
-        fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+// parseSlashingEvents returns the -s slashing followed by the --slashing-events
+// ones, as epochs. The -s validators are slashed a full slashings vector after
+// genesis so earlier events still have a valid epoch.
+func parseSlashingEvents() []types.SlashingEvent {
+    if slashingCount <= 0 {
+        fmt.Println("Error: --slashing-events requires the number of validators slashed (-s)")
         os.Exit(1)
     }
-    fmt.Println(string(output))
+
+    slashedEpoch := config.EPOCHS_PER_SLASHINGS_VECTOR
+    events := []types.SlashingEvent{{Epoch: slashedEpoch, BalanceGwei: uint64(slashingCount) * config.MAX_EFFECTIVE_BALANCE}}
+    if slashingEvents == "" {
+        return events
+    }
+
+    for _, pair := range strings.Split(slashingEvents, ",") {
+        daysStr, countStr, ok := strings.Cut(strings.TrimSpace(pair), ":")
+        days, err := strconv.ParseFloat(daysStr, 64)
+        count, countErr := strconv.ParseUint(countStr, 10, 64)
+        if !ok || err != nil || countErr != nil || count == 0 {
+            fmt.Printf("Error: Invalid slashing event '%s' (use days:count, such as -10:2000)\n", pair)
+            os.Exit(1)
+        }
+
+        // Events before the start of the vector are outside the window anyway
+        epoch := float64(slashedEpoch) + math.Round(days*float64(config.EPOCHS_PER_DAY))
+        events = append(events, types.SlashingEvent{
+            Epoch:       uint64(math.Max(0, epoch)),
+            BalanceGwei: count * config.MAX_EFFECTIVE_BALANCE,
+        })
+    }
+    return events
 }
 
 func formatNumber(n uint64) string {
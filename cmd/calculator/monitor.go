@@ -0,0 +1,73 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/monitor"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+// monitorSummary is the --monitor import, once read
+var monitorSummary *types.MonitorSummary
+
+// applyMonitor reads --monitor and, unless --miss-rate was given, takes the
+// miss rate from the effectiveness it reports, exiting on failure
+func applyMonitor() {
+    summary, err := monitor.Read(monitorPath)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error reading validator monitor: %v\n", err)
+        os.Exit(1)
+    }
+    monitorSummary = summary
+
+    if flag.CommandLine.Changed("miss-rate") {
+        return
+    }
+    missRate = 1 - summary.Effectiveness
+    fmt.Fprintf(os.Stderr, "Using %s effectiveness from %s attestations by %d validators (%s %s)\n",
+        formatPercent(summary.Effectiveness*100), formatNumber(summary.Attestations), summary.Validators,
+        summary.Client, summary.Format)
+}
+
+func handleMonitor() {
+    if monitorSummary == nil {
+        fmt.Println("Error: The monitor command requires a validator monitor log or metrics (--monitor)")
+        os.Exit(1)
+    }
+    summary := monitorSummary
+    recordRun(summary)
+
+    if printFormatted(summary) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+    highlight := color.New(color.FgGreen, color.Bold)
+
+    header.Println("\n=== Validator Monitor ===")
+    fmt.Printf("\nSource: %s %s (%s)\n", summary.Client, summary.Format, monitorPath)
+    fmt.Printf("Validators: %d\n", summary.Validators)
+
+    share := func(n uint64) string {
+        return formatPercent(float64(n) / float64(summary.Attestations) * 100)
+    }
+    subheader.Println("\nAttestations:")
+    fmt.Printf("- Duties: %s\n", formatNumber(summary.Attestations))
+    fmt.Printf("- Included: %s (%s)\n", formatNumber(summary.Included), share(summary.Included))
+    fmt.Printf("- Missed: %s (%s)\n", formatNumber(summary.Attestations-summary.Included),
+        share(summary.Attestations-summary.Included))
+    fmt.Printf("- Timely Source: %s (%s)\n", formatNumber(summary.TimelySource), share(summary.TimelySource))
+    fmt.Printf("- Timely Target: %s (%s)\n", formatNumber(summary.TimelyTarget), share(summary.TimelyTarget))
+    fmt.Printf("- Timely Head: %s (%s)\n", formatNumber(summary.TimelyHead), share(summary.TimelyHead))
+    if summary.AverageInclusionDistance > 0 {
+        fmt.Printf("- Average Inclusion Distance: %s slots\n", formatDecimal(summary.AverageInclusionDistance))
+    }
+
+    highlight.Printf("\nEffectiveness: %s (--miss-rate %s)\n\n", formatPercent(summary.Effectiveness*100),
+        formatDecimal(1-summary.Effectiveness))
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/beacon"
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/provider"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+)
+
+func handleNetworks() {
+    if networkList == "" {
+        fmt.Println("Error: The networks command requires --networks name=url,...")
+        os.Exit(1)
+    }
+
+    report := &types.MultiNetworkReport{}
+    weightedAPY := 0.0
+    for _, entry := range strings.Split(networkList, ",") {
+        name, url, found := strings.Cut(strings.TrimSpace(entry), "=")
+        if !found || name == "" || url == "" {
+            fmt.Printf("Error: Invalid network '%s' (use name=beacon-url)\n", entry)
+            os.Exit(1)
+        }
+
+        network := reportNetwork(name, url)
+        if network.Snapshot != nil {
+            report.ActiveValidators += network.Snapshot.ActiveValidators
+            weightedAPY += network.Snapshot.APY * float64(network.Snapshot.ActiveValidators)
+        }
+        report.Networks = append(report.Networks, network)
+    }
+    if report.ActiveValidators > 0 {
+        report.AverageAPY = weightedAPY / float64(report.ActiveValidators)
+    }
+    recordRun(report)
+
+    if printFormatted(report) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    highlight := color.New(color.FgGreen, color.Bold)
+    warning := color.New(color.FgRed)
+
+    header.Println("\n=== Multi-Network Report ===")
+    fmt.Println()
+    fmt.Printf("%-12s %-8s %-12s %-14s %-10s %-10s %-18s\n",
+        "Network", "Epoch", "Validators", "Participation", "APY", "Slot", "Rewards/Validator")
+    fmt.Println(strings.Repeat("-", 90))
+    for _, network := range report.Networks {
+        if network.Snapshot == nil {
+            warning.Printf("%-12s %s\n", network.Network, network.Error)
+            continue
+        }
+        snapshot := network.Snapshot
+        fmt.Printf("%-12s %-8d %-12s %-14s %-10s %-10s %-18s\n",
+            network.Network, snapshot.Epoch, formatNumber(uint64(snapshot.ActiveValidators)),
+            formatPercent(snapshot.Participation*100), formatPercent(snapshot.APY),
+            fmt.Sprintf("%ds", network.SecondsPerSlot),
+            formatETH(network.AnnualRewards)+" / "+formatETH(network.StakePerValidator))
+    }
+
+    fmt.Println()
+    highlight.Printf("Total Active Validators: %s\n", formatNumber(uint64(report.ActiveValidators)))
+    highlight.Printf("Validator-Weighted APY: %s\n", formatPercent(report.AverageAPY))
+    fmt.Println("Rewards are annual, per validator, in each network's staking token.")
+    fmt.Println()
+}
+
+// reportNetwork loads a network's chain spec and live conditions from its
+// beacon node and projects its rewards. The chain parameters are global, so the
+// previous ones are restored afterwards. Failures are reported in the result.
+func reportNetwork(name, url string) types.NetworkReport {
+    network := types.NetworkReport{Network: name, BeaconURL: url}
+    client := beacon.NewClient(url)
+
+    saved := config.Save()
+    defer saved.Restore()
+
+    spec, err := client.GetSpec()
+    if err == nil {
+        _, err = config.ApplySpec(spec)
+    }
+    if err != nil {
+        network.Error = fmt.Sprintf("loading chain spec: %v", err)
+        return network
+    }
+
+    snapshot, err := (&provider.BeaconNode{Client: client}).Snapshot()
+    if err != nil {
+        network.Error = fmt.Sprintf("fetching network snapshot: %v", err)
+        return network
+    }
+    if snapshot.ActiveValidators == 0 {
+        network.Error = "no active validators"
+        return network
+    }
+
+    results := projectRewardsAt(createNetworkState(snapshot.ActiveValidators), snapshot.Participation)
+    snapshot.APY = results.APY
+    network.Snapshot = snapshot
+    network.SecondsPerSlot = config.SECONDS_PER_SLOT
+    network.StakePerValidator = float64(config.MAX_EFFECTIVE_BALANCE) / 1e9
+    network.AnnualRewards = results.TotalAnnualRewardsGwei / 1e9
+    return network
+}
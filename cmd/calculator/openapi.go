@@ -0,0 +1,337 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "reflect"
+    "strings"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// openAPIDocument describes the serve endpoints as an OpenAPI 3 document. The
+// result schemas are generated from the JSON tags of the result types, so they
+// always match what the server returns.
+func openAPIDocument() (map[string]interface{}, error) {
+    schemas := map[string]interface{}{}
+    rewardsRef, err := openAPISchema(reflect.TypeOf(types.RewardResults{}), schemas)
+    if err != nil {
+        return nil, err
+    }
+
+    queryParameter := func(name, typ, description string) map[string]interface{} {
+        return map[string]interface{}{
+            "name":        name,
+            "in":          "query",
+            "required":    false,
+            "description": description,
+            "schema":      map[string]interface{}{"type": typ},
+        }
+    }
+    textResponse := func(description string) map[string]interface{} {
+        return map[string]interface{}{
+            "description": description,
+            "content": map[string]interface{}{
+                "text/plain": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+            },
+        }
+    }
+    jsonResponse := func(description string, schema map[string]interface{}) map[string]interface{} {
+        return map[string]interface{}{
+            "description": description,
+            "content": map[string]interface{}{
+                "application/json": map[string]interface{}{"schema": schema},
+            },
+        }
+    }
+    object := func(properties map[string]interface{}) map[string]interface{} {
+        return map[string]interface{}{"type": "object", "properties": properties}
+    }
+    array := func(items map[string]interface{}) map[string]interface{} {
+        return map[string]interface{}{"type": "array", "items": items}
+    }
+    str := map[string]interface{}{"type": "string"}
+    number := map[string]interface{}{"type": "number"}
+
+    // The Grafana JSON datasource endpoints take a JSON body and answer 405
+    // to anything but POST
+    grafanaPost := func(operationID, summary string, request, response map[string]interface{},
+        errors map[string]interface{}) map[string]interface{} {
+
+        responses := map[string]interface{}{
+            "200": jsonResponse(summary, response),
+            "405": textResponse("Method other than POST"),
+            "429": textResponse("Rate limit exceeded; see the Retry-After header"),
+        }
+        for code, response := range errors {
+            responses[code] = response
+        }
+        return map[string]interface{}{
+            "post": map[string]interface{}{
+                "operationId": operationID,
+                "summary":     summary,
+                "tags":        []string{"grafana"},
+                "requestBody": map[string]interface{}{
+                    "required": false,
+                    "content":  map[string]interface{}{"application/json": map[string]interface{}{"schema": request}},
+                },
+                "responses": responses,
+            },
+        }
+    }
+    grafanaHealth := func(operationID string) map[string]interface{} {
+        return map[string]interface{}{
+            "get": map[string]interface{}{
+                "operationId": operationID,
+                "summary":     "Grafana datasource health check",
+                "tags":        []string{"grafana"},
+                "responses": map[string]interface{}{
+                    "200": textResponse("OK"),
+                    "405": textResponse("Method other than GET"),
+                    "429": textResponse("Rate limit exceeded; see the Retry-After header"),
+                },
+            },
+        }
+    }
+    // Dashboard variables arrive as strings, so payload numbers may be either
+    numeric := map[string]interface{}{"oneOf": []interface{}{number, str}}
+    payload := object(map[string]interface{}{
+        "validators":    numeric,
+        "participation": numeric,
+        "sweep":         map[string]interface{}{"type": "string", "enum": []string{"validators", "participation"}},
+        "values": map[string]interface{}{
+            "oneOf":       []interface{}{str, array(numeric)},
+            "description": fmt.Sprintf("The swept values, as a list or comma-separated, at most %d", maxGrafanaSweep),
+        },
+        "policy":  map[string]interface{}{"type": "string", "enum": []string{"sqrt", "capped", "targeted"}},
+        "slashed": numeric,
+        "epochs":  numeric,
+    })
+    query := object(map[string]interface{}{
+        "range": object(map[string]interface{}{
+            "from": map[string]interface{}{"type": "string", "format": "date-time"},
+            "to":   map[string]interface{}{"type": "string", "format": "date-time"},
+        }),
+        "targets": map[string]interface{}{
+            "type":     "array",
+            "maxItems": maxGrafanaTargets,
+            "items": object(map[string]interface{}{
+                "target":  str,
+                "hide":    map[string]interface{}{"type": "boolean"},
+                "payload": payload,
+                "data":    payload,
+            }),
+        },
+    })
+    series := map[string]interface{}{"oneOf": []interface{}{
+        object(map[string]interface{}{
+            "target":     str,
+            "datapoints": array(array(number)),
+        }),
+        object(map[string]interface{}{
+            "type":    map[string]interface{}{"type": "string", "enum": []string{"table"}},
+            "columns": array(object(map[string]interface{}{"text": str, "type": str})),
+            "rows":    array(array(number)),
+        }),
+    }}
+
+    return map[string]interface{}{
+        "openapi": "3.0.3",
+        "info": map[string]interface{}{
+            "title":       "ETH Rewards Calculator API",
+            "description": "Projected Ethereum validator rewards, served by eth-rewards serve",
+            "version":     "1.0.0",
+        },
+        "paths": map[string]interface{}{
+            "/api/rewards": map[string]interface{}{
+                "get": map[string]interface{}{
+                    "operationId": "getRewards",
+                    "summary":     "Project one validator's annual rewards",
+                    "description": "Flags given to serve other than -v and -p (fees, MEV, risk) apply to every request.",
+                    "parameters": []interface{}{
+                        queryParameter("validators", "integer", "Number of active validators (defaults to the live network with --beacon-url or --explorer-url, otherwise -v)"),
+                        queryParameter("participation", "number", "Network participation rate, 0.0-1.0 (defaults to the live network with --beacon-url or --explorer-url, otherwise -p)"),
+                    },
+                    "responses": map[string]interface{}{
+                        "200": map[string]interface{}{
+                            "description": "Projected rewards; amounts in Gwei unless the field name says otherwise",
+                            "content": map[string]interface{}{
+                                "application/json": map[string]interface{}{"schema": rewardsRef},
+                            },
+                        },
+                        "400": textResponse("Invalid parameters"),
+                        "405": textResponse("Method other than GET"),
+                        "429": textResponse("Rate limit exceeded; see the Retry-After header"),
+                        "502": textResponse("A parameter was left out and the live network conditions could not be fetched"),
+                    },
+                },
+            },
+            "/grafana":        grafanaHealth("getGrafanaHealth"),
+            "/grafana/":       grafanaHealth("getGrafanaHealthSlash"),
+            "/grafana/search": grafanaPost("searchGrafanaMetrics", "Metric names", object(nil), array(str), nil),
+            "/grafana/metrics": grafanaPost("getGrafanaMetrics", "Metrics with their payload options", object(nil),
+                array(object(map[string]interface{}{
+                    "value":    str,
+                    "label":    str,
+                    "payloads": array(object(nil)),
+                })), nil),
+            "/grafana/query": grafanaPost("queryGrafana",
+                "Each target's metric: a flat time series over the range, or a table over the swept values",
+                query, array(series), map[string]interface{}{
+                    "400": textResponse(fmt.Sprintf("Invalid query, unknown metric, more than %d targets, or a body over %d bytes",
+                        maxGrafanaTargets, maxGrafanaQueryBytes)),
+                }),
+            "/grafana/annotations": grafanaPost("getGrafanaAnnotations", "Annotations (always none)", object(nil),
+                array(object(nil)), nil),
+            "/slack": map[string]interface{}{
+                "post": map[string]interface{}{
+                    "operationId": "slackCommand",
+                    "summary":     "Answer a Slack slash command",
+                    "description": "Served only when SLACK_SIGNING_SECRET is set. Requests must carry Slack's v0 signature.",
+                    "parameters": []interface{}{
+                        map[string]interface{}{"name": "X-Slack-Request-Timestamp", "in": "header", "required": true, "schema": str},
+                        map[string]interface{}{"name": "X-Slack-Signature", "in": "header", "required": true, "schema": str},
+                    },
+                    "requestBody": map[string]interface{}{
+                        "required": true,
+                        "content": map[string]interface{}{
+                            "application/x-www-form-urlencoded": map[string]interface{}{
+                                "schema": object(map[string]interface{}{
+                                    "text": map[string]interface{}{"type": "string", "description": "The command, such as apy 1.1m 0.97"},
+                                }),
+                            },
+                        },
+                    },
+                    "responses": map[string]interface{}{
+                        "200": jsonResponse("The reply, posted in the channel", object(map[string]interface{}{
+                            "response_type": str,
+                            "text":          str,
+                        })),
+                        "400": textResponse("Unreadable body or form"),
+                        "401": textResponse("Missing, stale, or invalid signature"),
+                        "405": textResponse("Method other than POST"),
+                    },
+                },
+            },
+            "/openapi.json": map[string]interface{}{
+                "get": map[string]interface{}{
+                    "operationId": "getOpenAPI",
+                    "summary":     "This document",
+                    "responses": map[string]interface{}{
+                        "200": map[string]interface{}{
+                            "description": "OpenAPI 3 document",
+                            "content": map[string]interface{}{
+                                "application/json": map[string]interface{}{"schema": map[string]interface{}{"type": "object"}},
+                            },
+                        },
+                    },
+                },
+            },
+        },
+        "components": map[string]interface{}{"schemas": schemas},
+    }, nil
+}
+
+// numberSchema allows the string form of big numbers when the server was
+// started with --big-numbers string
+func numberSchema(schema map[string]interface{}) map[string]interface{} {
+    if bigNumbers != "string" {
+        return schema
+    }
+    return map[string]interface{}{"oneOf": []interface{}{
+        schema,
+        map[string]interface{}{"type": "string", "description": "Numbers beyond 2^53 - 1 in magnitude, as decimal digits"},
+    }}
+}
+
+// openAPISchema returns the schema for t, adding named structs to schemas and
+// referring to them by $ref. Kinds JSON cannot carry, such as channels and
+// functions, are an error.
+func openAPISchema(t reflect.Type, schemas map[string]interface{}) (map[string]interface{}, error) {
+    if t == reflect.TypeOf(time.Time{}) {
+        return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+    }
+
+    switch t.Kind() {
+    case reflect.Ptr:
+        return openAPISchema(t.Elem(), schemas)
+    case reflect.Bool:
+        return map[string]interface{}{"type": "boolean"}, nil
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        return numberSchema(map[string]interface{}{"type": "integer", "format": "int64"}), nil
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        return numberSchema(map[string]interface{}{"type": "integer", "format": "int64", "minimum": 0}), nil
+    case reflect.Float32, reflect.Float64:
+        return numberSchema(map[string]interface{}{"type": "number", "format": "double"}), nil
+    case reflect.String:
+        return map[string]interface{}{"type": "string"}, nil
+    case reflect.Slice, reflect.Array:
+        items, err := openAPISchema(t.Elem(), schemas)
+        if err != nil {
+            return nil, err
+        }
+        return map[string]interface{}{"type": "array", "items": items}, nil
+    case reflect.Map:
+        values, err := openAPISchema(t.Elem(), schemas)
+        if err != nil {
+            return nil, err
+        }
+        return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+    case reflect.Struct:
+        ref := map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+        if _, done := schemas[t.Name()]; done {
+            return ref, nil
+        }
+        // Reserve the name first so recursive types terminate
+        schemas[t.Name()] = nil
+
+        properties := map[string]interface{}{}
+        var required []string
+        for i := 0; i < t.NumField(); i++ {
+            field := t.Field(i)
+            if !field.IsExported() {
+                continue
+            }
+            name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+            if name == "-" {
+                continue
+            }
+            if name == "" {
+                name = field.Name
+            }
+            schema, err := openAPISchema(field.Type, schemas)
+            if err != nil {
+                return nil, fmt.Errorf("%s.%s: %v", t.Name(), field.Name, err)
+            }
+            properties[name] = schema
+            if !strings.Contains(options, "omitempty") {
+                required = append(required, name)
+            }
+        }
+
+        schema := map[string]interface{}{"type": "object", "properties": properties}
+        if len(required) > 0 {
+            schema["required"] = required
+        }
+        schemas[t.Name()] = schema
+        return ref, nil
+    default:
+        return nil, fmt.Errorf("openapi: unsupported type %s", t)
+    }
+}
+
+func handleOpenAPI() {
+    document, err := openAPIDocument()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error describing the API: %v\n", err)
+        os.Exit(1)
+    }
+    output, err := json.MarshalIndent(document, "", "  ")
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+        os.Exit(1)
+    }
+    fmt.Println(string(output))
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+    document, err := openAPIDocument()
+    if err != nil {
+        t.Fatal(err)
+    }
+    paths := document["paths"].(map[string]interface{})
+    for _, path := range []string{"/api/rewards", "/openapi.json", "/grafana", "/grafana/", "/grafana/query", "/slack"} {
+        if _, ok := paths[path]; !ok {
+            t.Errorf("%s is not described", path)
+        }
+    }
+    rewards := paths["/api/rewards"].(map[string]interface{})["get"].(map[string]interface{})
+    if _, ok := rewards["responses"].(map[string]interface{})["502"]; !ok {
+        t.Error("/api/rewards does not describe its 502")
+    }
+
+    recorder := httptest.NewRecorder()
+    serveOpenAPI(recorder, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+    if recorder.Code != http.StatusOK || !json.Valid(recorder.Body.Bytes()) {
+        t.Errorf("/openapi.json answered %d", recorder.Code)
+    }
+}
+
+func TestOpenAPISchemaUnsupported(t *testing.T) {
+    type result struct {
+        Updates chan int `json:"updates"`
+    }
+    tests := []reflect.Type{
+        reflect.TypeOf(func() {}),
+        reflect.TypeOf(result{}),
+        reflect.TypeOf([]result{}),
+        reflect.TypeOf(map[string]*result{}),
+    }
+    for _, typ := range tests {
+        if _, err := openAPISchema(typ, map[string]interface{}{}); err == nil {
+            t.Errorf("%s described without error", typ)
+        }
+    }
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strconv"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+)
+
+func handleOpportunity() {
+    requireValidators("opportunity")
+
+    if forecastMonths <= 0 {
+        fmt.Println("Error: Horizon must be at least one month (-m)")
+        os.Exit(1)
+    }
+    if capitalETH <= 0 {
+        fmt.Println("Error: Amount of ETH to stake must be greater than 0 (-e)")
+        os.Exit(1)
+    }
+    if activationQueue < 0 || exitQueue < 0 {
+        fmt.Println("Error: Queue lengths must not be negative")
+        os.Exit(1)
+    }
+
+    results := projectRewards(createNetworkState(validatorCount))
+    horizonDays := float64(forecastMonths) * 365.25 / 12
+    comparison := calculator.CompareOpportunityCost(results, capitalETH, horizonDays,
+        activationQueue, exitQueue, parseAlternatives())
+    recordRun(comparison)
+
+    if printFormatted(comparison) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+    highlight := color.New(color.FgGreen, color.Bold)
+    warning := color.New(color.FgRed, color.Bold)
+
+    header.Println("\n=== Staking Opportunity Cost ===")
+
+    fmt.Printf("\nCapital: %s ETH over %d months\n", formatETH(comparison.Capital), forecastMonths)
+    fmt.Printf("Staking APY (risk-adjusted): %s\n", formatPercent(comparison.StakingAPY))
+
+    subheader.Println("\nIlliquidity:")
+    fmt.Printf("- Activation Queue: %s days\n", formatDecimal(comparison.ActivationDays))
+    fmt.Printf("- Exit Queue: %s days (still earning)\n", formatDecimal(comparison.ExitQueueDays))
+    fmt.Printf("- Withdrawal Delay: %s days\n", formatDecimal(comparison.WithdrawalDays))
+    fmt.Printf("- From Exit Request to Withdrawal: %s days\n", formatDecimal(comparison.IlliquidDays))
+
+    subheader.Println("\nReturns over the Horizon:")
+    fmt.Printf("%-20s %-12s %-16s %-18s\n", "", "Yield", "Return ETH", "Staking Advantage")
+    fmt.Println(strings.Repeat("-", 68))
+    fmt.Printf("%-20s %-12s %-16s %-18s\n", "Staking", formatPercent(comparison.EffectiveYield),
+        formatETH(comparison.StakingReturn), "")
+    for _, alternative := range comparison.Alternatives {
+        fmt.Printf("%-20s %-12s %-16s %-18s\n", alternative.Name, formatPercent(alternative.Yield),
+            formatETH(alternative.Return), formatETH(alternative.Advantage))
+    }
+
+    fmt.Println()
+    for _, alternative := range comparison.Alternatives {
+        if alternative.StakingBetter {
+            highlight.Printf("- Staking beats %s by %s ETH\n", alternative.Name, formatETH(alternative.Advantage))
+        } else {
+            warning.Printf("- %s beats staking by %s ETH\n", alternative.Name, formatETH(-alternative.Advantage))
+        }
+    }
+    fmt.Printf("\nAfter the queue and withdrawal delays, staking beats any alternative yielding less than %s.\n\n",
+        formatPercent(comparison.EffectiveYield))
+}
+
+// parseAlternatives reads --alternatives, falling back to --yield as a single
+// alternative
+func parseAlternatives() []types.Alternative {
+    if alternatives == "" {
+        return []types.Alternative{{Name: "Alternative", Yield: alternativeYield}}
+    }
+
+    var parsed []types.Alternative
+    for _, entry := range strings.Split(alternatives, ",") {
+        name, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+        yield, err := strconv.ParseFloat(value, 64)
+        if !found || name == "" || err != nil {
+            fmt.Printf("Error: Invalid alternative '%s' (use name=yield)\n", entry)
+            os.Exit(1)
+        }
+        parsed = append(parsed, types.Alternative{Name: name, Yield: yield})
+    }
+    return parsed
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/config"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+func handleOutage() {
+    requireValidators("outage")
+    if offline <= 0 {
+        fmt.Println("Error: --offline must be greater than 0 for outage")
+        os.Exit(1)
+    }
+    if trials <= 0 {
+        fmt.Println("Error: Trials must be greater than 0")
+        os.Exit(1)
+    }
+    if !flag.CommandLine.Changed("seed") {
+        seed = time.Now().UnixNano()
+    }
+
+    // A slot the validator is offline for any part of is missed
+    slotTime := time.Duration(config.SECONDS_PER_SLOT) * time.Second
+    slots := uint64((offline + slotTime - 1) / slotTime)
+
+    state := createNetworkState(validatorCount)
+    results := projectRewards(state)
+    bar := newProgress("Simulating", trials)
+    outage := calculator.SimulateOutage(state, results, startSlot, slots, trials, seed, bar.step)
+    bar.finish()
+    recordRun(outage)
+
+    if printFormatted(outage) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+
+    header.Println("\n=== Outage Cost ===")
+
+    fmt.Printf("\nNetwork Validators: %s\n", formatNumber(uint64(validatorCount)))
+    fmt.Printf("Offline: slots %s to %s (%s slots, %s), epochs %s to %s\n",
+        formatNumber(outage.StartSlot), formatNumber(outage.StartSlot+outage.Slots-1), formatNumber(outage.Slots),
+        time.Duration(outage.Seconds)*time.Second, formatNumber(outage.FirstEpoch), formatNumber(outage.LastEpoch))
+    fmt.Printf("Trials: %s (seed %d)\n", formatNumber(uint64(outage.Trials)), outage.Seed)
+
+    subheader.Println("\nMissed Duties (expected):")
+    fmt.Printf("- Attestations: %s\n", formatDecimal(outage.MissedAttestations))
+    fmt.Printf("- Block Proposals: %s chance of missing one\n", formatProbability(outage.ProposalProbability))
+    fmt.Printf("- Sync Committee Slots: %s (%s chance of being on the committee)\n",
+        formatDecimal(outage.MissedSyncDuties), formatProbability(outage.SyncCommitteeProbability))
+
+    subheader.Println("\nCost (rewards forgone and penalties):")
+    fmt.Printf("- Attestations: %s ETH\n", formatETH(outage.AttestationCost))
+    fmt.Printf("- Block Proposals: %s ETH\n", formatETH(outage.ProposalCost))
+    fmt.Printf("- Sync Committee: %s ETH\n", formatETH(outage.SyncCost))
+    fmt.Printf("- Expected Total: %s ETH\n", formatETH(outage.Cost))
+    for _, p := range outage.Percentiles {
+        fmt.Printf("- P%g: %s ETH\n", p.Percentile, formatETH(p.Cost))
+    }
+    fmt.Printf("- Worst of %s Trials: %s ETH\n", formatNumber(uint64(outage.Trials)), formatETH(outage.Max))
+    fmt.Printf("- Earned Back In: %s hours online\n\n", formatDecimal(outage.RecoveryHours))
+}
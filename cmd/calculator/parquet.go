@@ -0,0 +1,178 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "reflect"
+
+    "github.com/eth-rewards-calculator/internal/parquet"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// Parquet rows flatten sweep results into one record per data point so they
+// load directly into pandas or DuckDB. Amounts are in ETH and rates in percent,
+// matching the JSON field names.
+
+type comparisonRow struct {
+    ValidatorCount    int64   `parquet:"validator_count"`
+    ParticipationRate float64 `parquet:"participation_rate"`
+    TotalStaked       int64   `parquet:"total_staked_eth"`
+    BaseReward        int64   `parquet:"base_reward_gwei"`
+    AnnualRewards     float64 `parquet:"annual_rewards_eth"`
+    APY               float64 `parquet:"apy_percentage"`
+    DailyRewards      float64 `parquet:"daily_rewards_eth"`
+}
+
+func newComparisonRow(results *types.RewardResults) comparisonRow {
+    return comparisonRow{
+        ValidatorCount:    int64(results.ValidatorCount),
+        ParticipationRate: results.ParticipationRate,
+        TotalStaked:       int64(results.TotalStakedGwei / 1e9),
+        BaseReward:        int64(results.BaseRewardPerEpochGwei),
+        AnnualRewards:     results.TotalAnnualRewardsGwei / 1e9,
+        APY:               results.APY,
+        DailyRewards:      results.DailyRewardsGwei / 1e9,
+    }
+}
+
+type participationRow struct {
+    ValidatorCount          int64   `parquet:"validator_count"`
+    ParticipationRate       float64 `parquet:"participation_rate"`
+    ParticipationMultiplier float64 `parquet:"participation_multiplier"`
+    BaseAPY                 float64 `parquet:"base_apy_percentage"`
+    EffectiveAPY            float64 `parquet:"effective_apy_percentage"`
+    ConsensusRewards        float64 `parquet:"consensus_rewards_eth"`
+    ExecutionRewards        float64 `parquet:"execution_rewards_eth"`
+    AnnualRewards           float64 `parquet:"annual_rewards_eth"`
+    InactivityLeakActive    bool    `parquet:"inactivity_leak_active"`
+}
+
+func newParticipationRow(results *types.RewardResults) participationRow {
+    return participationRow{
+        ValidatorCount:          int64(results.ValidatorCount),
+        ParticipationRate:       results.ParticipationRate,
+        ParticipationMultiplier: results.ParticipationMultiplier,
+        BaseAPY:                 results.BaseAPY,
+        EffectiveAPY:            results.EffectiveAPY,
+        ConsensusRewards:        results.ConsensusRewardsAnnualGwei / 1e9,
+        ExecutionRewards:        results.ExecutionRewardsAnnualGwei / 1e9,
+        AnnualRewards:           results.TotalAnnualRewardsGwei / 1e9,
+        InactivityLeakActive:    results.InactivityLeakActive,
+    }
+}
+
+type forecastRow struct {
+    Model          string  `parquet:"model"`
+    Month          int64   `parquet:"month"`
+    ValidatorCount int64   `parquet:"validator_count"`
+    TotalStaked    int64   `parquet:"total_staked_eth"`
+    APY            float64 `parquet:"apy_percentage"`
+    APYChange      float64 `parquet:"apy_change_percentage"`
+}
+
+func newForecastRow(model string, point types.ForecastPoint) forecastRow {
+    return forecastRow{
+        Model:          model,
+        Month:          int64(point.Month),
+        ValidatorCount: int64(point.ValidatorCount),
+        TotalStaked:    int64(point.TotalStaked),
+        APY:            point.APY,
+        APYChange:      point.APYChange,
+    }
+}
+
+type skimRow struct {
+    Number     int64   `parquet:"number"`
+    Date       string  `parquet:"date"`
+    Days       float64 `parquet:"days"`
+    Amount     float64 `parquet:"amount_eth"`
+    Cumulative float64 `parquet:"cumulative_eth"`
+}
+
+func newSkimRow(event types.SkimEvent) skimRow {
+    return skimRow{
+        Number:     int64(event.Number),
+        Date:       event.Date,
+        Days:       event.Days,
+        Amount:     event.Amount,
+        Cumulative: event.Cumulative,
+    }
+}
+
+type compoundingRow struct {
+    Month         int64   `parquet:"month"`
+    Validators    int64   `parquet:"validators"`
+    NewValidators int64   `parquet:"new_validators"`
+    Rewards       float64 `parquet:"rewards_eth"`
+    Pool          float64 `parquet:"pool_eth"`
+    TotalValue    float64 `parquet:"total_value_eth"`
+}
+
+func newCompoundingRow(month types.CompoundingMonth) compoundingRow {
+    return compoundingRow{
+        Month:         int64(month.Month),
+        Validators:    int64(month.Validators),
+        NewValidators: int64(month.NewValidators),
+        Rewards:       month.Rewards,
+        Pool:          month.Pool,
+        TotalValue:    month.TotalValue,
+    }
+}
+
+type simulationRow struct {
+    Trial       int64   `parquet:"trial"`
+    Seed        int64   `parquet:"seed"`
+    Proposals   int64   `parquet:"proposals"`
+    SyncPeriods int64   `parquet:"sync_committee_periods"`
+    Rewards     float64 `parquet:"annual_rewards_eth"`
+}
+
+func newSimulationRow(seed int64, trial types.SimulationTrial) simulationRow {
+    return simulationRow{
+        Trial:       int64(trial.Trial),
+        Seed:        seed,
+        Proposals:   int64(trial.Proposals),
+        SyncPeriods: int64(trial.SyncPeriods),
+        Rewards:     trial.Rewards,
+    }
+}
+
+type issuanceRow struct {
+    Policy         string  `parquet:"policy"`
+    ValidatorCount int64   `parquet:"validator_count"`
+    TotalStaked    int64   `parquet:"total_staked_eth"`
+    BaseAPY        float64 `parquet:"base_apy_percentage"`
+    EffectiveAPY   float64 `parquet:"effective_apy_percentage"`
+    APYChange      float64 `parquet:"apy_change_vs_sqrt_percentage"`
+    AnnualIssuance float64 `parquet:"annual_issuance_eth"`
+    InflationRate  float64 `parquet:"inflation_rate_percentage"`
+}
+
+func newIssuanceRow(result types.IssuanceResult) issuanceRow {
+    return issuanceRow{
+        Policy:         result.Policy,
+        ValidatorCount: int64(result.ValidatorCount),
+        TotalStaked:    int64(result.TotalStaked),
+        BaseAPY:        result.BaseAPY,
+        EffectiveAPY:   result.EffectiveAPY,
+        APYChange:      result.APYChange,
+        AnnualIssuance: result.AnnualIssuance,
+        InflationRate:  result.InflationRate,
+    }
+}
+
+// printParquet writes rows to stdout as a Parquet file, rounding floats like printJSON
+func printParquet(rows interface{}) {
+    roundFloats(reflect.ValueOf(rows))
+
+    if err := parquet.Write(os.Stdout, rows); err != nil {
+        fmt.Fprintf(os.Stderr, "Error writing Parquet output: %v\n", err)
+        os.Exit(1)
+    }
+}
+
+// rejectParquet exits when --format parquet is used outside a sweep
+func rejectParquet() {
+    fmt.Println("Error: Parquet output is only supported for sweeps (-c, --compare-participation, forecast, issuance, skims, compound, simulate)")
+    os.Exit(1)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+    "bytes"
+    "encoding/binary"
+    "math"
+    "reflect"
+    "testing"
+
+    "github.com/eth-rewards-calculator/internal/parquet"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+func TestParquetRows(t *testing.T) {
+    results := &types.RewardResults{
+        ValidatorCount:             1_000_000,
+        ParticipationRate:          0.95,
+        TotalStakedGwei:            32_000_000 * 1e9,
+        BaseRewardPerEpochGwei:     14_025,
+        TotalAnnualRewardsGwei:     1.25e9,
+        ConsensusRewardsAnnualGwei: 0.9e9,
+        ExecutionRewardsAnnualGwei: 0.35e9,
+        DailyRewardsGwei:           3.4e6,
+        APY:                        3.9,
+        InactivityLeakActive:       true,
+    }
+
+    // Amounts are converted from Gwei to ETH
+    comparison := newComparisonRow(results)
+    if comparison.TotalStaked != 32_000_000 || comparison.BaseReward != 14_025 ||
+        comparison.AnnualRewards != 1.25 || comparison.DailyRewards != 0.0034 {
+        t.Errorf("comparison row %+v", comparison)
+    }
+    participation := newParticipationRow(results)
+    if participation.ConsensusRewards != 0.9 || participation.ExecutionRewards != 0.35 ||
+        participation.AnnualRewards != 1.25 || !participation.InactivityLeakActive {
+        t.Errorf("participation row %+v", participation)
+    }
+
+    // Every row type writes, with a column for each field
+    tables := map[string]interface{}{
+        "comparison":    []comparisonRow{comparison},
+        "participation": []participationRow{participation},
+        "forecast":      []forecastRow{newForecastRow("linear", types.ForecastPoint{Month: 3, ValidatorCount: 1_000_000})},
+        "skims":         []skimRow{newSkimRow(types.SkimEvent{Number: 1, Date: "2026-10-16", Amount: 0.01})},
+        "compound":      []compoundingRow{newCompoundingRow(types.CompoundingMonth{Month: 1, Validators: 1})},
+        "simulate":      []simulationRow{newSimulationRow(42, types.SimulationTrial{Trial: 1, Proposals: 3})},
+        "issuance":      []issuanceRow{newIssuanceRow(types.IssuanceResult{Policy: "sqrt", ValidatorCount: 1_000_000})},
+    }
+    for name, rows := range tables {
+        var file bytes.Buffer
+        if err := parquet.Write(&file, rows); err != nil {
+            t.Errorf("%s: %v", name, err)
+            continue
+        }
+        data := file.Bytes()
+        if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+            t.Errorf("%s: not framed by PAR1", name)
+            continue
+        }
+        footerLength := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+        if footerLength <= 0 || footerLength > len(data)-12 {
+            t.Errorf("%s: footer length %d in a %d-byte file", name, footerLength, len(data))
+            continue
+        }
+        footer := data[len(data)-8-footerLength : len(data)-8]
+        rowType := reflect.TypeOf(rows).Elem()
+        for i := 0; i < rowType.NumField(); i++ {
+            if column := rowType.Field(i).Tag.Get("parquet"); !bytes.Contains(footer, []byte(column)) {
+                t.Errorf("%s: no %s column", name, column)
+            }
+        }
+    }
+
+    // The first column's values follow its page header, PLAIN encoded
+    var file bytes.Buffer
+    parquet.Write(&file, []comparisonRow{comparison})
+    var value [8]byte
+    binary.LittleEndian.PutUint64(value[:], 1_000_000)
+    if !bytes.Contains(file.Bytes()[:64], value[:]) {
+        t.Error("validator count not found in the first column chunk")
+    }
+    binary.LittleEndian.PutUint64(value[:], math.Float64bits(0.95))
+    if !bytes.Contains(file.Bytes(), value[:]) {
+        t.Error("participation rate not found")
+    }
+}
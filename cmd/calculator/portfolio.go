@@ -0,0 +1,75 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/portfolio"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    flag "github.com/spf13/pflag"
+)
+
+// portfolioValidators are the operator's own validators, when given. Pending
+// ones come from deposit data and have yet to pass the activation queue.
+var (
+    portfolioValidators []types.PortfolioValidator
+    portfolioSource     string
+    portfolioPending    bool
+)
+
+// loadKeystores counts the keystores in --keystore-dir as the operator's
+// validators, exiting on failure. Unless --eth was given, their stake also
+// becomes the capital that staking setups start from.
+func loadKeystores() {
+    validators, err := portfolio.ScanKeystores(keystoreDir)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error reading keystores: %v\n", err)
+        os.Exit(1)
+    }
+
+    portfolioValidators = validators
+    portfolioSource = keystoreDir
+    usePortfolioCapital()
+}
+
+// loadDepositData reads the deposits in --deposit-data as the operator's
+// pending validators, exiting on failure
+func loadDepositData() {
+    validators, err := portfolio.ReadDepositData(depositDataPath)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error reading deposit data: %v\n", err)
+        os.Exit(1)
+    }
+
+    portfolioValidators = validators
+    portfolioSource = depositDataPath
+    portfolioPending = true
+    usePortfolioCapital()
+}
+
+// credentialNames describes how each kind of withdrawal credentials accrues rewards
+var credentialNames = map[string]string{
+    "0x01": "(skimming)",
+    "0x02": "(compounding)",
+}
+
+// shortPubkey abbreviates a pubkey for tables
+func shortPubkey(pubkey string) string {
+    if len(pubkey) <= 14 {
+        return pubkey
+    }
+    return pubkey[:8] + "…" + pubkey[len(pubkey)-4:]
+}
+
+// usePortfolioCapital sets --eth to the portfolio's stake unless it was given
+func usePortfolioCapital() {
+    if flag.CommandLine.Changed("eth") {
+        return
+    }
+    var staked uint64
+    for _, v := range portfolioValidators {
+        staked += v.Amount
+    }
+    capitalETH = float64(staked) / 1e9
+}
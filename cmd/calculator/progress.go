@@ -0,0 +1,103 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/mattn/go-isatty"
+)
+
+const (
+    // progressDelay is how long a run goes before progress is shown, so quick
+    // runs print nothing
+    progressDelay = time.Second
+    // progressBarWidth is the length of the progress bar on a terminal
+    progressBarWidth = 30
+)
+
+// progress reports how far a long run has got on stderr, with an estimate of
+// the time left. On a terminal it redraws a bar in place; otherwise, such as
+// in a log file, it prints a status line every progressInterval.
+type progress struct {
+    label    string
+    total    int
+    done     int
+    start    time.Time
+    last     time.Time
+    interval time.Duration
+    terminal bool
+    drawn    bool
+}
+
+// newProgress starts reporting progress through total steps, or returns nil
+// when progress is turned off. A nil progress ignores every call.
+func newProgress(label string, total int) *progress {
+    if noProgress || total <= 0 {
+        return nil
+    }
+
+    terminal := isatty.IsTerminal(os.Stderr.Fd()) || isatty.IsCygwinTerminal(os.Stderr.Fd())
+    interval := 10 * time.Second
+    if terminal {
+        interval = 200 * time.Millisecond
+    }
+
+    now := time.Now()
+    return &progress{
+        label:    label,
+        total:    total,
+        start:    now,
+        last:     now.Add(progressDelay - interval),
+        interval: interval,
+        terminal: terminal,
+    }
+}
+
+// step records n more completed steps
+func (p *progress) step(n int) {
+    if p == nil {
+        return
+    }
+    p.done = min(p.done+n, p.total)
+
+    now := time.Now()
+    if now.Sub(p.last) < p.interval {
+        return
+    }
+    p.last = now
+
+    percent := float64(p.done) / float64(p.total) * 100
+    status := fmt.Sprintf("%s: %d/%d (%.0f%%), %s", p.label, p.done, p.total, percent, p.eta(now))
+    if p.terminal {
+        filled := p.done * progressBarWidth / p.total
+        bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+        fmt.Fprintf(os.Stderr, "\r[%s] %s\033[K", bar, status)
+    } else {
+        fmt.Fprintln(os.Stderr, status)
+    }
+    p.drawn = true
+}
+
+// eta estimates the time left from the average time per step so far
+func (p *progress) eta(now time.Time) string {
+    if p.done == 0 {
+        return "ETA unknown"
+    }
+    elapsed := now.Sub(p.start)
+    remaining := time.Duration(float64(elapsed) / float64(p.done) * float64(p.total-p.done))
+    return "ETA " + remaining.Round(time.Second).String()
+}
+
+// finish clears the progress bar so the results start on a clean line
+func (p *progress) finish() {
+    if p == nil || !p.drawn {
+        return
+    }
+    if p.terminal {
+        fmt.Fprint(os.Stderr, "\r\033[K")
+    } else {
+        fmt.Fprintf(os.Stderr, "%s: done in %s\n", p.label, time.Since(p.start).Round(time.Second))
+    }
+}
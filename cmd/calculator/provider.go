@@ -0,0 +1,108 @@
+package main
+
+import (
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/lightclient"
+    "github.com/eth-rewards-calculator/internal/provider"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    flag "github.com/spf13/pflag"
+)
+
+// liveProvider is the source stateProvider returns, created on first use so
+// every caller shares its cache and endpoint health
+var (
+    liveProvider provider.StateProvider
+    liveFailover *provider.Failover
+)
+
+// stateProvider returns the source of live network conditions: the endpoints
+// from liveEndpoints, failing over between them. Answers are reused for
+// --state-ttl.
+func stateProvider() provider.StateProvider {
+    if liveProvider != nil {
+        return liveProvider
+    }
+
+    liveFailover = provider.NewFailover(liveEndpoints(), retries, retryBackoff)
+    var source provider.StateProvider = liveFailover
+    if stateTTL > 0 {
+        source = provider.NewCached(source, stateTTL)
+    }
+    liveProvider = source
+    return liveProvider
+}
+
+// liveEndpoints lists the sources of live conditions in order of preference:
+// the explorers at --explorer-url, then the beacon nodes at --beacon-url. The
+// default beacon node is left out when an explorer is given.
+func liveEndpoints() []provider.Endpoint {
+    var endpoints []provider.Endpoint
+    for _, u := range splitURLs(explorerURL) {
+        endpoints = append(endpoints, provider.Endpoint{Name: u, Source: provider.NewExplorer(u, explorerAPIKey)})
+    }
+    if len(endpoints) == 0 || flag.CommandLine.Changed("beacon-url") {
+        for _, u := range splitURLs(beaconURL) {
+            endpoints = append(endpoints, provider.Endpoint{Name: u, Source: beaconProvider(u)})
+        }
+    }
+    return endpoints
+}
+
+// beaconProvider reads the conditions from the beacon node at url, verifying
+// them against --checkpoint when given
+func beaconProvider(url string) provider.StateProvider {
+    if checkpoint == "" {
+        return provider.NewBeaconNode(url)
+    }
+    root, _ := lightclient.ParseRoot(checkpoint) // checked in main
+    return provider.NewVerified(url, root)
+}
+
+// primaryBeaconURL is the first --beacon-url, which commands that read a
+// particular validator or the chain spec talk to
+func primaryBeaconURL() string {
+    if urls := splitURLs(beaconURL); len(urls) > 0 {
+        return urls[0]
+    }
+    return beaconURL
+}
+
+// splitURLs splits a comma-separated list of URLs
+func splitURLs(list string) []string {
+    var urls []string
+    for _, u := range strings.Split(list, ",") {
+        if u = strings.TrimSpace(u); u != "" {
+            urls = append(urls, u)
+        }
+    }
+    return urls
+}
+
+// liveSource reports whether a source of live conditions was given on the
+// command line
+func liveSource() bool {
+    return explorerURL != "" || checkpoint != "" || flag.CommandLine.Changed("beacon-url")
+}
+
+// sourceName names the endpoint stateProvider last took conditions from, for
+// messages
+func sourceName() string {
+    stateProvider()
+    if checkpoint != "" {
+        return liveFailover.Current() + " (verified against the checkpoint)"
+    }
+    return liveFailover.Current()
+}
+
+// flagProvider is a fixed snapshot of the conditions given with -v, -p, and
+// the queue flags
+func flagProvider() provider.StateProvider {
+    return &provider.Static{State: types.NetworkSnapshot{
+        ActiveValidators: validatorCount,
+        Participation:    participation,
+        ActivationQueue:  activationQueue,
+        ExitQueue:        exitQueue,
+    }}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+func handleQueue() {
+    // With a beacon node or explorer and no -v, the live queues replace any not given
+    if validatorCount <= 0 && liveSource() {
+        fetchQueues()
+    }
+    requireValidators("queue")
+
+    if activationQueue < 0 || exitQueue < 0 {
+        fmt.Println("Error: Queue lengths must not be negative")
+        os.Exit(1)
+    }
+
+    queue := calculator.EstimateValidatorQueue(validatorCount, activationQueue, exitQueue)
+    recordRun(queue)
+
+    if printFormatted(queue) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+
+    header.Println("\n=== Validator Queues ===")
+
+    fmt.Printf("\nActive Validators: %s\n", formatNumber(uint64(queue.ActiveValidators)))
+
+    subheader.Println("\nActivation Queue:")
+    fmt.Printf("- Pending: %s validators\n", formatNumber(uint64(queue.Activation.Pending)))
+    if queue.Activation.Capped {
+        fmt.Printf("- Churn Limit: %d validators per epoch (capped)\n", queue.Activation.ChurnLimit)
+    } else {
+        fmt.Printf("- Churn Limit: %d validators per epoch\n", queue.Activation.ChurnLimit)
+    }
+    fmt.Printf("- Wait: %s days (%s epochs)\n", formatDecimal(queue.Activation.Days), formatDecimal(queue.Activation.Epochs))
+
+    subheader.Println("\nExit Queue:")
+    fmt.Printf("- Pending: %s validators\n", formatNumber(uint64(queue.Exit.Pending)))
+    fmt.Printf("- Churn Limit: %d validators per epoch\n", queue.Exit.ChurnLimit)
+    fmt.Printf("- Wait: %s days (%s epochs)\n", formatDecimal(queue.Exit.Days), formatDecimal(queue.Exit.Epochs))
+
+    subheader.Println("\nValidator Set:")
+    fmt.Printf("- Net Change with Both Queues Full: %+.0f validators per day\n\n", queue.NetChangePerDay)
+}
+
+// fetchQueues takes the active validator count and, unless given on the
+// command line, the activation and exit queue lengths from the beacon node or
+// explorer
+func fetchQueues() {
+    snapshot, err := stateProvider().Snapshot()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error fetching validator queues: %v\n", err)
+        os.Exit(1)
+    }
+
+    validatorCount = snapshot.ActiveValidators
+    if !flag.CommandLine.Changed("activation-queue") {
+        activationQueue = snapshot.ActivationQueue
+    }
+    if !flag.CommandLine.Changed("exit-queue") {
+        exitQueue = snapshot.ExitQueue
+    }
+}
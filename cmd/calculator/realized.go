@@ -0,0 +1,73 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/beacon"
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/config"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+func handleRealized() {
+    if !flag.CommandLine.Changed("validator-index") {
+        fmt.Println("Error: The realized command requires a validator index (--validator-index)")
+        os.Exit(1)
+    }
+
+    client := beacon.NewClient(primaryBeaconURL())
+    realized, err := client.GetRealizedRewards(validatorIndex, fromEpoch, toEpoch)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error fetching realized rewards: %v\n", err)
+        os.Exit(1)
+    }
+
+    recordRun(realized)
+
+    if printFormatted(realized) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+    highlight := color.New(color.FgGreen, color.Bold)
+
+    header.Println("\n=== Realized Validator Rewards ===")
+
+    epochs := realized.ToEpoch - realized.FromEpoch + 1
+    fmt.Printf("\nValidator Index: %d\n", realized.ValidatorIndex)
+    fmt.Printf("Epochs: %d - %d (%d epochs, %s days)\n",
+        realized.FromEpoch, realized.ToEpoch, epochs, formatDecimal(float64(epochs)/float64(config.EPOCHS_PER_DAY)))
+
+    subheader.Println("\nAttestations:")
+    fmt.Printf("- Source: %d Gwei\n", realized.SourceRewardsGwei)
+    fmt.Printf("- Target: %d Gwei\n", realized.TargetRewardsGwei)
+    fmt.Printf("- Head: %d Gwei\n", realized.HeadRewardsGwei)
+    fmt.Printf("- Inactivity: %d Gwei\n", realized.InactivityPenaltiesGwei)
+    fmt.Printf("- Total: %s ETH\n", formatGweiAsETH(float64(realized.AttestationRewardsGwei)))
+
+    subheader.Println("\nBlock Proposals:")
+    fmt.Printf("- Proposed: %d of %d scheduled\n", realized.ProposalsMade, realized.ProposalsScheduled)
+    fmt.Printf("- Rewards: %s ETH\n", formatGweiAsETH(float64(realized.BlockRewardsGwei)))
+
+    subheader.Println("\nSync Committee:")
+    fmt.Printf("- Epochs in Committee: %d\n", realized.SyncCommitteeEpochs)
+    fmt.Printf("- Rewards: %s ETH\n", formatGweiAsETH(float64(realized.SyncCommitteeRewardsGwei)))
+
+    highlight.Printf("\nTotal Realized Rewards: %s ETH\n", formatGweiAsETH(float64(realized.TotalRewardsGwei)))
+
+    // Compare against the calculator's projection for the same span
+    if validatorCount > 0 {
+        results := calculator.CalculateRewards(createNetworkState(validatorCount), participation)
+        projected := results.ConsensusRewardsAnnualGwei / float64(config.EPOCHS_PER_YEAR) * float64(epochs)
+
+        subheader.Println("\nProjection Accuracy:")
+        fmt.Printf("- Projected Consensus Rewards: %s ETH\n", formatGweiAsETH(projected))
+        fmt.Printf("- Realized / Projected: %s\n", formatPercent(float64(realized.TotalRewardsGwei)/projected*100))
+    }
+
+    fmt.Println()
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+func handleRestaking() {
+    requireValidators("restaking")
+
+    if restakingAPR < 0 {
+        fmt.Println("Error: Restaking APR must not be negative")
+        os.Exit(1)
+    }
+    if restakingSlashProb < 0 || restakingSlashProb > 1 || restakingSlashLoss < 0 || restakingSlashLoss > 1 {
+        fmt.Println("Error: Restaking slashing probability and loss must be between 0.0 and 1.0")
+        os.Exit(1)
+    }
+
+    results := projectRewards(createNetworkState(validatorCount))
+
+    comparison := &types.SetupComparison{
+        Capital: capitalETH,
+        Setups: []types.StakingSetup{
+            calculator.SoloStakingSetup(results, capitalETH),
+            calculator.RestakedSetup(results, capitalETH, restakingAPR, restakingSlashProb, restakingSlashLoss),
+        },
+    }
+
+    outputSetups("Restaking Yield Stacking", comparison)
+
+    if textOutput {
+        fmt.Printf("Restaking: %s APR, %s annual slashing probability, %s of stake lost if slashed\n\n",
+            formatPercent(restakingAPR), formatProbability(restakingSlashProb), formatPercent(restakingSlashLoss*100))
+    }
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "strconv"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/publish"
+)
+
+// scheduleHeader is the CSV header for scheduled results
+var scheduleHeader = []string{"timestamp", "epoch", "validators", "participation", "apy_percentage",
+    "risk_adjusted_apy", "consensus_rewards_eth", "execution_rewards_eth", "total_rewards_eth"}
+
+// runScheduled recomputes the projection every --every and writes one CSV row
+// (or, with --format influx, one line protocol line) per run, appended to
+// --append or written to stdout, and published to --publish if set. With
+// --live the validator count and participation come from the beacon node
+// each time.
+func runScheduled() {
+    if every < 0 {
+        fmt.Println("Error: --every must not be negative")
+        os.Exit(1)
+    }
+    if !live && validatorCount == 0 {
+        fmt.Println("Error: Scheduled runs need a validator count (-v) or --live")
+        os.Exit(1)
+    }
+
+    var out io.Writer = os.Stdout
+    writeHeader := true
+    if appendPath != "" {
+        f, err := os.OpenFile(appendPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "Error opening results file: %v\n", err)
+            os.Exit(1)
+        }
+        defer f.Close()
+
+        // Only a new file gets the header
+        info, err := f.Stat()
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "Error opening results file: %v\n", err)
+            os.Exit(1)
+        }
+        writeHeader = info.Size() == 0
+        out = f
+    }
+
+    var publisher publish.Publisher
+    var topic string
+    if publishURL != "" {
+        var err error
+        if publisher, err = publish.Open(publishURL); err != nil {
+            fmt.Printf("Error: %v\n", err)
+            os.Exit(1)
+        }
+        defer publisher.Close()
+        topic = publish.Topic(publishTopic, networkName())
+    }
+
+    influx := outputFormat == "influx"
+    writer := csv.NewWriter(out)
+    if writeHeader && !influx {
+        writeScheduleRow(writer, scheduleHeader)
+    }
+
+    for {
+        row, err := scheduledRow()
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "Error fetching network snapshot: %v\n", err)
+            // A one-off run fails; a schedule skips the run and tries again
+            if every == 0 {
+                os.Exit(1)
+            }
+        } else if influx {
+            writeScheduleInflux(out, row)
+        } else {
+            writeScheduleRow(writer, row)
+        }
+        if err == nil && publisher != nil {
+            if err := publishScheduled(publisher, topic, row); err != nil {
+                fmt.Fprintf(os.Stderr, "Error publishing results: %v\n", err)
+                if every == 0 {
+                    os.Exit(1)
+                }
+            }
+        }
+        if every == 0 {
+            return
+        }
+        time.Sleep(every)
+    }
+}
+
+// scheduledRow computes one projection and formats it as a CSV row
+func scheduledRow() ([]string, error) {
+    count, rate, epoch := validatorCount, participation, ""
+    if live {
+        snapshot, err := stateProvider().Snapshot()
+        if err != nil {
+            return nil, err
+        }
+        count, rate = snapshot.ActiveValidators, snapshot.Participation
+        epoch = strconv.FormatUint(snapshot.Epoch, 10)
+    }
+
+    results := projectRewardsAt(createNetworkState(count), rate)
+    recordRun(results)
+
+    return []string{
+        time.Now().UTC().Format(time.RFC3339),
+        epoch,
+        strconv.Itoa(count),
+        strconv.FormatFloat(roundTo(rate, precision), 'f', -1, 64),
+        strconv.FormatFloat(roundTo(results.APY, precision), 'f', -1, 64),
+        strconv.FormatFloat(roundTo(results.RiskAdjustedAPY, precision), 'f', -1, 64),
+        formatGweiAsETH(results.ConsensusRewardsAnnualGwei),
+        formatGweiAsETH(results.ExecutionRewardsAnnualGwei),
+        formatGweiAsETH(results.TotalAnnualRewardsGwei),
+    }, nil
+}
+
+// writeScheduleRow writes and flushes one row, so each run is on disk at once
+func writeScheduleRow(writer *csv.Writer, row []string) {
+    writer.Write(row)
+    writer.Flush()
+    if err := writer.Error(); err != nil {
+        fmt.Fprintf(os.Stderr, "Error writing results: %v\n", err)
+        os.Exit(1)
+    }
+}
+
+// writeScheduleInflux writes one row as a line protocol line at the time of
+// the run, leaving out the epoch when there is none
+func writeScheduleInflux(out io.Writer, row []string) {
+    at, err := time.Parse(time.RFC3339, row[0])
+    if err != nil {
+        at = time.Now()
+    }
+    if err := writeInfluxLine(out, influxTags(), scheduleFields(row), at); err != nil {
+        fmt.Fprintf(os.Stderr, "Error writing results: %v\n", err)
+        os.Exit(1)
+    }
+}
+
+// publishScheduled publishes one row to the bus as a JSON object, with the
+// network and scenario it was computed for
+func publishScheduled(publisher publish.Publisher, topic string, row []string) error {
+    message := object{{"timestamp", row[0]}, {"network", networkName()}}
+    if scenario != "" {
+        message = append(message, field{"scenario", scenario})
+    }
+    data, err := json.Marshal(append(message, scheduleFields(row)...))
+    if err != nil {
+        return err
+    }
+    return publisher.Publish(topic, data)
+}
+
+// scheduleFields returns the numbers of a row by their header names, leaving
+// out the timestamp and, when there is none, the epoch
+func scheduleFields(row []string) object {
+    fields := object{}
+    for i, value := range row[1:] {
+        if value != "" {
+            fields = append(fields, field{scheduleHeader[i+1], json.Number(value)})
+        }
+    }
+    return fields
+}
+
+// networkName names the chain results are computed for: --network, or the
+// preset when it is not set
+func networkName() string {
+    if network != "" {
+        return network
+    }
+    return preset
+}
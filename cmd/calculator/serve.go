@@ -0,0 +1,167 @@
+package main
+
+import (
+    "embed"
+    "encoding/json"
+    "fmt"
+    "io/fs"
+    "net/http"
+    "os"
+    "strconv"
+
+    "github.com/eth-rewards-calculator/internal/bot"
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/provider"
+)
+
+// webFiles is the single-page calculator served at /
+//
+//go:embed web
+var webFiles embed.FS
+
+// rewardsCache holds /api/rewards responses by validator count and participation
+var rewardsCache *responseCache
+
+// serveSource supplies the validator count and participation for requests
+// that leave them out: the live network with --beacon-url or --explorer-url,
+// otherwise -v and -p
+var serveSource provider.StateProvider
+
+func handleServe() {
+    web, err := fs.Sub(webFiles, "web")
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error loading web files: %v\n", err)
+        os.Exit(1)
+    }
+
+    if cacheSize < 0 || rateLimit < 0 || rateBurst < 1 {
+        fmt.Println("Error: Cache size and rate limit must not be negative, and the burst must be at least 1")
+        os.Exit(1)
+    }
+    rewardsCache = newResponseCache(cacheSize)
+    serveSource = flagProvider()
+    if liveSource() {
+        serveSource = stateProvider()
+    }
+
+    api, grafana := serveRewards, serveGrafana
+    if rateLimit > 0 {
+        limiter := newRateLimiter(rateLimit, rateBurst)
+        api, grafana = limiter.limit(api), limiter.limit(grafana)
+    }
+
+    mux := http.NewServeMux()
+    mux.Handle("/", http.FileServer(http.FS(web)))
+    mux.HandleFunc("/api/rewards", api)
+    mux.HandleFunc("/grafana", grafana)
+    mux.HandleFunc("/grafana/", grafana)
+    mux.HandleFunc("/openapi.json", serveOpenAPI)
+    if secret := os.Getenv("SLACK_SIGNING_SECRET"); secret != "" {
+        mux.Handle("/slack", bot.NewSlackHandler(secret, newChatBot()))
+        fmt.Println("Answering Slack slash commands at /slack")
+    }
+
+    fmt.Printf("Serving the calculator on http://%s (API at /api/rewards, described by /openapi.json; Grafana JSON datasource at /grafana)\n", listenAddr)
+    if err := http.ListenAndServe(listenAddr, mux); err != nil {
+        fmt.Fprintf(os.Stderr, "Error serving: %v\n", err)
+        os.Exit(1)
+    }
+}
+
+// serveOpenAPI answers GET /openapi.json with the OpenAPI document for the API
+func serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    document, err := openAPIDocument()
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    output, err := json.MarshalIndent(document, "", "  ")
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.Write(output)
+}
+
+// serveRewards answers GET /api/rewards?validators=N&participation=P with the
+// same JSON as the command line. Missing parameters come from serveSource;
+// the other flags (fees, MEV, risk) apply to every request.
+func serveRewards(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    query := r.URL.Query()
+    count, rate := validatorCount, participation
+    if query.Get("validators") == "" || query.Get("participation") == "" {
+        snapshot, err := serveSource.Snapshot()
+        if err != nil {
+            http.Error(w, fmt.Sprintf("fetching network conditions: %v", err), http.StatusBadGateway)
+            return
+        }
+        count, rate = snapshot.ActiveValidators, snapshot.Participation
+    }
+
+    if value := query.Get("validators"); value != "" {
+        parsed, err := strconv.Atoi(value)
+        if err != nil {
+            http.Error(w, "invalid validators", http.StatusBadRequest)
+            return
+        }
+        count = parsed
+    }
+    state, err := createTemplateState(count)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    if value := query.Get("participation"); value != "" {
+        parsed, err := strconv.ParseFloat(value, 64)
+        if err != nil {
+            http.Error(w, "invalid participation", http.StatusBadRequest)
+            return
+        }
+        rate = parsed
+    }
+    if rate <= 0 || rate > 1 {
+        http.Error(w, "participation must be between 0.0 and 1.0", http.StatusBadRequest)
+        return
+    }
+
+    // The other inputs are fixed for the server's lifetime, so these two
+    // identify the response
+    key := fmt.Sprintf("%d/%g", count, rate)
+    output, cached := rewardsCache.get(key)
+    if !cached {
+        output, err = marshalJSON(projectTemplateRewardsAt(state, count, rate))
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        rewardsCache.put(key, output)
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    if cached {
+        w.Header().Set("X-Cache", "HIT")
+    } else {
+        w.Header().Set("X-Cache", "MISS")
+    }
+    w.Write(output)
+}
+
+// checkServeCount rejects a validator count beyond the ETH supply
+func checkServeCount(count int) error {
+    if count <= 0 || count > calculator.MaxValidators() {
+        return fmt.Errorf("validators must be between 1 and %d", calculator.MaxValidators())
+    }
+    return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+// histogramWidth is the length of the longest histogram bar
+const histogramWidth = 50
+
+func handleSimulate() {
+    requireValidators("simulate")
+    if trials <= 0 || bins <= 0 {
+        fmt.Println("Error: Trials and bins must be greater than 0")
+        os.Exit(1)
+    }
+    if !flag.CommandLine.Changed("seed") {
+        seed = time.Now().UnixNano()
+    }
+
+    results := projectRewards(createNetworkState(validatorCount))
+    bar := newProgress("Simulating", trials)
+    if parquetOutput {
+        // One row per trial, in the order drawn, rather than the summary
+        simulation, drawn := calculator.SimulateAnnualRewardsTrials(results, trials, bins, seed, bar.step)
+        bar.finish()
+        recordRun(simulation)
+
+        rows := make([]simulationRow, len(drawn))
+        for i, trial := range drawn {
+            rows[i] = newSimulationRow(simulation.Seed, trial)
+        }
+        printParquet(rows)
+        return
+    }
+    simulation := calculator.SimulateAnnualRewardsWithProgress(results, trials, bins, seed, bar.step)
+    bar.finish()
+    recordRun(simulation)
+
+    if printFormatted(simulation) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+
+    header.Println("\n=== Simulated Annual Rewards ===")
+
+    fmt.Printf("\nNetwork Validators: %s\n", formatNumber(uint64(validatorCount)))
+    fmt.Printf("Trials: %s (seed %d)\n", formatNumber(uint64(simulation.Trials)), simulation.Seed)
+
+    subheader.Println("\nDistribution:")
+    fmt.Printf("- Mean: %s ETH\n", formatETH(simulation.Mean))
+    fmt.Printf("- Standard Deviation: %s ETH\n", formatETH(simulation.StdDev))
+    fmt.Printf("- Range: %s - %s ETH\n", formatETH(simulation.Min), formatETH(simulation.Max))
+    for _, p := range simulation.Percentiles {
+        fmt.Printf("- P%g: %s ETH\n", p.Percentile, formatETH(p.Rewards))
+    }
+
+    subheader.Println("\nHistogram (ETH per year):")
+    printHistogram(simulation.Histogram)
+    fmt.Println()
+}
+
+func printHistogram(histogram []types.HistogramBin) {
+    largest := 0
+    for _, bin := range histogram {
+        largest = max(largest, bin.Count)
+    }
+
+    for _, bin := range histogram {
+        bar := strings.Repeat("#", bin.Count*histogramWidth/largest)
+        fmt.Printf("%12s - %-12s %-*s %s\n",
+            formatETH(bin.Lower), formatETH(bin.Upper), histogramWidth, bar, formatPercent(bin.Share*100))
+    }
+}
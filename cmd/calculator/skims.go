@@ -0,0 +1,67 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+
+    "github.com/fatih/color"
+)
+
+func handleSkims() {
+    requireValidators("skims")
+    if forecastMonths <= 0 {
+        fmt.Println("Error: Months must be greater than 0")
+        os.Exit(1)
+    }
+
+    results := projectRewards(createNetworkState(validatorCount))
+    projection := calculator.ProjectSkims(results, time.Now().UTC(), float64(forecastMonths)*365.25/12)
+    recordRun(projection)
+
+    if parquetOutput {
+        rows := make([]skimRow, len(projection.Calendar))
+        for i, event := range projection.Calendar {
+            rows[i] = newSkimRow(event)
+        }
+        printParquet(rows)
+        return
+    }
+
+    if printFormatted(projection) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+
+    header.Println("\n=== Partial Withdrawal Skims ===")
+
+    fmt.Printf("\nNetwork Validators: %s\n", formatNumber(uint64(validatorCount)))
+
+    subheader.Println("\nWithdrawal Sweep:")
+    fmt.Printf("- Full Sweep Cycle: %s days\n", formatDecimal(projection.CycleDays))
+    fmt.Printf("- Skims per Year: %s\n", formatDecimal(projection.SkimsPerYear))
+    fmt.Printf("- Amount per Skim: %s ETH\n", formatETH(projection.AmountPerSkim))
+    fmt.Printf("- Skimmed per Year: %s ETH\n", formatETH(projection.AnnualSkimmed))
+
+    subheader.Println("\nBalance Between Skims:")
+    fmt.Printf("- Peak Balance: %s ETH\n", formatETH(projection.Balance.PeakBalance))
+    fmt.Printf("- Average Balance: %s ETH\n", formatETH(projection.Balance.AverageBalance))
+    fmt.Printf("- APY on 32 ETH: %s\n", formatPercent(projection.Balance.NaiveAPY))
+    fmt.Printf("- APY on Average Balance: %s (%s)\n",
+        formatPercent(projection.Balance.EffectiveAPY), formatSignedPercent(projection.Balance.APYDifference))
+
+    subheader.Printf("\nExpected Skims (next %d months):\n", forecastMonths)
+    fmt.Printf("%-6s %-12s %-12s %-14s %-14s\n", "#", "Date", "Day", "Amount ETH", "Cumulative ETH")
+    fmt.Println(strings.Repeat("-", 62))
+    for _, event := range projection.Calendar {
+        fmt.Printf("%-6d %-12s %-12s %-14s %-14s\n",
+            event.Number, event.Date, formatDecimal(event.Days), formatETH(event.Amount), formatETH(event.Cumulative))
+    }
+
+    fmt.Println()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+)
+
+func handleSlashing() {
+    requireValidators("slashing")
+
+    if slashingCount <= 0 {
+        fmt.Println("Error: Please specify the number of validators slashed together with -s")
+        os.Exit(1)
+    }
+    if slashingCount > validatorCount {
+        fmt.Println("Error: Slashed validators (-s) must not exceed the validator count (-v)")
+        os.Exit(1)
+    }
+    if !config.IsKnownFork(fork) {
+        fmt.Printf("Error: Unknown fork %q (known forks: %s)\n", fork, strings.Join(config.KnownForks, ", "))
+        os.Exit(1)
+    }
+
+    state := createNetworkState(validatorCount)
+    state.CurrentFork = fork
+    impact := calculator.EstimateSlashingImpact(state, slashingCount)
+    recordRun(impact)
+
+    if printFormatted(impact) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+    warning := color.New(color.FgRed, color.Bold)
+
+    header.Println("\n=== Slashing Impact ===")
+
+    fmt.Printf("\nNetwork Validators: %s\n", formatNumber(uint64(validatorCount)))
+    fmt.Printf("Fork Rules: %s\n", impact.Fork)
+    fmt.Printf("Slashed Together: %s validators (%s ETH, %s of stake)\n",
+        formatNumber(uint64(impact.SlashedValidatorCount)), formatETH(impact.SlashedBalance),
+        formatPercent(impact.NetworkPercentage))
+
+    subheader.Println("\nPenalty per Validator:")
+    fmt.Printf("%-14s %-16s %-18s %-16s %-12s\n", "", "Initial ETH", "Proportional ETH", "Total ETH", "Of Stake")
+    fmt.Println(strings.Repeat("-", 80))
+    for _, row := range []struct {
+        label   string
+        penalty types.SlashingPenalty
+    }{
+        {"Alone", impact.SingleValidatorPenalty},
+        {"Correlated", impact.CorrelatedPenalty},
+    } {
+        fmt.Printf("%-14s %-16s %-18s %-16s %-12s\n", row.label, formatETH(row.penalty.Initial),
+            formatETH(row.penalty.Proportional), formatETH(row.penalty.Total), formatPercent(row.penalty.Percentage))
+    }
+
+    subheader.Println("\nNetwork Impact:")
+    fmt.Printf("- Total Penalties: %s ETH\n", formatETH(impact.NetworkImpact.TotalPenalties))
+    fmt.Printf("- Stake Removed: %s ETH\n", formatETH(impact.NetworkImpact.ReducedStaking))
+    warning.Printf("- Security Impact: %s\n\n", impact.NetworkImpact.SecurityImpact)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/beacon"
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/config"
+
+    flag "github.com/spf13/pflag"
+)
+
+// loadBeaconSpec replaces the mainnet constants with the chain spec reported
+// by the beacon node, exiting on failure
+func loadBeaconSpec() {
+    spec, err := beacon.NewClient(primaryBeaconURL()).GetSpec()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error loading chain spec: %v\n", err)
+        os.Exit(1)
+    }
+
+    applied, err := config.ApplySpec(spec)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error loading chain spec: %v\n", err)
+        os.Exit(1)
+    }
+
+    fmt.Fprintf(os.Stderr, "Loaded %d chain parameters from %s\n", len(applied), primaryBeaconURL())
+}
+
+// loadChainConfig applies the --chain-config file, exiting on failure
+func loadChainConfig() {
+    applied, err := config.LoadChainConfig(chainConfig)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error loading chain config: %v\n", err)
+        os.Exit(1)
+    }
+
+    fmt.Fprintf(os.Stderr, "Loaded %d chain parameters from %s\n", len(applied), chainConfig)
+}
+
+// applyAsOf takes the validator count and participation from the mainnet
+// history on the --as-of date, unless they were given, exiting on failure
+func applyAsOf() {
+    date, err := time.Parse("2006-01-02", asOf)
+    if err != nil {
+        fmt.Printf("Error: Invalid date '%s' (use YYYY-MM-DD)\n", asOf)
+        os.Exit(1)
+    }
+    sample, err := calculator.NetworkAsOf(date)
+    if err != nil {
+        fmt.Printf("Error: %v\n", err)
+        os.Exit(1)
+    }
+
+    if !flag.CommandLine.Changed("validators") {
+        validatorCount = sample.ActiveValidators
+    }
+    if !flag.CommandLine.Changed("participation") {
+        participation = sample.Participation
+    }
+    fmt.Fprintf(os.Stderr, "Using mainnet as of %s: %s validators, %s participation\n",
+        sample.Date, formatNumber(uint64(sample.ActiveValidators)), formatPercent(sample.Participation*100))
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+func handleSSV() {
+    requireValidators("ssv")
+
+    if ssvOperators <= 0 {
+        fmt.Println("Error: An SSV cluster needs at least one operator")
+        os.Exit(1)
+    }
+    if ssvOperatorFee < 0 || ssvNetworkFee < 0 || ssvPrice < 0 {
+        fmt.Println("Error: SSV fees and price must not be negative")
+        os.Exit(1)
+    }
+
+    results := projectRewards(createNetworkState(validatorCount))
+
+    comparison := &types.SetupComparison{
+        Capital: capitalETH,
+        Setups: []types.StakingSetup{
+            calculator.SoloStakingSetup(results, capitalETH),
+            calculator.SSVSetup(results, capitalETH, ssvOperators, ssvOperatorFee, ssvNetworkFee, ssvPrice),
+        },
+    }
+
+    outputSetups("SSV Operator Fees", comparison)
+
+    if textOutput {
+        perValidator := float64(ssvOperators)*ssvOperatorFee + ssvNetworkFee
+        fmt.Printf("SSV fees per validator: %s SSV/year (%d operators x %s + %s network) = %s ETH/year at %s ETH/SSV\n\n",
+            formatDecimal(perValidator), ssvOperators, formatDecimal(ssvOperatorFee), formatDecimal(ssvNetworkFee),
+            formatETH(perValidator*ssvPrice), formatETH(ssvPrice))
+    }
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+    "compress/gzip"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/beacon"
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/lightclient"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    flag "github.com/spf13/pflag"
+)
+
+// stateFileVersion is the StateFile layout state export writes
+const stateFileVersion = 1
+
+func handleState() {
+    if flag.NArg() != 3 || (flag.Arg(1) != "export" && flag.Arg(1) != "import") {
+        fmt.Println("Error: Use 'state export FILE' to save a network state or 'state import FILE' to analyze a saved one")
+        os.Exit(1)
+    }
+    if flag.Arg(1) == "export" {
+        exportState(flag.Arg(2))
+    } else {
+        importState(flag.Arg(2))
+    }
+}
+
+// exportState saves the finalized state of the beacon node at --beacon-url,
+// verified with --checkpoint when given, or the modelled state of -v
+// validators
+func exportState(path string) {
+    var state *types.NetworkState
+    var source string
+    var err error
+    switch {
+    case checkpoint != "":
+        root, _ := lightclient.ParseRoot(checkpoint) // checked in main
+        source = primaryBeaconURL() + " (verified against checkpoint " + checkpoint + ")"
+        state, err = lightclient.New(beacon.NewClient(primaryBeaconURL()), root).State()
+    case flag.CommandLine.Changed("beacon-url"):
+        source = primaryBeaconURL()
+        state, err = beacon.NewClient(primaryBeaconURL()).GetNetworkState()
+    case validatorCount > 0:
+        source = fmt.Sprintf("model of %d validators", validatorCount)
+        state = createNetworkState(validatorCount)
+    default:
+        fmt.Println("Error: state export needs --beacon-url to capture a live state, or -v for a modelled one")
+        os.Exit(1)
+    }
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error capturing network state: %v\n", err)
+        os.Exit(1)
+    }
+
+    file := types.StateFile{
+        Version:    stateFileVersion,
+        CapturedAt: time.Now().UTC(),
+        Source:     source,
+        State:      *state,
+    }
+    if err := writeStateFile(path, &file); err != nil {
+        fmt.Fprintf(os.Stderr, "Error writing state: %v\n", err)
+        os.Exit(1)
+    }
+    fmt.Fprintf(os.Stderr, "Saved %s active validators at epoch %d from %s\n",
+        formatNumber(uint64(len(state.Validators))), state.CurrentEpoch, source)
+}
+
+// importState loads a saved state and projects rewards on it as the default
+// calculation does. Without -p, a state with participation flags supplies
+// the participation rate.
+func importState(path string) {
+    file, err := readStateFile(path)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error reading state: %v\n", err)
+        os.Exit(1)
+    }
+    state := &file.State
+    if err := state.Validate(); err != nil {
+        fmt.Printf("Error: Invalid network state in %s: %v\n", path, err)
+        os.Exit(1)
+    }
+
+    validatorCount = len(state.Validators)
+    if !flag.CommandLine.Changed("participation") && state.PreviousEpochParticipation != nil {
+        participation = timelyTargetShare(state)
+    }
+    if file.CapturedAt.IsZero() {
+        fmt.Fprintf(os.Stderr, "Loaded %s active validators at epoch %d from %s\n",
+            formatNumber(uint64(validatorCount)), state.CurrentEpoch, file.Source)
+    } else {
+        fmt.Fprintf(os.Stderr, "Loaded %s active validators at epoch %d, captured %s from %s\n",
+            formatNumber(uint64(validatorCount)), state.CurrentEpoch, file.CapturedAt.Format(time.RFC3339), file.Source)
+    }
+
+    results := projectRewards(state)
+    recordRun(results)
+    if !printFormatted(results) {
+        outputFormatted(results, state, detailed)
+    }
+    if showPenalties {
+        showPenaltyExamples(state)
+    }
+}
+
+// timelyTargetShare is the share of the state's validators that earned the
+// timely target flag in the previous epoch
+func timelyTargetShare(state *types.NetworkState) float64 {
+    timely := 0
+    for _, flags := range state.PreviousEpochParticipation {
+        if flags>>config.TIMELY_TARGET_FLAG_INDEX&1 == 1 {
+            timely++
+        }
+    }
+    return float64(timely) / float64(len(state.PreviousEpochParticipation))
+}
+
+// isSSZ reports whether path names an SSZ beacon state rather than a JSON
+// state file
+func isSSZ(path string) bool {
+    return strings.HasSuffix(strings.TrimSuffix(path, ".gz"), ".ssz")
+}
+
+// writeStateFile writes file to path, - for stdout, as JSON or, for a .ssz
+// path, as an SSZ beacon state, compressing it when path ends in .gz
+func writeStateFile(path string, file *types.StateFile) error {
+    var w io.Writer = os.Stdout
+    if path != "-" {
+        f, err := os.Create(path)
+        if err != nil {
+            return err
+        }
+        defer f.Close()
+        w = f
+    }
+    if strings.HasSuffix(path, ".gz") {
+        gz := gzip.NewWriter(w)
+        defer gz.Close()
+        w = gz
+    }
+    if isSSZ(path) {
+        data, err := file.State.MarshalSSZ()
+        if err != nil {
+            return err
+        }
+        _, err = w.Write(data)
+        return err
+    }
+    return json.NewEncoder(w).Encode(file)
+}
+
+// readStateFile reads a state written by writeStateFile, or any SSZ beacon
+// state, from path, - for stdin
+func readStateFile(path string) (*types.StateFile, error) {
+    var r io.Reader = os.Stdin
+    if path != "-" {
+        f, err := os.Open(path)
+        if err != nil {
+            return nil, err
+        }
+        defer f.Close()
+        r = f
+    }
+    if strings.HasSuffix(path, ".gz") {
+        gz, err := gzip.NewReader(r)
+        if err != nil {
+            return nil, err
+        }
+        defer gz.Close()
+        r = gz
+    }
+    if isSSZ(path) {
+        data, err := io.ReadAll(r)
+        if err != nil {
+            return nil, err
+        }
+        file := types.StateFile{Version: stateFileVersion, Source: path}
+        if err := file.State.UnmarshalSSZ(data); err != nil {
+            return nil, err
+        }
+        return &file, nil
+    }
+
+    var file types.StateFile
+    if err := json.NewDecoder(r).Decode(&file); err != nil {
+        return nil, err
+    }
+    if file.Version != stateFileVersion {
+        return nil, fmt.Errorf("unsupported state file version %d (expected %d)", file.Version, stateFileVersion)
+    }
+    return &file, nil
+}
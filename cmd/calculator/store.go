@@ -0,0 +1,59 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/store"
+
+    flag "github.com/spf13/pflag"
+)
+
+// runStore is the database opened with --store, or nil
+var runStore *store.Store
+
+// openStore opens the --store database, exiting on failure
+func openStore() {
+    if storePath == "" {
+        return
+    }
+
+    var err error
+    runStore, err = store.Open(storePath)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Error opening run store: %v\n", err)
+        os.Exit(1)
+    }
+}
+
+// recordRun saves the invocation's flag values and results when --store is set
+func recordRun(results interface{}) {
+    if runStore == nil {
+        return
+    }
+
+    inputs := make(map[string]string)
+    flag.VisitAll(func(f *flag.Flag) {
+        if f.Name != "store" {
+            inputs[f.Name] = f.Value.String()
+        }
+    })
+
+    if _, err := runStore.Record(runCommandName(), os.Args[1:], inputs, results); err != nil {
+        fmt.Fprintf(os.Stderr, "Warning: Could not record run: %v\n", err)
+    }
+}
+
+// runCommandName names what this invocation runs: the command, or the kind of
+// rewards projection when there is none
+func runCommandName() string {
+    switch {
+    case flag.NArg() > 0:
+        return flag.Arg(0)
+    case compare != "":
+        return "compare"
+    case compareParticipation:
+        return "compare-participation"
+    }
+    return "rewards"
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// sweepFilter is one --filter condition, such as apy>3.0
+type sweepFilter struct {
+    metric    string
+    operator  string
+    threshold float64
+}
+
+// sweepOptions are the --sort-by and --filter settings for a sweep
+type sweepOptions struct {
+    sortBy     string
+    descending bool
+    filters    []sweepFilter
+}
+
+// filterOperators are the comparisons --filter accepts, longest first so
+// >= is not read as >
+var filterOperators = []string{">=", "<=", "!=", "==", ">", "<", "="}
+
+// sweepMetric returns the named number of a sweep's results: the validator
+// count, the participation rate and multiplier, the effective APY, or any
+// --columns metric
+func sweepMetric(name string) (func(*types.RewardResults) float64, bool) {
+    switch name {
+    case "validators":
+        return func(r *types.RewardResults) float64 { return float64(r.ValidatorCount) }, true
+    case "participation":
+        return func(r *types.RewardResults) float64 { return r.ParticipationRate }, true
+    case "multiplier":
+        return func(r *types.RewardResults) float64 { return r.ParticipationMultiplier }, true
+    case "effective_apy":
+        return func(r *types.RewardResults) float64 { return r.EffectiveAPY }, true
+    }
+    for _, column := range comparisonColumns {
+        if column.name == name {
+            column := column
+            return func(r *types.RewardResults) float64 {
+                switch value := column.value(r).(type) {
+                case uint64:
+                    return float64(value)
+                case float64:
+                    return value
+                }
+                return 0
+            }, true
+        }
+    }
+    return nil, false
+}
+
+// sweepMetricNames lists the names sweepMetric accepts, for error messages
+func sweepMetricNames() string {
+    names := []string{"validators", "participation", "multiplier", "effective_apy"}
+    for _, column := range comparisonColumns {
+        names = append(names, column.name)
+    }
+    return strings.Join(names, ", ")
+}
+
+// parseSweepOptions reads --sort-by and --filter, exiting on an unknown
+// metric or a malformed condition
+func parseSweepOptions() sweepOptions {
+    var options sweepOptions
+    if sortBy != "" {
+        options.sortBy = sortBy
+        if name, order, ok := strings.Cut(sortBy, ":"); ok {
+            if order != "asc" && order != "desc" {
+                fmt.Printf("Error: Unknown sort order '%s' (use asc or desc)\n", order)
+                os.Exit(1)
+            }
+            options.sortBy, options.descending = name, order == "desc"
+        }
+        if _, ok := sweepMetric(options.sortBy); !ok {
+            fmt.Printf("Error: Unknown metric '%s' to sort by (use %s)\n", options.sortBy, sweepMetricNames())
+            os.Exit(1)
+        }
+    }
+
+    if filter == "" {
+        return options
+    }
+    for _, condition := range strings.Split(filter, ",") {
+        condition = strings.TrimSpace(condition)
+        at := strings.IndexAny(condition, "<>=!")
+        if at <= 0 {
+            fmt.Printf("Error: Filter '%s' must be a metric, a comparison, and a number, such as apy>3.0\n", condition)
+            os.Exit(1)
+        }
+        parsed := sweepFilter{metric: strings.TrimSpace(condition[:at])}
+        for _, operator := range filterOperators {
+            if strings.HasPrefix(condition[at:], operator) {
+                parsed.operator = operator
+                break
+            }
+        }
+        threshold, err := strconv.ParseFloat(strings.TrimSpace(condition[at+len(parsed.operator):]), 64)
+        if parsed.operator == "" || err != nil {
+            fmt.Printf("Error: Filter '%s' must be a metric, a comparison, and a number, such as apy>3.0\n", condition)
+            os.Exit(1)
+        }
+        if _, ok := sweepMetric(parsed.metric); !ok {
+            fmt.Printf("Error: Unknown metric '%s' to filter on (use %s)\n", parsed.metric, sweepMetricNames())
+            os.Exit(1)
+        }
+        parsed.threshold = threshold
+        options.filters = append(options.filters, parsed)
+    }
+    return options
+}
+
+// apply keeps the results that pass every filter, sorted as selected
+func (o sweepOptions) apply(sweep []*types.RewardResults) []*types.RewardResults {
+    var kept []*types.RewardResults
+    for _, results := range sweep {
+        if o.matches(results) {
+            kept = append(kept, results)
+        }
+    }
+
+    if o.sortBy != "" {
+        metric, _ := sweepMetric(o.sortBy)
+        sort.SliceStable(kept, func(i, j int) bool {
+            if o.descending {
+                return metric(kept[i]) > metric(kept[j])
+            }
+            return metric(kept[i]) < metric(kept[j])
+        })
+    }
+    return kept
+}
+
+// matches reports whether results pass every filter
+func (o sweepOptions) matches(results *types.RewardResults) bool {
+    for _, f := range o.filters {
+        metric, _ := sweepMetric(f.metric)
+        value := metric(results)
+        var ok bool
+        switch f.operator {
+        case ">":
+            ok = value > f.threshold
+        case ">=":
+            ok = value >= f.threshold
+        case "<":
+            ok = value < f.threshold
+        case "<=":
+            ok = value <= f.threshold
+        case "=", "==":
+            ok = value == f.threshold
+        case "!=":
+            ok = value != f.threshold
+        }
+        if !ok {
+            return false
+        }
+    }
+    return true
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+
+    "github.com/fatih/color"
+)
+
+func handleTarget() {
+    requireValidators("target")
+
+    if (targetETH > 0) == (targetUSD > 0) || targetETH < 0 || targetUSD < 0 {
+        fmt.Println("Error: Give a positive monthly income with either --target-eth or --target-usd")
+        os.Exit(1)
+    }
+    if targetUSD > 0 && ethPrice <= 0 {
+        fmt.Println("Error: --target-usd needs a positive --eth-price")
+        os.Exit(1)
+    }
+
+    monthly := targetETH
+    if targetUSD > 0 {
+        monthly = targetUSD / ethPrice
+    }
+
+    results := projectRewards(createNetworkState(validatorCount))
+    target := calculator.SolveTargetIncome(results, monthly)
+    if targetUSD > 0 {
+        target.MonthlyTargetUSD = targetUSD
+        target.ETHPrice = ethPrice
+    }
+    if target.Validators == 0 {
+        fmt.Printf("Error: Net APY is %s, so no stake reaches the target\n", formatPercent(target.NetAPY))
+        os.Exit(1)
+    }
+    recordRun(target)
+
+    if printFormatted(target) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+    highlight := color.New(color.FgGreen, color.Bold)
+
+    header.Println("\n=== Target Income ===")
+
+    fmt.Printf("\nNetwork Validators: %s\n", formatNumber(uint64(validatorCount)))
+    fmt.Printf("Net APY: %s\n", formatPercent(target.NetAPY))
+    if target.MonthlyTargetUSD > 0 {
+        fmt.Printf("Target: $%s per month (%s ETH at $%s)\n", formatDecimal(target.MonthlyTargetUSD),
+            formatETH(target.MonthlyTarget), formatDecimal(target.ETHPrice))
+    } else {
+        fmt.Printf("Target: %s ETH per month\n", formatETH(target.MonthlyTarget))
+    }
+
+    subheader.Println("\n32 ETH Validators (0x01):")
+    highlight.Printf("- Validators Needed: %s\n", formatNumber(uint64(target.Validators)))
+    fmt.Printf("- Stake: %s ETH\n", formatNumber(uint64(target.ValidatorStake)))
+
+    subheader.Println("\nCompounding Validators (0x02):")
+    highlight.Printf("- Stake Needed: %s ETH\n", formatNumber(uint64(target.CompoundingStake)))
+    fmt.Printf("- Validators: %s\n", formatNumber(uint64(target.CompoundingValidators)))
+    fmt.Println("- Rewards compound into the balance, so income grows after the first month")
+
+    fmt.Println()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+
+    "github.com/fatih/color"
+)
+
+func handleThreshold() {
+    requireValidators("threshold")
+    if targetAPY <= 0 {
+        fmt.Println("Error: --target-apy must be greater than 0")
+        os.Exit(1)
+    }
+
+    threshold := calculator.SolveAPYThresholds(createNetworkState(validatorCount), participation, missRate,
+        slashingProb, priorityFees*1e9, mevReward*1e9, targetAPY)
+    recordRun(threshold)
+
+    if printFormatted(threshold) {
+        return
+    }
+
+    header := color.New(color.FgCyan, color.Bold)
+    subheader := color.New(color.FgYellow, color.Bold)
+    highlight := color.New(color.FgGreen, color.Bold)
+
+    header.Println("\n=== APY Thresholds ===")
+
+    fmt.Printf("\nNetwork Validators: %s\n", formatNumber(uint64(validatorCount)))
+    fmt.Printf("Current Risk-Adjusted APY: %s\n", formatPercent(threshold.CurrentAPY))
+    fmt.Printf("Target APY: %s\n", formatPercent(threshold.TargetAPY))
+
+    subheader.Printf("\nYour Effectiveness (at %s network participation):\n", formatPercent(participation*100))
+    if threshold.EffectivenessFound {
+        highlight.Printf("- APY drops to target below %s effectiveness (%s of duties missed)\n",
+            formatPercent(threshold.Effectiveness*100), formatPercent((1-threshold.Effectiveness)*100))
+    } else {
+        fmt.Println("- No effectiveness between 0% and 100% gives the target APY")
+    }
+
+    subheader.Printf("\nNetwork Participation (at %s miss rate):\n", formatPercent(missRate*100))
+    if threshold.ParticipationFound {
+        highlight.Printf("- APY reaches target at %s participation\n", formatPercent(threshold.Participation*100))
+    } else {
+        fmt.Println("- No participation rate between 1% and 100% gives the target APY")
+    }
+
+    fmt.Println()
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+    "bufio"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/beacon"
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/tracker"
+    "github.com/eth-rewards-calculator/internal/types"
+
+    "github.com/fatih/color"
+    flag "github.com/spf13/pflag"
+)
+
+func handleTrack() {
+    if !flag.CommandLine.Changed("validator-index") {
+        fmt.Println("Error: The track command requires a validator index (--validator-index)")
+        os.Exit(1)
+    }
+    requireValidators("track")
+    if follow {
+        requireStreamFormat("track --follow")
+    }
+
+    t := tracker.New(validatorIndex, createNetworkState(validatorCount))
+
+    if textOutput {
+        header := color.New(color.FgCyan, color.Bold)
+        header.Println("\n=== Validator Performance Tracking ===")
+        fmt.Printf("\nValidator Index: %d\n\n", validatorIndex)
+        fmt.Printf("%-10s %-16s %-16s %-12s %-12s\n", "Epoch", "Realized ETH", "Ideal ETH", "Efficiency", "Cumulative")
+        fmt.Println(strings.Repeat("-", 70))
+    }
+
+    if outcomesPath != "" {
+        trackOutcomesFile(t)
+    } else {
+        trackBeacon(t)
+    }
+
+    summary := t.Summary()
+    if summary.Epochs == 0 {
+        fmt.Println("Error: No epoch outcomes were tracked")
+        os.Exit(1)
+    }
+    outputPerformance(summary)
+}
+
+// trackOutcomesFile reads duty outcomes exported from a beacon node or
+// validator client logs, one JSON record per line in the realized command's format
+func trackOutcomesFile(t *tracker.Tracker) {
+    var r io.Reader = os.Stdin
+    if outcomesPath != "-" {
+        f, err := os.Open(outcomesPath)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "Error reading outcomes: %v\n", err)
+            os.Exit(1)
+        }
+        defer f.Close()
+        r = f
+    }
+
+    scanner := bufio.NewScanner(r)
+    for line := 1; scanner.Scan(); line++ {
+        if len(scanner.Bytes()) == 0 {
+            continue
+        }
+
+        var outcome types.RealizedRewards
+        if err := json.Unmarshal(scanner.Bytes(), &outcome); err != nil {
+            fmt.Fprintf(os.Stderr, "Error reading outcomes: line %d: %v\n", line, err)
+            os.Exit(1)
+        }
+        trackOutcome(t, &outcome)
+    }
+    if err := scanner.Err(); err != nil {
+        fmt.Fprintf(os.Stderr, "Error reading outcomes: %v\n", err)
+        os.Exit(1)
+    }
+}
+
+// trackBeacon fetches outcomes epoch by epoch from --from-epoch. With --follow
+// it keeps going as new epochs finalize; otherwise it stops after --to-epoch.
+func trackBeacon(t *tracker.Tracker) {
+    if !follow && toEpoch < fromEpoch {
+        fmt.Printf("Error: End epoch %d is before start epoch %d\n", toEpoch, fromEpoch)
+        os.Exit(1)
+    }
+
+    client := beacon.NewClient(primaryBeaconURL())
+    pollInterval := time.Duration(config.SECONDS_PER_SLOT) * time.Second
+
+    // Text output already shows each epoch as it arrives
+    var bar *progress
+    if !follow && !textOutput {
+        bar = newProgress("Backtesting epochs", int(toEpoch-fromEpoch+1))
+    }
+    defer bar.finish()
+
+    for epoch := fromEpoch; follow || epoch <= toEpoch; epoch++ {
+        // Only finalized epochs have settled rewards
+        for {
+            finalized, err := client.GetFinalizedEpoch()
+            if err != nil {
+                fmt.Fprintf(os.Stderr, "Error fetching finalized epoch: %v\n", err)
+                os.Exit(1)
+            }
+            if epoch <= finalized {
+                break
+            }
+            if !follow {
+                fmt.Printf("Error: Epoch %d is not finalized yet (finalized: %d)\n", epoch, finalized)
+                os.Exit(1)
+            }
+            time.Sleep(pollInterval)
+        }
+
+        outcome, err := client.GetRealizedRewards(validatorIndex, epoch, epoch)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "Error fetching realized rewards: %v\n", err)
+            os.Exit(1)
+        }
+        trackOutcome(t, outcome)
+        bar.step(1)
+    }
+}
+
+// trackOutcome adds one outcome and reports the running efficiency
+func trackOutcome(t *tracker.Tracker, outcome *types.RealizedRewards) {
+    before := t.Summary()
+    summary, err := t.Add(outcome)
+    if err != nil {
+        fmt.Printf("Error: %v\n", err)
+        os.Exit(1)
+    }
+
+    if !textOutput {
+        // Following never finishes, so stream a summary per epoch
+        if follow {
+            printFormatted(summary)
+        }
+        return
+    }
+
+    realized := summary.RealizedTotalGwei - before.RealizedTotalGwei
+    ideal := summary.IdealTotalGwei - before.IdealTotalGwei
+    efficiency := "-"
+    if ideal > 0 {
+        efficiency = formatPercent(float64(realized) / float64(ideal) * 100)
+    }
+
+    epochs := fmt.Sprint(outcome.FromEpoch)
+    if outcome.ToEpoch != outcome.FromEpoch {
+        epochs = fmt.Sprintf("%d-%d", outcome.FromEpoch, outcome.ToEpoch)
+    }
+    fmt.Printf("%-10s %-16s %-16s %-12s %-12s\n",
+        epochs,
+        formatGweiAsETH(float64(realized)),
+        formatGweiAsETH(float64(ideal)),
+        efficiency,
+        formatPercent(summary.Efficiency))
+}
+
+func outputPerformance(summary types.PerformanceSummary) {
+    recordRun(summary)
+
+    if printFormatted(summary) {
+        return
+    }
+
+    subheader := color.New(color.FgYellow, color.Bold)
+    highlight := color.New(color.FgGreen, color.Bold)
+
+    fmt.Printf("\nEpochs: %d - %d (%d epochs, %s days)\n",
+        summary.FromEpoch, summary.ToEpoch, summary.Epochs,
+        formatDecimal(float64(summary.Epochs)/float64(config.EPOCHS_PER_DAY)))
+
+    subheader.Println("\nRealized vs Ideal:")
+    fmt.Printf("- Attestations: %s / %s ETH\n",
+        formatGweiAsETH(float64(summary.RealizedAttestationGwei)), formatGweiAsETH(float64(summary.IdealAttestationGwei)))
+    fmt.Printf("- Block Proposals (%d of %d made): %s / %s ETH\n", summary.ProposalsMade, summary.ProposalsScheduled,
+        formatGweiAsETH(float64(summary.RealizedProposalsGwei)), formatGweiAsETH(float64(summary.IdealProposalsGwei)))
+    fmt.Printf("- Sync Committee (%d epochs): %s / %s ETH\n", summary.SyncCommitteeEpochs,
+        formatGweiAsETH(float64(summary.RealizedSyncGwei)), formatGweiAsETH(float64(summary.IdealSyncGwei)))
+    fmt.Printf("- Total: %s / %s ETH\n",
+        formatGweiAsETH(float64(summary.RealizedTotalGwei)), formatGweiAsETH(float64(summary.IdealTotalGwei)))
+
+    highlight.Printf("\nEfficiency: %s\n\n", formatPercent(summary.Efficiency))
+}
@@ -0,0 +1,50 @@
+//go:build js && wasm
+
+// Command wasm exposes pkg/rewards to JavaScript. Loaded with wasm_exec.js it
+// defines a global ethRewards.calculate(params) that takes an object with the
+// JSON field names of rewards.Params and returns the rewards.Result object, or
+// {error: message} on invalid input. Missing fields take the command-line
+// defaults.
+package main
+
+import (
+    "encoding/json"
+    "syscall/js"
+
+    "github.com/eth-rewards-calculator/pkg/rewards"
+)
+
+func calculate(this js.Value, args []js.Value) interface{} {
+    params := rewards.DefaultParams(0)
+    if len(args) > 0 {
+        input := js.Global().Get("JSON").Call("stringify", args[0]).String()
+        if err := json.Unmarshal([]byte(input), &params); err != nil {
+            return errorObject(err)
+        }
+    }
+
+    result, err := rewards.Calculate(params)
+    if err != nil {
+        return errorObject(err)
+    }
+
+    output, err := json.Marshal(result)
+    if err != nil {
+        return errorObject(err)
+    }
+    return js.Global().Get("JSON").Call("parse", string(output))
+}
+
+// errorObject reports err to JavaScript, since a Go callback cannot throw
+func errorObject(err error) interface{} {
+    return map[string]interface{}{"error": err.Error()}
+}
+
+func main() {
+    js.Global().Set("ethRewards", js.ValueOf(map[string]interface{}{
+        "calculate": js.FuncOf(calculate),
+    }))
+
+    // Keep the Go runtime alive for calls from JavaScript
+    select {}
+}
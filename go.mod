@@ -4,11 +4,14 @@ go 1.23
 
 require (
 	github.com/fatih/color v1.14.1
+	github.com/mattn/go-isatty v0.0.17
+	github.com/mattn/go-sqlite3 v1.14.22
+	github.com/protolambda/bls12-381-util v0.1.0
 	github.com/spf13/pflag v1.0.5
 )
 
 require (
+	github.com/kilic/bls12-381 v0.1.0 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
-	github.com/mattn/go-isatty v0.0.17 // indirect
-	golang.org/x/sys v0.15.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
 )
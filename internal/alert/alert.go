@@ -0,0 +1,91 @@
+// Package alert watches live network conditions for thresholds being crossed
+// and reports them to a webhook.
+package alert
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "net/http"
+
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// Metrics that can be alerted on
+const (
+    MetricAPY             = "apy"
+    MetricParticipation   = "participation"
+    MetricActivationQueue = "activation_queue"
+    MetricExitQueue       = "exit_queue"
+)
+
+// Thresholds configure when alerts fire; zero disables a threshold
+type Thresholds struct {
+    MinAPY             float64 // percent
+    MinParticipation   float64 // 0.0-1.0
+    MaxActivationQueue int
+    MaxExitQueue       int
+}
+
+// Monitor fires an alert when a metric crosses its threshold. A metric that
+// stays past its threshold does not fire again until it has recovered.
+type Monitor struct {
+    thresholds Thresholds
+    breached   map[string]bool
+}
+
+// NewMonitor creates a monitor with every metric in range
+func NewMonitor(thresholds Thresholds) *Monitor {
+    return &Monitor{thresholds: thresholds, breached: make(map[string]bool)}
+}
+
+// Check compares a snapshot with the thresholds and returns the newly crossed ones
+func (m *Monitor) Check(snapshot types.NetworkSnapshot) []types.Alert {
+    var alerts []types.Alert
+    check := func(metric string, value, threshold float64, enabled, breached bool, message string) {
+        breached = enabled && breached
+        if breached && !m.breached[metric] {
+            alerts = append(alerts, types.Alert{
+                Metric:    metric,
+                Value:     value,
+                Threshold: threshold,
+                Message:   message,
+                Snapshot:  snapshot,
+            })
+        }
+        m.breached[metric] = breached
+    }
+
+    t := m.thresholds
+    check(MetricAPY, snapshot.APY, t.MinAPY, t.MinAPY > 0, snapshot.APY < t.MinAPY,
+        fmt.Sprintf("Projected APY %.2f%% fell below %.2f%%", snapshot.APY, t.MinAPY))
+    check(MetricParticipation, snapshot.Participation, t.MinParticipation, t.MinParticipation > 0,
+        snapshot.Participation < t.MinParticipation,
+        fmt.Sprintf("Participation %.2f%% fell below %.2f%%", snapshot.Participation*100, t.MinParticipation*100))
+    check(MetricActivationQueue, float64(snapshot.ActivationQueue), float64(t.MaxActivationQueue),
+        t.MaxActivationQueue > 0, snapshot.ActivationQueue > t.MaxActivationQueue,
+        fmt.Sprintf("Activation queue of %d validators exceeds %d", snapshot.ActivationQueue, t.MaxActivationQueue))
+    check(MetricExitQueue, float64(snapshot.ExitQueue), float64(t.MaxExitQueue),
+        t.MaxExitQueue > 0, snapshot.ExitQueue > t.MaxExitQueue,
+        fmt.Sprintf("Exit queue of %d validators exceeds %d", snapshot.ExitQueue, t.MaxExitQueue))
+    return alerts
+}
+
+// SendWebhook POSTs the alert as JSON to url
+func SendWebhook(client *http.Client, url string, alert types.Alert) error {
+    payload, err := json.Marshal(alert)
+    if err != nil {
+        return fmt.Errorf("encoding alert: %w", err)
+    }
+
+    resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+    if err != nil {
+        return fmt.Errorf("posting alert: %w", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+        return fmt.Errorf("posting alert: webhook responded %s", resp.Status)
+    }
+    return nil
+}
@@ -0,0 +1,123 @@
+package beacon
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "strings"
+    "time"
+)
+
+// DefaultTimeout bounds every request made to the beacon node
+const DefaultTimeout = 30 * time.Second
+
+// Client talks to the standard Ethereum beacon node REST API
+type Client struct {
+    BaseURL    string
+    HTTPClient *http.Client
+}
+
+// NewClient creates a client for the beacon node at baseURL (e.g. http://localhost:5052)
+func NewClient(baseURL string) *Client {
+    return &Client{
+        BaseURL:    strings.TrimRight(baseURL, "/"),
+        HTTPClient: &http.Client{Timeout: DefaultTimeout},
+    }
+}
+
+// APIError is returned when the beacon node responds with a non-200 status
+type APIError struct {
+    StatusCode int
+    Message    string
+}
+
+func (e *APIError) Error() string {
+    return fmt.Sprintf("beacon node returned %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 from the beacon node, e.g. for a missed slot
+func IsNotFound(err error) bool {
+    apiErr, ok := err.(*APIError)
+    return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// get performs a GET request and decodes the "data" field of the response into out
+func (c *Client) get(path string, out interface{}) error {
+    return c.do(http.MethodGet, path, nil, out)
+}
+
+// post performs a POST request with a JSON body and decodes the "data" field into out
+func (c *Client) post(path string, body interface{}, out interface{}) error {
+    return c.do(http.MethodPost, path, body, out)
+}
+
+func (c *Client) do(method, path string, body interface{}, out interface{}) error {
+    resp, err := c.send(method, path, body, "application/json")
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    envelope := struct {
+        Data interface{} `json:"data"`
+    }{Data: out}
+
+    if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+        return fmt.Errorf("decoding response from %s: %w", path, err)
+    }
+
+    return nil
+}
+
+// send performs a request and returns the response if its status is 200. The
+// caller closes the body.
+func (c *Client) send(method, path string, body interface{}, accept string) (*http.Response, error) {
+    var reader io.Reader
+    if body != nil {
+        payload, err := json.Marshal(body)
+        if err != nil {
+            return nil, fmt.Errorf("encoding request body: %w", err)
+        }
+        reader = bytes.NewReader(payload)
+    }
+
+    req, err := http.NewRequest(method, c.BaseURL+path, reader)
+    if err != nil {
+        return nil, fmt.Errorf("creating request: %w", err)
+    }
+    req.Header.Set("Accept", accept)
+    if body != nil {
+        req.Header.Set("Content-Type", "application/json")
+    }
+
+    resp, err := c.HTTPClient.Do(req)
+    if err != nil {
+        return nil, fmt.Errorf("requesting %s: %w", path, err)
+    }
+
+    if resp.StatusCode != http.StatusOK {
+        defer resp.Body.Close()
+        var apiErr struct {
+            Message string `json:"message"`
+        }
+        json.NewDecoder(resp.Body).Decode(&apiErr)
+        return nil, &APIError{StatusCode: resp.StatusCode, Message: apiErr.Message}
+    }
+
+    return resp, nil
+}
+
+// GetFinalizedEpoch fetches the chain's latest finalized epoch
+func (c *Client) GetFinalizedEpoch() (uint64, error) {
+    var checkpoints struct {
+        Finalized struct {
+            Epoch uint64 `json:"epoch,string"`
+        } `json:"finalized"`
+    }
+    if err := c.get("/eth/v1/beacon/states/head/finality_checkpoints", &checkpoints); err != nil {
+        return 0, err
+    }
+    return checkpoints.Finalized.Epoch, nil
+}
@@ -0,0 +1,158 @@
+package beacon
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "strings"
+    "time"
+)
+
+// StateTimeout bounds the download of a full beacon state, which runs to
+// hundreds of megabytes on mainnet
+const StateTimeout = 10 * time.Minute
+
+// BeaconBlockHeader is a block header as the REST API encodes it
+type BeaconBlockHeader struct {
+    Slot          uint64 `json:"slot,string"`
+    ProposerIndex uint64 `json:"proposer_index,string"`
+    ParentRoot    string `json:"parent_root"`
+    StateRoot     string `json:"state_root"`
+    BodyRoot      string `json:"body_root"`
+}
+
+// LightClientHeader is the header light-client messages carry. The execution
+// payload header added in Capella is not used.
+type LightClientHeader struct {
+    Beacon BeaconBlockHeader `json:"beacon"`
+}
+
+// SyncCommitteeKeys are the public keys of a sync committee
+type SyncCommitteeKeys struct {
+    Pubkeys         []string `json:"pubkeys"`
+    AggregatePubkey string   `json:"aggregate_pubkey"`
+}
+
+// SyncAggregate is a sync committee's signature over a block and which
+// members took part
+type SyncAggregate struct {
+    SyncCommitteeBits      string `json:"sync_committee_bits"`
+    SyncCommitteeSignature string `json:"sync_committee_signature"`
+}
+
+// LightClientBootstrap is the header of a checkpoint block and its state's
+// current sync committee, with the proof tying the two together
+type LightClientBootstrap struct {
+    Header                     LightClientHeader `json:"header"`
+    CurrentSyncCommittee       SyncCommitteeKeys `json:"current_sync_committee"`
+    CurrentSyncCommitteeBranch []string          `json:"current_sync_committee_branch"`
+}
+
+// LightClientUpdate is a sync committee's signature over an attested header,
+// which proves a finalized header and, in updates between periods, the next
+// sync committee. Finality updates carry no next committee.
+type LightClientUpdate struct {
+    AttestedHeader          LightClientHeader  `json:"attested_header"`
+    NextSyncCommittee       *SyncCommitteeKeys `json:"next_sync_committee,omitempty"`
+    NextSyncCommitteeBranch []string           `json:"next_sync_committee_branch,omitempty"`
+    FinalizedHeader         LightClientHeader  `json:"finalized_header"`
+    FinalityBranch          []string           `json:"finality_branch"`
+    SyncAggregate           SyncAggregate      `json:"sync_aggregate"`
+    SignatureSlot           uint64             `json:"signature_slot,string"`
+}
+
+// ForkScheduleEntry is one fork of /eth/v1/config/fork_schedule
+type ForkScheduleEntry struct {
+    PreviousVersion string `json:"previous_version"`
+    CurrentVersion  string `json:"current_version"`
+    Epoch           uint64 `json:"epoch,string"`
+}
+
+// GetLightClientBootstrap fetches the bootstrap for a checkpoint block root
+func (c *Client) GetLightClientBootstrap(blockRoot string) (*LightClientBootstrap, error) {
+    var bootstrap LightClientBootstrap
+    if err := c.get("/eth/v1/beacon/light_client/bootstrap/"+blockRoot, &bootstrap); err != nil {
+        return nil, fmt.Errorf("fetching light client bootstrap: %w", err)
+    }
+    return &bootstrap, nil
+}
+
+// GetLightClientUpdates fetches the best update of each of count sync
+// committee periods from startPeriod
+func (c *Client) GetLightClientUpdates(startPeriod, count uint64) ([]LightClientUpdate, error) {
+    path := fmt.Sprintf("/eth/v1/beacon/light_client/updates?start_period=%d&count=%d", startPeriod, count)
+    resp, err := c.send(http.MethodGet, path, nil, "application/json")
+    if err != nil {
+        return nil, fmt.Errorf("fetching light client updates: %w", err)
+    }
+    defer resp.Body.Close()
+
+    // Unlike other responses, this is a list of enveloped updates
+    var envelopes []struct {
+        Data LightClientUpdate `json:"data"`
+    }
+    if err := json.NewDecoder(resp.Body).Decode(&envelopes); err != nil {
+        return nil, fmt.Errorf("decoding response from %s: %w", path, err)
+    }
+
+    updates := make([]LightClientUpdate, len(envelopes))
+    for i, envelope := range envelopes {
+        updates[i] = envelope.Data
+    }
+    return updates, nil
+}
+
+// GetLightClientFinalityUpdate fetches the latest finalized header the node
+// can prove to a light client
+func (c *Client) GetLightClientFinalityUpdate() (*LightClientUpdate, error) {
+    var update LightClientUpdate
+    if err := c.get("/eth/v1/beacon/light_client/finality_update", &update); err != nil {
+        return nil, fmt.Errorf("fetching light client finality update: %w", err)
+    }
+    return &update, nil
+}
+
+// GetGenesisValidatorsRoot fetches the root that, with the fork version,
+// separates signatures on this chain from those on others
+func (c *Client) GetGenesisValidatorsRoot() (string, error) {
+    var genesis struct {
+        GenesisValidatorsRoot string `json:"genesis_validators_root"`
+    }
+    if err := c.get("/eth/v1/beacon/genesis", &genesis); err != nil {
+        return "", fmt.Errorf("fetching genesis: %w", err)
+    }
+    return genesis.GenesisValidatorsRoot, nil
+}
+
+// GetForkSchedule fetches the chain's forks, past and scheduled, oldest first
+func (c *Client) GetForkSchedule() ([]ForkScheduleEntry, error) {
+    var schedule []ForkScheduleEntry
+    if err := c.get("/eth/v1/config/fork_schedule", &schedule); err != nil {
+        return nil, fmt.Errorf("fetching fork schedule: %w", err)
+    }
+    return schedule, nil
+}
+
+// GetStateSSZ downloads a full beacon state as SSZ, with the fork it was
+// encoded under. The debug API serves it, which some public nodes disable.
+func (c *Client) GetStateSSZ(stateID string) (string, []byte, error) {
+    slow := *c
+    slow.HTTPClient = &http.Client{Timeout: StateTimeout, Transport: c.HTTPClient.Transport}
+
+    path := "/eth/v2/debug/beacon/states/" + stateID
+    resp, err := slow.send(http.MethodGet, path, nil, "application/octet-stream")
+    if err != nil {
+        return "", nil, fmt.Errorf("fetching beacon state: %w", err)
+    }
+    defer resp.Body.Close()
+    if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+        return "", nil, fmt.Errorf("fetching beacon state: %s answered with JSON rather than SSZ", path)
+    }
+
+    data, err := io.ReadAll(resp.Body)
+    if err != nil {
+        return "", nil, fmt.Errorf("reading response from %s: %w", path, err)
+    }
+    return resp.Header.Get("Eth-Consensus-Version"), data, nil
+}
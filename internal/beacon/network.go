@@ -0,0 +1,201 @@
+package beacon
+
+import (
+    "encoding/hex"
+    "fmt"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// GetValidatorStatusCounts counts the validators in a state by status
+// (pending_queued, active_ongoing, active_exiting, ...). The response lists
+// every validator, so this is a large request on mainnet.
+func (c *Client) GetValidatorStatusCounts(stateID string) (map[string]int, error) {
+    var validators []struct {
+        Status string `json:"status"`
+    }
+    if err := c.get(fmt.Sprintf("/eth/v1/beacon/states/%s/validators", stateID), &validators); err != nil {
+        return nil, err
+    }
+
+    counts := make(map[string]int)
+    for _, validator := range validators {
+        counts[validator.Status]++
+    }
+    return counts, nil
+}
+
+// GetActiveValidatorCount counts the active validators in a state
+func (c *Client) GetActiveValidatorCount(stateID string) (int, error) {
+    counts, err := c.GetValidatorStatusCounts(stateID)
+    if err != nil {
+        return 0, err
+    }
+    return activeCount(counts), nil
+}
+
+// activeCount sums the active statuses in validator status counts
+func activeCount(counts map[string]int) int {
+    return counts["active_ongoing"] + counts["active_exiting"] + counts["active_slashed"]
+}
+
+// GetParticipation returns the fraction of validators that earned a target
+// reward in an epoch, from the attestation rewards of every validator
+func (c *Client) GetParticipation(epoch uint64) (float64, error) {
+    rewards, err := c.GetAttestationRewards(epoch)
+    if err != nil {
+        return 0, err
+    }
+    if len(rewards.TotalRewards) == 0 {
+        return 0, fmt.Errorf("no attestation rewards for epoch %d", epoch)
+    }
+
+    timely := 0
+    for _, reward := range rewards.TotalRewards {
+        if reward.Target > 0 {
+            timely++
+        }
+    }
+    return float64(timely) / float64(len(rewards.TotalRewards)), nil
+}
+
+// GetNetworkSnapshot collects the live network conditions at the latest
+// finalized epoch: active validators, participation, and queue lengths
+func (c *Client) GetNetworkSnapshot() (*types.NetworkSnapshot, error) {
+    epoch, err := c.GetFinalizedEpoch()
+    if err != nil {
+        return nil, err
+    }
+
+    counts, err := c.GetValidatorStatusCounts("finalized")
+    if err != nil {
+        return nil, err
+    }
+
+    participation, err := c.GetParticipation(epoch)
+    if err != nil {
+        return nil, err
+    }
+
+    return &types.NetworkSnapshot{
+        Epoch:            epoch,
+        ActiveValidators: activeCount(counts),
+        Participation:    participation,
+        ActivationQueue:  counts["pending_queued"],
+        ExitQueue:        counts["active_exiting"],
+    }, nil
+}
+
+// Checkpoint is an epoch boundary block the chain justified or finalized
+type Checkpoint struct {
+    Epoch uint64 `json:"epoch,string"`
+    Root  string `json:"root"`
+}
+
+// FinalityCheckpoints is the response of /eth/v1/beacon/states/{state_id}/finality_checkpoints
+type FinalityCheckpoints struct {
+    PreviousJustified Checkpoint `json:"previous_justified"`
+    CurrentJustified  Checkpoint `json:"current_justified"`
+    Finalized         Checkpoint `json:"finalized"`
+}
+
+// GetFinalityCheckpoints fetches the justified and finalized checkpoints of a state
+func (c *Client) GetFinalityCheckpoints(stateID string) (*FinalityCheckpoints, error) {
+    var checkpoints FinalityCheckpoints
+    if err := c.get("/eth/v1/beacon/states/"+stateID+"/finality_checkpoints", &checkpoints); err != nil {
+        return nil, err
+    }
+    return &checkpoints, nil
+}
+
+// GetNodeVersion fetches the client name and version the node reports, such
+// as Lighthouse/v5.3.0-d6ba8c3/x86_64-linux
+func (c *Client) GetNodeVersion() (string, error) {
+    var version struct {
+        Version string `json:"version"`
+    }
+    if err := c.get("/eth/v1/node/version", &version); err != nil {
+        return "", err
+    }
+    return version.Version, nil
+}
+
+// validatorRecord is an entry of /eth/v1/beacon/states/{state_id}/validators
+type validatorRecord struct {
+    Status    string `json:"status"`
+    Validator struct {
+        Pubkey                     string `json:"pubkey"`
+        WithdrawalCredentials      string `json:"withdrawal_credentials"`
+        EffectiveBalance           uint64 `json:"effective_balance,string"`
+        Slashed                    bool   `json:"slashed"`
+        ActivationEligibilityEpoch uint64 `json:"activation_eligibility_epoch,string"`
+        ActivationEpoch            uint64 `json:"activation_epoch,string"`
+        ExitEpoch                  uint64 `json:"exit_epoch,string"`
+        WithdrawableEpoch          uint64 `json:"withdrawable_epoch,string"`
+    } `json:"validator"`
+}
+
+// GetNetworkState captures the active validators of the latest finalized
+// state as a NetworkState, with its epochs and fork. The API does not expose
+// participation flags or inactivity scores, so the state has neither.
+func (c *Client) GetNetworkState() (*types.NetworkState, error) {
+    epoch, err := c.GetFinalizedEpoch()
+    if err != nil {
+        return nil, err
+    }
+
+    checkpoints, err := c.GetFinalityCheckpoints("finalized")
+    if err != nil {
+        return nil, err
+    }
+    var fork struct {
+        CurrentVersion string `json:"current_version"`
+    }
+    if err := c.get("/eth/v1/beacon/states/finalized/fork", &fork); err != nil {
+        return nil, err
+    }
+
+    var records []validatorRecord
+    if err := c.get("/eth/v1/beacon/states/finalized/validators", &records); err != nil {
+        return nil, err
+    }
+
+    state := &types.NetworkState{
+        CurrentEpoch:   epoch,
+        FinalizedEpoch: checkpoints.Finalized.Epoch,
+        JustifiedEpoch: checkpoints.CurrentJustified.Epoch,
+        CurrentFork:    config.ForkForVersion(fork.CurrentVersion),
+    }
+    for _, record := range records {
+        if !strings.HasPrefix(record.Status, "active") {
+            continue
+        }
+        v := record.Validator
+        validator := types.Validator{
+            EffectiveBalance:           v.EffectiveBalance,
+            Slashed:                    v.Slashed,
+            ActivationEligibilityEpoch: v.ActivationEligibilityEpoch,
+            ActivationEpoch:            v.ActivationEpoch,
+            ExitEpoch:                  v.ExitEpoch,
+            WithdrawableEpoch:          v.WithdrawableEpoch,
+        }
+        decodeHex(validator.Pubkey[:], v.Pubkey)
+        decodeHex(validator.WithdrawalCredentials[:], v.WithdrawalCredentials)
+        state.Validators = append(state.Validators, validator)
+        state.TotalActiveBalance += v.EffectiveBalance
+    }
+    if len(state.Validators) == 0 {
+        return nil, fmt.Errorf("no active validators in the finalized state")
+    }
+    return state, nil
+}
+
+// decodeHex fills dst from 0x-prefixed hex, leaving it zero if s does not fit
+func decodeHex(dst []byte, s string) {
+    raw, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+    if err == nil && len(raw) == len(dst) {
+        copy(dst, raw)
+    }
+}
@@ -0,0 +1,254 @@
+package beacon
+
+import (
+    "fmt"
+    "strconv"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// AttestationReward is a validator's attestation reward breakdown for one epoch (Gwei)
+type AttestationReward struct {
+    ValidatorIndex uint64 `json:"validator_index,string"`
+    Head           int64  `json:"head,string"`
+    Target         int64  `json:"target,string"`
+    Source         int64  `json:"source,string"`
+    InclusionDelay int64  `json:"inclusion_delay,string,omitempty"`
+    Inactivity     int64  `json:"inactivity,string"`
+}
+
+// IdealAttestationReward is the maximum attestation reward for an effective balance (Gwei)
+type IdealAttestationReward struct {
+    EffectiveBalance uint64 `json:"effective_balance,string"`
+    Head             int64  `json:"head,string"`
+    Target           int64  `json:"target,string"`
+    Source           int64  `json:"source,string"`
+    InclusionDelay   int64  `json:"inclusion_delay,string,omitempty"`
+    Inactivity       int64  `json:"inactivity,string"`
+}
+
+// AttestationRewards is the response of /eth/v1/beacon/rewards/attestations/{epoch}
+type AttestationRewards struct {
+    IdealRewards []IdealAttestationReward `json:"ideal_rewards"`
+    TotalRewards []AttestationReward      `json:"total_rewards"`
+}
+
+// BlockReward is the proposer reward breakdown for one block (Gwei)
+type BlockReward struct {
+    ProposerIndex     uint64 `json:"proposer_index,string"`
+    Total             int64  `json:"total,string"`
+    Attestations      int64  `json:"attestations,string"`
+    SyncAggregate     int64  `json:"sync_aggregate,string"`
+    ProposerSlashings int64  `json:"proposer_slashings,string"`
+    AttesterSlashings int64  `json:"attester_slashings,string"`
+}
+
+// SyncCommitteeReward is a validator's sync committee reward for one block (Gwei)
+type SyncCommitteeReward struct {
+    ValidatorIndex uint64 `json:"validator_index,string"`
+    Reward         int64  `json:"reward,string"`
+}
+
+// ProposerDuty assigns a block proposal to a validator
+type ProposerDuty struct {
+    Pubkey         string `json:"pubkey"`
+    ValidatorIndex uint64 `json:"validator_index,string"`
+    Slot           uint64 `json:"slot,string"`
+}
+
+// SyncCommittee lists the validators in the sync committee for an epoch
+type SyncCommittee struct {
+    Validators []string `json:"validators"`
+}
+
+// GetAttestationRewards fetches attestation rewards for the given validators in an epoch
+func (c *Client) GetAttestationRewards(epoch uint64, validatorIndices ...uint64) (*AttestationRewards, error) {
+    var rewards AttestationRewards
+    path := fmt.Sprintf("/eth/v1/beacon/rewards/attestations/%d", epoch)
+    if err := c.post(path, indexStrings(validatorIndices), &rewards); err != nil {
+        return nil, err
+    }
+    return &rewards, nil
+}
+
+// GetBlockRewards fetches the proposer reward for the block at the given slot
+func (c *Client) GetBlockRewards(slot uint64) (*BlockReward, error) {
+    var reward BlockReward
+    if err := c.get(fmt.Sprintf("/eth/v1/beacon/rewards/blocks/%d", slot), &reward); err != nil {
+        return nil, err
+    }
+    return &reward, nil
+}
+
+// GetSyncCommitteeRewards fetches sync committee rewards for the given validators in the block at slot
+func (c *Client) GetSyncCommitteeRewards(slot uint64, validatorIndices ...uint64) ([]SyncCommitteeReward, error) {
+    var rewards []SyncCommitteeReward
+    path := fmt.Sprintf("/eth/v1/beacon/rewards/sync_committee/%d", slot)
+    if err := c.post(path, indexStrings(validatorIndices), &rewards); err != nil {
+        return nil, err
+    }
+    return rewards, nil
+}
+
+// GetProposerDuties fetches the block proposers for every slot of an epoch
+func (c *Client) GetProposerDuties(epoch uint64) ([]ProposerDuty, error) {
+    var duties []ProposerDuty
+    if err := c.get(fmt.Sprintf("/eth/v1/validator/duties/proposer/%d", epoch), &duties); err != nil {
+        return nil, err
+    }
+    return duties, nil
+}
+
+// GetSyncCommittee fetches the sync committee members active in an epoch
+func (c *Client) GetSyncCommittee(epoch uint64) (*SyncCommittee, error) {
+    var committee SyncCommittee
+    stateID := epoch * config.SLOTS_PER_EPOCH
+    path := fmt.Sprintf("/eth/v1/beacon/states/%d/sync_committees?epoch=%d", stateID, epoch)
+    if err := c.get(path, &committee); err != nil {
+        return nil, err
+    }
+    return &committee, nil
+}
+
+// GetRealizedRewards sums the attestation, block, and sync committee rewards a validator
+// actually earned over an inclusive range of epochs
+func (c *Client) GetRealizedRewards(validatorIndex, fromEpoch, toEpoch uint64) (*types.RealizedRewards, error) {
+    if toEpoch < fromEpoch {
+        return nil, fmt.Errorf("end epoch %d is before start epoch %d", toEpoch, fromEpoch)
+    }
+
+    realized := &types.RealizedRewards{
+        ValidatorIndex: validatorIndex,
+        FromEpoch:      fromEpoch,
+        ToEpoch:        toEpoch,
+    }
+
+    // Sync committee membership only changes once per period
+    membership := make(map[uint64]bool)
+
+    for epoch := fromEpoch; epoch <= toEpoch; epoch++ {
+        if err := c.addAttestationRewards(realized, epoch); err != nil {
+            return nil, err
+        }
+        if err := c.addBlockRewards(realized, epoch); err != nil {
+            return nil, err
+        }
+
+        period := epoch / config.EPOCHS_PER_SYNC_COMMITTEE_PERIOD
+        inCommittee, known := membership[period]
+        if !known {
+            var err error
+            inCommittee, err = c.isSyncCommitteeMember(realized.ValidatorIndex, epoch)
+            if err != nil {
+                return nil, err
+            }
+            membership[period] = inCommittee
+        }
+
+        if inCommittee {
+            if err := c.addSyncCommitteeRewards(realized, epoch); err != nil {
+                return nil, err
+            }
+        }
+    }
+
+    realized.TotalRewardsGwei = realized.AttestationRewardsGwei + realized.BlockRewardsGwei + realized.SyncCommitteeRewardsGwei
+
+    return realized, nil
+}
+
+func (c *Client) addAttestationRewards(realized *types.RealizedRewards, epoch uint64) error {
+    rewards, err := c.GetAttestationRewards(epoch, realized.ValidatorIndex)
+    if err != nil {
+        return fmt.Errorf("attestation rewards for epoch %d: %w", epoch, err)
+    }
+
+    for _, reward := range rewards.TotalRewards {
+        if reward.ValidatorIndex != realized.ValidatorIndex {
+            continue
+        }
+        realized.SourceRewardsGwei += reward.Source
+        realized.TargetRewardsGwei += reward.Target
+        realized.HeadRewardsGwei += reward.Head
+        realized.InactivityPenaltiesGwei += reward.Inactivity
+        realized.AttestationRewardsGwei += reward.Source + reward.Target + reward.Head + reward.Inactivity
+    }
+
+    return nil
+}
+
+func (c *Client) addBlockRewards(realized *types.RealizedRewards, epoch uint64) error {
+    duties, err := c.GetProposerDuties(epoch)
+    if err != nil {
+        return fmt.Errorf("proposer duties for epoch %d: %w", epoch, err)
+    }
+
+    for _, duty := range duties {
+        if duty.ValidatorIndex != realized.ValidatorIndex {
+            continue
+        }
+
+        realized.ProposalsScheduled++
+        reward, err := c.GetBlockRewards(duty.Slot)
+        if IsNotFound(err) {
+            // Missed proposal
+            continue
+        }
+        if err != nil {
+            return fmt.Errorf("block rewards for slot %d: %w", duty.Slot, err)
+        }
+
+        realized.ProposalsMade++
+        realized.BlockRewardsGwei += reward.Total
+    }
+
+    return nil
+}
+
+func (c *Client) isSyncCommitteeMember(validatorIndex, epoch uint64) (bool, error) {
+    committee, err := c.GetSyncCommittee(epoch)
+    if err != nil {
+        return false, fmt.Errorf("sync committee for epoch %d: %w", epoch, err)
+    }
+
+    member := strconv.FormatUint(validatorIndex, 10)
+    for _, index := range committee.Validators {
+        if index == member {
+            return true, nil
+        }
+    }
+
+    return false, nil
+}
+
+func (c *Client) addSyncCommitteeRewards(realized *types.RealizedRewards, epoch uint64) error {
+    realized.SyncCommitteeEpochs++
+    for slot := epoch * config.SLOTS_PER_EPOCH; slot < (epoch+1)*config.SLOTS_PER_EPOCH; slot++ {
+        rewards, err := c.GetSyncCommitteeRewards(slot, realized.ValidatorIndex)
+        if IsNotFound(err) {
+            // No block in this slot
+            continue
+        }
+        if err != nil {
+            return fmt.Errorf("sync committee rewards for slot %d: %w", slot, err)
+        }
+
+        for _, reward := range rewards {
+            if reward.ValidatorIndex == realized.ValidatorIndex {
+                realized.SyncCommitteeRewardsGwei += reward.Reward
+            }
+        }
+    }
+
+    return nil
+}
+
+// indexStrings formats validator indices the way the beacon API expects them in request bodies
+func indexStrings(indices []uint64) []string {
+    result := make([]string, len(indices))
+    for i, index := range indices {
+        result[i] = strconv.FormatUint(index, 10)
+    }
+    return result
+}
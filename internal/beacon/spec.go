@@ -0,0 +1,21 @@
+package beacon
+
+import "fmt"
+
+// GetSpec fetches the chain configuration from /eth/v1/config/spec. Values are
+// returned as the strings the beacon node reports; entries that are not plain
+// strings (such as schedules some clients include) are skipped.
+func (c *Client) GetSpec() (map[string]string, error) {
+    var raw map[string]interface{}
+    if err := c.get("/eth/v1/config/spec", &raw); err != nil {
+        return nil, fmt.Errorf("fetching chain spec: %w", err)
+    }
+
+    spec := make(map[string]string, len(raw))
+    for key, value := range raw {
+        if s, ok := value.(string); ok {
+            spec[key] = s
+        }
+    }
+    return spec, nil
+}
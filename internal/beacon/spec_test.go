@@ -0,0 +1,39 @@
+package beacon
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+)
+
+func TestGetSpec(t *testing.T) {
+    // Values arrive as strings; some clients add a BLOB_SCHEDULE list
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/eth/v1/config/spec" {
+            http.NotFound(w, r)
+            return
+        }
+        w.Write([]byte(`{"data": {
+            "CONFIG_NAME": "mainnet",
+            "SECONDS_PER_SLOT": "12",
+            "ELECTRA_FORK_VERSION": "0x05000000",
+            "BLOB_SCHEDULE": [{"EPOCH": "364032", "MAX_BLOBS_PER_BLOCK": "9"}],
+            "NUMBER": 7
+        }}`))
+    }))
+    defer server.Close()
+
+    spec, err := NewClient(server.URL + "/").GetSpec()
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := map[string]string{"CONFIG_NAME": "mainnet", "SECONDS_PER_SLOT": "12", "ELECTRA_FORK_VERSION": "0x05000000"}
+    if !reflect.DeepEqual(spec, want) {
+        t.Errorf("GetSpec = %v, want %v", spec, want)
+    }
+
+    if _, err := NewClient(server.URL + "/missing").GetSpec(); err == nil {
+        t.Error("a 404 gave a spec")
+    }
+}
@@ -0,0 +1,175 @@
+// Package bot answers calculator commands sent in chat, such as "/apy 1.1m 0.97"
+// or "/slash 500", and connects them to Telegram, Discord, and Slack.
+package bot
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/provider"
+    "github.com/eth-rewards-calculator/pkg/rewards"
+)
+
+// Bot turns chat commands into replies. Arguments left out of a command take
+// the defaults, or the live conditions from Source when it is set.
+type Bot struct {
+    Validators    int
+    Participation float64
+    Source        provider.StateProvider
+}
+
+
+const helpText = `Commands:
+/apy [validators] [participation] - projected APY, e.g. /apy 1.1m 0.97
+/slash <slashed> [validators] - penalty when validators are slashed together, e.g. /slash 500
+/help - this message
+Counts accept k and m suffixes (500k, 1.1m).`
+
+// Respond returns the reply to a message, or "" if it is not a command
+func (b *Bot) Respond(text string) string {
+    fields := strings.Fields(text)
+    if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+        return ""
+    }
+    // Group chats address commands as /apy@botname
+    command, _, _ := strings.Cut(strings.ToLower(fields[0]), "@")
+    args := fields[1:]
+
+    var reply string
+    var err error
+    switch command {
+    case "/apy":
+        reply, err = b.apy(args)
+    case "/slash":
+        reply, err = b.slash(args)
+    case "/help", "/start":
+        reply = helpText
+    default:
+        return ""
+    }
+    if err != nil {
+        return "Error: " + err.Error() + "\n\n" + helpText
+    }
+    return reply
+}
+
+func (b *Bot) apy(args []string) (string, error) {
+    if len(args) > 2 {
+        return "", fmt.Errorf("too many arguments")
+    }
+
+    params := rewards.DefaultParams(b.Validators)
+    params.Participation = b.Participation
+    if b.Source != nil && len(args) < 2 {
+        snapshot, err := b.Source.Snapshot()
+        if err != nil {
+            return "", fmt.Errorf("fetching network conditions: %v", err)
+        }
+        params = rewards.DefaultParams(snapshot.ActiveValidators)
+        params.Participation = snapshot.Participation
+    }
+    if len(args) > 0 {
+        count, err := parseCount(args[0])
+        if err != nil {
+            return "", err
+        }
+        params.Validators = count
+    }
+    if len(args) > 1 {
+        rate, err := parseRate(args[1])
+        if err != nil {
+            return "", err
+        }
+        params.Participation = rate
+    }
+
+    result, err := rewards.Calculate(params)
+    if err != nil {
+        return "", err
+    }
+
+    reply := fmt.Sprintf("%s validators at %.1f%% participation:\nAPY: %.2f%%\nRewards: %.4f ETH per year per validator",
+        formatCount(result.Validators), result.Participation*100, result.APY, result.TotalRewards)
+    if result.NetworkHealthWarning != "" {
+        reply += "\n" + result.NetworkHealthWarning
+    }
+    return reply, nil
+}
+
+func (b *Bot) slash(args []string) (string, error) {
+    if len(args) == 0 || len(args) > 2 {
+        return "", fmt.Errorf("give the number of slashed validators")
+    }
+
+    slashed, err := parseCount(args[0])
+    if err != nil {
+        return "", err
+    }
+    validators := b.Validators
+    if len(args) > 1 {
+        if validators, err = parseCount(args[1]); err != nil {
+            return "", err
+        }
+    } else if b.Source != nil {
+        if validators, err = b.Source.ActiveValidators(); err != nil {
+            return "", fmt.Errorf("fetching the validator count: %v", err)
+        }
+    }
+
+    result, err := rewards.Slashing(validators, slashed)
+    if err != nil {
+        return "", err
+    }
+    return fmt.Sprintf("%s of %s validators slashed together:\nInitial penalty: %.4f ETH\nProportional penalty: %.4f ETH\nTotal: %.4f ETH (%.2f%% of stake)",
+        formatCount(result.Slashed), formatCount(result.Validators), result.InitialPenalty,
+        result.ProportionalPenalty, result.TotalPenalty, result.PercentageOfStake), nil
+}
+
+// parseCount parses a validator count such as 500000, 500k, 1.1m, or 1,000,000
+func parseCount(arg string) (int, error) {
+    text := strings.ToLower(strings.ReplaceAll(arg, ",", ""))
+    multiplier := 1.0
+    switch {
+    case strings.HasSuffix(text, "k"):
+        multiplier, text = 1e3, strings.TrimSuffix(text, "k")
+    case strings.HasSuffix(text, "m"):
+        multiplier, text = 1e6, strings.TrimSuffix(text, "m")
+    }
+
+    value, err := strconv.ParseFloat(text, 64)
+    count := int(value*multiplier + 0.5)
+    if err != nil || count <= 0 {
+        return 0, fmt.Errorf("invalid count %q", arg)
+    }
+    if count > rewards.MaxValidators() {
+        return 0, fmt.Errorf("count %q exceeds the %s validators the ETH supply allows", arg, formatCount(rewards.MaxValidators()))
+    }
+    return count, nil
+}
+
+// parseRate parses a participation rate as a fraction (0.97) or percentage (97%)
+func parseRate(arg string) (float64, error) {
+    text := strings.TrimSuffix(arg, "%")
+    value, err := strconv.ParseFloat(text, 64)
+    if err != nil {
+        return 0, fmt.Errorf("invalid participation %q", arg)
+    }
+    if strings.HasSuffix(arg, "%") || value > 1 {
+        value /= 100
+    }
+    return value, nil
+}
+
+// formatCount formats a count with thousands separators
+func formatCount(n int) string {
+    digits := strconv.Itoa(n)
+    var out strings.Builder
+    for i, digit := range digits {
+        if i > 0 && (len(digits)-i)%3 == 0 {
+            out.WriteByte(',')
+        }
+        out.WriteRune(digit)
+    }
+    return out.String()
+}
@@ -0,0 +1,98 @@
+package bot
+
+import (
+    "crypto/ed25519"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "strings"
+)
+
+// Discord interaction and response types
+const (
+    discordPing               = 1
+    discordApplicationCommand = 2
+    discordPong               = 1
+    discordMessage            = 4
+)
+
+// DiscordHandler answers Discord slash commands sent to an interactions
+// endpoint URL. Discord signs every request with the application's public key;
+// unsigned or tampered requests are rejected.
+type DiscordHandler struct {
+    PublicKey ed25519.PublicKey
+    Bot       *Bot
+}
+
+// NewDiscordHandler creates a handler for the hex public key shown on the
+// application's page in the Discord developer portal
+func NewDiscordHandler(publicKey string, bot *Bot) (*DiscordHandler, error) {
+    key, err := hex.DecodeString(publicKey)
+    if err != nil || len(key) != ed25519.PublicKeySize {
+        return nil, fmt.Errorf("invalid Discord public key")
+    }
+    return &DiscordHandler{PublicKey: key, Bot: bot}, nil
+}
+
+type discordInteraction struct {
+    Type int `json:"type"`
+    Data struct {
+        Name    string `json:"name"`
+        Options []struct {
+            Value interface{} `json:"value"`
+        } `json:"options"`
+    } `json:"data"`
+}
+
+func (h *DiscordHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+    body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+    if err != nil {
+        http.Error(w, "reading request", http.StatusBadRequest)
+        return
+    }
+
+    signature, err := hex.DecodeString(r.Header.Get("X-Signature-Ed25519"))
+    message := append([]byte(r.Header.Get("X-Signature-Timestamp")), body...)
+    if err != nil || !ed25519.Verify(h.PublicKey, message, signature) {
+        http.Error(w, "invalid request signature", http.StatusUnauthorized)
+        return
+    }
+
+    var interaction discordInteraction
+    if err := json.Unmarshal(body, &interaction); err != nil {
+        http.Error(w, "invalid interaction", http.StatusBadRequest)
+        return
+    }
+
+    var response interface{}
+    switch interaction.Type {
+    case discordPing:
+        response = map[string]int{"type": discordPong}
+    case discordApplicationCommand:
+        // Rebuild the chat command from the slash command's options in order
+        command := []string{"/" + interaction.Data.Name}
+        for _, option := range interaction.Data.Options {
+            command = append(command, fmt.Sprint(option.Value))
+        }
+        reply := h.Bot.Respond(strings.Join(command, " "))
+        if reply == "" {
+            reply = helpText
+        }
+        response = map[string]interface{}{
+            "type": discordMessage,
+            "data": map[string]string{"content": reply},
+        }
+    default:
+        http.Error(w, "unsupported interaction type", http.StatusBadRequest)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,87 @@
+package bot
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "io"
+    "net/http"
+    "net/url"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// slackMaxAge rejects signed requests older than this, so captured requests
+// cannot be replayed
+const slackMaxAge = 5 * time.Minute
+
+// SlackHandler answers a Slack slash command such as "/eth apy 1.1m 0.97".
+// The command's text is handled like a chat command ("apy 1.1m 0.97" as
+// "/apy 1.1m 0.97"). Slack signs every request with the app's signing secret;
+// unsigned, tampered, or stale requests are rejected.
+type SlackHandler struct {
+    SigningSecret string
+    Bot           *Bot
+}
+
+// NewSlackHandler creates a handler for the signing secret shown on the Slack
+// app's Basic Information page
+func NewSlackHandler(signingSecret string, bot *Bot) *SlackHandler {
+    return &SlackHandler{SigningSecret: signingSecret, Bot: bot}
+}
+
+func (h *SlackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+    body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+    if err != nil {
+        http.Error(w, "reading request", http.StatusBadRequest)
+        return
+    }
+
+    if !h.verify(r.Header.Get("X-Slack-Request-Timestamp"), r.Header.Get("X-Slack-Signature"), body, time.Now()) {
+        http.Error(w, "invalid request signature", http.StatusUnauthorized)
+        return
+    }
+
+    form, err := url.ParseQuery(string(body))
+    if err != nil {
+        http.Error(w, "invalid form", http.StatusBadRequest)
+        return
+    }
+
+    reply := helpText
+    if text := strings.TrimSpace(form.Get("text")); text != "" {
+        if answer := h.Bot.Respond("/" + strings.TrimPrefix(text, "/")); answer != "" {
+            reply = answer
+        }
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]string{
+        "response_type": "in_channel",
+        "text":          reply,
+    })
+}
+
+// verify checks Slack's v0 signature: an HMAC-SHA256 of "v0:timestamp:body"
+func (h *SlackHandler) verify(timestamp, signature string, body []byte, now time.Time) bool {
+    seconds, err := strconv.ParseInt(timestamp, 10, 64)
+    if err != nil {
+        return false
+    }
+    age := now.Sub(time.Unix(seconds, 0))
+    if age > slackMaxAge || age < -slackMaxAge {
+        return false
+    }
+
+    mac := hmac.New(sha256.New, []byte(h.SigningSecret))
+    mac.Write([]byte("v0:" + timestamp + ":"))
+    mac.Write(body)
+    expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+    return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,103 @@
+package bot
+
+import (
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/url"
+    "strconv"
+    "time"
+)
+
+// telegramPollSeconds is how long each getUpdates call waits for new messages
+const telegramPollSeconds = 30
+
+// Telegram answers commands sent to a Telegram bot using long polling
+type Telegram struct {
+    Token      string
+    Bot        *Bot
+    HTTPClient *http.Client
+    APIURL     string // defaults to https://api.telegram.org
+}
+
+// NewTelegram creates a Telegram connection for the bot token from @BotFather
+func NewTelegram(token string, bot *Bot) *Telegram {
+    return &Telegram{
+        Token:      token,
+        Bot:        bot,
+        HTTPClient: &http.Client{Timeout: (telegramPollSeconds + 10) * time.Second},
+        APIURL:     "https://api.telegram.org",
+    }
+}
+
+type telegramUpdate struct {
+    UpdateID int64 `json:"update_id"`
+    Message  *struct {
+        MessageID int64  `json:"message_id"`
+        Text      string `json:"text"`
+        Chat      struct {
+            ID int64 `json:"id"`
+        } `json:"chat"`
+    } `json:"message"`
+}
+
+// Run polls for messages and replies to commands until an API call fails
+func (t *Telegram) Run() error {
+    var offset int64
+    for {
+        var updates []telegramUpdate
+        err := t.call("getUpdates", url.Values{
+            "offset":          {strconv.FormatInt(offset, 10)},
+            "timeout":         {strconv.Itoa(telegramPollSeconds)},
+            "allowed_updates": {`["message"]`},
+        }, &updates)
+        if err != nil {
+            return err
+        }
+
+        for _, update := range updates {
+            offset = update.UpdateID + 1
+            if update.Message == nil {
+                continue
+            }
+            reply := t.Bot.Respond(update.Message.Text)
+            if reply == "" {
+                continue
+            }
+            err := t.call("sendMessage", url.Values{
+                "chat_id":             {strconv.FormatInt(update.Message.Chat.ID, 10)},
+                "text":                {reply},
+                "reply_to_message_id": {strconv.FormatInt(update.Message.MessageID, 10)},
+            }, nil)
+            if err != nil {
+                return err
+            }
+        }
+    }
+}
+
+// call invokes a Bot API method and decodes its result into out
+func (t *Telegram) call(method string, params url.Values, out interface{}) error {
+    resp, err := t.HTTPClient.PostForm(fmt.Sprintf("%s/bot%s/%s", t.APIURL, t.Token, method), params)
+    if err != nil {
+        // The error includes the URL, and with it the token
+        return fmt.Errorf("telegram %s: request failed", method)
+    }
+    defer resp.Body.Close()
+
+    var envelope struct {
+        OK          bool            `json:"ok"`
+        Description string          `json:"description"`
+        Result      json.RawMessage `json:"result"`
+    }
+    if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+        return fmt.Errorf("telegram %s: decoding response: %w", method, err)
+    }
+    if !envelope.OK {
+        return fmt.Errorf("telegram %s: %s", method, envelope.Description)
+    }
+    if out == nil {
+        return nil
+    }
+    return json.Unmarshal(envelope.Result, out)
+}
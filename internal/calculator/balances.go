@@ -0,0 +1,51 @@
+package calculator
+
+import (
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// EffectiveBalanceRates scales a 32 ETH validator's rewards to each effective
+// balance. Attestation and sync committee rewards, and since Electra the
+// chance of proposing, are all proportional to the effective balance, so every
+// reward scales with it. The balance range is the hysteresis band the
+// effective balance holds in; a validator whose balance equals its effective
+// balance needs DaysToNextStep of consensus rewards to climb to the next. Effective
+// balances above MAX_EFFECTIVE_BALANCE are only reachable with 0x02
+// credentials, which compound up to MAX_EFFECTIVE_BALANCE_ELECTRA.
+func EffectiveBalanceRates(results *types.RewardResults, effectiveBalances []uint64) []types.EffectiveBalanceRate {
+    hysteresis := config.EFFECTIVE_BALANCE_INCREMENT / config.HYSTERESIS_QUOTIENT
+    downward := hysteresis * config.HYSTERESIS_DOWNWARD_MULTIPLIER
+    upward := hysteresis * config.HYSTERESIS_UPWARD_MULTIPLIER
+    daysPerYear := float64(config.EPOCHS_PER_YEAR) / float64(config.EPOCHS_PER_DAY)
+
+    rates := make([]types.EffectiveBalanceRate, len(effectiveBalances))
+    for i, effective := range effectiveBalances {
+        scale := float64(EffectiveBalanceIncrements(effective)) / float64(EffectiveBalanceIncrements(config.MAX_EFFECTIVE_BALANCE))
+        compounding := effective > config.MAX_EFFECTIVE_BALANCE
+        maxEffective := config.MAX_EFFECTIVE_BALANCE
+        if compounding {
+            maxEffective = config.MAX_EFFECTIVE_BALANCE_ELECTRA
+        }
+
+        rate := types.EffectiveBalanceRate{
+            EffectiveBalance: float64(effective) / 1e9,
+            MinBalance:       float64(effective-downward) / 1e9,
+            Compounding:      compounding,
+            DailyRewards:     results.TotalAnnualRewardsGwei * scale / daysPerYear / 1e9,
+            AnnualRewards:    results.TotalAnnualRewardsGwei * scale / 1e9,
+            RelativeRewards:  scale * 100,
+        }
+        rate.APY = rate.AnnualRewards / rate.EffectiveBalance * 100
+
+        // At the maximum the excess is skimmed, so there is no next step
+        if effective < maxEffective {
+            rate.MaxBalance = float64(effective+upward) / 1e9
+            if consensus := results.ConsensusRewardsAnnualGwei * scale; consensus > 0 {
+                rate.DaysToNextStep = float64(upward) / consensus * daysPerYear
+            }
+        }
+        rates[i] = rate
+    }
+    return rates
+}
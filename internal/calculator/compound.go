@@ -0,0 +1,56 @@
+package calculator
+
+import (
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// ProjectCompounding follows an operator who pools every validator's net rewards
+// (skims plus execution rewards, less expected losses) and funds a new 32 ETH
+// validator whenever the pool allows, month by month. New validators are assumed
+// to start earning the month after they are funded; the activation queue and
+// the network's own growth are ignored. It is compared with withdrawing the
+// rewards instead, which leaves the validator count unchanged.
+func ProjectCompounding(results *types.RewardResults, capitalETH float64, months int) *types.CompoundingProjection {
+    stakeETH := float64(config.MAX_EFFECTIVE_BALANCE) / 1e9
+    netPerValidator := (results.TotalAnnualRewardsGwei - results.ExpectedMissedRewardsGwei - results.ExpectedPenaltiesGwei -
+        results.ExpectedSlashingLossGwei) / 1e9 / 12
+
+    validators := int(capitalETH / stakeETH)
+    pool := capitalETH - float64(validators)*stakeETH
+    withdrawn := 0.0
+
+    projection := &types.CompoundingProjection{
+        Capital:           capitalETH,
+        InitialValidators: validators,
+    }
+
+    for month := 1; month <= months; month++ {
+        rewards := float64(validators) * netPerValidator
+        pool += rewards
+        withdrawn += float64(projection.InitialValidators) * netPerValidator
+
+        funded := int(pool / stakeETH)
+        validators += funded
+        pool -= float64(funded) * stakeETH
+
+        projection.Timeline = append(projection.Timeline, types.CompoundingMonth{
+            Month:         month,
+            Validators:    validators,
+            NewValidators: funded,
+            Rewards:       rewards,
+            Pool:          pool,
+            TotalValue:    float64(validators)*stakeETH + pool,
+        })
+    }
+
+    years := float64(months) / 12
+    projection.FinalValidators = validators
+    projection.FinalValue = float64(validators)*stakeETH + pool
+    projection.WithdrawnValue = capitalETH + withdrawn
+    projection.CompoundedAPY = (math.Pow(projection.FinalValue/capitalETH, 1/years) - 1) * 100
+    projection.WithdrawnAPY = (math.Pow(projection.WithdrawnValue/capitalETH, 1/years) - 1) * 100
+    return projection
+}
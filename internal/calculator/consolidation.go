@@ -0,0 +1,60 @@
+package calculator
+
+import (
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// ConsolidationChurnLimit returns the balance in Gwei that consolidations may
+// move per epoch under Electra: what is left of the balance churn after the
+// activation and exit churn takes its share
+func ConsolidationChurnLimit(totalActiveBalance uint64) uint64 {
+    balanceChurn := max(config.MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA, totalActiveBalance/config.CHURN_LIMIT_QUOTIENT)
+    balanceChurn -= balanceChurn % config.EFFECTIVE_BALANCE_INCREMENT
+    return balanceChurn - min(config.MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT, balanceChurn)
+}
+
+// PlanConsolidations times requests consolidations of 32 ETH sources queued
+// behind queueAhead others. Requests reach the beacon chain
+// MAX_CONSOLIDATION_REQUESTS_PER_PAYLOAD per block, and each source's exit epoch
+// is pushed back as the consolidation churn is used up. A source stops earning
+// at its exit epoch but its balance reaches the target only when it becomes
+// withdrawable, so every consolidation idles 32 ETH for the withdrawability
+// delay. The rewards difference compares the sources' income over the window
+// until the last consolidation completes with leaving them as they are.
+func PlanConsolidations(results *types.RewardResults, requests, queueAhead int) *types.ConsolidationPlan {
+    churn := ConsolidationChurnLimit(uint64(results.ValidatorCount) * config.MAX_EFFECTIVE_BALANCE)
+    plan := &types.ConsolidationPlan{
+        Requests:   requests,
+        QueueAhead: queueAhead,
+        ChurnLimit: float64(churn) / 1e9,
+        PerEpoch:   float64(churn) / float64(config.MAX_EFFECTIVE_BALANCE),
+        IdleEpochs: float64(config.MIN_VALIDATOR_WITHDRAWABILITY_DELAY),
+    }
+
+    // The beacon chain drops consolidation requests while the churn cannot
+    // cover even one validator's activation balance
+    if churn <= config.MAX_EFFECTIVE_BALANCE || requests == 0 {
+        plan.Disabled = churn <= config.MAX_EFFECTIVE_BALANCE
+        return plan
+    }
+
+    completion := func(n int) float64 {
+        inclusion := math.Ceil(float64(n)/float64(config.MAX_CONSOLIDATION_REQUESTS_PER_PAYLOAD)) /
+            float64(config.SLOTS_PER_EPOCH)
+        churned := float64(queueAhead+n) / plan.PerEpoch
+        return math.Max(inclusion, churned) + float64(1+config.MAX_SEED_LOOKAHEAD) + plan.IdleEpochs
+    }
+    epochsPerDay := float64(config.EPOCHS_PER_DAY)
+    plan.FirstDays = completion(1) / epochsPerDay
+    plan.LastEpochs = completion(requests)
+    plan.LastDays = plan.LastEpochs / epochsPerDay
+
+    perEpoch := results.TotalAnnualRewardsGwei / 1e9 / float64(config.EPOCHS_PER_YEAR)
+    plan.RewardsKept = float64(requests) * perEpoch * plan.LastEpochs
+    plan.RewardsDifference = -float64(requests) * perEpoch * plan.IdleEpochs
+    plan.RewardsEarned = plan.RewardsKept + plan.RewardsDifference
+    return plan
+}
@@ -0,0 +1,144 @@
+package calculator
+
+import (
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// CalculateEpochDeltas computes every validator's attestation rewards and
+// penalties for the transition into state.CurrentEpoch, following the
+// consensus spec's get_flag_index_deltas and get_inactivity_penalty_deltas.
+// A validator with no exit epoch set has not exited.
+func CalculateEpochDeltas(state *types.NetworkState) ([]types.EpochDelta, error) {
+    if err := state.Validate(); err != nil {
+        return nil, err
+    }
+
+    deltas := make([]types.EpochDelta, len(state.Validators))
+    for i := range deltas {
+        deltas[i].ValidatorIndex = i
+    }
+
+    // No rewards are processed at genesis
+    if state.CurrentEpoch == 0 {
+        return deltas, nil
+    }
+    previousEpoch := state.CurrentEpoch - 1
+
+    // Balances of unslashed validators that earned each flag
+    weights := [3]uint64{config.TIMELY_SOURCE_WEIGHT, config.TIMELY_TARGET_WEIGHT, config.TIMELY_HEAD_WEIGHT}
+    var participatingBalance [3]uint64
+    for i := range state.Validators {
+        validator := &state.Validators[i]
+        if validator.Slashed || !isActiveValidator(validator, previousEpoch) {
+            continue
+        }
+        for flag := range weights {
+            if hasFlag(state, i, flag) {
+                participatingBalance[flag] += validator.EffectiveBalance
+            }
+        }
+    }
+
+    baseRewardPerIncrement := GetBaseRewardPerIncrement(state)
+    activeIncrements := EffectiveBalanceIncrements(state.TotalActiveBalance)
+    inLeak := inInactivityLeak(previousEpoch, state.FinalizedEpoch)
+    forkConfig := config.GetForkConfig(state.CurrentFork)
+
+    for i := range state.Validators {
+        validator := &state.Validators[i]
+        if !isEligibleValidator(validator, previousEpoch) {
+            continue
+        }
+
+        baseReward := EffectiveBalanceIncrements(validator.EffectiveBalance) * baseRewardPerIncrement
+        flagDeltas := [3]*int64{&deltas[i].Source, &deltas[i].Target, &deltas[i].Head}
+
+        for flag, weight := range weights {
+            if !validator.Slashed && hasFlag(state, i, flag) {
+                // Rewards are withheld during an inactivity leak
+                if !inLeak {
+                    participatingIncrements := EffectiveBalanceIncrements(max(config.EFFECTIVE_BALANCE_INCREMENT, participatingBalance[flag]))
+                    reward := mulDiv(baseReward*weight, participatingIncrements, activeIncrements*config.WEIGHT_DENOMINATOR)
+                    *flagDeltas[flag] += int64(reward)
+                }
+            } else if flag != config.TIMELY_HEAD_FLAG_INDEX {
+                *flagDeltas[flag] -= int64(baseReward * weight / config.WEIGHT_DENOMINATOR)
+            }
+        }
+
+        // Validators that missed the target accrue the inactivity penalty
+        if validator.Slashed || !hasFlag(state, i, config.TIMELY_TARGET_FLAG_INDEX) {
+            penalty := mulDiv(validator.EffectiveBalance, validator.InactivityScore,
+                config.INACTIVITY_SCORE_BIAS*forkConfig.InactivityPenaltyQuotient)
+            deltas[i].Inactivity -= int64(penalty)
+        }
+    }
+
+    return deltas, nil
+}
+
+// UpdateInactivityScores applies the consensus spec's
+// process_inactivity_updates, which runs just before the rewards and
+// penalties of the transition CalculateEpochDeltas computes, so the
+// inactivity penalties use the updated scores
+func UpdateInactivityScores(state *types.NetworkState) {
+    if state.CurrentEpoch == 0 {
+        return
+    }
+    previousEpoch := state.CurrentEpoch - 1
+    inLeak := inInactivityLeak(previousEpoch, state.FinalizedEpoch)
+
+    for i := range state.Validators {
+        validator := &state.Validators[i]
+        if !isEligibleValidator(validator, previousEpoch) {
+            continue
+        }
+        timely := !validator.Slashed && isActiveValidator(validator, previousEpoch) &&
+            hasFlag(state, i, config.TIMELY_TARGET_FLAG_INDEX)
+        validator.InactivityScore = CalculateInactivityScore(validator.InactivityScore, timely, !inLeak)
+    }
+}
+
+// inInactivityLeak reports whether the finality delay, the epochs from the
+// finalized epoch to previousEpoch, exceeds MIN_EPOCHS_TO_INACTIVITY_PENALTY,
+// as the spec's is_in_inactivity_leak does. A state finalized in or after
+// previousEpoch has no delay.
+func inInactivityLeak(previousEpoch, finalizedEpoch uint64) bool {
+    return finalizedEpoch < previousEpoch && previousEpoch-finalizedEpoch > config.MIN_EPOCHS_TO_INACTIVITY_PENALTY
+}
+
+// ComputeTotalActiveBalance sums the effective balances of the validators
+// active in epoch, as the spec's get_total_active_balance does. Validators not
+// yet activated or already exited are left out. A slashed validator still
+// counts until its exit epoch, since slashing only schedules the exit. The
+// result is at least EFFECTIVE_BALANCE_INCREMENT.
+func ComputeTotalActiveBalance(state *types.NetworkState, epoch uint64) uint64 {
+    var total uint64
+    for i := range state.Validators {
+        if isActiveValidator(&state.Validators[i], epoch) {
+            total += state.Validators[i].EffectiveBalance
+        }
+    }
+    return max(config.EFFECTIVE_BALANCE_INCREMENT, total)
+}
+
+// isActiveValidator reports whether the validator was active in epoch
+func isActiveValidator(validator *types.Validator, epoch uint64) bool {
+    return validator.ActivationEpoch <= epoch && (validator.ExitEpoch == 0 || epoch < validator.ExitEpoch)
+}
+
+// isEligibleValidator reports whether the validator receives rewards or
+// penalties for epoch: it was active, or it is slashed and not yet withdrawable
+func isEligibleValidator(validator *types.Validator, epoch uint64) bool {
+    return isActiveValidator(validator, epoch) ||
+        (validator.Slashed && epoch+1 < validator.WithdrawableEpoch)
+}
+
+// hasFlag reports whether validator index earned the participation flag in the previous epoch
+func hasFlag(state *types.NetworkState, index, flag int) bool {
+    if state.PreviousEpochParticipation == nil {
+        return true
+    }
+    return state.PreviousEpochParticipation[index]&(1<<flag) != 0
+}
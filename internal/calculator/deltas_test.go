@@ -0,0 +1,141 @@
+package calculator
+
+import (
+    "testing"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// deltaState is a network of four 32 ETH validators at currentEpoch, finalized
+// at finalizedEpoch: one attesting to everything, one to the source only, one
+// offline, and one slashed that attested to everything. Each starts with an
+// inactivity score of 40.
+func deltaState(currentEpoch, finalizedEpoch uint64) *types.NetworkState {
+    state := &types.NetworkState{
+        CurrentEpoch:               currentEpoch,
+        FinalizedEpoch:             finalizedEpoch,
+        PreviousEpochParticipation: []byte{0b111, 0b001, 0, 0b111},
+    }
+    for i := 0; i < 4; i++ {
+        state.Validators = append(state.Validators, types.Validator{
+            EffectiveBalance: config.MAX_EFFECTIVE_BALANCE,
+            InactivityScore:  40,
+        })
+    }
+    state.Validators[3].Slashed = true
+    state.Validators[3].WithdrawableEpoch = currentEpoch + 100
+    state.TotalActiveBalance = ComputeTotalActiveBalance(state, currentEpoch)
+    return state
+}
+
+func TestInInactivityLeak(t *testing.T) {
+    delay := config.MIN_EPOCHS_TO_INACTIVITY_PENALTY
+    tests := []struct {
+        previous, finalized uint64
+        want                bool
+    }{
+        {999, 998, false},
+        {999, 999, false},
+        {999, 1000, false}, // finalized in the current epoch
+        {999, 999 - delay, false},
+        {999, 998 - delay, true},
+        {5, 0, true},
+    }
+    for _, tt := range tests {
+        if got := inInactivityLeak(tt.previous, tt.finalized); got != tt.want {
+            t.Errorf("inInactivityLeak(%d, %d) = %v, want %v", tt.previous, tt.finalized, got, tt.want)
+        }
+    }
+}
+
+func TestCalculateEpochDeltas(t *testing.T) {
+    tests := []struct {
+        name                         string
+        currentEpoch, finalizedEpoch uint64
+        inLeak                       bool
+    }{
+        {"finalizing", 1000, 998, false},
+        {"finalized in the current epoch", 1000, 1000, false},
+        {"inactivity leak", 1000, 990, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            state := deltaState(tt.currentEpoch, tt.finalizedEpoch)
+            deltas, err := CalculateEpochDeltas(state)
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            // Two of four validators' stake earned the source, one the target
+            // and head. Rewards are withheld in a leak; penalties are not.
+            baseReward := EffectiveBalanceIncrements(config.MAX_EFFECTIVE_BALANCE) * GetBaseRewardPerIncrement(state)
+            reward := func(weight, participants uint64) int64 {
+                if tt.inLeak {
+                    return 0
+                }
+                return int64(baseReward * weight * participants / (4 * config.WEIGHT_DENOMINATOR))
+            }
+            penalty := func(weight uint64) int64 {
+                return -int64(baseReward * weight / config.WEIGHT_DENOMINATOR)
+            }
+            quotient := config.GetForkConfig(state.CurrentFork).InactivityPenaltyQuotient
+            inactivity := -int64(config.MAX_EFFECTIVE_BALANCE * 40 / (config.INACTIVITY_SCORE_BIAS * quotient))
+
+            source, target, head := config.TIMELY_SOURCE_WEIGHT, config.TIMELY_TARGET_WEIGHT, config.TIMELY_HEAD_WEIGHT
+            want := []types.EpochDelta{
+                {ValidatorIndex: 0, Source: reward(source, 2), Target: reward(target, 1), Head: reward(head, 1)},
+                {ValidatorIndex: 1, Source: reward(source, 2), Target: penalty(target), Inactivity: inactivity},
+                {ValidatorIndex: 2, Source: penalty(source), Target: penalty(target), Inactivity: inactivity},
+                {ValidatorIndex: 3, Source: penalty(source), Target: penalty(target), Inactivity: inactivity},
+            }
+            for i := range want {
+                if deltas[i] != want[i] {
+                    t.Errorf("validator %d: got %+v, want %+v", i, deltas[i], want[i])
+                }
+            }
+        })
+    }
+
+    genesis := deltaState(0, 0)
+    deltas, err := CalculateEpochDeltas(genesis)
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, delta := range deltas {
+        if delta.Net() != 0 {
+            t.Errorf("validator %d changes by %d at genesis", delta.ValidatorIndex, delta.Net())
+        }
+    }
+
+    bad := deltaState(1000, 998)
+    bad.FinalizedEpoch = 1001
+    if _, err := CalculateEpochDeltas(bad); err == nil {
+        t.Error("finalized epoch after the current epoch accepted")
+    }
+}
+
+func TestUpdateInactivityScores(t *testing.T) {
+    bias, recovery := config.INACTIVITY_SCORE_BIAS, config.INACTIVITY_SCORE_RECOVERY_RATE
+    tests := []struct {
+        name                         string
+        currentEpoch, finalizedEpoch uint64
+        want                         []uint64
+    }{
+        // Scores recover while the chain finalizes, even when it finalized
+        // in the current epoch
+        {"finalizing", 1000, 998, []uint64{39 - recovery, 40 + bias - recovery, 40 + bias - recovery, 40 + bias - recovery}},
+        {"finalized in the current epoch", 1000, 1000, []uint64{39 - recovery, 40 + bias - recovery, 40 + bias - recovery, 40 + bias - recovery}},
+        {"inactivity leak", 1000, 990, []uint64{39, 40 + bias, 40 + bias, 40 + bias}},
+        {"genesis", 0, 0, []uint64{40, 40, 40, 40}},
+    }
+    for _, tt := range tests {
+        state := deltaState(tt.currentEpoch, tt.finalizedEpoch)
+        UpdateInactivityScores(state)
+        for i, want := range tt.want {
+            if got := state.Validators[i].InactivityScore; got != want {
+                t.Errorf("%s: validator %d has score %d, want %d", tt.name, i, got, want)
+            }
+        }
+    }
+}
@@ -0,0 +1,49 @@
+package calculator
+
+import (
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// maxEquilibriumIterations bounds the fixed-point search in SolveStakingEquilibrium
+const maxEquilibriumIterations = 20
+
+// SolveStakingEquilibrium finds the validator count at which staking APY equals
+// the given opportunity-cost yield (in percent). Beyond that point stakers would
+// earn more elsewhere, so it predicts where total stake stabilizes.
+func SolveStakingEquilibrium(targetYield, participationRate float64) *types.EquilibriumResult {
+    maxCount := int(config.TOTAL_ETH_SUPPLY / (config.MAX_EFFECTIVE_BALANCE / 1e9))
+    apyAt := func(count int) float64 {
+        return ValidatorSetComparison(participationRate, count)[0].APY
+    }
+
+    // APY falls with 1/sqrt(total stake), so N' = N * (APY(N) / target)^2
+    // converges in a few steps
+    count := 1000000
+    apy := apyAt(count)
+    for i := 0; i < maxEquilibriumIterations; i++ {
+        next := int(math.Round(float64(count) * math.Pow(apy/targetYield, 2)))
+        next = int(math.Max(1, math.Min(float64(next), float64(maxCount))))
+        // Integer rounding in the base reward can leave the search alternating
+        // between neighbours, which is as close as the curve allows
+        if math.Abs(float64(next-count)) <= 1 {
+            break
+        }
+
+        count = next
+        apy = apyAt(count)
+    }
+
+    totalStaked := uint64(count) * config.MAX_EFFECTIVE_BALANCE / 1e9
+
+    return &types.EquilibriumResult{
+        TargetYield:    targetYield,
+        ValidatorCount: count,
+        TotalStaked:    totalStaked,
+        StakingRatio:   float64(totalStaked) / config.TOTAL_ETH_SUPPLY * 100,
+        APY:            apy,
+        ExceedsSupply:  count == maxCount && apy > targetYield,
+    }
+}
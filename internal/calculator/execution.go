@@ -0,0 +1,34 @@
+package calculator
+
+import (
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// ApplyExecutionRewards adds expected execution layer income to the results. Priority
+// fees and MEV are given in Gwei per proposed block and are earned only on proposals,
+// so unlike consensus rewards they do not scale with network participation.
+func ApplyExecutionRewards(results *types.RewardResults, priorityFeesPerBlock, mevPerBlock float64) {
+    results.PriorityFeesPerBlockGwei = priorityFeesPerBlock
+    results.MEVPerBlockGwei = mevPerBlock
+    results.PriorityFeesAnnualGwei = priorityFeesPerBlock * results.ExpectedProposalsPerYear
+    results.MEVRewardsAnnualGwei = mevPerBlock * results.ExpectedProposalsPerYear
+    results.ExecutionRewardsAnnualGwei = results.PriorityFeesAnnualGwei + results.MEVRewardsAnnualGwei
+
+    // The APYs gain the execution APY, less any a previous call added, so
+    // adjustments already made to them are kept
+    executionAPY := results.ExecutionRewardsAnnualGwei / float64(config.MAX_EFFECTIVE_BALANCE) * 100
+    consensusAPY := results.ConsensusRewardsAnnualGwei / float64(config.MAX_EFFECTIVE_BALANCE) * 100
+    addedAPY := executionAPY - (results.APY - consensusAPY)
+
+    results.TotalAnnualRewardsGwei = results.ConsensusRewardsAnnualGwei + results.ExecutionRewardsAnnualGwei
+    results.APY = consensusAPY + executionAPY
+    results.BaseAPY += addedAPY
+    results.EffectiveAPY += addedAPY
+    results.RiskAdjustedAPY += addedAPY
+
+    setPeriods(results)
+
+    // Execution income rides on proposals, so it widens the spread too
+    setRewardVariance(results)
+}
@@ -0,0 +1,52 @@
+package calculator
+
+import (
+    "math"
+    "testing"
+
+    "github.com/eth-rewards-calculator/internal/config"
+)
+
+func TestApplyExecutionRewards(t *testing.T) {
+    state, err := NewTemplateState(1_000_000)
+    if err != nil {
+        t.Fatal(err)
+    }
+    results := calculateRewards(state, 1_000_000, 0.9)
+    ApplyRiskAdjustment(state, results, 0.02, 0.02, 0.001)
+    before := *results
+
+    ApplyExecutionRewards(results, 0.02e9, 0.05e9)
+    executionAPY := (0.02e9 + 0.05e9) * results.ExpectedProposalsPerYear / float64(config.MAX_EFFECTIVE_BALANCE) * 100
+    if executionAPY <= 0 {
+        t.Fatal("no execution income")
+    }
+
+    // Execution income adds to every APY, keeping the risk adjustment and
+    // participation boost already applied
+    apys := []struct {
+        name          string
+        before, after float64
+    }{
+        {"APY", before.APY, results.APY},
+        {"base APY", before.BaseAPY, results.BaseAPY},
+        {"effective APY", before.EffectiveAPY, results.EffectiveAPY},
+        {"risk-adjusted APY", before.RiskAdjustedAPY, results.RiskAdjustedAPY},
+    }
+    for _, apy := range apys {
+        if math.Abs(apy.after-(apy.before+executionAPY)) > 1e-9 {
+            t.Errorf("%s: %g before, %g after, want %g more", apy.name, apy.before, apy.after, executionAPY)
+        }
+    }
+    if results.RiskAdjustedAPY >= results.APY {
+        t.Errorf("risk-adjusted APY %g is not below the APY %g", results.RiskAdjustedAPY, results.APY)
+    }
+
+    // Applying it again replaces the execution income rather than adding it twice
+    again := *results
+    ApplyExecutionRewards(&again, 0.02e9, 0.05e9)
+    if math.Abs(again.RiskAdjustedAPY-results.RiskAdjustedAPY) > 1e-9 || math.Abs(again.EffectiveAPY-results.EffectiveAPY) > 1e-9 {
+        t.Errorf("applied twice: risk-adjusted %g, effective %g; once %g, %g",
+            again.RiskAdjustedAPY, again.EffectiveAPY, results.RiskAdjustedAPY, results.EffectiveAPY)
+    }
+}
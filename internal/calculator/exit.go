@@ -0,0 +1,114 @@
+package calculator
+
+import (
+    "math"
+    "math/big"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// EstimateExitDelay estimates how long an exit takes, split into the time spent in the
+// churn-limited exit queue (still earning rewards) and the time from the exit epoch
+// until the balance is swept to the withdrawal address (earning nothing)
+func EstimateExitDelay(activeValidators, exitQueueLength int) (queueEpochs, withdrawalEpochs float64) {
+    queueEpochs = float64(exitQueueLength) / float64(exitChurnLimit(activeValidators))
+
+    // On average the withdrawal sweep reaches the validator halfway through a full pass
+    withdrawalsPerEpoch := float64(config.MAX_WITHDRAWALS_PER_PAYLOAD * config.SLOTS_PER_EPOCH)
+    sweepEpochs := float64(activeValidators) / withdrawalsPerEpoch / 2
+
+    withdrawalEpochs = float64(config.MIN_VALIDATOR_WITHDRAWABILITY_DELAY) + sweepEpochs
+
+    return
+}
+
+// exitChurnLimit is the number of validators that may exit per epoch. Exit
+// churn is not capped like activation churn.
+func exitChurnLimit(activeValidators int) uint64 {
+    return max(config.MIN_PER_EPOCH_CHURN_LIMIT, uint64(activeValidators)/config.CHURN_LIMIT_QUOTIENT)
+}
+
+// ApplyExitDelayCost computes the opportunity cost of the capital locked during an exit,
+// given the yield (in percent) it could earn elsewhere
+func ApplyExitDelayCost(results *types.RewardResults, exitQueueLength int, alternativeYield float64) {
+    queueEpochs, withdrawalEpochs := EstimateExitDelay(results.ValidatorCount, exitQueueLength)
+
+    queueDays := queueEpochs / float64(config.EPOCHS_PER_DAY)
+    withdrawalDays := withdrawalEpochs / float64(config.EPOCHS_PER_DAY)
+    stake := float64(config.MAX_EFFECTIVE_BALANCE)
+
+    // While queued the stake still earns staking rewards, so only the yield gap is lost;
+    // after the exit epoch the full alternative yield is lost
+    queueCost := stake * math.Max(0, alternativeYield-results.APY) / 100 * queueDays / 365.25
+    withdrawalCost := stake * alternativeYield / 100 * withdrawalDays / 365.25
+
+    results.AlternativeYield = alternativeYield
+    results.ExitQueueDays = queueDays
+    results.WithdrawalDelayDays = withdrawalDays
+    results.ExitDelayCostGwei = queueCost + withdrawalCost
+}
+
+// WithdrawalRequestFee returns the EIP-7002 fee in wei for submitting a withdrawal
+// request when the system contract holds excess requests above its target. The
+// fee grows exponentially with the excess, using the EIP's integer approximation.
+func WithdrawalRequestFee(excess uint64) *big.Int {
+    factor := new(big.Int).SetUint64(config.MIN_WITHDRAWAL_REQUEST_FEE)
+    numerator := new(big.Int).SetUint64(excess)
+    denominator := new(big.Int).SetUint64(config.WITHDRAWAL_REQUEST_FEE_UPDATE_FRACTION)
+
+    // fake_exponential(factor, numerator, denominator)
+    output := new(big.Int)
+    accum := new(big.Int).Mul(factor, denominator)
+    for i := int64(1); accum.Sign() > 0; i++ {
+        output.Add(output, accum)
+        accum.Mul(accum, numerator)
+        accum.Div(accum, new(big.Int).Mul(denominator, big.NewInt(i)))
+    }
+    return output.Div(output, denominator)
+}
+
+// PlanExit times an exit requested from the execution layer: the request waits
+// behind pendingRequests in the system contract, then joins the churn-limited
+// exit queue behind exitQueueLength validators, then waits out the
+// withdrawability delay and the withdrawal sweep.
+func PlanExit(activeValidators, exitQueueLength int, pendingRequests, excessRequests uint64) *types.ExitPlan {
+    fee := WithdrawalRequestFee(excessRequests)
+    feeETH, _ := new(big.Float).Quo(new(big.Float).SetInt(fee), big.NewFloat(1e18)).Float64()
+
+    plan := &types.ExitPlan{
+        ExcessRequests:  excessRequests,
+        PendingRequests: pendingRequests,
+        RequestFeeWei:   fee.String(),
+        RequestFee:      feeETH,
+        // Without new requests the excess falls by the target every block
+        BlocksToMinimumFee: (excessRequests + config.TARGET_WITHDRAWAL_REQUESTS_PER_BLOCK - 1) /
+            config.TARGET_WITHDRAWAL_REQUESTS_PER_BLOCK,
+    }
+
+    // Requests are dequeued in order, MAX_WITHDRAWAL_REQUESTS_PER_BLOCK per block
+    requestSlots := pendingRequests/config.MAX_WITHDRAWAL_REQUESTS_PER_BLOCK + 1
+    elapsed := float64(requestSlots) / float64(config.SLOTS_PER_EPOCH)
+    plan.Milestones = append(plan.Milestones, exitMilestone("Request processed by the beacon chain", elapsed))
+
+    // An exit cannot take effect before the seed lookahead has passed
+    queueEpochs, withdrawalEpochs := EstimateExitDelay(activeValidators, exitQueueLength)
+    elapsed += math.Max(queueEpochs, float64(1+config.MAX_SEED_LOOKAHEAD))
+    plan.Milestones = append(plan.Milestones, exitMilestone("Exit epoch reached, rewards stop", elapsed))
+
+    elapsed += float64(config.MIN_VALIDATOR_WITHDRAWABILITY_DELAY)
+    plan.Milestones = append(plan.Milestones, exitMilestone("Validator withdrawable", elapsed))
+
+    elapsed += withdrawalEpochs - float64(config.MIN_VALIDATOR_WITHDRAWABILITY_DELAY)
+    plan.Milestones = append(plan.Milestones, exitMilestone("Balance swept to the execution address", elapsed))
+
+    return plan
+}
+
+func exitMilestone(stage string, epochs float64) types.ExitMilestone {
+    return types.ExitMilestone{
+        Stage:  stage,
+        Epochs: epochs,
+        Days:   epochs / float64(config.EPOCHS_PER_DAY),
+    }
+}
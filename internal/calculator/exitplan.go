@@ -0,0 +1,66 @@
+package calculator
+
+import (
+    "math"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// PlanStaggeredExits schedules voluntary exits for a fleet of validators so
+// every balance is withdrawn by deadline. The fleet exits through the exit
+// churn behind queueAhead validators, one after another, and the last exit is
+// placed as late as the withdrawal delay allows so the validators keep earning.
+// Each exit is submitted the seed lookahead before its exit epoch, assuming
+// the queue has drained by then. When the deadline cannot be met, every exit
+// is submitted at start and the schedule shows when the fleet is out.
+func PlanStaggeredExits(results *types.RewardResults, validators, queueAhead int, start, deadline time.Time) *types.ExitSchedule {
+    churn := float64(exitChurnLimit(results.ValidatorCount))
+    lookahead := float64(1 + config.MAX_SEED_LOOKAHEAD)
+    _, withdrawal := EstimateExitDelay(results.ValidatorCount, queueAhead)
+    epochsPerDay := float64(config.EPOCHS_PER_DAY)
+    perEpoch := results.TotalAnnualRewardsGwei / 1e9 / float64(config.EPOCHS_PER_YEAR)
+
+    schedule := &types.ExitSchedule{
+        Validators:     validators,
+        Deadline:       deadline.Format("2006-01-02"),
+        QueueAhead:     queueAhead,
+        ChurnLimit:     uint64(churn),
+        WithdrawalDays: withdrawal / epochsPerDay,
+    }
+
+    earliest := func(k int) float64 { return lookahead + float64(queueAhead+k)/churn }
+    lastExit := deadline.Sub(start).Hours()/24*epochsPerDay - withdrawal
+    schedule.Feasible = lastExit-float64(validators-1)/churn >= earliest(1)
+
+    date := func(epochs float64) string {
+        return start.Add(time.Duration(epochs / epochsPerDay * 24 * float64(time.Hour))).Format("2006-01-02")
+    }
+
+    var batch *types.ExitBatch
+    for k := 1; k <= validators; k++ {
+        exit, submit := earliest(k), 0.0
+        if schedule.Feasible {
+            exit = math.Max(exit, lastExit-float64(validators-k)/churn)
+            submit = exit - lookahead
+        }
+        income := perEpoch * exit
+        schedule.ExpectedIncome += income
+        schedule.ImmediateIncome += perEpoch * earliest(k)
+
+        if day := date(submit); batch == nil || batch.Date != day {
+            schedule.Batches = append(schedule.Batches, types.ExitBatch{Date: day})
+            batch = &schedule.Batches[len(schedule.Batches)-1]
+        }
+        batch.Validators++
+        batch.Cumulative = k
+        batch.ExitDate = date(exit)
+        batch.Income += income
+
+        if k == validators {
+            schedule.CompletionDate = date(exit + withdrawal)
+        }
+    }
+    return schedule
+}
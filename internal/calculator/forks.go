@@ -0,0 +1,95 @@
+package calculator
+
+import (
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// ComparedForks are the reward regimes CompareForks reports: Phase 0, Altair,
+// and Bellatrix, whose rules still apply in later forks
+var ComparedForks = []string{"phase0", "altair", "bellatrix"}
+
+// CompareForks computes the same scenario under each fork's reward weights and
+// penalty quotients. inactivityEpochs is the length of a leak an offline
+// validator sits through, and slashedCount the validators slashed together.
+func CompareForks(validatorCount int, participationRate float64, inactivityEpochs, slashedCount int) []types.ForkComparison {
+    template := []types.Validator{{EffectiveBalance: config.MAX_EFFECTIVE_BALANCE}}
+    comparisons := make([]types.ForkComparison, len(ComparedForks))
+
+    for i, fork := range ComparedForks {
+        state := &types.NetworkState{
+            Validators:         template,
+            TotalActiveBalance: uint64(validatorCount) * config.MAX_EFFECTIVE_BALANCE,
+            CurrentEpoch:       1000,
+            FinalizedEpoch:     998,
+            CurrentFork:        fork,
+        }
+        forkConfig := config.GetForkConfig(fork)
+
+        var comparison types.ForkComparison
+        if fork == "phase0" {
+            comparison = phase0Rewards(state, validatorCount, participationRate)
+        } else {
+            // Altair's participation flag weights are unchanged since
+            results := calculateRewards(state, validatorCount, participationRate)
+            comparison = types.ForkComparison{
+                BaseRewardGwei:                 results.BaseRewardPerEpochGwei,
+                AttestationRewardPerEpochGwei:  results.AttestationRewardPerEpochGwei,
+                ProposerRewardPerBlockGwei:     results.AvgProposerRewardPerBlockGwei * results.ParticipationMultiplier,
+                AttestationRewardsAnnualGwei:   results.AttestationRewardsAnnualGwei,
+                ProposerRewardsAnnualGwei:      results.ProposerRewardsAnnualGwei,
+                SyncCommitteeRewardsAnnualGwei: results.SyncCommitteeRewardsAnnualGwei,
+            }
+        }
+
+        comparison.Fork = fork
+        comparison.TotalAnnualRewardsGwei = comparison.AttestationRewardsAnnualGwei + comparison.ProposerRewardsAnnualGwei +
+            comparison.SyncCommitteeRewardsAnnualGwei
+        comparison.APY = comparison.TotalAnnualRewardsGwei / float64(config.MAX_EFFECTIVE_BALANCE) * 100
+
+        // The leak penalty grows by one step each epoch (finality delay in Phase 0,
+        // the inactivity score since Altair), so the total is quadratic in its length
+        epochs := float64(inactivityEpochs)
+        comparison.InactivityPenaltyQuotient = forkConfig.InactivityPenaltyQuotient
+        comparison.InactivityLeakLossGwei = math.Min(float64(config.MAX_EFFECTIVE_BALANCE),
+            float64(config.MAX_EFFECTIVE_BALANCE)*epochs*(epochs+1)/2/float64(forkConfig.InactivityPenaltyQuotient))
+
+        slashedBalance := uint64(max(1, uint64(slashedCount))) * config.MAX_EFFECTIVE_BALANCE
+        comparison.SlashingPenaltyGwei = slashingPenaltiesFor(state, typicalValidator(state), slashedBalance).TotalPenaltyGwei
+
+        comparisons[i] = comparison
+    }
+
+    return comparisons
+}
+
+// phase0Rewards models Phase 0 rewards: a base reward split four ways (source,
+// target, head, inclusion delay), with the proposer paid 1/PROPOSER_REWARD_QUOTIENT
+// of the base reward for every attestation it includes and no sync committee.
+// Participation is applied as in calculateRewards so forks compare like for like.
+func phase0Rewards(state *types.NetworkState, validatorCount int, participationRate float64) types.ForkComparison {
+    // Before Altair the base reward scaled with the effective balance itself,
+    // not its increments
+    baseReward := mulDiv(config.MAX_EFFECTIVE_BALANCE, config.BASE_REWARD_FACTOR,
+        IntegerSquareRoot(state.TotalActiveBalance)) / config.BASE_REWARDS_PER_EPOCH
+    proposerReward := baseReward / config.PROPOSER_REWARD_QUOTIENT
+
+    // Source, target, and head each pay the full base reward; inclusion with a
+    // delay of one slot pays the rest after the proposer's share
+    attestationReward := 3*baseReward + baseReward - proposerReward
+
+    // Every attestation is included once, in one of SLOTS_PER_EPOCH blocks
+    perBlock := float64(proposerReward) * float64(validatorCount) / float64(config.SLOTS_PER_EPOCH)
+    proposalsPerYear := float64(config.SLOTS_PER_EPOCH*config.EPOCHS_PER_YEAR) / float64(validatorCount)
+
+    multiplier := 1.0 / participationRate
+    return types.ForkComparison{
+        BaseRewardGwei:                baseReward,
+        AttestationRewardPerEpochGwei: attestationReward,
+        ProposerRewardPerBlockGwei:    perBlock * multiplier,
+        AttestationRewardsAnnualGwei:  float64(attestationReward) * float64(config.EPOCHS_PER_YEAR) * multiplier,
+        ProposerRewardsAnnualGwei:     perBlock * proposalsPerYear * multiplier,
+    }
+}
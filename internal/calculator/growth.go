@@ -0,0 +1,98 @@
+package calculator
+
+import (
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// GrowthModel projects the size of the validator set into the future
+type GrowthModel interface {
+    Name() string
+    ValidatorsAt(months float64) int
+}
+
+// LinearGrowth adds a fixed number of validators every month
+type LinearGrowth struct {
+    Initial  int
+    PerMonth float64
+}
+
+// Name returns the model identifier used in reports
+func (g LinearGrowth) Name() string {
+    return "linear"
+}
+
+// ValidatorsAt returns the projected validator count after the given number of months
+func (g LinearGrowth) ValidatorsAt(months float64) int {
+    count := float64(g.Initial) + g.PerMonth*months
+    return int(math.Max(1, math.Round(count)))
+}
+
+// LogisticGrowth grows at Rate per month and saturates at Capacity validators
+type LogisticGrowth struct {
+    Initial  int
+    Capacity int
+    Rate     float64
+}
+
+// NewLogisticGrowth creates a logistic model whose initial growth matches perMonth
+// validators per month, so it can be compared directly against a linear model
+func NewLogisticGrowth(initial, capacity int, perMonth float64) LogisticGrowth {
+    rate := 0.0
+    if initial > 0 && capacity > initial {
+        rate = perMonth / (float64(initial) * (1 - float64(initial)/float64(capacity)))
+    }
+
+    return LogisticGrowth{
+        Initial:  initial,
+        Capacity: capacity,
+        Rate:     rate,
+    }
+}
+
+// Name returns the model identifier used in reports
+func (g LogisticGrowth) Name() string {
+    return "logistic"
+}
+
+// ValidatorsAt returns the projected validator count after the given number of months
+func (g LogisticGrowth) ValidatorsAt(months float64) int {
+    if g.Initial <= 0 || g.Capacity <= 0 {
+        return int(math.Max(1, float64(g.Initial)))
+    }
+
+    // N(t) = K / (1 + ((K - N0) / N0) * e^(-rt))
+    k := float64(g.Capacity)
+    n0 := float64(g.Initial)
+    count := k / (1 + ((k-n0)/n0)*math.Exp(-g.Rate*months))
+
+    return int(math.Max(1, math.Round(count)))
+}
+
+// ForecastAPY projects validator count and APY month by month under a growth model
+func ForecastAPY(model GrowthModel, months int, participation float64) *types.GrowthForecast {
+    counts := make([]int, months+1)
+    for month := range counts {
+        counts[month] = model.ValidatorsAt(float64(month))
+    }
+
+    comparison := ValidatorSetComparison(participation, counts...)
+
+    forecast := &types.GrowthForecast{
+        Model:  model.Name(),
+        Points: make([]types.ForecastPoint, len(comparison)),
+    }
+
+    for month, result := range comparison {
+        forecast.Points[month] = types.ForecastPoint{
+            Month:          month,
+            ValidatorCount: result.ValidatorCount,
+            TotalStaked:    result.TotalStaked,
+            APY:            result.APY,
+            APYChange:      result.APY - comparison[0].APY,
+        }
+    }
+
+    return forecast
+}
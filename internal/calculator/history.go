@@ -0,0 +1,44 @@
+package calculator
+
+import (
+    "fmt"
+    "math"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/config"
+)
+
+// NetworkAsOf returns the mainnet validator set on a date, interpolating
+// linearly between the bundled samples. Dates outside the samples are errors.
+func NetworkAsOf(date time.Time) (config.NetworkSample, error) {
+    samples := config.HistoricalNetwork
+    first, last := samples[0], samples[len(samples)-1]
+
+    var prev config.NetworkSample
+    var prevDate time.Time
+    for i, sample := range samples {
+        sampleDate, err := time.Parse("2006-01-02", sample.Date)
+        if err != nil {
+            return config.NetworkSample{}, err
+        }
+        if date.Equal(sampleDate) {
+            return sample, nil
+        }
+        if date.Before(sampleDate) {
+            if i == 0 {
+                break
+            }
+            f := date.Sub(prevDate).Hours() / sampleDate.Sub(prevDate).Hours()
+            validators := float64(prev.ActiveValidators) + f*float64(sample.ActiveValidators-prev.ActiveValidators)
+            return config.NetworkSample{
+                Date:             date.Format("2006-01-02"),
+                ActiveValidators: int(math.Round(validators)),
+                Participation:    prev.Participation + f*(sample.Participation-prev.Participation),
+            }, nil
+        }
+        prev, prevDate = sample, sampleDate
+    }
+
+    return config.NetworkSample{}, fmt.Errorf("no mainnet history for %s (available from %s to %s)",
+        date.Format("2006-01-02"), first.Date, last.Date)
+}
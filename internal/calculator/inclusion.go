@@ -0,0 +1,132 @@
+package calculator
+
+import (
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// InclusionModel describes how attestations make it into blocks. Every
+// validator votes once per epoch, so a slot's committees produce
+// validators / SLOTS_PER_EPOCH votes. Each block carries aggregates of the
+// votes not yet on chain, and its proposer is paid for the flags each vote
+// earns the first time it is included. A vote must be included within the
+// inclusion window, and it earns the head flag only in the next slot and the
+// source flag within √SLOTS_PER_EPOCH slots.
+type InclusionModel struct {
+    // MissedSlotRate is the share of slots without a block. A block after
+    // missed slots picks up their votes as well.
+    MissedSlotRate float64
+
+    // AggregateCoverage is the share of the votes still pending that a block's
+    // aggregates carry. Aggregators miss votes that arrive late or never reach
+    // them; those wait for a later block within the window.
+    AggregateCoverage float64
+}
+
+// DefaultInclusionModel is the model reward projections use, close to
+// mainnet, where about 1% of slots are missed and aggregates carry most votes
+var DefaultInclusionModel = InclusionModel{
+    MissedSlotRate:    0.01,
+    AggregateCoverage: 0.97,
+}
+
+// inclusionEstimate is what the model expects of an average block
+type inclusionEstimate struct {
+    votesPerBlock float64 // included for the first time
+    includedShare float64 // of all votes, within the window
+    sourceShare   float64 // of included votes, in time for the source flag
+    headShare     float64 // of included votes, in time for the head flag
+}
+
+// EstimateAttestationsPerBlock estimates how many votes a block includes for
+// the first time under the default inclusion model. The number of aggregates
+// a block holds caps it, although the cap only binds after many missed slots.
+func EstimateAttestationsPerBlock(state *types.NetworkState) float64 {
+    return estimateInclusion(state, len(state.Validators), DefaultInclusionModel).votesPerBlock
+}
+
+// inclusionWindow is the average number of slots after its own in which a
+// vote can be included. Deneb (EIP-7045) allows a vote until the end of the
+// epoch after its own, between one and two epochs; before, it had one epoch.
+func inclusionWindow(fork string) float64 {
+    slots := float64(config.SLOTS_PER_EPOCH)
+    if forkBefore(fork, "deneb") {
+        return slots
+    }
+    return (3*slots - 1) / 2
+}
+
+// estimateInclusion applies model to a network of validatorCount validators
+// under state's fork. A pending vote is included at each later slot with
+// probability q, when the slot has a block and its aggregates carry the vote.
+func estimateInclusion(state *types.NetworkState, validatorCount int, model InclusionModel) inclusionEstimate {
+    window := inclusionWindow(state.CurrentFork)
+    q := (1 - model.MissedSlotRate) * model.AggregateCoverage
+    includedBy := func(slots float64) float64 { return 1 - math.Pow(1-q, math.Min(slots, window)) }
+
+    estimate := inclusionEstimate{includedShare: includedBy(window)}
+    if estimate.includedShare > 0 {
+        estimate.sourceShare = includedBy(float64(IntegerSquareRoot(config.SLOTS_PER_EPOCH))) / estimate.includedShare
+        estimate.headShare = includedBy(float64(config.MIN_ATTESTATION_INCLUSION_DELAY)) / estimate.includedShare
+    }
+
+    // Blocks share out the votes of every slot, missed or not
+    votesPerSlot := float64(validatorCount) / float64(config.SLOTS_PER_EPOCH)
+    estimate.votesPerBlock = votesPerSlot * estimate.includedShare / (1 - model.MissedSlotRate)
+
+    // Before Electra an aggregate covers one committee; from Electra
+    // (EIP-7549) it can cover every committee of a slot
+    capacity := float64(config.MAX_ATTESTATIONS_ELECTRA) * votesPerSlot
+    if forkBefore(state.CurrentFork, "electra") {
+        committees := math.Max(1, math.Min(float64(config.MAX_COMMITTEES_PER_SLOT),
+            math.Floor(votesPerSlot/float64(config.TARGET_COMMITTEE_SIZE))))
+        capacity = float64(config.MAX_ATTESTATIONS) * votesPerSlot / committees
+    }
+    estimate.votesPerBlock = math.Min(estimate.votesPerBlock, capacity)
+    return estimate
+}
+
+// CalculateAttestationInclusionReward calculates rewards for including attestations in a block
+func CalculateAttestationInclusionReward(state *types.NetworkState, participationRate float64) uint64 {
+    return attestationInclusionReward(state, len(state.Validators), participationRate)
+}
+
+// attestationInclusionReward is the proposer's reward for the votes an
+// average block includes, as the spec's process_attestation pays it: for
+// each flag a vote earns, the vote's base reward times the flag's weight,
+// divided by (WEIGHT_DENOMINATOR - PROPOSER_WEIGHT) * WEIGHT_DENOMINATOR /
+// PROPOSER_WEIGHT. Only participating validators vote, each with the
+// network's average effective balance increments. Without a proposer weight,
+// or with nothing but one, there is no reward.
+func attestationInclusionReward(state *types.NetworkState, validatorCount int, participationRate float64) uint64 {
+    if config.PROPOSER_WEIGHT == 0 || config.PROPOSER_WEIGHT >= config.WEIGHT_DENOMINATOR {
+        return 0
+    }
+    estimate := estimateInclusion(state, validatorCount, DefaultInclusionModel)
+    votes := estimate.votesPerBlock * participationRate
+    increments := float64(EffectiveBalanceIncrements(state.TotalActiveBalance)) / float64(validatorCount)
+
+    // Every included vote earns the target flag
+    weightPerVote := float64(config.TIMELY_TARGET_WEIGHT) +
+        float64(config.TIMELY_SOURCE_WEIGHT)*estimate.sourceShare +
+        float64(config.TIMELY_HEAD_WEIGHT)*estimate.headShare
+    denominator := float64((config.WEIGHT_DENOMINATOR - config.PROPOSER_WEIGHT) * config.WEIGHT_DENOMINATOR / config.PROPOSER_WEIGHT)
+
+    return uint64(votes * increments * float64(GetBaseRewardPerIncrement(state)) * weightPerVote / denominator)
+}
+
+// forkBefore reports whether fork came before target, both among
+// config.KnownForks. An unset or unknown fork is the latest.
+func forkBefore(fork, target string) bool {
+    index := func(name string) int {
+        for i, known := range config.KnownForks {
+            if known == name {
+                return i
+            }
+        }
+        return len(config.KnownForks)
+    }
+    return index(fork) < index(target)
+}
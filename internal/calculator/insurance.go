@@ -0,0 +1,42 @@
+package calculator
+
+import (
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// EvaluateSlashingInsurance compares an annual insurance premium (in basis points of
+// stake) against the expected loss from being slashed together with correlatedCount
+// validators at the given annual probability
+func EvaluateSlashingInsurance(state *types.NetworkState, premiumBps, slashingProbability float64,
+    correlatedCount int) *types.InsuranceResult {
+
+    if correlatedCount < 1 {
+        correlatedCount = 1
+    }
+
+    validator := typicalValidator(state)
+    stake := validator.EffectiveBalance
+    premium := float64(stake) * premiumBps / 10000
+
+    single := slashingPenaltiesFor(state, validator, stake)
+    correlated := slashingPenaltiesFor(state, validator, uint64(correlatedCount)*config.MAX_EFFECTIVE_BALANCE)
+
+    expectedLoss := float64(correlated.TotalPenaltyGwei) * slashingProbability
+    breakEven := 0.0
+    if correlated.TotalPenaltyGwei > 0 {
+        breakEven = premium / float64(correlated.TotalPenaltyGwei)
+    }
+
+    return &types.InsuranceResult{
+        PremiumBps:           premiumBps,
+        AnnualPremium:        premium / 1e9,
+        SlashingProbability:  slashingProbability,
+        SinglePenalty:        float64(single.TotalPenaltyGwei) / 1e9,
+        CorrelatedCount:      correlatedCount,
+        CorrelatedPenalty:    float64(correlated.TotalPenaltyGwei) / 1e9,
+        ExpectedLoss:         expectedLoss / 1e9,
+        BreakEvenProbability: breakEven,
+        CoverageRational:     premium <= expectedLoss,
+    }
+}
@@ -0,0 +1,112 @@
+package calculator
+
+import (
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// IssuancePolicy describes a reward curve as the annual issuance yield paid to stakers.
+// Policies receive the yield of the current sqrt curve so alternatives can be
+// expressed as modifications of it.
+type IssuancePolicy interface {
+    Name() string
+    Yield(totalStakedETH, sqrtYield float64) float64
+}
+
+// SqrtIssuance is the current protocol curve where yield falls with 1/sqrt(total stake)
+type SqrtIssuance struct{}
+
+// Name returns the policy identifier used in reports
+func (p SqrtIssuance) Name() string {
+    return "sqrt"
+}
+
+// Yield returns the yield of the current curve unchanged
+func (p SqrtIssuance) Yield(totalStakedETH, sqrtYield float64) float64 {
+    return sqrtYield
+}
+
+// CappedIssuance follows the sqrt curve until network issuance reaches MaxIssuanceETH per year
+type CappedIssuance struct {
+    MaxIssuanceETH float64
+}
+
+// Name returns the policy identifier used in reports
+func (p CappedIssuance) Name() string {
+    return "capped"
+}
+
+// Yield returns the sqrt curve yield, limited so total issuance never exceeds the cap
+func (p CappedIssuance) Yield(totalStakedETH, sqrtYield float64) float64 {
+    if totalStakedETH <= 0 {
+        return sqrtYield
+    }
+    return math.Min(sqrtYield, p.MaxIssuanceETH/totalStakedETH)
+}
+
+// TargetedIssuance scales the sqrt curve by 1/(1 + D/k) so that total issuance
+// peaks when PeakStakeETH is staked and declines beyond it, discouraging
+// the staking ratio from growing past the target
+type TargetedIssuance struct {
+    PeakStakeETH float64
+}
+
+// Name returns the policy identifier used in reports
+func (p TargetedIssuance) Name() string {
+    return "targeted"
+}
+
+// Yield returns the sqrt curve yield dampened as total stake approaches the peak
+func (p TargetedIssuance) Yield(totalStakedETH, sqrtYield float64) float64 {
+    if p.PeakStakeETH <= 0 {
+        return sqrtYield
+    }
+    return sqrtYield / (1 + totalStakedETH/p.PeakStakeETH)
+}
+
+// CompareIssuancePolicies evaluates APY and inflation under each policy for the given state
+func CompareIssuancePolicies(state *types.NetworkState, participationRate float64,
+    policies ...IssuancePolicy) []types.IssuanceResult {
+
+    return CompareTemplateIssuancePolicies(state, len(state.Validators), participationRate, policies...)
+}
+
+// CompareTemplateIssuancePolicies is CompareIssuancePolicies for a network of
+// validatorCount validators that all look like the state's first, such as a
+// NewTemplateState
+func CompareTemplateIssuancePolicies(state *types.NetworkState, validatorCount int, participationRate float64,
+    policies ...IssuancePolicy) []types.IssuanceResult {
+
+    rewards := calculateRewards(state, validatorCount, participationRate)
+    stakedETH := float64(state.TotalActiveBalance) / 1e9
+    sqrtYield := rewards.BaseAPY / 100
+
+    results := make([]types.IssuanceResult, len(policies))
+
+    for i, policy := range policies {
+        yield := policy.Yield(stakedETH, sqrtYield)
+
+        // Participation boosts the yield of active validators the same way for every curve
+        ratio := 0.0
+        if sqrtYield > 0 {
+            ratio = yield / sqrtYield
+        }
+
+        annualIssuance := yield * stakedETH
+
+        results[i] = types.IssuanceResult{
+            Policy:         policy.Name(),
+            ValidatorCount: validatorCount,
+            TotalStaked:    state.TotalActiveBalance / 1e9,
+            BaseAPY:        yield * 100,
+            EffectiveAPY:   rewards.EffectiveAPY * ratio,
+            APYChange:      (yield - sqrtYield) * 100,
+            AnnualIssuance: annualIssuance,
+            InflationRate:  annualIssuance / config.TOTAL_ETH_SUPPLY * 100,
+        }
+    }
+
+    return results
+}
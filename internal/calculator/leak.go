@@ -0,0 +1,148 @@
+package calculator
+
+import (
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// leakCohort is a group of identical validators during an inactivity leak
+type leakCohort struct {
+    count     uint64
+    balance   uint64 // per validator
+    effective uint64
+    score     uint64
+    ejected   uint64 // epoch of ejection, or 0 while active
+}
+
+// stake is the cohort's active effective balance
+func (c *leakCohort) stake() uint64 {
+    if c.ejected > 0 {
+        return 0
+    }
+    return c.count * c.effective
+}
+
+// updateEffectiveBalance applies the spec's hysteresis to the effective balance
+func (c *leakCohort) updateEffectiveBalance() {
+    hysteresis := config.EFFECTIVE_BALANCE_INCREMENT / config.HYSTERESIS_QUOTIENT
+    downward := hysteresis * config.HYSTERESIS_DOWNWARD_MULTIPLIER
+    upward := hysteresis * config.HYSTERESIS_UPWARD_MULTIPLIER
+    if c.balance+downward < c.effective || c.effective+upward < c.balance {
+        c.effective = effectiveBalance(c.balance, config.MAX_EFFECTIVE_BALANCE)
+    }
+}
+
+// SimulateLeak follows the validators that stay online and those that go
+// offline through a period of non-finality, epoch by epoch, for at most
+// maxEpochs epochs (a year if 0). The chain stops finalizing at the start.
+// Offline validators pay missed-attestation and inactivity penalties until
+// they are ejected; online validators earn nothing once the leak starts. As
+// the offline stake drains, the online share grows, and finality resumes when
+// it reaches two thirds. Proposer and sync committee rewards are left out, and
+// ejected validators are assumed to leave without waiting in the exit queue.
+func SimulateLeak(validatorCount int, participationRate float64, maxEpochs uint64) *types.LeakAnalysis {
+    if maxEpochs == 0 {
+        maxEpochs = config.EPOCHS_PER_YEAR
+    }
+
+    onlineCount := uint64(math.Round(float64(validatorCount) * participationRate))
+    start := config.MAX_EFFECTIVE_BALANCE
+    online := &leakCohort{count: onlineCount, balance: start, effective: start}
+    offline := &leakCohort{count: uint64(validatorCount) - onlineCount, balance: start, effective: start}
+
+    quotient := config.GetForkConfig("bellatrix").InactivityPenaltyQuotient
+    flagWeights := config.TIMELY_SOURCE_WEIGHT + config.TIMELY_TARGET_WEIGHT + config.TIMELY_HEAD_WEIGHT
+    missedWeights := config.TIMELY_SOURCE_WEIGHT + config.TIMELY_TARGET_WEIGHT
+
+    analysis := &types.LeakAnalysis{
+        Validators:       validatorCount,
+        Participation:    participationRate,
+        StartOnlineShare: stakeShare(online.stake(), offline.stake()),
+    }
+    record := func(epoch uint64) {
+        analysis.Trajectory = append(analysis.Trajectory, types.LeakPoint{
+            Epoch:          epoch,
+            Days:           float64(epoch) / float64(config.EPOCHS_PER_DAY),
+            OnlineBalance:  online.balance,
+            OfflineBalance: offline.balance,
+            OfflineScore:   offline.score,
+            OnlineShare:    stakeShare(online.stake(), offline.stake()),
+        })
+    }
+    record(0)
+
+    epoch := uint64(0)
+    for epoch < maxEpochs {
+        onlineStake, offlineStake := online.stake(), offline.stake()
+        total := onlineStake + offlineStake
+        // Two thirds of the stake attesting justifies and finalizes again
+        if epoch > 0 && onlineStake*3 >= total*2 {
+            analysis.FinalityRestored = true
+            break
+        }
+        epoch++
+
+        // The finality delay grows by one each epoch
+        inLeak := epoch > config.MIN_EPOCHS_TO_INACTIVITY_PENALTY
+        online.score = CalculateInactivityScore(online.score, true, !inLeak)
+        offline.score = CalculateInactivityScore(offline.score, false, !inLeak)
+
+        baseRewardPerIncrement := mulDiv(config.EFFECTIVE_BALANCE_INCREMENT, config.BASE_REWARD_FACTOR, IntegerSquareRoot(total))
+        activeIncrements := EffectiveBalanceIncrements(total)
+
+        // Attestation rewards are withheld during the leak
+        if !inLeak {
+            baseReward := EffectiveBalanceIncrements(online.effective) * baseRewardPerIncrement
+            online.balance += mulDiv(baseReward*flagWeights, EffectiveBalanceIncrements(onlineStake),
+                activeIncrements*config.WEIGHT_DENOMINATOR)
+        }
+
+        if offline.ejected == 0 && offline.count > 0 {
+            baseReward := EffectiveBalanceIncrements(offline.effective) * baseRewardPerIncrement
+            penalty := baseReward*missedWeights/config.WEIGHT_DENOMINATOR +
+                mulDiv(offline.effective, offline.score, config.INACTIVITY_SCORE_BIAS*quotient)
+            offline.balance -= min(penalty, offline.balance)
+
+            offline.updateEffectiveBalance()
+            if offline.effective <= config.EJECTION_BALANCE {
+                offline.ejected = epoch
+            }
+        }
+        online.updateEffectiveBalance()
+
+        if epoch%config.EPOCHS_PER_DAY == 0 {
+            record(epoch)
+        }
+    }
+    if last := analysis.Trajectory[len(analysis.Trajectory)-1]; last.Epoch != epoch {
+        record(epoch)
+    }
+
+    analysis.Epochs = epoch
+    analysis.EndOnlineShare = stakeShare(online.stake(), offline.stake())
+    analysis.Online = leakCohortResult("online", online, start)
+    analysis.Offline = leakCohortResult("offline", offline, start)
+    analysis.Burned = -(analysis.Online.TotalChange + analysis.Offline.TotalChange)
+    return analysis
+}
+
+// stakeShare is the online share of the active stake in percent
+func stakeShare(onlineStake, offlineStake uint64) float64 {
+    if onlineStake+offlineStake == 0 {
+        return 0
+    }
+    return float64(onlineStake) / float64(onlineStake+offlineStake) * 100
+}
+
+func leakCohortResult(name string, c *leakCohort, start uint64) types.LeakCohort {
+    return types.LeakCohort{
+        Name:         name,
+        Validators:   int(c.count),
+        StartBalance: start,
+        EndBalance:   c.balance,
+        TotalChange:  (float64(c.balance) - float64(start)) * float64(c.count),
+        EjectedEpoch: c.ejected,
+    }
+}
@@ -0,0 +1,60 @@
+package calculator
+
+import (
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// ProposerLuck rates a validator's proposal count over a range of epochs.
+// validatorCounts samples the network size across the range, oldest first;
+// each sample covers an equal share of the epochs. Proposals are rare,
+// independent draws, so the count is modeled as Poisson.
+func ProposerLuck(proposals int, epochs uint64, validatorCounts []int) *types.LuckResult {
+    // Sum 1/N over every slot, one segment per network size sample
+    expected := 0.0
+    slots := epochs * config.SLOTS_PER_EPOCH
+    segments := uint64(len(validatorCounts))
+    for i, count := range validatorCounts {
+        segmentSlots := slots*uint64(i+1)/segments - slots*uint64(i)/segments
+        expected += float64(segmentSlots) / float64(count)
+    }
+
+    below := poissonCDF(proposals-1, expected)
+    atMost := poissonCDF(proposals, expected)
+
+    result := &types.LuckResult{
+        Proposals:          proposals,
+        Epochs:             epochs,
+        Days:               float64(epochs) / float64(config.EPOCHS_PER_DAY),
+        ExpectedProposals:  expected,
+        // Mid-rank: ties count half, so an exactly average result sits near 50
+        Percentile:         (below + atMost) / 2 * 100,
+        ProbabilityAtMost:  atMost,
+        ProbabilityAtLeast: 1 - below,
+    }
+    if expected > 0 {
+        result.Luck = float64(proposals) / expected * 100
+    }
+
+    return result
+}
+
+// poissonCDF returns P(X <= k) for X ~ Poisson(lambda)
+func poissonCDF(k int, lambda float64) float64 {
+    if k < 0 {
+        return 0
+    }
+    if lambda == 0 {
+        return 1
+    }
+
+    // Sum the terms in log space so large lambdas do not underflow exp(-lambda)
+    sum := 0.0
+    for i := 0; i <= k; i++ {
+        logGamma, _ := math.Lgamma(float64(i + 1))
+        sum += math.Exp(float64(i)*math.Log(lambda) - lambda - logGamma)
+    }
+    return math.Min(1, sum)
+}
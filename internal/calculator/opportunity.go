@@ -0,0 +1,50 @@
+package calculator
+
+import (
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// CompareOpportunityCost compares staking capital ETH over horizonDays with
+// putting it into each alternative, at simple interest. The alternatives are
+// taken to be liquid, while a new validator earns nothing until it leaves the
+// activation queue, and must request its exit early enough to be withdrawn by
+// the end of the horizon: rewards continue through the exit queue but stop for
+// the withdrawal delay. Staking earns the risk-adjusted APY.
+func CompareOpportunityCost(results *types.RewardResults, capital, horizonDays float64,
+    activationQueueLength, exitQueueLength int, alternatives []types.Alternative) *types.OpportunityComparison {
+    daysPerYear := 365.25
+    queueEpochs, withdrawalEpochs := EstimateExitDelay(results.ValidatorCount, exitQueueLength)
+    activation := EstimateValidatorQueue(results.ValidatorCount, activationQueueLength, 0).Activation
+
+    comparison := &types.OpportunityComparison{
+        Capital:        capital,
+        HorizonDays:    horizonDays,
+        StakingAPY:     results.RiskAdjustedAPY,
+        ActivationDays: activation.Days,
+        ExitQueueDays:  queueEpochs / float64(config.EPOCHS_PER_DAY),
+        WithdrawalDays: withdrawalEpochs / float64(config.EPOCHS_PER_DAY),
+    }
+    comparison.IlliquidDays = comparison.ExitQueueDays + comparison.WithdrawalDays
+
+    earningDays := math.Max(0, horizonDays-comparison.ActivationDays-comparison.WithdrawalDays)
+    comparison.StakingReturn = capital * results.RiskAdjustedAPY / 100 * earningDays / daysPerYear
+    if horizonDays > 0 && capital > 0 {
+        comparison.EffectiveYield = comparison.StakingReturn / capital / (horizonDays / daysPerYear) * 100
+    }
+
+    for _, alternative := range alternatives {
+        altReturn := capital * alternative.Yield / 100 * horizonDays / daysPerYear
+        comparison.Alternatives = append(comparison.Alternatives, types.AlternativeReturn{
+            Name:          alternative.Name,
+            Yield:         alternative.Yield,
+            Return:        altReturn,
+            Advantage:     comparison.StakingReturn - altReturn,
+            StakingBetter: comparison.StakingReturn > altReturn,
+        })
+    }
+
+    return comparison
+}
@@ -0,0 +1,113 @@
+package calculator
+
+import (
+    "math"
+    "math/rand"
+    "sort"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// OutagePercentiles are the percentiles reported for simulated outage costs
+var OutagePercentiles = []float64{50, 90, 99}
+
+// SimulateOutage costs an outage of slots slots starting at startSlot for one
+// validator on a finalizing network. Each epoch the validator attests at one
+// slot drawn uniformly, and it misses the attestation if that slot falls in
+// the outage. Each slot of the outage it is the proposer with probability
+// 1/validators and misses the block, so a trial draws the missed blocks from
+// the binomial distribution over the outage's slots. Each sync committee
+// period the outage touches it is drawn for the committee with the usual
+// probability, and then misses every slot of the period in the outage, paying
+// a penalty equal to the reward. Costs are rewards forgone plus penalties,
+// with the participation boost and execution rewards of results. Expected
+// values are exact; trials draw the duties to give the spread.
+func SimulateOutage(state *types.NetworkState, results *types.RewardResults, startSlot, slots uint64,
+    trials int, seed int64, progress func(trials int)) *types.OutageResult {
+
+    slotsPerEpoch := config.SLOTS_PER_EPOCH
+    slotsPerPeriod := slotsPerEpoch * config.EPOCHS_PER_SYNC_COMMITTEE_PERIOD
+    endSlot := startSlot + slots // exclusive
+
+    penalties := penaltiesFor(state, typicalValidator(state), false, false, false)
+    attestationCost := float64(results.AttestationRewardPerEpochGwei)*results.ParticipationMultiplier +
+        float64(penalties.TotalAttestationPenaltyGwei)
+    blockCost := results.AvgProposerRewardPerBlockGwei*results.ParticipationMultiplier +
+        results.PriorityFeesPerBlockGwei + results.MEVPerBlockGwei
+    syncSlotCost := 2 * float64(CalculateSyncCommitteeReward(state, 1))
+
+    result := &types.OutageResult{
+        StartSlot:  startSlot,
+        Slots:      slots,
+        Seconds:    slots * config.SECONDS_PER_SLOT,
+        FirstEpoch: startSlot / slotsPerEpoch,
+        LastEpoch:  (endSlot - 1) / slotsPerEpoch,
+        Trials:     trials,
+        Seed:       seed,
+    }
+
+    // The outage covers part of its first and last epochs and periods
+    covered := func(from, to uint64) float64 { return float64(min(endSlot, to) - max(startSlot, from)) }
+    var epochShares, periodSlots []float64
+    for epoch := result.FirstEpoch; epoch <= result.LastEpoch; epoch++ {
+        epochShares = append(epochShares, covered(epoch*slotsPerEpoch, (epoch+1)*slotsPerEpoch)/float64(slotsPerEpoch))
+        result.MissedAttestations += epochShares[len(epochShares)-1]
+    }
+    offCommittee := 1.0
+    for period := startSlot / slotsPerPeriod; period <= (endSlot-1)/slotsPerPeriod; period++ {
+        periodSlots = append(periodSlots, covered(period*slotsPerPeriod, (period+1)*slotsPerPeriod))
+        result.MissedSyncDuties += periodSlots[len(periodSlots)-1] * results.SyncCommitteeProbability
+        offCommittee *= 1 - results.SyncCommitteeProbability
+    }
+    result.MissedProposals = float64(slots) * results.ProposerProbability
+    result.ProposalProbability = 1 - math.Pow(1-results.ProposerProbability, float64(slots))
+    result.SyncCommitteeProbability = 1 - offCommittee
+
+    result.AttestationCost = result.MissedAttestations * attestationCost / 1e9
+    result.ProposalCost = result.MissedProposals * blockCost / 1e9
+    result.SyncCost = result.MissedSyncDuties * syncSlotCost / 1e9
+    result.Cost = result.AttestationCost + result.ProposalCost + result.SyncCost
+
+    rng := rand.New(rand.NewSource(seed))
+    costs := make([]float64, trials)
+    for i := range costs {
+        var cost float64
+        for _, share := range epochShares {
+            if rng.Float64() < share {
+                cost += attestationCost
+            }
+        }
+        cost += float64(sampleBinomial(rng, slots, results.ProposerProbability)) * blockCost
+        for _, missed := range periodSlots {
+            if rng.Float64() < results.SyncCommitteeProbability {
+                cost += missed * syncSlotCost
+            }
+        }
+        costs[i] = cost / 1e9
+
+        if progress != nil && (i+1)%simulationProgressStep == 0 {
+            progress(simulationProgressStep)
+        }
+    }
+    if progress != nil && trials%simulationProgressStep != 0 {
+        progress(trials % simulationProgressStep)
+    }
+
+    sort.Float64s(costs)
+    result.Max = costs[trials-1]
+    for _, percentile := range OutagePercentiles {
+        index := int(math.Ceil(percentile/100*float64(trials))) - 1
+        result.Percentiles = append(result.Percentiles, types.OutagePercentile{
+            Percentile: percentile,
+            Cost:       costs[int(math.Max(0, float64(index)))],
+        })
+    }
+
+    // Time to earn the expected cost back once online again
+    if results.TotalAnnualRewardsGwei > 0 {
+        hoursPerYear := float64(config.EPOCHS_PER_YEAR*config.SLOTS_PER_EPOCH*config.SECONDS_PER_SLOT) / 3600
+        result.RecoveryHours = result.Cost * 1e9 / results.TotalAnnualRewardsGwei * hoursPerYear
+    }
+    return result
+}
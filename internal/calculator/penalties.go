@@ -7,43 +7,59 @@ import (
 
 // CalculatePenalties computes attestation penalties for missed duties
 func CalculatePenalties(state *types.NetworkState, validatorIndex int,
+    correctSource, correctTarget, correctHead bool) (*types.PenaltyResults, error) {
+    
+    validator, err := validatorAt(state, validatorIndex)
+    if err != nil {
+        return nil, err
+    }
+    return penaltiesFor(state, validator, correctSource, correctTarget, correctHead), nil
+}
+
+// penaltiesFor is CalculatePenalties for a validator already looked up
+func penaltiesFor(state *types.NetworkState, validator *types.Validator,
     correctSource, correctTarget, correctHead bool) *types.PenaltyResults {
     
-    baseReward := GetBaseReward(state, validatorIndex)
+    baseReward := baseRewardFor(state, validator.EffectiveBalance)
     
     results := &types.PenaltyResults{
-        InactivityScore: state.Validators[validatorIndex].InactivityScore,
+        InactivityScore: validator.InactivityScore,
     }
     
     // Calculate penalties for missed attestation components
     if !correctSource {
-        results.SourcePenalty = baseReward * config.TIMELY_SOURCE_WEIGHT / config.WEIGHT_DENOMINATOR
+        results.SourcePenaltyGwei = baseReward * config.TIMELY_SOURCE_WEIGHT / config.WEIGHT_DENOMINATOR
     }
     if !correctTarget {
-        results.TargetPenalty = baseReward * config.TIMELY_TARGET_WEIGHT / config.WEIGHT_DENOMINATOR
+        results.TargetPenaltyGwei = baseReward * config.TIMELY_TARGET_WEIGHT / config.WEIGHT_DENOMINATOR
     }
     if !correctHead {
-        results.HeadPenalty = baseReward * config.TIMELY_HEAD_WEIGHT / config.WEIGHT_DENOMINATOR
+        results.HeadPenaltyGwei = baseReward * config.TIMELY_HEAD_WEIGHT / config.WEIGHT_DENOMINATOR
     }
     
-    results.TotalAttestationPenalty = results.SourcePenalty + results.TargetPenalty + results.HeadPenalty
+    results.TotalAttestationPenaltyGwei = results.SourcePenaltyGwei + results.TargetPenaltyGwei + results.HeadPenaltyGwei
     
     // Calculate inactivity penalty if applicable
     if state.CurrentEpoch > state.FinalizedEpoch+config.MIN_ATTESTATION_INCLUSION_DELAY {
-        results.InactivityPenalty = GetInactivityPenalty(state, validatorIndex)
+        results.InactivityPenaltyGwei = inactivityPenaltyFor(state, validator)
     }
     
-    // Daily projections
-    results.DailyAttestationPenalty = float64(results.TotalAttestationPenalty*config.EPOCHS_PER_DAY) / 1e9
-    results.DailyInactivityPenalty = float64(results.InactivityPenalty*config.EPOCHS_PER_DAY) / 1e9
+    setPenaltyPeriods(results)
     
     return results
 }
 
 // GetInactivityPenalty calculates the inactivity leak penalty
-func GetInactivityPenalty(state *types.NetworkState, validatorIndex int) uint64 {
-    validator := &state.Validators[validatorIndex]
-    
+func GetInactivityPenalty(state *types.NetworkState, validatorIndex int) (uint64, error) {
+    validator, err := validatorAt(state, validatorIndex)
+    if err != nil {
+        return 0, err
+    }
+    return inactivityPenaltyFor(state, validator), nil
+}
+
+// inactivityPenaltyFor is GetInactivityPenalty for a validator already looked up
+func inactivityPenaltyFor(state *types.NetworkState, validator *types.Validator) uint64 {
     // Only applies during non-finality
     if state.CurrentEpoch <= state.FinalizedEpoch+config.MIN_ATTESTATION_INCLUSION_DELAY {
         return 0
@@ -58,40 +74,98 @@ func GetInactivityPenalty(state *types.NetworkState, validatorIndex int) uint64
     return penaltyNumerator / penaltyDenominator
 }
 
-// CalculateInactivityScore computes the inactivity score for a validator
+// CalculateInactivityScore computes a validator's inactivity score after one
+// epoch, as in the consensus spec's process_inactivity_updates. isActive is
+// whether the validator attested to the correct target, and isFinalized
+// whether the chain is finalizing, i.e. not in an inactivity leak.
 func CalculateInactivityScore(previousScore uint64, isActive bool, isFinalized bool) uint64 {
+    score := previousScore
+    if isActive {
+        score -= min(1, score)
+    } else {
+        score += config.INACTIVITY_SCORE_BIAS
+    }
+
+    // Scores recover quickly once the chain finalizes again
     if isFinalized {
-        if previousScore > 0 {
-            // Decrease score during finality
-            return previousScore - min(1, previousScore)
+        score -= min(config.INACTIVITY_SCORE_RECOVERY_RATE, score)
+    }
+    return score
+}
+
+// AdvanceWithoutFinality moves state forward epochs epochs during which the
+// chain does not finalize, updating each validator's inactivity score epoch by
+// epoch. offline reports whether a validator misses the target throughout.
+// The leak, and with it score growth, starts once the finality delay exceeds
+// MIN_EPOCHS_TO_INACTIVITY_PENALTY.
+func AdvanceWithoutFinality(state *types.NetworkState, epochs uint64, offline func(index int) bool) {
+    type start struct {
+        score   uint64
+        offline bool
+    }
+    // Validators that start alike end alike, so each distinct start is
+    // evolved once rather than once per validator
+    evolved := make(map[start]uint64)
+
+    for i := range state.Validators {
+        validator := &state.Validators[i]
+        key := start{validator.InactivityScore, offline(i)}
+        if score, ok := evolved[key]; ok {
+            validator.InactivityScore = score
+            continue
         }
-        return 0
+
+        score := validator.InactivityScore
+        for epoch := state.CurrentEpoch + 1; epoch <= state.CurrentEpoch+epochs; epoch++ {
+            inLeak := inInactivityLeak(epoch-1, state.FinalizedEpoch)
+            score = CalculateInactivityScore(score, !key.offline, !inLeak)
+        }
+        evolved[key] = score
+        validator.InactivityScore = score
     }
+
+    state.CurrentEpoch += epochs
+}
+
+// CalculateSlashingPenalties computes all slashing-related penalties, with
+// totalSlashedBalance slashed within the correlation window
+func CalculateSlashingPenalties(state *types.NetworkState, validatorIndex int, 
+    totalSlashedBalance uint64) (*types.SlashingResults, error) {
     
-    // Increase score during non-finality
-    if !isActive {
-        return previousScore + config.INACTIVITY_SCORE_BIAS
+    validator, err := validatorAt(state, validatorIndex)
+    if err != nil {
+        return nil, err
     }
-    
-    // Active but not finalizing
-    return previousScore + 1
+    return slashingPenaltiesFor(state, validator, totalSlashedBalance), nil
 }
 
-// CalculateSlashingPenalties computes all slashing-related penalties
-func CalculateSlashingPenalties(state *types.NetworkState, validatorIndex int, 
+// slashingPenaltiesFor is CalculateSlashingPenalties for a validator already
+// looked up
+func slashingPenaltiesFor(state *types.NetworkState, validator *types.Validator,
     totalSlashedBalance uint64) *types.SlashingResults {
     
-    validator := &state.Validators[validatorIndex]
     forkConfig := config.GetForkConfig(state.CurrentFork)
     
     // Initial penalty
     initialPenalty := validator.EffectiveBalance / forkConfig.MinSlashingPenaltyQuotient
     
-    // Proportional penalty (correlation penalty)
-    proportionalPenalty := validator.EffectiveBalance * 
-                          min(totalSlashedBalance*forkConfig.ProportionalSlashingMultiplier, 
-                              state.TotalActiveBalance) / 
-                          state.TotalActiveBalance
+    // Proportional penalty (correlation penalty). Before Electra the spec
+    // divides the validator's share of the adjusted slashed balance by the
+    // total balance; Electra rounds the penalty per effective balance
+    // increment instead.
+    adjustedTotalSlashingBalance := min(totalSlashedBalance*forkConfig.ProportionalSlashingMultiplier, 
+                                        state.TotalActiveBalance)
+    var proportionalPenalty uint64
+    if forkBefore(state.CurrentFork, "electra") {
+        proportionalPenalty = mulDiv(EffectiveBalanceIncrements(validator.EffectiveBalance),
+                                     adjustedTotalSlashingBalance, state.TotalActiveBalance) *
+                              config.EFFECTIVE_BALANCE_INCREMENT
+    } else {
+        penaltyPerIncrement := adjustedTotalSlashingBalance / 
+                              EffectiveBalanceIncrements(state.TotalActiveBalance)
+        proportionalPenalty = penaltyPerIncrement * 
+                              EffectiveBalanceIncrements(validator.EffectiveBalance)
+    }
     
     totalPenalty := initialPenalty + proportionalPenalty
     
@@ -100,48 +174,89 @@ func CalculateSlashingPenalties(state *types.NetworkState, validatorIndex int,
     proposerReward := whistleblowerReward / config.PROPOSER_REWARD_QUOTIENT
     
     return &types.SlashingResults{
-        InitialPenalty:      initialPenalty,
-        ProportionalPenalty: proportionalPenalty,
-        TotalPenalty:        totalPenalty,
-        PercentageOfStake:   float64(totalPenalty) / float64(validator.EffectiveBalance) * 100,
-        WhistleblowerReward: whistleblowerReward,
-        ProposerReward:      proposerReward,
+        InitialPenaltyGwei:      initialPenalty,
+        ProportionalPenaltyGwei: proportionalPenalty,
+        TotalPenaltyGwei:        totalPenalty,
+        PercentageOfStake:       float64(totalPenalty) / float64(validator.EffectiveBalance) * 100,
+        WhistleblowerRewardGwei: whistleblowerReward,
+        ProposerRewardGwei:      proposerReward,
     }
 }
 
+// SlashingMidpoint is the epoch at which a validator slashed in slashedEpoch
+// pays the correlation penalty, halfway through the slashings vector
+func SlashingMidpoint(slashedEpoch uint64) uint64 {
+    return slashedEpoch + config.EPOCHS_PER_SLASHINGS_VECTOR/2
+}
+
+// SlashingsVectorAt builds the slashings vector as it stands in epoch: the
+// events of the EPOCHS_PER_SLASHINGS_VECTOR epochs up to and including epoch,
+// indexed by epoch modulo the vector length. Older entries have been reset and
+// later ones have not happened yet.
+func SlashingsVectorAt(events []types.SlashingEvent, epoch uint64) []uint64 {
+    vector := make([]uint64, config.EPOCHS_PER_SLASHINGS_VECTOR)
+    for _, event := range events {
+        if event.Epoch <= epoch && epoch-event.Epoch < config.EPOCHS_PER_SLASHINGS_VECTOR {
+            vector[event.Epoch%config.EPOCHS_PER_SLASHINGS_VECTOR] += event.BalanceGwei
+        }
+    }
+    return vector
+}
+
+// CalculateWindowedSlashingPenalties computes the penalties of a validator as
+// the spec's process_slashings does: state.SlashingsPerEpoch is the slashings
+// vector at the validator's SlashingMidpoint, and the correlation penalty is
+// charged against everything slashed in it, including the validator itself.
+// Slashings up to half the vector length before or after the validator's own
+// count; those further away do not.
+func CalculateWindowedSlashingPenalties(state *types.NetworkState, validatorIndex int) (*types.SlashingResults, error) {
+    var slashed uint64
+    for _, balance := range state.SlashingsPerEpoch {
+        slashed += balance
+    }
+    return CalculateSlashingPenalties(state, validatorIndex, slashed)
+}
+
 // EstimateSlashingImpact estimates the impact of a slashing event on the network
-func EstimateSlashingImpact(state *types.NetworkState, slashedValidatorCount int) map[string]interface{} {
+func EstimateSlashingImpact(state *types.NetworkState, slashedValidatorCount int) *types.SlashingImpact {
     slashedBalance := uint64(slashedValidatorCount) * config.MAX_EFFECTIVE_BALANCE
     slashingPercentage := float64(slashedBalance) / float64(state.TotalActiveBalance) * 100
     
     // Calculate penalties for different scenarios
-    singleSlashing := CalculateSlashingPenalties(state, 0, config.MAX_EFFECTIVE_BALANCE)
-    correlatedSlashing := CalculateSlashingPenalties(state, 0, slashedBalance)
-    
-    return map[string]interface{}{
-        "slashed_validator_count": slashedValidatorCount,
-        "slashed_balance_eth":     float64(slashedBalance) / 1e9,
-        "network_percentage":      slashingPercentage,
-        "single_validator_penalty": map[string]interface{}{
-            "initial_eth":      float64(singleSlashing.InitialPenalty) / 1e9,
-            "proportional_eth": float64(singleSlashing.ProportionalPenalty) / 1e9,
-            "total_eth":        float64(singleSlashing.TotalPenalty) / 1e9,
-            "percentage":       singleSlashing.PercentageOfStake,
-        },
-        "correlated_penalty": map[string]interface{}{
-            "initial_eth":      float64(correlatedSlashing.InitialPenalty) / 1e9,
-            "proportional_eth": float64(correlatedSlashing.ProportionalPenalty) / 1e9,
-            "total_eth":        float64(correlatedSlashing.TotalPenalty) / 1e9,
-            "percentage":       correlatedSlashing.PercentageOfStake,
-        },
-        "network_impact": map[string]interface{}{
-            "total_penalties_eth":  float64(correlatedSlashing.TotalPenalty*uint64(slashedValidatorCount)) / 1e9,
-            "reduced_staking_eth":  float64(slashedBalance) / 1e9,
-            "security_impact":      getSecurityImpactLevel(slashingPercentage),
+    validator := typicalValidator(state)
+    singleSlashing := slashingPenaltiesFor(state, validator, config.MAX_EFFECTIVE_BALANCE)
+    correlatedSlashing := slashingPenaltiesFor(state, validator, slashedBalance)
+    
+    fork := state.CurrentFork
+    if fork == "" {
+        fork = "bellatrix"
+    }
+    
+    return &types.SlashingImpact{
+        Fork:                   fork,
+        SlashedValidatorCount:  slashedValidatorCount,
+        SlashedBalance:         float64(slashedBalance) / 1e9,
+        NetworkPercentage:      slashingPercentage,
+        SingleValidatorPenalty: slashingPenaltyInETH(singleSlashing),
+        CorrelatedPenalty:      slashingPenaltyInETH(correlatedSlashing),
+        NetworkImpact: types.SlashingNetworkImpact{
+            TotalPenalties: float64(correlatedSlashing.TotalPenaltyGwei*uint64(slashedValidatorCount)) / 1e9,
+            ReducedStaking: float64(slashedBalance) / 1e9,
+            SecurityImpact: getSecurityImpactLevel(slashingPercentage),
         },
     }
 }
 
+// slashingPenaltyInETH converts a validator's slashing penalties to ETH
+func slashingPenaltyInETH(results *types.SlashingResults) types.SlashingPenalty {
+    return types.SlashingPenalty{
+        Initial:      float64(results.InitialPenaltyGwei) / 1e9,
+        Proportional: float64(results.ProportionalPenaltyGwei) / 1e9,
+        Total:        float64(results.TotalPenaltyGwei) / 1e9,
+        Percentage:   results.PercentageOfStake,
+    }
+}
+
 // Helper function to determine security impact level
 func getSecurityImpactLevel(slashingPercentage float64) string {
     switch {
@@ -0,0 +1,54 @@
+package calculator
+
+import (
+    "testing"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+func TestProportionalSlashingPenalty(t *testing.T) {
+    // A total balance that is not a whole number of validators, so the two
+    // roundings differ
+    total := 1_000_003*config.MAX_EFFECTIVE_BALANCE + 7*config.EFFECTIVE_BALANCE_INCREMENT
+    increment := config.EFFECTIVE_BALANCE_INCREMENT
+    balance := config.MAX_EFFECTIVE_BALANCE
+    stateFor := func(fork string) *types.NetworkState {
+        return &types.NetworkState{
+            Validators:         []types.Validator{{EffectiveBalance: balance}},
+            TotalActiveBalance: total,
+            CurrentFork:        fork,
+        }
+    }
+
+    for _, slashed := range []uint64{1, 100, 5_000, 400_000} {
+        for _, fork := range []string{"bellatrix", "capella", "deneb", "electra", ""} {
+            adjusted := min(slashed*config.MAX_EFFECTIVE_BALANCE*config.GetForkConfig(fork).ProportionalSlashingMultiplier, total)
+
+            // The spec's process_slashings: before Electra, balance //
+            // increment * adjusted // total * increment; from Electra,
+            // adjusted // (total // increment) per increment
+            want := bigMulDiv(balance/increment, adjusted, total) * increment
+            if fork == "electra" || fork == "" {
+                want = adjusted / (total / increment) * (balance / increment)
+            }
+
+            penalties, err := CalculateSlashingPenalties(stateFor(fork), 0, slashed*config.MAX_EFFECTIVE_BALANCE)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if penalties.ProportionalPenaltyGwei != want {
+                t.Errorf("%d slashed under %q: proportional penalty %d, want %d",
+                    slashed, fork, penalties.ProportionalPenaltyGwei, want)
+            }
+        }
+    }
+
+    // The roundings differ, so the fork matters
+    slashed := 5_000 * config.MAX_EFFECTIVE_BALANCE
+    before, _ := CalculateSlashingPenalties(stateFor("bellatrix"), 0, slashed)
+    after, _ := CalculateSlashingPenalties(stateFor("electra"), 0, slashed)
+    if before.ProportionalPenaltyGwei == after.ProportionalPenaltyGwei {
+        t.Errorf("Bellatrix and Electra both round to %d", before.ProportionalPenaltyGwei)
+    }
+}
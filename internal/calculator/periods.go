@@ -0,0 +1,67 @@
+package calculator
+
+import (
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// EpochPeriods scales an amount earned or lost every epoch to each standard period
+func EpochPeriods(perEpoch float64) types.PeriodAmounts {
+    return types.PeriodAmounts{
+        PerEpoch: perEpoch,
+        Daily:    perEpoch * float64(config.EPOCHS_PER_DAY),
+        Weekly:   perEpoch * float64(config.EPOCHS_PER_WEEK),
+        Monthly:  perEpoch * float64(config.EPOCHS_PER_MONTH),
+        Annual:   perEpoch * float64(config.EPOCHS_PER_YEAR),
+    }
+}
+
+// AnnualPeriods spreads an annual amount over each standard period
+func AnnualPeriods(annual float64) types.PeriodAmounts {
+    periods := EpochPeriods(annual / float64(config.EPOCHS_PER_YEAR))
+    periods.Annual = annual // exactly, not through the per-epoch amount
+    return periods
+}
+
+// setPeriods fills in the time-based projections from the annual amounts.
+// Expected losses and the net rewards they leave are listed once a risk
+// adjustment has set them.
+func setPeriods(results *types.RewardResults) {
+    total := AnnualPeriods(results.TotalAnnualRewardsGwei)
+    results.DailyRewardsGwei = total.Daily
+    results.WeeklyRewardsGwei = total.Weekly
+    results.MonthlyRewardsGwei = total.Monthly
+
+    rows := []types.PeriodRow{
+        {Name: "Attestation Rewards", PeriodAmounts: AnnualPeriods(results.AttestationRewardsAnnualGwei)},
+        {Name: "Proposer Rewards", PeriodAmounts: AnnualPeriods(results.ProposerRewardsAnnualGwei)},
+        {Name: "Sync Committee Rewards", PeriodAmounts: AnnualPeriods(results.SyncCommitteeRewardsAnnualGwei)},
+        {Name: "Execution Rewards", PeriodAmounts: AnnualPeriods(results.ExecutionRewardsAnnualGwei)},
+        {Name: "Total Rewards", PeriodAmounts: total},
+    }
+
+    losses := results.ExpectedMissedRewardsGwei + results.ExpectedPenaltiesGwei + results.ExpectedSlashingLossGwei
+    if losses > 0 {
+        rows = append(rows,
+            types.PeriodRow{Name: "Expected Missed Rewards", PeriodAmounts: AnnualPeriods(-results.ExpectedMissedRewardsGwei)},
+            types.PeriodRow{Name: "Expected Penalties", PeriodAmounts: AnnualPeriods(-results.ExpectedPenaltiesGwei)},
+            types.PeriodRow{Name: "Expected Slashing Loss", PeriodAmounts: AnnualPeriods(-results.ExpectedSlashingLossGwei)},
+            types.PeriodRow{Name: "Net Rewards", PeriodAmounts: AnnualPeriods(results.TotalAnnualRewardsGwei - losses)},
+        )
+    }
+    results.Periods = rows
+}
+
+// setPenaltyPeriods fills in the period summary of the penalties, charged
+// every epoch
+func setPenaltyPeriods(results *types.PenaltyResults) {
+    attestation := EpochPeriods(float64(results.TotalAttestationPenaltyGwei))
+    inactivity := EpochPeriods(float64(results.InactivityPenaltyGwei))
+    results.DailyAttestationPenalty = attestation.Daily / 1e9
+    results.DailyInactivityPenalty = inactivity.Daily / 1e9
+
+    results.Periods = []types.PeriodRow{
+        {Name: "Missed Attestations", PeriodAmounts: attestation},
+        {Name: "Inactivity Leak", PeriodAmounts: inactivity},
+    }
+}
@@ -0,0 +1,139 @@
+package calculator
+
+import (
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// ProjectPortfolio projects the operator's own validators' rewards over a year.
+// Rewards and proposal odds both grow with effective balance, so each validator
+// earns in proportion to its effective balance. A 0x01 validator's effective
+// balance stays at 32 ETH as its rewards are skimmed. A 0x02 (compounding)
+// validator keeps its consensus rewards, and its effective balance steps up with
+// its balance, up to MAX_EFFECTIVE_BALANCE_ELECTRA. The two are reported
+// separately, and the portfolio APY blends them by stake.
+func ProjectPortfolio(results *types.RewardResults, validators []types.PortfolioValidator, source string) *types.PortfolioProjection {
+    projection := &types.PortfolioProjection{
+        Source:     source,
+        Validators: len(validators),
+    }
+
+    rewards := validatorRewards(results)
+    groups := map[bool]*types.CredentialGroup{
+        false: {Credentials: "0x01"},
+        true:  {Credentials: "0x02"},
+    }
+    for _, v := range validators {
+        group := groups[v.Compounding()]
+        annual := rewards(v)
+
+        group.Validators++
+        group.TotalStaked += v.Amount
+        group.AnnualRewards += annual
+        projection.TotalStaked += v.Amount
+        projection.AnnualRewards += annual
+    }
+
+    for _, group := range []*types.CredentialGroup{groups[false], groups[true]} {
+        if group.Validators == 0 {
+            continue
+        }
+        group.APY = group.AnnualRewards / float64(group.TotalStaked) * 100
+        projection.Groups = append(projection.Groups, *group)
+    }
+
+    projection.MonthlyRewards = projection.AnnualRewards / 12
+    projection.DailyRewards = projection.AnnualRewards / 365.25
+    if projection.TotalStaked > 0 {
+        projection.APY = projection.AnnualRewards / float64(projection.TotalStaked) * 100
+    }
+    return projection
+}
+
+// validatorRewards returns a function giving a validator's rewards over its
+// first year active. Compounding validators are simulated, so results are
+// remembered by amount.
+func validatorRewards(results *types.RewardResults) func(types.PortfolioValidator) float64 {
+    compounding := make(map[uint64]float64)
+    return func(v types.PortfolioValidator) float64 {
+        if !v.Compounding() {
+            return results.TotalAnnualRewardsGwei * float64(effectiveBalance(v.Amount, config.MAX_EFFECTIVE_BALANCE)) /
+                float64(config.MAX_EFFECTIVE_BALANCE)
+        }
+        if annual, ok := compounding[v.Amount]; ok {
+            return annual
+        }
+        annual := compoundingRewards(results, v.Amount)
+        compounding[v.Amount] = annual
+        return annual
+    }
+}
+
+// compoundingRewards steps a 0x02 validator's balance epoch by epoch for a year.
+// Consensus rewards stay in the balance; execution rewards go to the fee
+// recipient and do not compound. The effective balance follows the balance
+// with the spec's hysteresis, so it rises once the balance is 1.25 ETH above it.
+func compoundingRewards(results *types.RewardResults, amount uint64) float64 {
+    scale := float64(config.MAX_EFFECTIVE_BALANCE) * float64(config.EPOCHS_PER_YEAR)
+    consensusPerGwei := results.ConsensusRewardsAnnualGwei / scale
+    executionPerGwei := results.ExecutionRewardsAnnualGwei / scale
+
+    hysteresis := float64(config.EFFECTIVE_BALANCE_INCREMENT / config.HYSTERESIS_QUOTIENT)
+    downward := hysteresis * float64(config.HYSTERESIS_DOWNWARD_MULTIPLIER)
+    upward := hysteresis * float64(config.HYSTERESIS_UPWARD_MULTIPLIER)
+
+    balance := float64(amount)
+    effective := effectiveBalance(amount, config.MAX_EFFECTIVE_BALANCE_ELECTRA)
+    total := 0.0
+    for epoch := uint64(0); epoch < config.EPOCHS_PER_YEAR; epoch++ {
+        consensus := consensusPerGwei * float64(effective)
+        balance += consensus
+        total += consensus + executionPerGwei*float64(effective)
+
+        if balance+downward < float64(effective) || float64(effective)+upward < balance {
+            effective = effectiveBalance(uint64(balance), config.MAX_EFFECTIVE_BALANCE_ELECTRA)
+        }
+    }
+    return total
+}
+
+// effectiveBalance is the effective balance a validator holding amount Gwei
+// settles at, given its maximum
+func effectiveBalance(amount, maxEffective uint64) uint64 {
+    return min(amount-amount%config.EFFECTIVE_BALANCE_INCREMENT, maxEffective)
+}
+
+// ProjectActivation adds the activation wait and first-year rewards of pending
+// deposits to a portfolio projection. The deposits join the activation queue in
+// order behind queueAhead validators, and each activates once the churn limit
+// has let everyone ahead of it through, plus the seed lookahead. Deposit
+// processing before the queue is ignored.
+func ProjectActivation(projection *types.PortfolioProjection, results *types.RewardResults,
+    validators []types.PortfolioValidator, activeValidators, queueAhead int) {
+    projection.ActivationQueue = queueAhead
+    projection.Deposits = nil
+    projection.FirstYearRewards = 0
+
+    daysPerYear := 365.25
+    lookahead := float64(1 + config.MAX_SEED_LOOKAHEAD)
+    rewards := validatorRewards(results)
+    for i, v := range validators {
+        epochs := EstimateValidatorQueue(activeValidators, queueAhead+i+1, 0).Activation.Epochs + lookahead
+        days := epochs / float64(config.EPOCHS_PER_DAY)
+
+        annual := rewards(v)
+        firstYear := annual * math.Max(0, daysPerYear-days) / daysPerYear
+
+        projection.Deposits = append(projection.Deposits, types.DepositProjection{
+            Pubkey:           v.Pubkey,
+            Amount:           v.Amount,
+            ActivationEpochs: epochs,
+            ActivationDays:   days,
+            FirstYearRewards: firstYear,
+        })
+        projection.FirstYearRewards += firstYear
+        projection.ActivationDays = days
+    }
+}
@@ -0,0 +1,102 @@
+package calculator
+
+import (
+    "fmt"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// Adjustments are the corrections applied on top of the base reward
+// projection. Amounts are in ETH and rates are fractions (0.0-1.0), except
+// the yield and tax rates, which are percentages. The zero value applies none.
+type Adjustments struct {
+    PriorityFees        float64 // per proposed block
+    MEV                 float64 // per proposed block
+    MissRate            float64
+    SyncUptime          float64 // of sync committee duties; 0 takes 1 - MissRate
+    SlashingProbability float64
+    ExitQueue           int
+    AlternativeYield    float64
+    TaxTreatment        string
+    IncomeTaxRate       float64
+    CapitalGainsRate    float64
+    HoldingYears        float64
+}
+
+// NewNetworkState builds a network of validators 32 ETH validators that is
+// finalizing, the state both the command line and pkg/rewards project from
+func NewNetworkState(validators int) (*types.NetworkState, error) {
+    if validators <= 0 {
+        return nil, fmt.Errorf("validators must be greater than 0")
+    }
+
+    state := &types.NetworkState{
+        Validators:     make([]types.Validator, validators),
+        CurrentEpoch:   1000,
+        FinalizedEpoch: 998,
+    }
+    for i := range state.Validators {
+        state.Validators[i] = types.Validator{EffectiveBalance: config.MAX_EFFECTIVE_BALANCE}
+    }
+    state.TotalActiveBalance = ComputeTotalActiveBalance(state, state.CurrentEpoch)
+
+    if err := state.Validate(); err != nil {
+        return nil, err
+    }
+    return state, nil
+}
+
+// NewTemplateState builds the network NewNetworkState would, but with one
+// template validator standing in for all validators. Every validator is
+// alike, so projections that take the count separately (see
+// ProjectTemplateRewards) cost the same at any size. The count is bounded by
+// the ETH supply instead of by memory.
+func NewTemplateState(validators int) (*types.NetworkState, error) {
+    if validators <= 0 || validators > MaxValidators() {
+        return nil, fmt.Errorf("validators must be between 1 and %d", MaxValidators())
+    }
+
+    state := &types.NetworkState{
+        Validators:         []types.Validator{{EffectiveBalance: config.MAX_EFFECTIVE_BALANCE}},
+        TotalActiveBalance: uint64(validators) * config.MAX_EFFECTIVE_BALANCE,
+        CurrentEpoch:       1000,
+        FinalizedEpoch:     998,
+    }
+    if err := state.Validate(); err != nil {
+        return nil, err
+    }
+    return state, nil
+}
+
+// MaxValidators is the most validators at the max effective balance the ETH
+// supply could fund
+func MaxValidators() int {
+    return int(config.TOTAL_ETH_SUPPLY / (config.MAX_EFFECTIVE_BALANCE / 1e9))
+}
+
+// ProjectRewards calculates one validator's rewards at participationRate with
+// every adjustment applied
+func ProjectRewards(state *types.NetworkState, participationRate float64, adjustments Adjustments) *types.RewardResults {
+    return ProjectTemplateRewards(state, len(state.Validators), participationRate, adjustments)
+}
+
+// ProjectTemplateRewards is ProjectRewards for a network of validatorCount
+// validators that all look like the state's first, such as a NewTemplateState
+func ProjectTemplateRewards(state *types.NetworkState, validatorCount int, participationRate float64,
+    adjustments Adjustments) *types.RewardResults {
+
+    results := calculateRewards(state, validatorCount, participationRate)
+    ApplyExecutionRewards(results, adjustments.PriorityFees*1e9, adjustments.MEV*1e9)
+    syncMissRate := adjustments.MissRate
+    if adjustments.SyncUptime > 0 {
+        syncMissRate = 1 - adjustments.SyncUptime
+    }
+    ApplyRiskAdjustment(state, results, adjustments.MissRate, syncMissRate, adjustments.SlashingProbability)
+    ApplyExitDelayCost(results, adjustments.ExitQueue, adjustments.AlternativeYield)
+    if adjustments.IncomeTaxRate > 0 || adjustments.CapitalGainsRate > 0 {
+        ApplyTaxTreatments(results, adjustments.TaxTreatment, adjustments.IncomeTaxRate,
+            adjustments.CapitalGainsRate, adjustments.HoldingYears)
+    }
+    return results
+}
@@ -0,0 +1,50 @@
+package calculator
+
+import (
+    "testing"
+
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+func TestProjectTemplateRewards(t *testing.T) {
+    adjustments := Adjustments{PriorityFees: 0.02, MEV: 0.05, MissRate: 0.01, SlashingProbability: 0.001}
+    for _, count := range []int{1, 1_000, 100_000} {
+        state, err := NewNetworkState(count)
+        if err != nil {
+            t.Fatal(err)
+        }
+        template, err := NewTemplateState(count)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if len(template.Validators) != 1 || template.TotalActiveBalance != state.TotalActiveBalance {
+            t.Fatalf("template of %d validators has %d validators and %d Gwei, want 1 and %d",
+                count, len(template.Validators), template.TotalActiveBalance, state.TotalActiveBalance)
+        }
+
+        want := ProjectRewards(state, 0.95, adjustments)
+        got := ProjectTemplateRewards(template, count, 0.95, adjustments)
+        if !sameRewards(got, want) {
+            t.Errorf("%d validators: template projects APY %g, risk-adjusted %g; full state %g, %g",
+                count, got.APY, got.RiskAdjustedAPY, want.APY, want.RiskAdjustedAPY)
+        }
+    }
+}
+
+func TestNewTemplateStateBounds(t *testing.T) {
+    for _, count := range []int{0, -1, MaxValidators() + 1} {
+        if _, err := NewTemplateState(count); err == nil {
+            t.Errorf("NewTemplateState(%d) returned no error", count)
+        }
+    }
+    if _, err := NewTemplateState(MaxValidators()); err != nil {
+        t.Errorf("NewTemplateState(%d): %v", MaxValidators(), err)
+    }
+}
+
+// sameRewards compares the figures a projection reports
+func sameRewards(a, b *types.RewardResults) bool {
+    return a.ValidatorCount == b.ValidatorCount && a.TotalStakedGwei == b.TotalStakedGwei &&
+        a.APY == b.APY && a.BaseAPY == b.BaseAPY && a.RiskAdjustedAPY == b.RiskAdjustedAPY &&
+        a.TotalAnnualRewardsGwei == b.TotalAnnualRewardsGwei && a.ExecutionRewardsAnnualGwei == b.ExecutionRewardsAnnualGwei
+}
@@ -9,10 +9,15 @@ import (
 
 // CalculateRewards computes all reward components for the given network state
 func CalculateRewards(state *types.NetworkState, participationRate float64) *types.RewardResults {
-    validatorCount := len(state.Validators)
-    
+    return calculateRewards(state, len(state.Validators), participationRate)
+}
+
+// calculateRewards computes rewards for a network of validatorCount validators
+// that all look like state.Validators[0]. Only the first validator is read, so
+// sweeps can pass a one-element template instead of a full validator slice.
+func calculateRewards(state *types.NetworkState, validatorCount int, participationRate float64) *types.RewardResults {
     // Calculate base reward for a validator with max effective balance
-    baseReward := GetBaseReward(state, 0)
+    baseReward := baseRewardFor(state, config.MAX_EFFECTIVE_BALANCE)
     sqrtTotal := IntegerSquareRoot(state.TotalActiveBalance)
     
     // Component rewards
@@ -21,37 +26,54 @@ func CalculateRewards(state *types.NetworkState, participationRate float64) *typ
     headReward := baseReward * config.TIMELY_HEAD_WEIGHT / config.WEIGHT_DENOMINATOR
     attestationReward := sourceReward + targetReward + headReward
     
-    // Proposer calculations
+    // Proposer calculations (one proposer is chosen per slot)
     proposerProbability := 1.0 / float64(validatorCount)
-    proposalsPerEpoch := proposerProbability
+    proposalsPerEpoch := proposerProbability * float64(config.SLOTS_PER_EPOCH)
     proposalsPerYear := proposalsPerEpoch * float64(config.EPOCHS_PER_YEAR)
     
     // Calculate realistic proposer reward including attestation inclusion
-    attestationInclusionReward := CalculateAttestationInclusionReward(state, participationRate)
-    estimatedAttestationsPerBlock := EstimateAttestationsPerBlock(state)
+    attestationInclusionReward := attestationInclusionReward(state, validatorCount, participationRate)
+    estimatedAttestationsPerBlock := estimateInclusion(state, validatorCount, DefaultInclusionModel).votesPerBlock
     inclusionEffectivenessRate := CalculateInclusionEffectivenessRate(participationRate)
     
     // Average proposer reward per block (with attestation inclusion)
     avgProposerReward := float64(attestationInclusionReward)
-    proposerRewardPerEpoch := avgProposerReward * proposerProbability
+    proposerRewardPerEpoch := avgProposerReward * proposalsPerEpoch
+    
+    // Expected sync committee income: SYNC_COMMITTEE_SIZE members are drawn each
+    // period and earn a reward every slot of it
+    syncCommitteeShare := math.Min(1, float64(config.SYNC_COMMITTEE_SIZE)/float64(validatorCount))
+    syncRewardPerSlot := float64(CalculateSyncCommitteeReward(state, 1))
+    syncIncomePerPeriod := syncRewardPerSlot * float64(config.SLOTS_PER_EPOCH) *
+                          float64(config.EPOCHS_PER_SYNC_COMMITTEE_PERIOD)
+    syncPeriodsPerYear := syncCommitteeShare * float64(config.EPOCHS_PER_YEAR) /
+                         float64(config.EPOCHS_PER_SYNC_COMMITTEE_PERIOD)
+    syncAnnual := syncIncomePerPeriod * syncPeriodsPerYear
     
     // Calculate base annual rewards (at 100% participation)
     baseAttestationAnnual := float64(attestationReward) * float64(config.EPOCHS_PER_YEAR)
     baseProposerAnnual := proposerRewardPerEpoch * float64(config.EPOCHS_PER_YEAR)
-    baseTotalAnnual := baseAttestationAnnual + baseProposerAnnual
+    baseTotalAnnual := baseAttestationAnnual + baseProposerAnnual + syncAnnual
     baseAPY := (baseTotalAnnual / float64(config.MAX_EFFECTIVE_BALANCE)) * 100
     
     // Apply participation economics - active validators get higher rewards when participation is low
     participationMultiplier := 1.0 / participationRate
     
-    // Effective rewards for active validators
+    // Effective rewards for active validators (sync rewards are fixed per participant)
     attestationAnnual := baseAttestationAnnual * participationMultiplier
     proposerAnnual := baseProposerAnnual * participationMultiplier
-    totalAnnual := attestationAnnual + proposerAnnual
+    consensusAnnual := attestationAnnual + proposerAnnual + syncAnnual
+    totalAnnual := consensusAnnual
     
     // Effective APY with participation boost
     effectiveAPY := (totalAnnual / float64(config.MAX_EFFECTIVE_BALANCE)) * 100
     
+    // Yield of each duty, boosted like the totals
+    apr := func(perEpoch uint64) float64 {
+        return float64(perEpoch) * float64(config.EPOCHS_PER_YEAR) * participationMultiplier /
+            float64(config.MAX_EFFECTIVE_BALANCE) * 100
+    }
+    
     // Check for inactivity leak conditions
     inactivityLeakActive := participationRate < 0.6667
     networkHealthWarning := ""
@@ -63,117 +85,149 @@ func CalculateRewards(state *types.NetworkState, participationRate float64) *typ
         networkHealthWarning = "CAUTION: Network participation below 80% - reduced security"
     }
     
-    return &types.RewardResults{
+    results := &types.RewardResults{
         // Input parameters
-        ValidatorCount:     validatorCount,
-        TotalStaked:       state.TotalActiveBalance,
+        ValidatorCount:    validatorCount,
+        TotalStakedGwei:   state.TotalActiveBalance,
         ParticipationRate: participationRate,
         
         // Base calculations
-        SqrtTotalBalance:   sqrtTotal,
-        BaseRewardPerEpoch: baseReward,
+        SqrtTotalBalance:           sqrtTotal,
+        BaseRewardPerIncrementGwei: GetBaseRewardPerIncrement(state),
+        BaseRewardPerEpochGwei:     baseReward,
         
         // Component rewards
-        SourceReward:              sourceReward,
-        TargetReward:              targetReward,
-        HeadReward:                headReward,
-        AttestationRewardPerEpoch: attestationReward,
+        SourceRewardGwei:              sourceReward,
+        TargetRewardGwei:              targetReward,
+        HeadRewardGwei:                headReward,
+        AttestationRewardPerEpochGwei: attestationReward,
         
         // Proposer calculations
-        ProposerProbability:       proposerProbability,
-        ExpectedProposalsPerYear:  proposalsPerYear,
-        AvgProposerRewardPerBlock: avgProposerReward,
-        ProposerRewardPerEpoch:    proposerRewardPerEpoch,
+        ProposerProbability:           proposerProbability,
+        ExpectedProposalsPerYear:      proposalsPerYear,
+        AvgProposerRewardPerBlockGwei: avgProposerReward,
+        ProposerRewardPerEpochGwei:    proposerRewardPerEpoch,
+        FirstProposal:                 EstimateFirstProposal(validatorCount),
+        
+        // Sync committee calculations
+        SyncCommitteeProbability:         syncCommitteeShare,
+        SyncCommitteePeriodsPerYear:      syncPeriodsPerYear,
+        SyncCommitteeIncomePerPeriodGwei: syncIncomePerPeriod,
         
         // Attestation inclusion details
-        EstimatedAttestationsPerBlock: estimatedAttestationsPerBlock,
-        AttestationInclusionReward:    attestationInclusionReward,
-        InclusionEffectivenessRate:    inclusionEffectivenessRate,
+        EstimatedAttestationsPerBlock:  estimatedAttestationsPerBlock,
+        AttestationInclusionRewardGwei: attestationInclusionReward,
+        InclusionEffectivenessRate:     inclusionEffectivenessRate,
         
         // Annual projections
-        AttestationRewardsAnnual: attestationAnnual,
-        ProposerRewardsAnnual:    proposerAnnual,
-        TotalAnnualRewards:       totalAnnual,
-        APY:                      effectiveAPY,
+        AttestationRewardsAnnualGwei:   attestationAnnual,
+        ProposerRewardsAnnualGwei:      proposerAnnual,
+        SyncCommitteeRewardsAnnualGwei: syncAnnual,
+        ConsensusRewardsAnnualGwei:     consensusAnnual,
+        TotalAnnualRewardsGwei:         totalAnnual,
+        APY:                            effectiveAPY,
         
-        // Time-based projections
-        DailyRewards:   totalAnnual / 365.25,
-        WeeklyRewards:  totalAnnual / 52.18,
-        MonthlyRewards: totalAnnual / 12,
+        // Yield by duty
+        SourceAPR:   apr(sourceReward),
+        TargetAPR:   apr(targetReward),
+        HeadAPR:     apr(headReward),
+        ProposerAPR: proposerAnnual / float64(config.MAX_EFFECTIVE_BALANCE) * 100,
+        SyncAPR:     syncAnnual / float64(config.MAX_EFFECTIVE_BALANCE) * 100,
         
         // Participation economics
         ParticipationMultiplier: participationMultiplier,
-        BaseAPY:                baseAPY,
-        EffectiveAPY:           effectiveAPY,
-        InactivityLeakActive:   inactivityLeakActive,
-        NetworkHealthWarning:   networkHealthWarning,
+        BaseAPY:                 baseAPY,
+        EffectiveAPY:            effectiveAPY,
+        InactivityLeakActive:    inactivityLeakActive,
+        NetworkHealthWarning:    networkHealthWarning,
+        
+        // Risk adjustment (none until ApplyRiskAdjustment is called)
+        RiskAdjustedAPY: effectiveAPY,
     }
+    setPeriods(results)
+    setRewardVariance(results)
+    
+    return results
 }
 
-// GetBaseReward calculates the base reward for a validator using Electra formula (Altair+)
-func GetBaseReward(state *types.NetworkState, validatorIndex int) uint64 {
-    totalBalance := state.TotalActiveBalance
-    effectiveBalance := state.Validators[validatorIndex].EffectiveBalance
-    
-    // Electra formula: removes division by BASE_REWARDS_PER_EPOCH (used in Phase 0)
-    return effectiveBalance * config.BASE_REWARD_FACTOR / 
-           IntegerSquareRoot(totalBalance)
+// setRewardVariance computes the standard deviation of annual rewards. Attestation
+// income is steady, but proposals arrive as a Poisson process and sync committee
+// duty is a yes-or-no draw every period, so both add spread around the mean.
+func setRewardVariance(results *types.RewardResults) {
+    perBlock := results.AvgProposerRewardPerBlockGwei*results.ParticipationMultiplier +
+        results.PriorityFeesPerBlockGwei + results.MEVPerBlockGwei
+    proposerVariance := results.ExpectedProposalsPerYear * perBlock * perBlock
+    
+    periodsPerYear := float64(config.EPOCHS_PER_YEAR) / float64(config.EPOCHS_PER_SYNC_COMMITTEE_PERIOD)
+    p := results.SyncCommitteeProbability
+    syncVariance := periodsPerYear * p * (1 - p) *
+        results.SyncCommitteeIncomePerPeriodGwei * results.SyncCommitteeIncomePerPeriodGwei
+    
+    results.ProposerRewardsStdDevGwei = math.Sqrt(proposerVariance)
+    results.SyncCommitteeRewardsStdDevGwei = math.Sqrt(syncVariance)
+    results.AnnualRewardsStdDevGwei = math.Sqrt(proposerVariance + syncVariance)
+    results.APYStdDev = results.AnnualRewardsStdDevGwei / float64(config.MAX_EFFECTIVE_BALANCE) * 100
 }
 
-// GetBaseRewardPerIncrement calculates base reward per increment using Electra formula (Altair+)
-func GetBaseRewardPerIncrement(state *types.NetworkState) uint64 {
-    return config.EFFECTIVE_BALANCE_INCREMENT * config.BASE_REWARD_FACTOR / 
-           IntegerSquareRoot(state.TotalActiveBalance)
+// FirstProposalPercentiles are the percentiles reported for the wait to a first proposal
+var FirstProposalPercentiles = []float64{50, 90, 99}
+
+// EstimateFirstProposal computes the expected wait and percentiles until a
+// validator's first block proposal. With a 1/validatorCount chance per slot,
+// the chance of having proposed within k slots is 1 - (1 - p)^k.
+func EstimateFirstProposal(validatorCount int) types.FirstProposalEstimate {
+    p := 1.0 / float64(validatorCount)
+    slotsPerDay := float64(config.SLOTS_PER_EPOCH * config.EPOCHS_PER_DAY)
+
+    expectedSlots := 1 / p
+    estimate := types.FirstProposalEstimate{
+        ExpectedEpochs: expectedSlots / float64(config.SLOTS_PER_EPOCH),
+        ExpectedDays:   expectedSlots / slotsPerDay,
+    }
+
+    for _, percentile := range FirstProposalPercentiles {
+        // A single validator proposes every slot
+        slots := 1.0
+        if p < 1 {
+            slots = math.Ceil(math.Log1p(-percentile/100) / math.Log1p(-p))
+        }
+        estimate.Percentiles = append(estimate.Percentiles, types.ProposalPercentile{
+            Percentile: percentile,
+            Epochs:     slots / float64(config.SLOTS_PER_EPOCH),
+            Days:       slots / slotsPerDay,
+        })
+    }
+
+    return estimate
 }
 
-// EstimateAttestationsPerBlock estimates how many attestations can fit in a block
-func EstimateAttestationsPerBlock(state *types.NetworkState) float64 {
-    validatorCount := float64(len(state.Validators))
-    
-    // Attestations come from validators in previous epochs
-    // Each epoch has 32 slots, so we get attestations from ~32 slots worth of validators
-    // But blocks have size limits, so we can't include all attestations
-    
-    // Conservative estimate: ~60% of validator attestations can be included per block
-    // This accounts for:
-    // - Block size limits
-    // - Some attestations being too old
-    // - Network propagation delays
-    maxIncludableRate := 0.6
-    
-    // Attestations per slot = validators / slots_per_epoch
-    attestationsPerSlot := validatorCount / float64(config.SLOTS_PER_EPOCH)
-    
-    // Estimate attestations from multiple previous slots that can be included
-    slotsToInclude := 8.0 // Conservative estimate of slots we can include from
-    
-    estimatedAttestations := attestationsPerSlot * slotsToInclude * maxIncludableRate
-    
-    return estimatedAttestations
+// GetBaseReward calculates the base reward for a validator as the spec does
+// since Altair: the base reward per increment times the validator's effective
+// balance increments, so any effective balance up to
+// MAX_EFFECTIVE_BALANCE_ELECTRA is handled alike
+func GetBaseReward(state *types.NetworkState, validatorIndex int) (uint64, error) {
+    validator, err := validatorAt(state, validatorIndex)
+    if err != nil {
+        return 0, err
+    }
+    return baseRewardFor(state, validator.EffectiveBalance), nil
 }
 
-// CalculateAttestationInclusionReward calculates rewards for including attestations in a block
-func CalculateAttestationInclusionReward(state *types.NetworkState, participationRate float64) uint64 {
-    baseRewardIncrement := GetBaseRewardPerIncrement(state)
-    estimatedAttestations := EstimateAttestationsPerBlock(state)
-    
-    // Each attestation has 3 components: source, target, head
-    // Proposer gets reward for each component included
-    avgComponentsPerAttestation := 2.8 // Account for some missed/late votes
-    
-    // Apply participation rate - not all validators are active
-    effectiveAttestations := estimatedAttestations * participationRate
-    
-    // Apply inclusion effectiveness - some attestations are late or missed
-    inclusionEffectiveness := 0.9 // 90% effectiveness rate
-    finalAttestations := effectiveAttestations * inclusionEffectiveness
-    
-    // Calculate total proposer reward
-    // Proposer gets 1/PROPOSER_REWARD_QUOTIENT of the attestation reward
-    proposerRewardPerComponent := baseRewardIncrement / config.PROPOSER_REWARD_QUOTIENT
-    totalInclusionReward := uint64(finalAttestations * avgComponentsPerAttestation) * proposerRewardPerComponent
-    
-    return totalInclusionReward
+// baseRewardFor is the base reward of a validator with effectiveBalance
+func baseRewardFor(state *types.NetworkState, effectiveBalance uint64) uint64 {
+    return EffectiveBalanceIncrements(effectiveBalance) * GetBaseRewardPerIncrement(state)
+}
+
+// GetBaseRewardPerIncrement calculates base reward per increment using Electra formula (Altair+)
+func GetBaseRewardPerIncrement(state *types.NetworkState) uint64 {
+    return mulDiv(config.EFFECTIVE_BALANCE_INCREMENT, config.BASE_REWARD_FACTOR,
+                  IntegerSquareRoot(state.TotalActiveBalance))
+}
+
+// EffectiveBalanceIncrements is the number of whole EFFECTIVE_BALANCE_INCREMENTs
+// in balance, the unit the spec weighs rewards and penalties in
+func EffectiveBalanceIncrements(balance uint64) uint64 {
+    return balance / config.EFFECTIVE_BALANCE_INCREMENT
 }
 
 // CalculateInclusionEffectivenessRate calculates the effective inclusion rate
@@ -190,9 +244,12 @@ func CalculateInclusionEffectivenessRate(participationRate float64) float64 {
 
 // CalculateAttestationReward computes reward for a single attestation
 func CalculateAttestationReward(state *types.NetworkState, validatorIndex int,
-    correctSource, correctTarget, correctHead bool, inclusionDelay uint64) uint64 {
+    correctSource, correctTarget, correctHead bool, inclusionDelay uint64) (uint64, error) {
     
-    baseReward := GetBaseReward(state, validatorIndex)
+    baseReward, err := GetBaseReward(state, validatorIndex)
+    if err != nil {
+        return 0, err
+    }
     reward := uint64(0)
     
     if correctSource {
@@ -211,7 +268,7 @@ func CalculateAttestationReward(state *types.NetworkState, validatorIndex int,
                 (config.PROPOSER_REWARD_QUOTIENT + inclusionDelay - config.MIN_ATTESTATION_INCLUSION_DELAY)
     }
     
-    return reward
+    return reward, nil
 }
 
 // CalculateProposerReward computes reward for block proposer
@@ -219,17 +276,16 @@ func CalculateProposerReward(state *types.NetworkState, attestingBalance uint64)
     baseRewardPerIncrement := GetBaseRewardPerIncrement(state)
     proposerRewardPerIncrement := baseRewardPerIncrement / config.PROPOSER_REWARD_QUOTIENT
     
-    return proposerRewardPerIncrement * attestingBalance / config.EFFECTIVE_BALANCE_INCREMENT
+    return proposerRewardPerIncrement * EffectiveBalanceIncrements(attestingBalance)
 }
 
-// CalculateSyncCommitteeReward computes sync committee participation reward
+// CalculateSyncCommitteeReward computes sync committee participation reward per slot
 func CalculateSyncCommitteeReward(state *types.NetworkState, participantCount int) uint64 {
-    baseReward := GetBaseReward(state, 0) // Assume max effective balance
-    totalActiveIncrements := state.TotalActiveBalance / config.EFFECTIVE_BALANCE_INCREMENT
-    totalBaseRewards := baseReward * totalActiveIncrements
+    totalActiveIncrements := EffectiveBalanceIncrements(state.TotalActiveBalance)
+    totalBaseRewards := mulDiv(GetBaseRewardPerIncrement(state), totalActiveIncrements, 1)
     
-    maxParticipantRewards := totalBaseRewards * config.SYNC_REWARD_WEIGHT / 
-                            config.WEIGHT_DENOMINATOR / config.SLOTS_PER_EPOCH
+    maxParticipantRewards := mulDiv(totalBaseRewards, config.SYNC_REWARD_WEIGHT, 
+                                    config.WEIGHT_DENOMINATOR) / config.SLOTS_PER_EPOCH
     participantReward := maxParticipantRewards / config.SYNC_COMMITTEE_SIZE
     
     return participantReward * uint64(participantCount)
@@ -250,14 +306,14 @@ func EstimateNetworkIssuance(state *types.NetworkState, participationRate float6
     results := CalculateRewards(state, participationRate)
     
     // Network-wide issuance
-    totalIssuancePerEpoch := results.BaseRewardPerEpoch * 4 * uint64(validatorCount) * 
+    totalIssuancePerEpoch := results.BaseRewardPerEpochGwei * 4 * uint64(validatorCount) * 
                             uint64(participationRate * float64(config.WEIGHT_DENOMINATOR)) / 
                             config.WEIGHT_DENOMINATOR
     
     totalIssuancePerYear := float64(totalIssuancePerEpoch) * float64(config.EPOCHS_PER_YEAR) / 1e9
     
     // Assume total ETH supply (this would need to be tracked properly)
-    totalSupply := uint64(120_000_000) // Approximate ETH supply
+    totalSupply := uint64(config.TOTAL_ETH_SUPPLY)
     inflationRate := (totalIssuancePerYear / float64(totalSupply)) * 100
     
     return &types.NetworkMetrics{
@@ -269,7 +325,7 @@ func EstimateNetworkIssuance(state *types.NetworkState, participationRate float6
         NetworkParticipation: participationRate,
         TotalSupply:          totalSupply,
         StakedPercentage:     float64(state.TotalActiveBalance/1e9) / float64(totalSupply) * 100,
-        YieldPerValidator:    results.TotalAnnualRewards / 1e9,
+        YieldPerValidator:    results.TotalAnnualRewardsGwei / 1e9,
     }
 }
 
@@ -0,0 +1,63 @@
+package calculator
+
+import (
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// ApplyRiskAdjustment reduces the projected return by the expected cost of missed
+// duties and slashing. missRate is the fraction of duties missed (0.0-1.0),
+// syncMissRate the fraction of sync committee duties missed while on the
+// committee, and slashingProbability the chance of being slashed within a year.
+func ApplyRiskAdjustment(state *types.NetworkState, results *types.RewardResults,
+    missRate, syncMissRate, slashingProbability float64) {
+
+    // Missed duties forfeit their reward and incur the attestation penalty
+    syncAnnual := results.SyncCommitteeRewardsAnnualGwei
+    penalties := penaltiesFor(state, typicalValidator(state), false, false, false)
+    missedRewards := (results.TotalAnnualRewardsGwei-syncAnnual)*missRate + syncAnnual*syncMissRate
+    missedPenalties := float64(penalties.TotalAttestationPenaltyGwei) * float64(config.EPOCHS_PER_YEAR) * missRate
+
+    // A committee member that misses a slot is penalized the reward it would
+    // have earned, so each missed sync duty costs twice its reward
+    syncPenalties := syncAnnual * syncMissRate
+    missedPenalties += syncPenalties
+
+    slashingLoss := ExpectedAnnualSlashingLoss(state, slashingProbability)
+
+    netAnnual := results.TotalAnnualRewardsGwei - missedRewards - missedPenalties - slashingLoss
+
+    results.MissRate = missRate
+    results.SyncMissRate = syncMissRate
+    results.SlashingProbability = slashingProbability
+    results.ExpectedMissedRewardsGwei = missedRewards
+    results.ExpectedPenaltiesGwei = missedPenalties
+    results.ExpectedSyncPenaltiesGwei = syncPenalties
+    results.ExpectedSlashingLossGwei = slashingLoss
+    results.RiskAdjustedAPY = netAnnual / float64(config.MAX_EFFECTIVE_BALANCE) * 100
+    setPeriods(results)
+}
+
+// HistoricalSlashingRate returns the observed number of slashings per validator-year on mainnet
+func HistoricalSlashingRate() float64 {
+    slashed := 0
+    validatorYears := 0
+
+    for _, year := range config.HistoricalSlashings {
+        slashed += year.SlashedValidators
+        validatorYears += year.AverageActiveValidators
+    }
+
+    if validatorYears == 0 {
+        return 0
+    }
+    return float64(slashed) / float64(validatorYears)
+}
+
+// ExpectedAnnualSlashingLoss returns the expected yearly loss in Gwei from a single
+// (uncorrelated) slashing of the validator at the given annual probability
+func ExpectedAnnualSlashingLoss(state *types.NetworkState, slashingProbability float64) float64 {
+    validator := typicalValidator(state)
+    slashing := slashingPenaltiesFor(state, validator, validator.EffectiveBalance)
+    return float64(slashing.TotalPenaltyGwei) * slashingProbability
+}
@@ -0,0 +1,115 @@
+package calculator
+
+import (
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// SoloStakingSetup computes the economics of running 32 ETH validators directly.
+// results should already include any risk adjustment.
+func SoloStakingSetup(results *types.RewardResults, capitalETH float64) types.StakingSetup {
+    stakeETH := float64(config.MAX_EFFECTIVE_BALANCE) / 1e9
+    validators := int(capitalETH / stakeETH)
+    deployed := float64(validators) * stakeETH
+
+    gross := float64(validators) * results.TotalAnnualRewardsGwei / 1e9
+    penalties := float64(validators) * (results.ExpectedMissedRewardsGwei + results.ExpectedPenaltiesGwei +
+        results.ExpectedSlashingLossGwei) / 1e9
+
+    return newStakingSetup("solo", validators, capitalETH, deployed, gross, penalties, 0)
+}
+
+// CSMBond returns the bond in Gwei required to run the given number of CSM validators
+func CSMBond(validators int) uint64 {
+    if validators <= 0 {
+        return 0
+    }
+    return config.CSM_FIRST_BOND + uint64(validators-1)*config.CSM_SUBSEQUENT_BOND
+}
+
+// CSMValidatorsForBond returns how many CSM validators a bond (in ETH) can support
+func CSMValidatorsForBond(bondETH float64) int {
+    bond := bondETH * 1e9
+    if bond < config.CSM_FIRST_BOND {
+        return 0
+    }
+    return 1 + int((bond-config.CSM_FIRST_BOND)/config.CSM_SUBSEQUENT_BOND)
+}
+
+// LidoCSMSetup computes the economics of bonding the capital as a Lido Community Staking
+// Module operator. The operator earns the module fee on the rewards of pool-funded
+// validators and the stETH rebase on the bond, while slashing losses come out of the bond.
+func LidoCSMSetup(results *types.RewardResults, capitalETH float64) types.StakingSetup {
+    validators := CSMValidatorsForBond(capitalETH)
+
+    // Fee rewards are withheld for frames where performance is below the threshold
+    performance := 1 - results.MissRate
+    if performance < config.CSM_PERFORMANCE_THRESHOLD {
+        performance = 0
+    }
+
+    operatorFee := float64(config.CSM_OPERATOR_FEE_BPS) / 10000
+    feeRewards := float64(validators) * results.TotalAnnualRewardsGwei / 1e9 * operatorFee * performance
+
+    // Excess bond above the curve stays in stETH and also earns the rebase
+    stETHAPR := results.APY / 100 * (1 - float64(config.LIDO_PROTOCOL_FEE_BPS)/10000)
+    bondRewards := capitalETH * stETHAPR
+
+    penalties := float64(validators) * results.ExpectedSlashingLossGwei / 1e9
+
+    return newStakingSetup("lido-csm", validators, capitalETH, capitalETH, feeRewards+bondRewards, penalties, 0)
+}
+
+func newStakingSetup(name string, validators int, capital, deployed, gross, penalties,
+    fees float64) types.StakingSetup {
+
+    net := gross - penalties - fees
+    roc := 0.0
+    if capital > 0 {
+        roc = net / capital * 100
+    }
+
+    return types.StakingSetup{
+        Name:            name,
+        Validators:      validators,
+        CapitalDeployed: deployed,
+        IdleCapital:     math.Max(0, capital-deployed),
+        GrossRewards:    gross,
+        Penalties:       penalties,
+        Fees:            fees,
+        NetRewards:      net,
+        ReturnOnCapital: roc,
+    }
+}
+
+// RestakedSetup adds an EigenLayer-style restaking layer on top of solo staking. The
+// restaked validators earn restakingAPR (percent) on their stake, and are exposed to
+// losing slashingLoss (fraction of stake) with annual probability slashingProbability.
+func RestakedSetup(results *types.RewardResults, capitalETH, restakingAPR,
+    slashingProbability, slashingLoss float64) types.StakingSetup {
+
+    solo := SoloStakingSetup(results, capitalETH)
+
+    restakingRewards := solo.CapitalDeployed * restakingAPR / 100
+    restakingPenalties := solo.CapitalDeployed * slashingProbability * slashingLoss
+
+    return newStakingSetup("restaked", solo.Validators, capitalETH, solo.CapitalDeployed,
+        solo.GrossRewards+restakingRewards, solo.Penalties+restakingPenalties, 0)
+}
+
+// SSVSetup runs the solo validators through an SSV cluster. Each of the cluster's
+// operators charges operatorFee and the network charges networkFee, both in SSV
+// tokens per validator per year, converted to ETH at ssvPriceETH.
+func SSVSetup(results *types.RewardResults, capitalETH float64, operators int,
+    operatorFee, networkFee, ssvPriceETH float64) types.StakingSetup {
+
+    solo := SoloStakingSetup(results, capitalETH)
+
+    feesPerValidator := (float64(operators)*operatorFee + networkFee) * ssvPriceETH
+    fees := float64(solo.Validators) * feesPerValidator
+
+    return newStakingSetup("ssv", solo.Validators, capitalETH, solo.CapitalDeployed,
+        solo.GrossRewards, solo.Penalties, fees)
+}
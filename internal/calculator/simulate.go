@@ -0,0 +1,185 @@
+package calculator
+
+import (
+    "math"
+    "math/rand"
+    "sort"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// SimulationPercentiles are the percentiles reported for simulated annual rewards
+var SimulationPercentiles = []float64{5, 25, 50, 75, 95}
+
+// SimulateAnnualRewards draws trials of one validator's annual rewards and bins
+// them into a histogram. Attestation income is fixed; each trial draws the
+// number of proposals (Poisson) and of sync committee periods served (one
+// draw per period), so the mean matches results.TotalAnnualRewards.
+func SimulateAnnualRewards(results *types.RewardResults, trials, bins int, seed int64) *types.SimulationResult {
+    return SimulateAnnualRewardsWithProgress(results, trials, bins, seed, nil)
+}
+
+// simulationProgressStep is how many trials run between progress callbacks
+const simulationProgressStep = 10000
+
+// SimulateAnnualRewardsWithProgress is SimulateAnnualRewards, calling progress
+// (when not nil) with the number of trials completed since the last call
+func SimulateAnnualRewardsWithProgress(results *types.RewardResults, trials, bins int, seed int64, progress func(trials int)) *types.SimulationResult {
+    return simulateAnnualRewards(results, trials, bins, seed, progress, nil)
+}
+
+// SimulateAnnualRewardsTrials is SimulateAnnualRewardsWithProgress that also
+// returns every trial in the order drawn
+func SimulateAnnualRewardsTrials(results *types.RewardResults, trials, bins int, seed int64,
+    progress func(trials int)) (*types.SimulationResult, []types.SimulationTrial) {
+
+    drawn := make([]types.SimulationTrial, 0, trials)
+    result := simulateAnnualRewards(results, trials, bins, seed, progress, func(trial types.SimulationTrial) {
+        drawn = append(drawn, trial)
+    })
+    return result, drawn
+}
+
+// simulateAnnualRewards runs the trials, passing each to record when not nil
+func simulateAnnualRewards(results *types.RewardResults, trials, bins int, seed int64,
+    progress func(trials int), record func(types.SimulationTrial)) *types.SimulationResult {
+
+    rng := rand.New(rand.NewSource(seed))
+
+    perBlock := results.AvgProposerRewardPerBlockGwei*results.ParticipationMultiplier +
+        results.PriorityFeesPerBlockGwei + results.MEVPerBlockGwei
+    periods := int(math.Round(float64(config.EPOCHS_PER_YEAR) / float64(config.EPOCHS_PER_SYNC_COMMITTEE_PERIOD)))
+    fixed := results.TotalAnnualRewardsGwei - results.ExpectedProposalsPerYear*perBlock -
+        float64(periods)*results.SyncCommitteeProbability*results.SyncCommitteeIncomePerPeriodGwei
+
+    outcomes := make([]float64, trials)
+    for i := range outcomes {
+        proposals := samplePoisson(rng, results.ExpectedProposalsPerYear)
+
+        served := 0
+        for j := 0; j < periods; j++ {
+            if rng.Float64() < results.SyncCommitteeProbability {
+                served++
+            }
+        }
+
+        annual := fixed + float64(proposals)*perBlock + float64(served)*results.SyncCommitteeIncomePerPeriodGwei
+        outcomes[i] = annual / 1e9
+        if record != nil {
+            record(types.SimulationTrial{Trial: i + 1, Proposals: proposals, SyncPeriods: served, Rewards: outcomes[i]})
+        }
+
+        if progress != nil && (i+1)%simulationProgressStep == 0 {
+            progress(simulationProgressStep)
+        }
+    }
+    if progress != nil && trials%simulationProgressStep != 0 {
+        progress(trials % simulationProgressStep)
+    }
+    sort.Float64s(outcomes)
+
+    result := &types.SimulationResult{
+        Trials: trials,
+        Seed:   seed,
+        Min:    outcomes[0],
+        Max:    outcomes[trials-1],
+    }
+
+    for _, v := range outcomes {
+        result.Mean += v
+    }
+    result.Mean /= float64(trials)
+    for _, v := range outcomes {
+        result.StdDev += (v - result.Mean) * (v - result.Mean)
+    }
+    result.StdDev = math.Sqrt(result.StdDev / float64(trials))
+
+    for _, percentile := range SimulationPercentiles {
+        index := int(math.Ceil(percentile/100*float64(trials))) - 1
+        result.Percentiles = append(result.Percentiles, types.SimulationPercentile{
+            Percentile: percentile,
+            Rewards:    outcomes[int(math.Max(0, float64(index)))],
+        })
+    }
+
+    result.Histogram = histogram(outcomes, bins)
+    return result
+}
+
+// histogram bins sorted values into equal-width bins spanning their range.
+// The last bin also includes the maximum.
+func histogram(sorted []float64, bins int) []types.HistogramBin {
+    low, high := sorted[0], sorted[len(sorted)-1]
+    if high == low {
+        return []types.HistogramBin{{Lower: low, Upper: high, Count: len(sorted), Share: 1}}
+    }
+
+    width := (high - low) / float64(bins)
+    histogram := make([]types.HistogramBin, bins)
+    for i := range histogram {
+        histogram[i].Lower = low + float64(i)*width
+        histogram[i].Upper = low + float64(i+1)*width
+    }
+    for _, v := range sorted {
+        bin := int((v - low) / width)
+        if bin >= bins {
+            bin = bins - 1
+        }
+        histogram[bin].Count++
+    }
+    for i := range histogram {
+        histogram[i].Share = float64(histogram[i].Count) / float64(len(sorted))
+    }
+
+    return histogram
+}
+
+// samplePoisson draws from a Poisson distribution. Large means use the normal
+// approximation, where counting individual arrivals would be too slow.
+func samplePoisson(rng *rand.Rand, mean float64) int {
+    if mean > 100 {
+        return int(math.Max(0, math.Round(mean+math.Sqrt(mean)*rng.NormFloat64())))
+    }
+
+    // Knuth: count uniform draws until their product falls below e^-mean
+    limit := math.Exp(-mean)
+    count := 0
+    for product := rng.Float64(); product > limit; product *= rng.Float64() {
+        count++
+    }
+    return count
+}
+
+// sampleBinomial draws the number of successes in n trials of probability p.
+// Large counts use the normal approximation; otherwise the distribution is
+// inverted term by term, which takes about n*p steps rather than n draws.
+func sampleBinomial(rng *rand.Rand, n uint64, p float64) uint64 {
+    if p <= 0 || n == 0 {
+        return 0
+    }
+    if p >= 1 {
+        return n
+    }
+    if p > 0.5 {
+        return n - sampleBinomial(rng, n, 1-p)
+    }
+
+    mean := float64(n) * p
+    if mean > 100 {
+        draw := math.Round(mean + math.Sqrt(mean*(1-p))*rng.NormFloat64())
+        return uint64(math.Min(float64(n), math.Max(0, draw)))
+    }
+
+    // Walk the cumulative distribution until it passes a uniform draw
+    u := rng.Float64()
+    term := math.Exp(float64(n) * math.Log1p(-p)) // P(X = 0)
+    cumulative := term
+    k := uint64(0)
+    for u > cumulative && k < n {
+        term *= float64(n-k) / float64(k+1) * p / (1 - p)
+        k++
+        cumulative += term
+    }
+    return k
+}
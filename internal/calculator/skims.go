@@ -0,0 +1,115 @@
+package calculator
+
+import (
+    "math"
+    "sort"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// ProjectSkims builds a calendar of the partial withdrawals a 0x01 validator
+// receives over the given number of days from start. Nearly every validator
+// has an excess balance to skim, so the sweep withdraws from
+// MAX_WITHDRAWALS_PER_PAYLOAD validators per slot and a full pass takes
+// validatorCount / MAX_WITHDRAWALS_PER_PAYLOAD slots. Each skim pays out the
+// consensus rewards earned since the last one; execution rewards go to the fee
+// recipient instead. The validator is assumed to be active already; its place in
+// the sweep is unknown, so the first skim is expected half a pass after start.
+func ProjectSkims(results *types.RewardResults, start time.Time, days float64) *types.SkimProjection {
+    cycleSlots := math.Ceil(float64(results.ValidatorCount) / float64(config.MAX_WITHDRAWALS_PER_PAYLOAD))
+    cycleDays := cycleSlots * float64(config.SECONDS_PER_SLOT) / 86400
+    perDay := results.ConsensusRewardsAnnualGwei / 1e9 / 365.25
+
+    projection := &types.SkimProjection{
+        CycleDays:     cycleDays,
+        SkimsPerYear:  365.25 / cycleDays,
+        AmountPerSkim: perDay * cycleDays,
+        AnnualSkimmed: results.ConsensusRewardsAnnualGwei / 1e9,
+    }
+
+    projection.Balance = simulateBalance(results, cycleDays, days)
+
+    cumulative := 0.0
+    for n, day := 1, cycleDays/2; day <= days; n, day = n+1, day+cycleDays {
+        cumulative += projection.AmountPerSkim
+
+        projection.Calendar = append(projection.Calendar, types.SkimEvent{
+            Number:     n,
+            Date:       start.Add(time.Duration(day * 24 * float64(time.Hour))).Format("2006-01-02"),
+            Days:       day,
+            Amount:     projection.AmountPerSkim,
+            Cumulative: cumulative,
+        })
+    }
+
+    return projection
+}
+
+// simulateBalance steps a validator's balance epoch by epoch over the given
+// number of days: consensus rewards raise it above 32 ETH and every skim resets
+// it. The excess earns nothing, since the effective balance is capped, so the
+// rewards are spread over a slightly larger average balance than 32 ETH.
+func simulateBalance(results *types.RewardResults, cycleDays, days float64) types.BalanceTrajectory {
+    stake := float64(config.MAX_EFFECTIVE_BALANCE) / 1e9
+    epochsPerDay := float64(config.EPOCHS_PER_DAY)
+    perEpoch := results.ConsensusRewardsAnnualGwei / 1e9 / 365.25 / epochsPerDay
+    cycleEpochs := math.Max(1, math.Round(cycleDays*epochsPerDay))
+    // Simulate at least one full cycle so the average covers a whole sawtooth
+    epochs := math.Max(cycleEpochs, math.Round(days*epochsPerDay))
+
+    // The first skim is half a cycle away, so half a cycle of rewards has built up
+    excess := perEpoch * math.Round(cycleEpochs/2)
+    nextSkim := cycleEpochs - math.Round(cycleEpochs/2)
+    peak, total := excess, 0.0
+    for epoch := 1.0; epoch <= epochs; epoch++ {
+        excess += perEpoch
+        peak = math.Max(peak, excess)
+        if epoch == nextSkim {
+            excess = 0
+            nextSkim += cycleEpochs
+        }
+        total += excess
+    }
+
+    trajectory := types.BalanceTrajectory{
+        PeakBalance:    stake + peak,
+        AverageBalance: stake + total/epochs,
+        NaiveAPY:       results.APY,
+    }
+    trajectory.EffectiveAPY = results.APY * stake / trajectory.AverageBalance
+    trajectory.APYDifference = trajectory.EffectiveAPY - trajectory.NaiveAPY
+    return trajectory
+}
+
+// ProjectRewardEvents lists the income a validator is expected to receive over
+// the given number of days from start: consensus rewards as partial withdrawal
+// skims, and execution rewards (priority fees and MEV) once per expected proposal
+func ProjectRewardEvents(results *types.RewardResults, start time.Time, days float64) []types.RewardEvent {
+    var events []types.RewardEvent
+    for _, skim := range ProjectSkims(results, start, days).Calendar {
+        events = append(events, types.RewardEvent{
+            Time:        start.Add(time.Duration(skim.Days * 24 * float64(time.Hour))),
+            Layer:       types.ConsensusLayer,
+            Amount:      skim.Amount,
+            Description: "Partial withdrawal",
+        })
+    }
+
+    perBlock := (results.PriorityFeesPerBlockGwei + results.MEVPerBlockGwei) / 1e9
+    if perBlock > 0 && results.ExpectedProposalsPerYear > 0 {
+        interval := 365.25 / results.ExpectedProposalsPerYear
+        for day := interval / 2; day <= days; day += interval {
+            events = append(events, types.RewardEvent{
+                Time:        start.Add(time.Duration(day * 24 * float64(time.Hour))),
+                Layer:       types.ExecutionLayer,
+                Amount:      perBlock,
+                Description: "Block proposal fees and MEV",
+            })
+        }
+    }
+
+    sort.Slice(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+    return events
+}
@@ -0,0 +1,39 @@
+package calculator
+
+import (
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// SolveTargetIncome finds the stake that earns monthlyTarget ETH per month net
+// of expected losses. With 0x01 credentials stake comes in whole 32 ETH
+// validators. With 0x02 credentials rewards accrue per effective balance
+// increment, so any whole number of ETH works, split across validators of up to
+// MAX_EFFECTIVE_BALANCE_ELECTRA. The 0x02 stake is what earns the target in the
+// first month; compounding raises the income after that. Activating a validator
+// still takes at least 32 ETH.
+func SolveTargetIncome(results *types.RewardResults, monthlyTarget float64) *types.TargetIncome {
+    stakeETH := float64(config.MAX_EFFECTIVE_BALANCE) / 1e9
+    netPerValidator := (results.TotalAnnualRewardsGwei - results.ExpectedMissedRewardsGwei - results.ExpectedPenaltiesGwei -
+        results.ExpectedSlashingLossGwei) / 1e9 / 12
+
+    target := &types.TargetIncome{
+        MonthlyTarget: monthlyTarget,
+        NetAPY:        netPerValidator * 12 / stakeETH * 100,
+    }
+    if netPerValidator <= 0 {
+        return target
+    }
+
+    target.Validators = int(math.Ceil(monthlyTarget / netPerValidator))
+    target.ValidatorStake = float64(target.Validators) * stakeETH
+
+    increment := float64(config.EFFECTIVE_BALANCE_INCREMENT) / 1e9
+    perIncrement := netPerValidator / stakeETH * increment
+    target.CompoundingStake = math.Max(stakeETH, math.Ceil(monthlyTarget/perIncrement)*increment)
+    target.CompoundingValidators = int(math.Ceil(target.CompoundingStake * 1e9 /
+        float64(config.MAX_EFFECTIVE_BALANCE_ELECTRA)))
+    return target
+}
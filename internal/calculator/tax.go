@@ -0,0 +1,70 @@
+package calculator
+
+import (
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// Tax treatments of staking rewards
+const (
+    // TaxAtReceipt taxes rewards as income when they are received
+    TaxAtReceipt = "receipt"
+    // TaxAtDisposal gives rewards a zero cost basis and taxes them as capital
+    // gains when they are sold
+    TaxAtDisposal = "disposal"
+)
+
+// TaxTreatments lists the supported tax treatments
+var TaxTreatments = []string{TaxAtReceipt, TaxAtDisposal}
+
+// IsTaxTreatment reports whether treatment is one of TaxTreatments
+func IsTaxTreatment(treatment string) bool {
+    for _, known := range TaxTreatments {
+        if treatment == known {
+            return true
+        }
+    }
+    return false
+}
+
+// ApplyTaxTreatments computes the risk-adjusted return left after tax under
+// every treatment and selects one as the after-tax APY. Rates are in percent.
+// Rewards are restaked for holdingYears: taxed at receipt, each year's rewards
+// lose the income tax; taxed at disposal, the tax is deferred until the
+// accumulated rewards are sold at the end, and the return is annualized.
+func ApplyTaxTreatments(results *types.RewardResults, treatment string, incomeRate, capitalGainsRate,
+    holdingYears float64) {
+
+    apy := results.RiskAdjustedAPY
+    stake := float64(config.MAX_EFFECTIVE_BALANCE)
+
+    atReceipt := apy * (1 - incomeRate/100)
+
+    growth := math.Pow(1+apy/100, holdingYears)
+    afterSale := 1 + (growth-1)*(1-capitalGainsRate/100)
+    atDisposal := (math.Pow(afterSale, 1/holdingYears) - 1) * 100
+
+    results.TaxResults = []types.TaxResult{
+        {
+            Treatment:   TaxAtReceipt,
+            TaxRate:     incomeRate,
+            AnnualTaxGwei:   math.Max(0, math.Round(stake*(apy-atReceipt)/100)),
+            AfterTaxAPY: atReceipt,
+        },
+        {
+            Treatment:   TaxAtDisposal,
+            TaxRate:     capitalGainsRate,
+            AnnualTaxGwei:   math.Max(0, math.Round(stake*(apy-atDisposal)/100)),
+            AfterTaxAPY: atDisposal,
+        },
+    }
+
+    results.TaxTreatment = treatment
+    for _, tax := range results.TaxResults {
+        if tax.Treatment == treatment {
+            results.AfterTaxAPY = tax.AfterTaxAPY
+        }
+    }
+}
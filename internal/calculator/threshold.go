@@ -0,0 +1,59 @@
+package calculator
+
+import (
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// thresholdIterations bounds the bisection in SolveAPYThresholds (precision 2^-40)
+const thresholdIterations = 40
+
+// thresholdMinParticipation is the lowest participation rate searched
+const thresholdMinParticipation = 0.01
+
+// SolveAPYThresholds finds where the risk-adjusted APY falls to targetAPY (in
+// percent): the validator's own duty effectiveness (1 - miss rate) at the given
+// network participation, and the network participation at the given miss rate.
+// Fees, MEV, and the slashing probability are held fixed; a threshold is only
+// found if the APY crosses the target within the searched range.
+func SolveAPYThresholds(state *types.NetworkState, participationRate, missRate, slashingProbability,
+    priorityFees, mev, targetAPY float64) *types.APYThreshold {
+
+    apyAt := func(participationRate, missRate float64) float64 {
+        results := CalculateRewards(state, participationRate)
+        ApplyExecutionRewards(results, priorityFees, mev)
+        ApplyRiskAdjustment(state, results, missRate, missRate, slashingProbability)
+        return results.RiskAdjustedAPY
+    }
+
+    threshold := &types.APYThreshold{
+        TargetAPY:  targetAPY,
+        CurrentAPY: apyAt(participationRate, missRate),
+    }
+
+    // The risk-adjusted APY is linear in the miss rate
+    full, none := apyAt(participationRate, 0), apyAt(participationRate, 1)
+    if none <= targetAPY && targetAPY <= full && full > none {
+        threshold.Effectiveness = (targetAPY - none) / (full - none)
+        threshold.EffectivenessFound = true
+    }
+
+    // The APY is monotonic in participation, so bisect between empty and full
+    // participation if the target lies between the two (participation 0 would
+    // divide by zero, so the lower end starts just above it)
+    low, high := thresholdMinParticipation, 1.0
+    lowAbove := apyAt(low, missRate) > targetAPY
+    if lowAbove != (apyAt(high, missRate) > targetAPY) {
+        for i := 0; i < thresholdIterations; i++ {
+            mid := (low + high) / 2
+            if (apyAt(mid, missRate) > targetAPY) == lowAbove {
+                low = mid
+            } else {
+                high = mid
+            }
+        }
+        threshold.Participation = (low + high) / 2
+        threshold.ParticipationFound = true
+    }
+
+    return threshold
+}
@@ -3,39 +3,36 @@ package calculator
 import (
     "fmt"
     "math"
+    "math/bits"
     
     "github.com/eth-rewards-calculator/internal/config"
     "github.com/eth-rewards-calculator/internal/types"
 )
 
-// ValidatorSetComparison compares rewards across different validator set sizes
+// ValidatorSetComparison compares rewards across different validator set sizes.
+// Every validator has the max effective balance, so a single template
+// validator stands in for the whole set and each count costs O(1).
 func ValidatorSetComparison(participation float64, validatorCounts ...int) []types.ComparisonResult {
     results := make([]types.ComparisonResult, len(validatorCounts))
+    template := []types.Validator{{EffectiveBalance: config.MAX_EFFECTIVE_BALANCE}}
     
     for i, count := range validatorCounts {
         state := &types.NetworkState{
-            Validators:         make([]types.Validator, count),
+            Validators:         template,
             TotalActiveBalance: uint64(count) * config.MAX_EFFECTIVE_BALANCE,
             CurrentEpoch:       1000,
             FinalizedEpoch:     998,
         }
         
-        // Initialize validators
-        for j := range state.Validators {
-            state.Validators[j] = types.Validator{
-                EffectiveBalance: config.MAX_EFFECTIVE_BALANCE,
-            }
-        }
-        
-        rewards := CalculateRewards(state, participation)
+        rewards := calculateRewards(state, count, participation)
         
         results[i] = types.ComparisonResult{
             ValidatorCount: count,
             TotalStaked:    state.TotalActiveBalance / 1e9,
-            BaseReward:     rewards.BaseRewardPerEpoch,
-            AnnualRewards:  rewards.TotalAnnualRewards / 1e9,
+            BaseRewardGwei: rewards.BaseRewardPerEpochGwei,
+            AnnualRewards:  rewards.TotalAnnualRewardsGwei / 1e9,
             APY:            rewards.APY,
-            DailyRewards:   rewards.DailyRewards / 1e9,
+            DailyRewards:   rewards.DailyRewardsGwei / 1e9,
         }
     }
     
@@ -57,19 +54,38 @@ func CalculateBreakEvenTime(apy float64) (years, months, days float64) {
     return
 }
 
-// EstimateValidatorQueue estimates activation queue time based on churn limit
-func EstimateValidatorQueue(currentValidators, pendingValidators int) (epochs, days float64) {
-    // Calculate churn limit
-    churnLimit := max(config.MIN_PER_EPOCH_CHURN_LIMIT, 
-                     uint64(currentValidators)/config.CHURN_LIMIT_QUOTIENT)
-    
-    // Cap at max churn limit
-    churnLimit = min(churnLimit, config.MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT)
-    
-    epochs = float64(pendingValidators) / float64(churnLimit)
-    days = epochs / float64(config.EPOCHS_PER_DAY)
-    
-    return
+// EstimateValidatorQueue estimates the wait through the activation and exit
+// queues. Since Deneb activation churn is capped at
+// MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT while exit churn keeps growing with the
+// validator set, so on a large network exits drain faster than deposits.
+func EstimateValidatorQueue(currentValidators, pendingActivations, pendingExits int) *types.ValidatorQueue {
+    queue := &types.ValidatorQueue{
+        ActiveValidators: currentValidators,
+        Activation:       queueDirection(pendingActivations, activationChurnLimit(currentValidators)),
+        Exit:             queueDirection(pendingExits, exitChurnLimit(currentValidators)),
+    }
+    queue.Activation.Capped = queue.Activation.ChurnLimit < exitChurnLimit(currentValidators)
+
+    queue.NetChangePerDay = float64(config.EPOCHS_PER_DAY) *
+        (float64(queue.Activation.ChurnLimit) - float64(queue.Exit.ChurnLimit))
+    return queue
+}
+
+// queueDirection is the wait for pending validators at churnLimit per epoch
+func queueDirection(pending int, churnLimit uint64) types.QueueDirection {
+    epochs := float64(pending) / float64(churnLimit)
+    return types.QueueDirection{
+        Pending:    pending,
+        ChurnLimit: churnLimit,
+        Epochs:     epochs,
+        Days:       epochs / float64(config.EPOCHS_PER_DAY),
+    }
+}
+
+// activationChurnLimit is the number of validators that may activate per
+// epoch, the exit churn capped as in Deneb (EIP-7514)
+func activationChurnLimit(activeValidators int) uint64 {
+    return min(exitChurnLimit(activeValidators), config.MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT)
 }
 
 // CalculateCompoundingReturns calculates returns with reinvestment
@@ -135,6 +151,40 @@ func CalculateNetReturns(grossAPY, inflationRate, taxRate float64) map[string]fl
 
 // Helper functions
 
+// mulDiv computes a*b/c with a 128-bit intermediate so the product cannot
+// wrap around. Results that do not fit in uint64 saturate at math.MaxUint64,
+// and a zero divisor yields 0.
+func mulDiv(a, b, c uint64) uint64 {
+    if c == 0 {
+        return 0
+    }
+    hi, lo := bits.Mul64(a, b)
+    if hi >= c {
+        return math.MaxUint64
+    }
+    quotient, _ := bits.Div64(hi, lo, c)
+    return quotient
+}
+
+// validatorAt returns the validator at index, or an error naming the index
+// and the size of the validator set if there is none
+func validatorAt(state *types.NetworkState, index int) (*types.Validator, error) {
+    if err := state.ValidateIndex(index); err != nil {
+        return nil, err
+    }
+    return &state.Validators[index], nil
+}
+
+// typicalValidator is the validator the network-wide estimates model: the
+// first in the state, which NewNetworkState makes like every other, or a
+// validator at MAX_EFFECTIVE_BALANCE if the state has none
+func typicalValidator(state *types.NetworkState) *types.Validator {
+    if len(state.Validators) == 0 {
+        return &types.Validator{EffectiveBalance: config.MAX_EFFECTIVE_BALANCE}
+    }
+    return &state.Validators[0]
+}
+
 func max(a, b uint64) uint64 {
     if a > b {
         return a
@@ -0,0 +1,178 @@
+package calculator
+
+import (
+    "fmt"
+    "math"
+    "math/big"
+    "math/rand"
+    "strings"
+    "testing"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// bigMulDiv is a*b/c computed exactly, saturating at MaxUint64 as mulDiv does
+func bigMulDiv(a, b, c uint64) uint64 {
+    if c == 0 {
+        return 0
+    }
+    product := new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+    quotient := product.Quo(product, new(big.Int).SetUint64(c))
+    if !quotient.IsUint64() {
+        return math.MaxUint64
+    }
+    return quotient.Uint64()
+}
+
+func TestMulDiv(t *testing.T) {
+    tests := []struct {
+        name    string
+        a, b, c uint64
+        want    uint64
+    }{
+        {"zero divisor", 10, 10, 0, 0},
+        {"zero factor", 0, math.MaxUint64, 7, 0},
+        {"small values", 7, 6, 4, 10},
+        {"base reward per increment", config.EFFECTIVE_BALANCE_INCREMENT, config.BASE_REWARD_FACTOR, 5_656_854, 11_313},
+        {"max numerator, max divisor", math.MaxUint64, math.MaxUint64, math.MaxUint64, math.MaxUint64},
+        {"max numerator, divisor just fits", math.MaxUint64, math.MaxUint64 - 1, math.MaxUint64, math.MaxUint64 - 1},
+        {"max numerator saturates", math.MaxUint64, math.MaxUint64, 1, math.MaxUint64},
+        {"quotient just saturates", math.MaxUint64, 2, 1, math.MaxUint64},
+        {"2048 ETH times max numerator", config.MAX_EFFECTIVE_BALANCE_ELECTRA, math.MaxUint64, config.MAX_EFFECTIVE_BALANCE_ELECTRA, math.MaxUint64},
+        {"2048 ETH squared", config.MAX_EFFECTIVE_BALANCE_ELECTRA, config.MAX_EFFECTIVE_BALANCE_ELECTRA, config.EFFECTIVE_BALANCE_INCREMENT, 4_194_304_000_000_000},
+        {"product overflows, quotient fits", 1 << 63, 1 << 10, 1 << 20, 1 << 53},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := mulDiv(tt.a, tt.b, tt.c)
+            if got != tt.want {
+                t.Errorf("mulDiv(%d, %d, %d) = %d, want %d", tt.a, tt.b, tt.c, got, tt.want)
+            }
+            if exact := bigMulDiv(tt.a, tt.b, tt.c); got != exact {
+                t.Errorf("mulDiv(%d, %d, %d) = %d, math/big gives %d", tt.a, tt.b, tt.c, got, exact)
+            }
+        })
+    }
+}
+
+// bigBaseReward is the spec's get_base_reward computed with math/big
+func bigBaseReward(effectiveBalance, totalActiveBalance uint64) uint64 {
+    increment := new(big.Int).SetUint64(config.EFFECTIVE_BALANCE_INCREMENT)
+    perIncrement := new(big.Int).Mul(increment, new(big.Int).SetUint64(config.BASE_REWARD_FACTOR))
+    perIncrement.Quo(perIncrement, new(big.Int).Sqrt(new(big.Int).SetUint64(totalActiveBalance)))
+    increments := new(big.Int).Quo(new(big.Int).SetUint64(effectiveBalance), increment)
+    return increments.Mul(increments, perIncrement).Uint64()
+}
+
+func TestGetBaseReward(t *testing.T) {
+    tests := []struct {
+        name               string
+        effectiveBalance   uint64
+        totalActiveBalance uint64
+    }{
+        {"32 ETH, one million validators", config.MAX_EFFECTIVE_BALANCE, 1_000_000 * config.MAX_EFFECTIVE_BALANCE},
+        {"2048 ETH, one million validators", config.MAX_EFFECTIVE_BALANCE_ELECTRA, 1_000_000 * config.MAX_EFFECTIVE_BALANCE},
+        {"2048 ETH alone", config.MAX_EFFECTIVE_BALANCE_ELECTRA, config.MAX_EFFECTIVE_BALANCE_ELECTRA},
+        {"2048 ETH, total of one Gwei", config.MAX_EFFECTIVE_BALANCE_ELECTRA, 1},
+        {"2048 ETH, all ETH staked", config.MAX_EFFECTIVE_BALANCE_ELECTRA, 120_000_000 * config.EFFECTIVE_BALANCE_INCREMENT},
+        {"2048 ETH, max total balance", config.MAX_EFFECTIVE_BALANCE_ELECTRA, math.MaxUint64},
+        {"partial increment", config.MAX_EFFECTIVE_BALANCE_ELECTRA - 1, 1_000_000 * config.MAX_EFFECTIVE_BALANCE},
+        {"zero effective balance", 0, 1_000_000 * config.MAX_EFFECTIVE_BALANCE},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            state := &types.NetworkState{
+                Validators:         []types.Validator{{EffectiveBalance: tt.effectiveBalance}},
+                TotalActiveBalance: tt.totalActiveBalance,
+            }
+            want := bigBaseReward(tt.effectiveBalance, tt.totalActiveBalance)
+            got, err := GetBaseReward(state, 0)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got != want {
+                t.Errorf("GetBaseReward = %d, math/big gives %d", got, want)
+            }
+        })
+    }
+}
+
+func TestValidatorIndexOutOfRange(t *testing.T) {
+    state, err := NewNetworkState(3)
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, index := range []int{-1, 3} {
+        if _, err := GetBaseReward(state, index); err == nil || !strings.Contains(err.Error(), fmt.Sprintf("index %d out of range [0, 3)", index)) {
+            t.Errorf("GetBaseReward(%d) error = %v, want the index and the set size", index, err)
+        }
+        if _, err := CalculatePenalties(state, index, false, false, false); err == nil {
+            t.Errorf("CalculatePenalties(%d) returned no error", index)
+        }
+        if _, err := CalculateSlashingPenalties(state, index, config.MAX_EFFECTIVE_BALANCE); err == nil {
+            t.Errorf("CalculateSlashingPenalties(%d) returned no error", index)
+        }
+    }
+}
+
+func TestIntegerSquareRoot(t *testing.T) {
+    for _, n := range []uint64{0, 1, 2, 3, 4, 15, 16, 17, 1 << 52, 1<<53 + 1, 1_000_000 * config.MAX_EFFECTIVE_BALANCE, math.MaxUint64 - 1, math.MaxUint64} {
+        want := new(big.Int).Sqrt(new(big.Int).SetUint64(n)).Uint64()
+        if got := IntegerSquareRoot(n); got != want {
+            t.Errorf("IntegerSquareRoot(%d) = %d, want %d", n, got, want)
+        }
+    }
+}
+
+// BenchmarkValidatorSetComparison shows that a comparison costs the same at
+// any validator count, since no per-validator state is built
+func BenchmarkValidatorSetComparison(b *testing.B) {
+    counts := []int{1_000, 10_000, 100_000, 1_000_000, 2_000_000, 5_000_000}
+    for _, count := range counts {
+        b.Run(fmt.Sprintf("validators=%d", count), func(b *testing.B) {
+            b.ReportAllocs()
+            for i := 0; i < b.N; i++ {
+                ValidatorSetComparison(0.95, count)
+            }
+        })
+    }
+    b.Run("sweep", func(b *testing.B) {
+        b.ReportAllocs()
+        for i := 0; i < b.N; i++ {
+            ValidatorSetComparison(0.95, counts...)
+        }
+    })
+}
+
+func TestSampleBinomial(t *testing.T) {
+    tests := []struct {
+        name string
+        n    uint64
+        p    float64
+    }{
+        {"rare proposals", 7200, 1.0 / 1_000_000},
+        {"small mean", 50, 0.1},
+        {"normal approximation", 100_000, 0.01},
+        {"likely successes", 1000, 0.9},
+    }
+    rng := rand.New(rand.NewSource(1))
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            const draws = 20_000
+            var sum float64
+            for i := 0; i < draws; i++ {
+                k := sampleBinomial(rng, tt.n, tt.p)
+                if k > tt.n {
+                    t.Fatalf("sampleBinomial(%d, %g) = %d", tt.n, tt.p, k)
+                }
+                sum += float64(k)
+            }
+            mean := float64(tt.n) * tt.p
+            stderr := math.Sqrt(mean*(1-tt.p)/draws)
+            if got := sum / draws; math.Abs(got-mean) > 5*stderr {
+                t.Errorf("mean of %d draws = %g, want %g", draws, got, mean)
+            }
+        })
+    }
+}
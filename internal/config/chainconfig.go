@@ -0,0 +1,70 @@
+package config
+
+import (
+    "bufio"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+)
+
+// ParseChainConfig reads a consensus layer config.yaml, as written by
+// ethereum-package and other devnet tooling, into chain spec values.
+// Only top-level "KEY: value" entries are read; nested entries such as
+// BLOB_SCHEDULE lists are skipped.
+func ParseChainConfig(r io.Reader) (map[string]string, error) {
+    spec := make(map[string]string)
+    scanner := bufio.NewScanner(r)
+    for line := 1; scanner.Scan(); line++ {
+        text := scanner.Text()
+        trimmed := strings.TrimSpace(text)
+        if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+            continue
+        }
+        // Indented lines and list items belong to a nested value
+        if text[0] == ' ' || text[0] == '\t' || text[0] == '-' {
+            continue
+        }
+
+        key, value, ok := strings.Cut(text, ":")
+        if !ok {
+            return nil, fmt.Errorf("line %d: expected KEY: value", line)
+        }
+        if i := strings.Index(value, " #"); i >= 0 {
+            value = value[:i]
+        }
+        value = strings.Trim(strings.TrimSpace(value), `'"`)
+        if value == "" {
+            continue
+        }
+        spec[strings.TrimSpace(key)] = value
+    }
+    return spec, scanner.Err()
+}
+
+// LoadChainConfig applies a config.yaml file: first the preset named by its
+// PRESET_BASE, if any, then its own values. It returns the keys that were applied.
+func LoadChainConfig(path string) ([]string, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    spec, err := ParseChainConfig(f)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", path, err)
+    }
+
+    if base, ok := spec["PRESET_BASE"]; ok {
+        if err := ApplyPreset(base); err != nil {
+            return nil, fmt.Errorf("%s: %w", path, err)
+        }
+    }
+
+    applied, err := ApplySpec(spec)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", path, err)
+    }
+    return applied, nil
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+// devnetConfig is the shape of an ethereum-package config.yaml
+const devnetConfig = `# Extends the minimal preset
+PRESET_BASE: 'minimal'
+CONFIG_NAME: "testnet" # quoted
+
+# Genesis
+GENESIS_FORK_VERSION: 0x10000038
+ELECTRA_FORK_VERSION: 0x60000038
+ELECTRA_FORK_EPOCH: 0
+
+---
+SECONDS_PER_SLOT: 6
+	INDENTED_BY_TAB: 1
+BLOB_SCHEDULE:
+  - EPOCH: 269568
+    MAX_BLOBS_PER_BLOCK: 6
+- EPOCH: 364032
+DEPOSIT_CONTRACT_ADDRESS: 0x4242424242424242424242424242424242424242
+TERMINAL_BLOCK_HASH_ACTIVATION_EPOCH:
+`
+
+func TestParseChainConfig(t *testing.T) {
+    spec, err := ParseChainConfig(strings.NewReader(devnetConfig))
+    if err != nil {
+        t.Fatal(err)
+    }
+    // Comments, quotes, blank values, and the nested BLOB_SCHEDULE are dropped
+    want := map[string]string{
+        "PRESET_BASE":              "minimal",
+        "CONFIG_NAME":              "testnet",
+        "GENESIS_FORK_VERSION":     "0x10000038",
+        "ELECTRA_FORK_VERSION":     "0x60000038",
+        "ELECTRA_FORK_EPOCH":       "0",
+        "SECONDS_PER_SLOT":         "6",
+        "DEPOSIT_CONTRACT_ADDRESS": "0x4242424242424242424242424242424242424242",
+    }
+    if !reflect.DeepEqual(spec, want) {
+        t.Errorf("ParseChainConfig =\n%v\nwant\n%v", spec, want)
+    }
+
+    if _, err := ParseChainConfig(strings.NewReader("SECONDS_PER_SLOT: 6\nnot a pair\n")); err == nil ||
+        err.Error() != "line 2: expected KEY: value" {
+        t.Errorf("line without a colon: %v", err)
+    }
+}
+
+func TestLoadChainConfig(t *testing.T) {
+    saved := Save()
+    defer saved.Restore()
+
+    dir := t.TempDir()
+    path := filepath.Join(dir, "config.yaml")
+    if err := os.WriteFile(path, []byte(devnetConfig), 0o644); err != nil {
+        t.Fatal(err)
+    }
+
+    // The minimal preset comes first, then the file's own values over it
+    applied, err := LoadChainConfig(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(applied) != 3 {
+        t.Errorf("applied %v, want the slot time and two fork versions", applied)
+    }
+    if SLOTS_PER_EPOCH != 8 || SECONDS_PER_SLOT != 6 || PHASE0_FORK_VERSION != "0x10000038" || EPOCHS_PER_DAY != 1800 {
+        t.Errorf("%d slots of %ds, genesis version %s, %d epochs a day; want 8, 6, 0x10000038, 1800",
+            SLOTS_PER_EPOCH, SECONDS_PER_SLOT, PHASE0_FORK_VERSION, EPOCHS_PER_DAY)
+    }
+
+    tests := []struct {
+        name, config, wantErr string
+    }{
+        {"unknown preset", "PRESET_BASE: gnosis\n", "gnosis"},
+        {"invalid value", "SLOTS_PER_EPOCH: many\n", "invalid value"},
+        {"not KEY: value", "SLOTS_PER_EPOCH = 8\n", "line 1: expected KEY: value"},
+    }
+    for _, tt := range tests {
+        saved.Restore()
+        bad := filepath.Join(dir, "bad.yaml")
+        if err := os.WriteFile(bad, []byte(tt.config), 0o644); err != nil {
+            t.Fatal(err)
+        }
+        _, err := LoadChainConfig(bad)
+        if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.HasPrefix(err.Error(), bad+": ") {
+            t.Errorf("%s: error %v, want one naming the file and mentioning %q", tt.name, err, tt.wantErr)
+        }
+    }
+    if _, err := LoadChainConfig(filepath.Join(dir, "missing.yaml")); err == nil {
+        t.Error("missing file loaded")
+    }
+}
@@ -1,93 +1,127 @@
 package config
 
-// Reward and penalty constants from Ethereum mainnet
-const (
+import "strings"
+
+// Chain parameters, defaulting to Ethereum mainnet. These are variables so a
+// chain spec loaded at startup (see ApplySpec) can replace them; they must not
+// be changed once calculations start.
+var (
     // Base parameters
-    BASE_REWARD_FACTOR             = 64
-    BASE_REWARDS_PER_EPOCH         = 4
-    PROPOSER_REWARD_QUOTIENT       = 8
-    WHISTLEBLOWER_REWARD_QUOTIENT  = 512
-    MIN_SLASHING_PENALTY_QUOTIENT  = 128
-    PROPORTIONAL_SLASHING_MULTIPLIER = 1
+    BASE_REWARD_FACTOR             uint64 = 64
+    BASE_REWARDS_PER_EPOCH         uint64 = 4
+    PROPOSER_REWARD_QUOTIENT       uint64 = 8
+    WHISTLEBLOWER_REWARD_QUOTIENT  uint64 = 512
+    MIN_SLASHING_PENALTY_QUOTIENT  uint64 = 128
+    PROPORTIONAL_SLASHING_MULTIPLIER uint64 = 1
     
     // Altair parameters
-    INACTIVITY_PENALTY_QUOTIENT_ALTAIR     = 50331648  // 2**24
-    MIN_SLASHING_PENALTY_QUOTIENT_ALTAIR   = 64
-    PROPORTIONAL_SLASHING_MULTIPLIER_ALTAIR = 2
+    INACTIVITY_PENALTY_QUOTIENT_ALTAIR     uint64 = 50331648  // 2**24
+    MIN_SLASHING_PENALTY_QUOTIENT_ALTAIR   uint64 = 64
+    PROPORTIONAL_SLASHING_MULTIPLIER_ALTAIR uint64 = 2
     
     // Bellatrix parameters
-    INACTIVITY_PENALTY_QUOTIENT_BELLATRIX     = 33554432  // 2**25
-    MIN_SLASHING_PENALTY_QUOTIENT_BELLATRIX   = 32
-    PROPORTIONAL_SLASHING_MULTIPLIER_BELLATRIX = 3
+    INACTIVITY_PENALTY_QUOTIENT_BELLATRIX     uint64 = 33554432  // 2**25
+    MIN_SLASHING_PENALTY_QUOTIENT_BELLATRIX   uint64 = 32
+    PROPORTIONAL_SLASHING_MULTIPLIER_BELLATRIX uint64 = 3
     
     // Phase 0 parameters (for backwards compatibility)
-    INACTIVITY_PENALTY_QUOTIENT    = 67108864  // 2**26
-    INACTIVITY_SCORE_BIAS          = 4
-    INACTIVITY_SCORE_RECOVERY_RATE = 16
+    INACTIVITY_PENALTY_QUOTIENT    uint64 = 67108864  // 2**26
+    INACTIVITY_SCORE_BIAS          uint64 = 4
+    INACTIVITY_SCORE_RECOVERY_RATE uint64 = 16
+    MIN_EPOCHS_TO_INACTIVITY_PENALTY uint64 = 4
     
-    // Participation flag weights
-    // TIMELY_SOURCE_WEIGHT = 6
-    // TIMELY_TARGET_WEIGHT = 10
-    // TIMELY_HEAD_WEIGHT   = 6
-    // SYNC_REWARD_WEIGHT   = 1
-    // PROPOSER_WEIGHT      = 3
-    // WEIGHT_DENOMINATOR   = 26
-
-	TIMELY_SOURCE_WEIGHT = 14
-    TIMELY_TARGET_WEIGHT = 26
-    TIMELY_HEAD_WEIGHT   = 14
-    SYNC_REWARD_WEIGHT   = 2
-    PROPOSER_WEIGHT      = 8
-    WEIGHT_DENOMINATOR   = 64
+    // Participation flag weights (the weights-26 overlay holds an alternative set)
+	TIMELY_SOURCE_WEIGHT uint64 = 14
+    TIMELY_TARGET_WEIGHT uint64 = 26
+    TIMELY_HEAD_WEIGHT   uint64 = 14
+    SYNC_REWARD_WEIGHT   uint64 = 2
+    PROPOSER_WEIGHT      uint64 = 8
+    WEIGHT_DENOMINATOR   uint64 = 64
     
     // Sync committee
-    SYNC_COMMITTEE_SIZE                   = 512
-    SYNC_COMMITTEE_SUBNET_COUNT          = 4
-    SYNC_REWARD_WEIGHT_DENOMINATOR       = 2
+    SYNC_COMMITTEE_SIZE                   uint64 = 512
+    EPOCHS_PER_SYNC_COMMITTEE_PERIOD      uint64 = 256
+    SYNC_COMMITTEE_SUBNET_COUNT          uint64 = 4
+    SYNC_REWARD_WEIGHT_DENOMINATOR       uint64 = 2
     
     // Balance parameters
-    EFFECTIVE_BALANCE_INCREMENT = 1000000000  // 1 ETH in Gwei
-    MAX_EFFECTIVE_BALANCE       = 32000000000 // 32 ETH in Gwei
-    EJECTION_BALANCE           = 16000000000 // 16 ETH in Gwei
+    EFFECTIVE_BALANCE_INCREMENT uint64 = 1000000000  // 1 ETH in Gwei
+    MAX_EFFECTIVE_BALANCE       uint64 = 32000000000 // 32 ETH in Gwei
+    EJECTION_BALANCE           uint64 = 16000000000 // 16 ETH in Gwei
+    MAX_EFFECTIVE_BALANCE_ELECTRA uint64 = 2048000000000 // 2048 ETH in Gwei, for 0x02 credentials
+    HYSTERESIS_QUOTIENT         uint64 = 4
+    HYSTERESIS_DOWNWARD_MULTIPLIER uint64 = 1
+    HYSTERESIS_UPWARD_MULTIPLIER   uint64 = 5
     
-    // Time parameters
-    SLOTS_PER_EPOCH                  = 32
-    EPOCHS_PER_YEAR                  = 82180 // 365.25 * 225
-    EPOCHS_PER_DAY                   = 225
-    EPOCHS_PER_WEEK                  = 1575
-    EPOCHS_PER_MONTH                 = 6848
-    SECONDS_PER_SLOT                 = 12
-    MIN_ATTESTATION_INCLUSION_DELAY  = 1
+    // Time parameters (see Networks for other slot times)
+    SLOTS_PER_EPOCH                  uint64 = 32
+    EPOCHS_PER_YEAR                  uint64 = 82180 // 365.25 * 225
+    EPOCHS_PER_DAY                   uint64 = 225
+    EPOCHS_PER_WEEK                  uint64 = 1575
+    EPOCHS_PER_MONTH                 uint64 = 6848
+    SECONDS_PER_SLOT                 uint64 = 12
+    MIN_ATTESTATION_INCLUSION_DELAY  uint64 = 1
+
+    // Attestation committees and aggregates
+    MAX_COMMITTEES_PER_SLOT  uint64 = 64
+    TARGET_COMMITTEE_SIZE    uint64 = 128
+    MAX_ATTESTATIONS         uint64 = 128 // aggregates per block, one committee each
+    MAX_ATTESTATIONS_ELECTRA uint64 = 8   // from Electra each may span every committee of a slot (EIP-7549)
 
-	// SLOTS_PER_EPOCH                  = 32
-    // EPOCHS_PER_YEAR                  = 98618 // 365.25 * 270
-    // EPOCHS_PER_DAY                   = 270
-    // EPOCHS_PER_WEEK                  = 1890
-    // EPOCHS_PER_MONTH                 = 8219
-    // SECONDS_PER_SLOT                 = 10
-    // MIN_ATTESTATION_INCLUSION_DELAY  = 1
-    
     // Fork versions (for reference)
-    PHASE0_FORK_VERSION    = "0x00000000"
-    ALTAIR_FORK_VERSION    = "0x01000000"
-    BELLATRIX_FORK_VERSION = "0x02000000"
-    CAPELLA_FORK_VERSION   = "0x03000000"
-    DENEB_FORK_VERSION     = "0x04000000"
-    ELECTRA_FORK_VERSION   = "0x05000000"
+    PHASE0_FORK_VERSION    string = "0x00000000"
+    ALTAIR_FORK_VERSION    string = "0x01000000"
+    BELLATRIX_FORK_VERSION string = "0x02000000"
+    CAPELLA_FORK_VERSION   string = "0x03000000"
+    DENEB_FORK_VERSION     string = "0x04000000"
+    ELECTRA_FORK_VERSION   string = "0x05000000"
     
     // Validator set limits
-    MIN_GENESIS_ACTIVE_VALIDATOR_COUNT = 16384
-    CHURN_LIMIT_QUOTIENT              = 65536
-    MIN_PER_EPOCH_CHURN_LIMIT         = 4
-    MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT = 8
+    MIN_GENESIS_ACTIVE_VALIDATOR_COUNT uint64 = 16384
+    CHURN_LIMIT_QUOTIENT              uint64 = 65536
+    MIN_PER_EPOCH_CHURN_LIMIT         uint64 = 4
+    MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT uint64 = 8
+    
+    // Electra balance churn (Gwei per epoch) and consolidation requests (EIP-7251)
+    MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA        uint64 = 128000000000 // 128 ETH
+    MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT uint64 = 256000000000 // 256 ETH
+    MAX_CONSOLIDATION_REQUESTS_PER_PAYLOAD   uint64 = 2
     
     // Slashing
-    EPOCHS_PER_SLASHINGS_VECTOR = 8192
-    WHISTLEBLOWER_REWARD_PROPORTION = 8 // 1/8 of validator effective balance
+    EPOCHS_PER_SLASHINGS_VECTOR uint64 = 8192
+    WHISTLEBLOWER_REWARD_PROPORTION uint64 = 8 // 1/8 of validator effective balance
+    
+    // Beacon state vector and list sizes that differ between presets
+    SLOTS_PER_HISTORICAL_ROOT         uint64 = 8192
+    EPOCHS_PER_HISTORICAL_VECTOR      uint64 = 65536
+    EPOCHS_PER_ETH1_VOTING_PERIOD     uint64 = 64
+    MIN_SEED_LOOKAHEAD                uint64 = 1
+    PENDING_PARTIAL_WITHDRAWALS_LIMIT uint64 = 134217728 // 2**27
+    PENDING_CONSOLIDATIONS_LIMIT      uint64 = 262144    // 2**18
     
     // Withdrawals
-    MAX_VALIDATORS_PER_WITHDRAWALS_SWEEP = 16384
-    MAX_WITHDRAWALS_PER_PAYLOAD = 16
+    MIN_VALIDATOR_WITHDRAWABILITY_DELAY uint64 = 256
+    MAX_VALIDATORS_PER_WITHDRAWALS_SWEEP uint64 = 16384
+    MAX_WITHDRAWALS_PER_PAYLOAD uint64 = 16
+    MAX_SEED_LOOKAHEAD uint64 = 4
+    
+    // Execution layer triggered withdrawal requests (EIP-7002, fee in wei)
+    MIN_WITHDRAWAL_REQUEST_FEE             uint64 = 1
+    WITHDRAWAL_REQUEST_FEE_UPDATE_FRACTION uint64 = 17
+    TARGET_WITHDRAWAL_REQUESTS_PER_BLOCK   uint64 = 2
+    MAX_WITHDRAWAL_REQUESTS_PER_BLOCK      uint64 = 16
+)
+
+// Participation flag indices: bit positions in a validator's participation flags
+const (
+    TIMELY_SOURCE_FLAG_INDEX = 0
+    TIMELY_TARGET_FLAG_INDEX = 1
+    TIMELY_HEAD_FLAG_INDEX   = 2
+)
+
+// Economic assumptions
+const (
+    TOTAL_ETH_SUPPLY = 120000000 // Approximate ETH supply
 )
 
 // Fork configuration
@@ -98,6 +132,51 @@ type ForkConfig struct {
     ProportionalSlashingMultiplier uint64
 }
 
+// KnownForks lists the fork names accepted for NetworkState.CurrentFork
+var KnownForks = []string{"phase0", "altair", "bellatrix", "merge", "capella", "deneb", "electra"}
+
+// IsKnownFork reports whether fork is one of KnownForks
+func IsKnownFork(fork string) bool {
+    for _, known := range KnownForks {
+        if fork == known {
+            return true
+        }
+    }
+    return false
+}
+
+// forkVersions maps each fork from Phase 0 to Electra to its version
+func forkVersions() map[string]string {
+    return map[string]string{
+        "phase0":    PHASE0_FORK_VERSION,
+        "altair":    ALTAIR_FORK_VERSION,
+        "bellatrix": BELLATRIX_FORK_VERSION,
+        "capella":   CAPELLA_FORK_VERSION,
+        "deneb":     DENEB_FORK_VERSION,
+        "electra":   ELECTRA_FORK_VERSION,
+    }
+}
+
+// ForkForVersion names the fork with the given version, or returns "" for a
+// version the chain parameters do not list
+func ForkForVersion(version string) string {
+    for fork, v := range forkVersions() {
+        if strings.EqualFold(v, version) {
+            return fork
+        }
+    }
+    return ""
+}
+
+// ForkVersion returns the version of the named fork, or "" for a fork the
+// chain parameters do not list
+func ForkVersion(fork string) string {
+    if fork == "merge" {
+        fork = "bellatrix"
+    }
+    return forkVersions()[fork]
+}
+
 // GetForkConfig returns configuration for a specific fork
 func GetForkConfig(fork string) ForkConfig {
     switch fork {
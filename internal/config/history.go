@@ -0,0 +1,49 @@
+package config
+
+// SlashingYear records how many validators were slashed on mainnet in a calendar year
+type SlashingYear struct {
+    Year                    int
+    SlashedValidators       int
+    AverageActiveValidators int
+}
+
+// HistoricalSlashings contains approximate mainnet slashing incidence per year
+// (rounded figures compiled from public beacon chain explorers)
+var HistoricalSlashings = []SlashingYear{
+    {Year: 2021, SlashedValidators: 180, AverageActiveValidators: 200000},
+    {Year: 2022, SlashedValidators: 30, AverageActiveValidators: 400000},
+    {Year: 2023, SlashedValidators: 60, AverageActiveValidators: 700000},
+    {Year: 2024, SlashedValidators: 40, AverageActiveValidators: 1000000},
+}
+
+// NetworkSample records the mainnet validator set on a date
+type NetworkSample struct {
+    Date             string // YYYY-MM-DD
+    ActiveValidators int
+    Participation    float64
+}
+
+// HistoricalNetwork contains approximate mainnet active validator counts and
+// attestation participation, oldest first (rounded figures compiled from
+// public beacon chain explorers)
+var HistoricalNetwork = []NetworkSample{
+    {Date: "2020-12-01", ActiveValidators: 21000, Participation: 0.97},
+    {Date: "2021-03-01", ActiveValidators: 100000, Participation: 0.98},
+    {Date: "2021-06-01", ActiveValidators: 170000, Participation: 0.99},
+    {Date: "2021-09-01", ActiveValidators: 240000, Participation: 0.99},
+    {Date: "2021-12-01", ActiveValidators: 280000, Participation: 0.99},
+    {Date: "2022-03-01", ActiveValidators: 325000, Participation: 0.99},
+    {Date: "2022-06-01", ActiveValidators: 385000, Participation: 0.99},
+    {Date: "2022-09-15", ActiveValidators: 425000, Participation: 0.98},
+    {Date: "2022-12-01", ActiveValidators: 480000, Participation: 0.99},
+    {Date: "2023-03-01", ActiveValidators: 520000, Participation: 0.99},
+    {Date: "2023-06-01", ActiveValidators: 600000, Participation: 0.98},
+    {Date: "2023-09-01", ActiveValidators: 780000, Participation: 0.99},
+    {Date: "2023-12-01", ActiveValidators: 880000, Participation: 0.99},
+    {Date: "2024-03-01", ActiveValidators: 960000, Participation: 0.99},
+    {Date: "2024-06-01", ActiveValidators: 1020000, Participation: 0.99},
+    {Date: "2024-09-01", ActiveValidators: 1060000, Participation: 0.99},
+    {Date: "2024-12-01", ActiveValidators: 1070000, Participation: 0.99},
+    {Date: "2025-03-01", ActiveValidators: 1040000, Participation: 0.99},
+    {Date: "2025-06-01", ActiveValidators: 1030000, Participation: 0.99},
+}
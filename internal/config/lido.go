@@ -0,0 +1,16 @@
+package config
+
+// Lido Community Staking Module (CSM) parameters from mainnet
+const (
+    // Bond curve: the first validator needs a larger bond than subsequent ones
+    CSM_FIRST_BOND      = 2400000000 // 2.4 ETH in Gwei
+    CSM_SUBSEQUENT_BOND = 1300000000 // 1.3 ETH in Gwei
+    
+    // Reward shares in basis points of validator rewards
+    CSM_OPERATOR_FEE_BPS  = 600  // Paid to the node operator
+    LIDO_PROTOCOL_FEE_BPS = 1000 // Total fee deducted before stETH holders are paid
+    
+    // Operators whose duty performance falls below this threshold in a
+    // distribution frame receive no fee rewards for that frame
+    CSM_PERFORMANCE_THRESHOLD = 0.9
+)
@@ -0,0 +1,73 @@
+package config
+
+import (
+    "fmt"
+    "sort"
+    "strconv"
+)
+
+// Network is a chain timing: its slot time, and the epochs-per-period values
+// projections scale by at its epoch length, given as published rather than
+// derived so rounding matches the chain's own figures
+type Network struct {
+    SecondsPerSlot uint64
+    SlotsPerEpoch  uint64
+    EpochsPerDay   uint64
+    EpochsPerWeek  uint64
+    EpochsPerMonth uint64
+    EpochsPerYear  uint64
+}
+
+// Networks holds the chain timings --network selects. Mainnet has 12-second
+// slots; slot-10s and slot-6s are the shorter slot times of faster chains and
+// slot-time proposals. All are published for 32-slot epochs. Rewards per
+// epoch are unchanged, so annual rewards grow with the epochs in a year.
+var Networks = map[string]Network{
+    "mainnet": {
+        SecondsPerSlot: 12,
+        SlotsPerEpoch:  32,
+        EpochsPerDay:   225,
+        EpochsPerWeek:  1575,
+        EpochsPerMonth: 6848,
+        EpochsPerYear:  82180, // 365.25 * 225
+    },
+    "slot-10s": {
+        SecondsPerSlot: 10,
+        SlotsPerEpoch:  32,
+        EpochsPerDay:   270,
+        EpochsPerWeek:  1890,
+        EpochsPerMonth: 8219,
+        EpochsPerYear:  98618, // 365.25 * 270
+    },
+    "slot-6s": {
+        SecondsPerSlot: 6,
+        SlotsPerEpoch:  32,
+        EpochsPerDay:   450,
+        EpochsPerWeek:  3150,
+        EpochsPerMonth: 13697,
+        EpochsPerYear:  164363, // 365.25 * 450
+    },
+}
+
+// ApplyNetwork replaces the slot time with that of a named network and derives
+// the epochs-per-period values again. The epoch length stays that of the
+// active preset or spec; at the network's own, the published values apply.
+func ApplyNetwork(name string) error {
+    network, ok := Networks[name]
+    if !ok {
+        names := make([]string, 0, len(Networks))
+        for known := range Networks {
+            names = append(names, known)
+        }
+        sort.Strings(names)
+        return fmt.Errorf("unknown network %q (known networks: %v)", name, names)
+    }
+
+    if _, err := ApplySpec(map[string]string{
+        "SECONDS_PER_SLOT": strconv.FormatUint(network.SecondsPerSlot, 10),
+    }); err != nil {
+        return err
+    }
+    deriveEpochsPerPeriod()
+    return nil
+}
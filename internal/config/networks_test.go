@@ -0,0 +1,41 @@
+package config
+
+import "testing"
+
+func TestApplyNetwork(t *testing.T) {
+    saved := Save()
+    defer saved.Restore()
+
+    tests := []struct {
+        preset, network       string
+        seconds, slots        uint64
+        epochsPerDay, perYear uint64
+    }{
+        {"mainnet", "mainnet", 12, 32, 225, 82180},
+        {"mainnet", "slot-10s", 10, 32, 270, 98618},
+        {"mainnet", "slot-6s", 6, 32, 450, 164363},
+        // The network changes the slot time only, so minimal keeps its
+        // 8-slot epochs and the periods are derived from them
+        {"minimal", "slot-6s", 6, 8, 1800, 657450},
+        {"minimal", "mainnet", 12, 8, 900, 328725},
+    }
+    for _, tt := range tests {
+        saved.Restore()
+        if err := ApplyPreset(tt.preset); err != nil {
+            t.Fatal(err)
+        }
+        if err := ApplyNetwork(tt.network); err != nil {
+            t.Fatal(err)
+        }
+        if SECONDS_PER_SLOT != tt.seconds || SLOTS_PER_EPOCH != tt.slots ||
+            EPOCHS_PER_DAY != tt.epochsPerDay || EPOCHS_PER_YEAR != tt.perYear {
+            t.Errorf("%s preset, %s network: %ds slots, %d per epoch, %d epochs a day, %d a year; want %d, %d, %d, %d",
+                tt.preset, tt.network, SECONDS_PER_SLOT, SLOTS_PER_EPOCH, EPOCHS_PER_DAY, EPOCHS_PER_YEAR,
+                tt.seconds, tt.slots, tt.epochsPerDay, tt.perYear)
+        }
+    }
+
+    if err := ApplyNetwork("slot-1s"); err == nil {
+        t.Error("unknown network accepted")
+    }
+}
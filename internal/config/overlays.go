@@ -0,0 +1,94 @@
+package config
+
+import (
+    "fmt"
+    "os"
+    "strings"
+)
+
+// Overlays holds named sets of chain parameters to apply over the current ones
+// when comparing constant choices. weights-64 is the Altair weight set mainnet
+// uses; weights-26 is the earlier set with a denominator of 26, which keeps
+// the same duties but gives the proposer and sync committee less.
+var Overlays = map[string]map[string]string{
+    "weights-64": {
+        "TIMELY_SOURCE_WEIGHT": "14",
+        "TIMELY_TARGET_WEIGHT": "26",
+        "TIMELY_HEAD_WEIGHT":   "14",
+        "SYNC_REWARD_WEIGHT":   "2",
+        "PROPOSER_WEIGHT":      "8",
+        "WEIGHT_DENOMINATOR":   "64",
+    },
+    "weights-26": {
+        "TIMELY_SOURCE_WEIGHT": "6",
+        "TIMELY_TARGET_WEIGHT": "10",
+        "TIMELY_HEAD_WEIGHT":   "6",
+        "SYNC_REWARD_WEIGHT":   "1",
+        "PROPOSER_WEIGHT":      "3",
+        "WEIGHT_DENOMINATOR":   "26",
+    },
+}
+
+// LoadOverlay returns the chain spec values of an overlay, given as the name
+// of an overlay or preset, KEY=value,... pairs, or a config.yaml file (with
+// the values of its PRESET_BASE preset under its own). Pairs must name chain
+// parameters the calculator uses; a file may hold others, which are ignored.
+func LoadOverlay(overlay string) (map[string]string, error) {
+    if values, ok := Overlays[overlay]; ok {
+        return copySpec(values), nil
+    }
+    if values, ok := Presets[overlay]; ok {
+        return copySpec(values), nil
+    }
+
+    if strings.Contains(overlay, "=") {
+        uints, strs := specUints(), specStrings()
+        spec := make(map[string]string)
+        for _, pair := range strings.Split(overlay, ",") {
+            key, value, _ := strings.Cut(pair, "=")
+            key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+            _, isUint := uints[key]
+            _, isString := strs[key]
+            if !isUint && !isString {
+                return nil, fmt.Errorf("unknown chain parameter %q", key)
+            }
+            spec[key] = value
+        }
+        return spec, nil
+    }
+
+    f, err := os.Open(overlay)
+    if err != nil {
+        if os.IsNotExist(err) {
+            return nil, fmt.Errorf("%q is not an overlay, preset, KEY=value list, or file", overlay)
+        }
+        return nil, err
+    }
+    defer f.Close()
+
+    values, err := ParseChainConfig(f)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", overlay, err)
+    }
+    spec := make(map[string]string)
+    if base, ok := values["PRESET_BASE"]; ok {
+        preset, ok := Presets[base]
+        if !ok {
+            return nil, fmt.Errorf("%s: unknown preset %q", overlay, base)
+        }
+        spec = copySpec(preset)
+    }
+    for key, value := range values {
+        spec[key] = value
+    }
+    return spec, nil
+}
+
+// copySpec returns a copy of spec that can be changed without touching it
+func copySpec(spec map[string]string) map[string]string {
+    copied := make(map[string]string, len(spec))
+    for key, value := range spec {
+        copied[key] = value
+    }
+    return copied
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+    "fmt"
+    "sort"
+)
+
+// Presets holds the consensus spec presets the calculator knows, as chain spec
+// values for ApplySpec. Mainnet is the built-in default; its preset sets
+// everything minimal does back to mainnet's values, so applying it after
+// another preset or spec restores them. Minimal matches the consensus-specs
+// minimal preset and config used by spec tests and local interop devnets.
+var Presets = map[string]map[string]string{
+    "mainnet": {
+        // Time parameters
+        "SLOTS_PER_EPOCH":  "32",
+        "SECONDS_PER_SLOT": "12",
+
+        // Committees and committee periods
+        "MAX_COMMITTEES_PER_SLOT":          "64",
+        "TARGET_COMMITTEE_SIZE":            "128",
+        "SYNC_COMMITTEE_SIZE":              "512",
+        "EPOCHS_PER_SYNC_COMMITTEE_PERIOD": "256",
+
+        // Phase 0 penalties
+        "INACTIVITY_PENALTY_QUOTIENT":      "67108864", // 2**26
+        "MIN_SLASHING_PENALTY_QUOTIENT":    "128",
+        "PROPORTIONAL_SLASHING_MULTIPLIER": "1",
+
+        // Validator set limits
+        "MIN_GENESIS_ACTIVE_VALIDATOR_COUNT":   "16384",
+        "CHURN_LIMIT_QUOTIENT":                 "65536",
+        "MIN_PER_EPOCH_CHURN_LIMIT":            "4",
+        "MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT": "8",
+
+        // Slashings and withdrawals
+        "EPOCHS_PER_SLASHINGS_VECTOR":          "8192",
+        "MAX_WITHDRAWALS_PER_PAYLOAD":          "16",
+        "MAX_VALIDATORS_PER_WITHDRAWALS_SWEEP": "16384",
+
+        // Beacon state vector and list sizes
+        "SLOTS_PER_HISTORICAL_ROOT":         "8192",
+        "EPOCHS_PER_HISTORICAL_VECTOR":      "65536",
+        "EPOCHS_PER_ETH1_VOTING_PERIOD":     "64",
+        "PENDING_PARTIAL_WITHDRAWALS_LIMIT": "134217728", // 2**27
+        "PENDING_CONSOLIDATIONS_LIMIT":      "262144",    // 2**18
+
+        // Fork versions
+        "GENESIS_FORK_VERSION":   "0x00000000",
+        "ALTAIR_FORK_VERSION":    "0x01000000",
+        "BELLATRIX_FORK_VERSION": "0x02000000",
+        "CAPELLA_FORK_VERSION":   "0x03000000",
+        "DENEB_FORK_VERSION":     "0x04000000",
+        "ELECTRA_FORK_VERSION":   "0x05000000",
+    },
+    "minimal": {
+        // Time parameters
+        "SLOTS_PER_EPOCH":  "8",
+        "SECONDS_PER_SLOT": "6",
+
+        // Smaller committees and shorter committee periods
+        "MAX_COMMITTEES_PER_SLOT":          "4",
+        "TARGET_COMMITTEE_SIZE":            "4",
+        "SYNC_COMMITTEE_SIZE":              "32",
+        "EPOCHS_PER_SYNC_COMMITTEE_PERIOD": "8",
+
+        // Phase 0 penalties
+        "INACTIVITY_PENALTY_QUOTIENT":      "33554432", // 2**25
+        "MIN_SLASHING_PENALTY_QUOTIENT":    "64",
+        "PROPORTIONAL_SLASHING_MULTIPLIER": "2",
+
+        // Validator set limits
+        "MIN_GENESIS_ACTIVE_VALIDATOR_COUNT":   "64",
+        "CHURN_LIMIT_QUOTIENT":                 "32",
+        "MIN_PER_EPOCH_CHURN_LIMIT":            "2",
+        "MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT": "4",
+
+        // Slashings and withdrawals
+        "EPOCHS_PER_SLASHINGS_VECTOR":          "64",
+        "MAX_WITHDRAWALS_PER_PAYLOAD":          "4",
+        "MAX_VALIDATORS_PER_WITHDRAWALS_SWEEP": "16",
+
+        // Beacon state vector and list sizes
+        "SLOTS_PER_HISTORICAL_ROOT":         "64",
+        "EPOCHS_PER_HISTORICAL_VECTOR":      "64",
+        "EPOCHS_PER_ETH1_VOTING_PERIOD":     "4",
+        "PENDING_PARTIAL_WITHDRAWALS_LIMIT": "64",
+        "PENDING_CONSOLIDATIONS_LIMIT":      "64",
+
+        // Fork versions
+        "GENESIS_FORK_VERSION":   "0x00000001",
+        "ALTAIR_FORK_VERSION":    "0x01000001",
+        "BELLATRIX_FORK_VERSION": "0x02000001",
+        "CAPELLA_FORK_VERSION":   "0x03000001",
+        "DENEB_FORK_VERSION":     "0x04000001",
+        "ELECTRA_FORK_VERSION":   "0x05000001",
+    },
+}
+
+// ApplyPreset replaces the chain parameters with those of a named preset
+func ApplyPreset(name string) error {
+    preset, ok := Presets[name]
+    if !ok {
+        names := make([]string, 0, len(Presets))
+        for known := range Presets {
+            names = append(names, known)
+        }
+        sort.Strings(names)
+        return fmt.Errorf("unknown preset %q (known presets: %v)", name, names)
+    }
+
+    _, err := ApplySpec(preset)
+    return err
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+    "reflect"
+    "sort"
+    "testing"
+)
+
+func TestPresetsSetTheSameKeys(t *testing.T) {
+    keys := func(preset map[string]string) []string {
+        var names []string
+        for key := range preset {
+            names = append(names, key)
+        }
+        sort.Strings(names)
+        return names
+    }
+    if mainnet, minimal := keys(Presets["mainnet"]), keys(Presets["minimal"]); !reflect.DeepEqual(mainnet, minimal) {
+        t.Errorf("mainnet sets %v, minimal %v", mainnet, minimal)
+    }
+}
+
+func TestApplyPreset(t *testing.T) {
+    saved := Save()
+    defer saved.Restore()
+
+    // The mainnet preset is the built-in default
+    if err := ApplyPreset("mainnet"); err != nil {
+        t.Fatal(err)
+    }
+    if !reflect.DeepEqual(Save(), saved) {
+        t.Error("the mainnet preset changes the built-in parameters")
+    }
+
+    if err := ApplyPreset("minimal"); err != nil {
+        t.Fatal(err)
+    }
+    if SLOTS_PER_EPOCH != 8 || SLOTS_PER_HISTORICAL_ROOT != 64 || ALTAIR_FORK_VERSION != "0x01000001" {
+        t.Errorf("minimal preset: %d slots per epoch, %d per historical root, Altair version %s",
+            SLOTS_PER_EPOCH, SLOTS_PER_HISTORICAL_ROOT, ALTAIR_FORK_VERSION)
+    }
+    if EPOCHS_PER_DAY != 1800 {
+        t.Errorf("minimal preset: %d epochs per day, want 1800", EPOCHS_PER_DAY)
+    }
+
+    // and applying mainnet over it restores every value, the epochs per
+    // period included
+    if err := ApplyPreset("mainnet"); err != nil {
+        t.Fatal(err)
+    }
+    if !reflect.DeepEqual(Save(), saved) {
+        t.Error("the mainnet preset does not undo the minimal preset")
+    }
+
+    if err := ApplyPreset("goerli"); err == nil {
+        t.Error("unknown preset accepted")
+    }
+}
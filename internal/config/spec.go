@@ -0,0 +1,203 @@
+package config
+
+import (
+    "fmt"
+    "strconv"
+)
+
+// specUints maps chain spec keys to the parameters they set
+func specUints() map[string]*uint64 {
+    return map[string]*uint64{
+        "BASE_REWARD_FACTOR":                         &BASE_REWARD_FACTOR,
+        "PROPOSER_REWARD_QUOTIENT":                   &PROPOSER_REWARD_QUOTIENT,
+        "WHISTLEBLOWER_REWARD_QUOTIENT":              &WHISTLEBLOWER_REWARD_QUOTIENT,
+        "MIN_SLASHING_PENALTY_QUOTIENT":              &MIN_SLASHING_PENALTY_QUOTIENT,
+        "PROPORTIONAL_SLASHING_MULTIPLIER":           &PROPORTIONAL_SLASHING_MULTIPLIER,
+        "INACTIVITY_PENALTY_QUOTIENT_ALTAIR":         &INACTIVITY_PENALTY_QUOTIENT_ALTAIR,
+        "MIN_SLASHING_PENALTY_QUOTIENT_ALTAIR":       &MIN_SLASHING_PENALTY_QUOTIENT_ALTAIR,
+        "PROPORTIONAL_SLASHING_MULTIPLIER_ALTAIR":    &PROPORTIONAL_SLASHING_MULTIPLIER_ALTAIR,
+        "INACTIVITY_PENALTY_QUOTIENT_BELLATRIX":      &INACTIVITY_PENALTY_QUOTIENT_BELLATRIX,
+        "MIN_SLASHING_PENALTY_QUOTIENT_BELLATRIX":    &MIN_SLASHING_PENALTY_QUOTIENT_BELLATRIX,
+        "PROPORTIONAL_SLASHING_MULTIPLIER_BELLATRIX": &PROPORTIONAL_SLASHING_MULTIPLIER_BELLATRIX,
+        "INACTIVITY_PENALTY_QUOTIENT":                &INACTIVITY_PENALTY_QUOTIENT,
+        "INACTIVITY_SCORE_BIAS":                      &INACTIVITY_SCORE_BIAS,
+        "INACTIVITY_SCORE_RECOVERY_RATE":             &INACTIVITY_SCORE_RECOVERY_RATE,
+        "MIN_EPOCHS_TO_INACTIVITY_PENALTY":           &MIN_EPOCHS_TO_INACTIVITY_PENALTY,
+        "MAX_SEED_LOOKAHEAD":                         &MAX_SEED_LOOKAHEAD,
+        "MAX_WITHDRAWAL_REQUESTS_PER_PAYLOAD":        &MAX_WITHDRAWAL_REQUESTS_PER_BLOCK,
+        "TIMELY_SOURCE_WEIGHT":                       &TIMELY_SOURCE_WEIGHT,
+        "TIMELY_TARGET_WEIGHT":                       &TIMELY_TARGET_WEIGHT,
+        "TIMELY_HEAD_WEIGHT":                         &TIMELY_HEAD_WEIGHT,
+        "SYNC_REWARD_WEIGHT":                         &SYNC_REWARD_WEIGHT,
+        "PROPOSER_WEIGHT":                            &PROPOSER_WEIGHT,
+        "WEIGHT_DENOMINATOR":                         &WEIGHT_DENOMINATOR,
+        "SYNC_COMMITTEE_SIZE":                        &SYNC_COMMITTEE_SIZE,
+        "EPOCHS_PER_SYNC_COMMITTEE_PERIOD":           &EPOCHS_PER_SYNC_COMMITTEE_PERIOD,
+        "SYNC_COMMITTEE_SUBNET_COUNT":                &SYNC_COMMITTEE_SUBNET_COUNT,
+        "EFFECTIVE_BALANCE_INCREMENT":                &EFFECTIVE_BALANCE_INCREMENT,
+        "MAX_EFFECTIVE_BALANCE":                      &MAX_EFFECTIVE_BALANCE,
+        "EJECTION_BALANCE":                           &EJECTION_BALANCE,
+        "MAX_EFFECTIVE_BALANCE_ELECTRA":              &MAX_EFFECTIVE_BALANCE_ELECTRA,
+        "HYSTERESIS_QUOTIENT":                        &HYSTERESIS_QUOTIENT,
+        "HYSTERESIS_DOWNWARD_MULTIPLIER":             &HYSTERESIS_DOWNWARD_MULTIPLIER,
+        "HYSTERESIS_UPWARD_MULTIPLIER":               &HYSTERESIS_UPWARD_MULTIPLIER,
+        "SLOTS_PER_EPOCH":                            &SLOTS_PER_EPOCH,
+        "SECONDS_PER_SLOT":                           &SECONDS_PER_SLOT,
+        "MIN_ATTESTATION_INCLUSION_DELAY":            &MIN_ATTESTATION_INCLUSION_DELAY,
+        "MAX_COMMITTEES_PER_SLOT":                    &MAX_COMMITTEES_PER_SLOT,
+        "TARGET_COMMITTEE_SIZE":                      &TARGET_COMMITTEE_SIZE,
+        "MAX_ATTESTATIONS":                           &MAX_ATTESTATIONS,
+        "MAX_ATTESTATIONS_ELECTRA":                   &MAX_ATTESTATIONS_ELECTRA,
+        "MIN_GENESIS_ACTIVE_VALIDATOR_COUNT":         &MIN_GENESIS_ACTIVE_VALIDATOR_COUNT,
+        "CHURN_LIMIT_QUOTIENT":                       &CHURN_LIMIT_QUOTIENT,
+        "MIN_PER_EPOCH_CHURN_LIMIT":                  &MIN_PER_EPOCH_CHURN_LIMIT,
+        "MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT":       &MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT,
+        "MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA":          &MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA,
+        "MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT":  &MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT,
+        "MAX_CONSOLIDATION_REQUESTS_PER_PAYLOAD":     &MAX_CONSOLIDATION_REQUESTS_PER_PAYLOAD,
+        "EPOCHS_PER_SLASHINGS_VECTOR":                &EPOCHS_PER_SLASHINGS_VECTOR,
+        "SLOTS_PER_HISTORICAL_ROOT":                  &SLOTS_PER_HISTORICAL_ROOT,
+        "EPOCHS_PER_HISTORICAL_VECTOR":               &EPOCHS_PER_HISTORICAL_VECTOR,
+        "EPOCHS_PER_ETH1_VOTING_PERIOD":              &EPOCHS_PER_ETH1_VOTING_PERIOD,
+        "MIN_SEED_LOOKAHEAD":                         &MIN_SEED_LOOKAHEAD,
+        "PENDING_PARTIAL_WITHDRAWALS_LIMIT":          &PENDING_PARTIAL_WITHDRAWALS_LIMIT,
+        "PENDING_CONSOLIDATIONS_LIMIT":               &PENDING_CONSOLIDATIONS_LIMIT,
+        "MIN_VALIDATOR_WITHDRAWABILITY_DELAY":        &MIN_VALIDATOR_WITHDRAWABILITY_DELAY,
+        "MAX_VALIDATORS_PER_WITHDRAWALS_SWEEP":       &MAX_VALIDATORS_PER_WITHDRAWALS_SWEEP,
+        "MAX_WITHDRAWALS_PER_PAYLOAD":                &MAX_WITHDRAWALS_PER_PAYLOAD,
+    }
+}
+
+// specStrings maps chain spec keys to the fork versions they set
+func specStrings() map[string]*string {
+    return map[string]*string{
+        "GENESIS_FORK_VERSION":   &PHASE0_FORK_VERSION,
+        "ALTAIR_FORK_VERSION":    &ALTAIR_FORK_VERSION,
+        "BELLATRIX_FORK_VERSION": &BELLATRIX_FORK_VERSION,
+        "CAPELLA_FORK_VERSION":   &CAPELLA_FORK_VERSION,
+        "DENEB_FORK_VERSION":     &DENEB_FORK_VERSION,
+        "ELECTRA_FORK_VERSION":   &ELECTRA_FORK_VERSION,
+    }
+}
+
+// zeroSpecKeys are the chain spec keys that may be zero: a reward weight of
+// zero turns off that reward. Every other value divides or bounds something.
+var zeroSpecKeys = map[string]bool{
+    "TIMELY_SOURCE_WEIGHT": true,
+    "TIMELY_TARGET_WEIGHT": true,
+    "TIMELY_HEAD_WEIGHT":   true,
+    "SYNC_REWARD_WEIGHT":   true,
+    "PROPOSER_WEIGHT":      true,
+}
+
+// ApplySpec replaces the chain parameters with values from a chain spec, such
+// as the response of a beacon node's /eth/v1/config/spec. Keys the calculator
+// does not use are ignored and missing keys keep their current value. When the
+// slot time or epoch length changes, the epochs-per-period values are derived
+// again. It returns the keys that were applied.
+func ApplySpec(spec map[string]string) ([]string, error) {
+    uints := specUints()
+    strs := specStrings()
+
+    // Parse everything first so a bad value leaves the parameters untouched
+    parsed := make(map[string]uint64)
+    for key, value := range spec {
+        if _, ok := uints[key]; !ok {
+            continue
+        }
+        n, err := strconv.ParseUint(value, 10, 64)
+        if err != nil {
+            return nil, fmt.Errorf("chain spec %s: invalid value %q", key, value)
+        }
+        if n == 0 && !zeroSpecKeys[key] {
+            return nil, fmt.Errorf("chain spec %s: must be greater than zero", key)
+        }
+        parsed[key] = n
+    }
+
+    slotsPerEpoch, secondsPerSlot := SLOTS_PER_EPOCH, SECONDS_PER_SLOT
+
+    var applied []string
+    for key, n := range parsed {
+        *uints[key] = n
+        applied = append(applied, key)
+    }
+    for key, value := range spec {
+        if target, ok := strs[key]; ok {
+            *target = value
+            applied = append(applied, key)
+        }
+    }
+
+    if SLOTS_PER_EPOCH != slotsPerEpoch || SECONDS_PER_SLOT != secondsPerSlot {
+        deriveEpochsPerPeriod()
+    }
+
+    return applied, nil
+}
+
+// deriveEpochsPerPeriod recomputes the epochs-per-period values from the slot
+// time and epoch length. A timing in Networks takes its published values, so
+// returning to mainnet's timing restores mainnet's.
+func deriveEpochsPerPeriod() {
+    for _, network := range Networks {
+        if network.SecondsPerSlot == SECONDS_PER_SLOT && network.SlotsPerEpoch == SLOTS_PER_EPOCH {
+            EPOCHS_PER_DAY = network.EpochsPerDay
+            EPOCHS_PER_WEEK = network.EpochsPerWeek
+            EPOCHS_PER_MONTH = network.EpochsPerMonth
+            EPOCHS_PER_YEAR = network.EpochsPerYear
+            return
+        }
+    }
+
+    secondsPerEpoch := float64(SECONDS_PER_SLOT * SLOTS_PER_EPOCH)
+
+    EPOCHS_PER_DAY = uint64(86400 / secondsPerEpoch)
+    EPOCHS_PER_WEEK = uint64(7 * 86400 / secondsPerEpoch)
+    EPOCHS_PER_YEAR = uint64(365.25 * 86400 / secondsPerEpoch)
+    EPOCHS_PER_MONTH = EPOCHS_PER_YEAR / 12
+}
+
+// Snapshot holds the values of every chain parameter, including the derived
+// epoch periods, so they can be restored after applying another chain's spec
+type Snapshot struct {
+    uints  map[string]uint64
+    strs   map[string]string
+    epochs [4]uint64
+}
+
+// Save records the current chain parameters
+func Save() Snapshot {
+    snapshot := Snapshot{uints: make(map[string]uint64), strs: make(map[string]string)}
+    for key, target := range specUints() {
+        snapshot.uints[key] = *target
+    }
+    for key, target := range specStrings() {
+        snapshot.strs[key] = *target
+    }
+    snapshot.epochs = [4]uint64{EPOCHS_PER_DAY, EPOCHS_PER_WEEK, EPOCHS_PER_MONTH, EPOCHS_PER_YEAR}
+    return snapshot
+}
+
+// Restore puts back the chain parameters recorded by Save
+func (s Snapshot) Restore() {
+    for key, target := range specUints() {
+        *target = s.uints[key]
+    }
+    for key, target := range specStrings() {
+        *target = s.strs[key]
+    }
+    EPOCHS_PER_DAY, EPOCHS_PER_WEEK, EPOCHS_PER_MONTH, EPOCHS_PER_YEAR = s.epochs[0], s.epochs[1], s.epochs[2], s.epochs[3]
+}
+
+// Values returns the recorded chain parameters as chain spec values
+func (s Snapshot) Values() map[string]string {
+    values := make(map[string]string, len(s.uints)+len(s.strs))
+    for key, n := range s.uints {
+        values[key] = strconv.FormatUint(n, 10)
+    }
+    for key, value := range s.strs {
+        values[key] = value
+    }
+    return values
+}
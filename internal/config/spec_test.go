@@ -0,0 +1,80 @@
+package config
+
+import (
+    "reflect"
+    "sort"
+    "strings"
+    "testing"
+)
+
+func TestApplySpec(t *testing.T) {
+    saved := Save()
+    defer saved.Restore()
+
+    // A beacon node's spec, with keys the calculator does not use
+    applied, err := ApplySpec(map[string]string{
+        "CONFIG_NAME":              "devnet",
+        "DEPOSIT_CONTRACT_ADDRESS": "0x4242424242424242424242424242424242424242",
+        "BASE_REWARD_FACTOR":       "32",
+        "SECONDS_PER_SLOT":         "6",
+        "PROPOSER_WEIGHT":          "0",
+        "ELECTRA_FORK_VERSION":     "0x05000038",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    sort.Strings(applied)
+    if want := []string{"BASE_REWARD_FACTOR", "ELECTRA_FORK_VERSION", "PROPOSER_WEIGHT", "SECONDS_PER_SLOT"}; !reflect.DeepEqual(applied, want) {
+        t.Errorf("applied %v, want %v", applied, want)
+    }
+    if BASE_REWARD_FACTOR != 32 || SECONDS_PER_SLOT != 6 || PROPOSER_WEIGHT != 0 || ELECTRA_FORK_VERSION != "0x05000038" {
+        t.Errorf("applied values %d, %d, %d, %s", BASE_REWARD_FACTOR, SECONDS_PER_SLOT, PROPOSER_WEIGHT, ELECTRA_FORK_VERSION)
+    }
+    // The new slot time takes its published epochs per period
+    if EPOCHS_PER_DAY != 450 || EPOCHS_PER_YEAR != 164363 {
+        t.Errorf("%d epochs a day, %d a year at 6s slots; want 450, 164363", EPOCHS_PER_DAY, EPOCHS_PER_YEAR)
+    }
+    // and a timing that is not published derives them
+    if _, err := ApplySpec(map[string]string{"SLOTS_PER_EPOCH": "16"}); err != nil {
+        t.Fatal(err)
+    }
+    if EPOCHS_PER_DAY != 900 || EPOCHS_PER_WEEK != 6300 || EPOCHS_PER_YEAR != 328725 || EPOCHS_PER_MONTH != 328725/12 {
+        t.Errorf("6s slots, 16 per epoch: %d, %d, %d, %d epochs per period",
+            EPOCHS_PER_DAY, EPOCHS_PER_WEEK, EPOCHS_PER_MONTH, EPOCHS_PER_YEAR)
+    }
+
+    // A snapshot's values put everything back
+    if _, err := ApplySpec(saved.Values()); err != nil {
+        t.Fatal(err)
+    }
+    if !reflect.DeepEqual(Save(), saved) {
+        t.Error("applying the saved values does not restore the parameters")
+    }
+}
+
+func TestApplySpecErrors(t *testing.T) {
+    saved := Save()
+    defer saved.Restore()
+
+    // Each bad value sits beside a valid one, which is not applied either
+    tests := []struct {
+        name    string
+        spec    map[string]string
+        wantErr string
+    }{
+        {"not a number", map[string]string{"BASE_REWARD_FACTOR": "sixty-four", "SECONDS_PER_SLOT": "6"}, "invalid value"},
+        {"negative", map[string]string{"SLOTS_PER_EPOCH": "-32", "BASE_REWARD_FACTOR": "32"}, "invalid value"},
+        {"overflow", map[string]string{"MAX_EFFECTIVE_BALANCE": "18446744073709551616", "BASE_REWARD_FACTOR": "32"}, "invalid value"},
+        {"zero divisor", map[string]string{"WEIGHT_DENOMINATOR": "0", "BASE_REWARD_FACTOR": "32"}, "must be greater than zero"},
+        {"zero slot time", map[string]string{"SECONDS_PER_SLOT": "0", "BASE_REWARD_FACTOR": "32"}, "must be greater than zero"},
+    }
+    for _, tt := range tests {
+        if _, err := ApplySpec(tt.spec); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+            t.Errorf("%s: error %v, want one mentioning %q", tt.name, err, tt.wantErr)
+        }
+        if !reflect.DeepEqual(Save(), saved) {
+            t.Errorf("%s: a rejected spec changed the parameters", tt.name)
+            saved.Restore()
+        }
+    }
+}
@@ -0,0 +1,154 @@
+// Package crosscheck checks the calculator's reward math against a consensus
+// client. It rebuilds an epoch transition from the client's own beacon state,
+// computes every validator's attestation rewards with the calculator, and
+// compares them with what the client's rewards API reports.
+package crosscheck
+
+import (
+    "encoding/binary"
+    "fmt"
+    "strconv"
+
+    "github.com/eth-rewards-calculator/internal/beacon"
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/ssz"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// components names the compared reward components, in EpochDelta order
+var components = []string{"source", "target", "head", "inactivity"}
+
+// Run compares the attestation rewards for epoch of the given validators, or
+// of every validator if none are given. The rewards for an epoch are paid at
+// the end of the next one, so the node must serve the state at that epoch's
+// last slot from its debug API.
+func Run(client *beacon.Client, epoch uint64, indices ...uint64) (*types.CrossCheck, error) {
+    state, fork, err := transitionState(client, epoch)
+    if err != nil {
+        return nil, err
+    }
+
+    // Scores are updated just before the rewards are paid
+    calculator.UpdateInactivityScores(state)
+    deltas, err := calculator.CalculateEpochDeltas(state)
+    if err != nil {
+        return nil, err
+    }
+
+    version, err := client.GetNodeVersion()
+    if err != nil {
+        return nil, fmt.Errorf("fetching node version: %w", err)
+    }
+    reference, err := client.GetAttestationRewards(epoch, indices...)
+    if err != nil {
+        return nil, fmt.Errorf("attestation rewards for epoch %d: %w", epoch, err)
+    }
+
+    check := &types.CrossCheck{
+        Epoch:      epoch,
+        Client:     version,
+        Fork:       fork,
+        Validators: len(reference.TotalRewards),
+        Components: make([]types.ComponentCheck, len(components)),
+    }
+    for i, name := range components {
+        check.Components[i].Component = name
+    }
+    for _, reward := range reference.TotalRewards {
+        if reward.ValidatorIndex >= uint64(len(deltas)) {
+            return nil, fmt.Errorf("the node reports rewards for validator %d, but the state has only %d validators",
+                reward.ValidatorIndex, len(deltas))
+        }
+        delta := deltas[reward.ValidatorIndex]
+        calculated := [4]int64{delta.Source, delta.Target, delta.Head, delta.Inactivity}
+        reported := [4]int64{reward.Source, reward.Target, reward.Head, reward.Inactivity}
+
+        for i := range components {
+            component := &check.Components[i]
+            component.Calculated += calculated[i]
+            component.Reference += reported[i]
+            if calculated[i] == reported[i] {
+                continue
+            }
+            component.Mismatches++
+            component.MaxAbsDelta = max(component.MaxAbsDelta, abs(calculated[i]-reported[i]))
+            check.Mismatches = append(check.Mismatches, types.RewardMismatch{
+                ValidatorIndex: reward.ValidatorIndex,
+                Component:      components[i],
+                Calculated:     calculated[i],
+                Reference:      reported[i],
+            })
+        }
+    }
+    return check, nil
+}
+
+// transitionState rebuilds the state the client paid epoch's attestation
+// rewards from: the whole validator registry at the last slot of the next
+// epoch, with the finality that epoch's justification and finalization left
+func transitionState(client *beacon.Client, epoch uint64) (*types.NetworkState, string, error) {
+    slot := (epoch+2)*config.SLOTS_PER_EPOCH - 1
+    fork, data, err := client.GetStateSSZ(strconv.FormatUint(slot, 10))
+    if err != nil {
+        return nil, "", err
+    }
+    layout, err := ssz.BeaconState(fork)
+    if err != nil {
+        return nil, "", err
+    }
+    fields, err := layout.Fields(data)
+    if err != nil {
+        return nil, "", fmt.Errorf("decoding beacon state: %w", err)
+    }
+    field := func(name string) []byte { return fields[layout.Index(name)] }
+    if got := binary.LittleEndian.Uint64(field("slot")); got != slot {
+        return nil, "", fmt.Errorf("the node sent the state at slot %d, not %d", got, slot)
+    }
+
+    // Finalization runs before the rewards, so the leak they see is the one
+    // the next epoch's first state records
+    checkpoints, err := client.GetFinalityCheckpoints(strconv.FormatUint(slot+1, 10))
+    if err != nil {
+        return nil, "", fmt.Errorf("finality checkpoints after epoch %d: %w", epoch+1, err)
+    }
+
+    state := &types.NetworkState{
+        CurrentEpoch:   epoch + 1,
+        FinalizedEpoch: checkpoints.Finalized.Epoch,
+        JustifiedEpoch: checkpoints.CurrentJustified.Epoch,
+        CurrentFork:    fork,
+    }
+    if !config.IsKnownFork(fork) {
+        state.CurrentFork = "electra" // later forks kept Electra's reward and penalty parameters
+    }
+
+    validators, err := ssz.Elements(ssz.Validator, field("validators"))
+    if err != nil {
+        return nil, "", fmt.Errorf("validators: %w", err)
+    }
+    flags := field("previous_epoch_participation")
+    scores, err := ssz.Elements(ssz.Uint64, field("inactivity_scores"))
+    if err != nil || len(flags) != len(validators) || len(scores) != len(validators) {
+        return nil, "", fmt.Errorf("participation flags and inactivity scores do not match the %d validators", len(validators))
+    }
+
+    // Keep the whole registry so positions stay validator indices
+    state.Validators = make([]types.Validator, len(validators))
+    for i, raw := range validators {
+        if err := state.Validators[i].UnmarshalSSZ(raw); err != nil {
+            return nil, "", fmt.Errorf("validator %d: %w", i, err)
+        }
+        state.Validators[i].InactivityScore = binary.LittleEndian.Uint64(scores[i])
+    }
+    state.PreviousEpochParticipation = append([]uint8(nil), flags...)
+    state.TotalActiveBalance = calculator.ComputeTotalActiveBalance(state, state.CurrentEpoch)
+    return state, fork, nil
+}
+
+func abs(x int64) int64 {
+    if x < 0 {
+        return -x
+    }
+    return x
+}
@@ -0,0 +1,82 @@
+// Package export writes reward events as CSV files that tax and accounting
+// software can import.
+package export
+
+import (
+    "encoding/csv"
+    "fmt"
+    "io"
+    "strconv"
+
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// Format describes the CSV layout expected by one tax tool
+type Format struct {
+    Header        []string
+    DefaultLabels map[string]string // income label or tag per event layer
+    row           func(event types.RewardEvent, label string) []string
+}
+
+// Formats are the supported export formats by name
+var Formats = map[string]Format{
+    // Koinly universal template
+    "koinly": {
+        Header: []string{"Date", "Sent Amount", "Sent Currency", "Received Amount", "Received Currency",
+            "Fee Amount", "Fee Currency", "Net Worth Amount", "Net Worth Currency", "Label", "Description", "TxHash"},
+        DefaultLabels: map[string]string{types.ConsensusLayer: "staking", types.ExecutionLayer: "income"},
+        row: func(event types.RewardEvent, label string) []string {
+            return []string{event.Time.UTC().Format("2006-01-02 15:04 UTC"), "", "", amount(event), "ETH",
+                "", "", "", "", label, event.Description, ""}
+        },
+    },
+    // CoinTracker CSV import
+    "cointracker": {
+        Header: []string{"Date", "Received Quantity", "Received Currency", "Sent Quantity", "Sent Currency",
+            "Fee Amount", "Fee Currency", "Tag"},
+        DefaultLabels: map[string]string{types.ConsensusLayer: "staked", types.ExecutionLayer: "income"},
+        row: func(event types.RewardEvent, label string) []string {
+            return []string{event.Time.UTC().Format("01/02/2006 15:04:05"), amount(event), "ETH", "", "",
+                "", "", label}
+        },
+    },
+    // Generic accounting ledger
+    "csv": {
+        Header: []string{"date", "layer", "label", "amount_eth", "currency", "description"},
+        DefaultLabels: map[string]string{types.ConsensusLayer: "consensus rewards", types.ExecutionLayer: "execution rewards"},
+        row: func(event types.RewardEvent, label string) []string {
+            return []string{event.Time.UTC().Format("2006-01-02T15:04:05Z"), event.Layer, label, amount(event),
+                "ETH", event.Description}
+        },
+    },
+}
+
+// Write writes events as CSV in the named format. labels overrides the
+// format's default label for a layer.
+func Write(w io.Writer, format string, events []types.RewardEvent, labels map[string]string) error {
+    f, ok := Formats[format]
+    if !ok {
+        return fmt.Errorf("unknown export format %q", format)
+    }
+
+    out := csv.NewWriter(w)
+    if err := out.Write(f.Header); err != nil {
+        return err
+    }
+    for _, event := range events {
+        label := f.DefaultLabels[event.Layer]
+        if custom := labels[event.Layer]; custom != "" {
+            label = custom
+        }
+        if err := out.Write(f.row(event, label)); err != nil {
+            return err
+        }
+    }
+
+    out.Flush()
+    return out.Error()
+}
+
+func amount(event types.RewardEvent) string {
+    return strconv.FormatFloat(event.Amount, 'f', 9, 64)
+}
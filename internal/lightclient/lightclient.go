@@ -0,0 +1,456 @@
+// Package lightclient follows a beacon node's finalized head with the Altair
+// light-client protocol. Starting from a block root the user trusts, each
+// sync committee signs the headers of its period and hands over to the next,
+// so a node that does not hold the committees' keys cannot forge a header.
+package lightclient
+
+import (
+    "encoding/binary"
+    "encoding/hex"
+    "fmt"
+    "strings"
+    "sync"
+
+    "github.com/eth-rewards-calculator/internal/beacon"
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/ssz"
+
+    blsu "github.com/protolambda/bls12-381-util"
+)
+
+// Positions of the proven fields among the beacon state's fields. From
+// Electra on, the state has more than 32 fields, which adds a level to every
+// proof.
+const (
+    finalizedRootIndex        = 41 // finalized_checkpoint.root
+    currentSyncCommitteeIndex = 22
+    nextSyncCommitteeIndex    = 23
+)
+
+// maxUpdates is how many periods' updates a node returns per request
+const maxUpdates = 128
+
+// domainSyncCommittee separates sync committee signatures from other duties'
+var domainSyncCommittee = [4]byte{0x07, 0x00, 0x00, 0x00}
+
+// forkNames names the entries of a fork schedule, which lists every fork
+// from genesis in order
+var forkNames = []string{"phase0", "altair", "bellatrix", "capella", "deneb", "electra", "fulu"}
+
+// Header is a verified beacon block header
+type Header struct {
+    Slot          uint64
+    ProposerIndex uint64
+    ParentRoot    [32]byte
+    StateRoot     [32]byte
+    BodyRoot      [32]byte
+}
+
+// Root is the header's hash tree root, which is also the block's root
+func (h Header) Root() [32]byte {
+    data := make([]byte, 0, 112)
+    data = binary.LittleEndian.AppendUint64(data, h.Slot)
+    data = binary.LittleEndian.AppendUint64(data, h.ProposerIndex)
+    data = append(data, h.ParentRoot[:]...)
+    data = append(data, h.StateRoot[:]...)
+    data = append(data, h.BodyRoot[:]...)
+    root, _ := ssz.BeaconBlockHeader.Root(data) // cannot fail at this size
+    return root
+}
+
+// Epoch is the epoch of the header's slot
+func (h Header) Epoch() uint64 {
+    return h.Slot / config.SLOTS_PER_EPOCH
+}
+
+// Client verifies a beacon node's finalized headers from a trusted
+// checkpoint. It is safe for concurrent use.
+type Client struct {
+    node       *beacon.Client
+    checkpoint [32]byte
+
+    mu    sync.Mutex
+    store *store // nil until bootstrapped
+}
+
+// New creates a client that trusts the block with root checkpoint, which
+// should be a recent finalized block: a node has to serve a bootstrap for it,
+// and sync committees far enough in the past can no longer be trusted.
+func New(node *beacon.Client, checkpoint [32]byte) *Client {
+    return &Client{node: node, checkpoint: checkpoint}
+}
+
+// store is what the client has verified so far
+type store struct {
+    genesisValidatorsRoot [32]byte
+    forks                 []fork
+    finalized             Header
+    period                uint64         // of the current committee
+    current               []*blsu.Pubkey // committee signing in period
+    next                  []*blsu.Pubkey // committee signing in period+1, once known
+    nextRoot              [32]byte
+}
+
+// fork is a fork of the schedule
+type fork struct {
+    name    string
+    epoch   uint64
+    version [4]byte
+}
+
+// Finalized brings the client up to the node's latest finalized header and
+// returns it
+func (c *Client) Finalized() (Header, error) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    if c.store == nil {
+        s, err := c.bootstrap()
+        if err != nil {
+            return Header{}, err
+        }
+        c.store = s
+    }
+    s := c.store
+
+    // Walk the committee hand-overs to the current period
+    for {
+        period := s.period
+        updates, err := c.node.GetLightClientUpdates(period, maxUpdates)
+        if err != nil {
+            return Header{}, err
+        }
+        for i := range updates {
+            if err := s.apply(&updates[i]); err != nil {
+                return Header{}, fmt.Errorf("rejecting light client update: %w", err)
+            }
+        }
+        if s.period == period {
+            break
+        }
+    }
+
+    update, err := c.node.GetLightClientFinalityUpdate()
+    if err != nil {
+        return Header{}, err
+    }
+    if err := s.apply(update); err != nil {
+        return Header{}, fmt.Errorf("rejecting light client finality update: %w", err)
+    }
+    return s.finalized, nil
+}
+
+// bootstrap takes the checkpoint's sync committee from the node, checking it
+// against the checkpoint
+func (c *Client) bootstrap() (*store, error) {
+    s := &store{}
+
+    // The genesis root and fork versions only select the signing domain: a
+    // wrong one makes every signature fail rather than a forgery pass
+    root, err := c.node.GetGenesisValidatorsRoot()
+    if err != nil {
+        return nil, err
+    }
+    if s.genesisValidatorsRoot, err = parseRoot(root); err != nil {
+        return nil, fmt.Errorf("genesis validators root: %w", err)
+    }
+    schedule, err := c.node.GetForkSchedule()
+    if err != nil {
+        return nil, err
+    }
+    for i, entry := range schedule {
+        if i >= len(forkNames) {
+            break
+        }
+        version, err := parseHex(entry.CurrentVersion, 4)
+        if err != nil {
+            return nil, fmt.Errorf("fork schedule: %w", err)
+        }
+        f := fork{name: forkNames[i], epoch: entry.Epoch}
+        copy(f.version[:], version)
+        s.forks = append(s.forks, f)
+    }
+    if len(s.forks) < 2 {
+        return nil, fmt.Errorf("fork schedule has no Altair fork, so the chain has no sync committees")
+    }
+
+    bootstrap, err := c.node.GetLightClientBootstrap(hexRoot(c.checkpoint))
+    if err != nil {
+        return nil, err
+    }
+    header, err := parseHeader(bootstrap.Header.Beacon)
+    if err != nil {
+        return nil, fmt.Errorf("bootstrap header: %w", err)
+    }
+    if header.Root() != c.checkpoint {
+        return nil, fmt.Errorf("bootstrap header has root %s, not the checkpoint %s", hexRoot(header.Root()), hexRoot(c.checkpoint))
+    }
+
+    committee, committeeRoot, err := parseCommittee(&bootstrap.CurrentSyncCommittee)
+    if err != nil {
+        return nil, fmt.Errorf("bootstrap sync committee: %w", err)
+    }
+    _, depth := s.branchDepths(header)
+    branch, err := parseBranch(bootstrap.CurrentSyncCommitteeBranch, depth)
+    if err != nil {
+        return nil, fmt.Errorf("bootstrap sync committee proof: %w", err)
+    }
+    if !ssz.VerifyBranch(committeeRoot, branch, currentSyncCommitteeIndex, header.StateRoot) {
+        return nil, fmt.Errorf("bootstrap sync committee is not in the checkpoint's state")
+    }
+
+    s.finalized = header
+    s.period = syncPeriod(header.Slot)
+    s.current = committee
+    return s, nil
+}
+
+// apply checks an update's signature and proofs, then moves the store to its
+// finalized header and learns its next sync committee
+func (s *store) apply(update *beacon.LightClientUpdate) error {
+    attested, err := parseHeader(update.AttestedHeader.Beacon)
+    if err != nil {
+        return fmt.Errorf("attested header: %w", err)
+    }
+    finalized, err := parseHeader(update.FinalizedHeader.Beacon)
+    if err != nil {
+        return fmt.Errorf("finalized header: %w", err)
+    }
+    if update.SignatureSlot <= attested.Slot || attested.Slot < finalized.Slot {
+        return fmt.Errorf("slots out of order: signed at %d, attested %d, finalized %d",
+            update.SignatureSlot, attested.Slot, finalized.Slot)
+    }
+
+    var committee []*blsu.Pubkey
+    switch signaturePeriod := syncPeriod(update.SignatureSlot); {
+    case signaturePeriod == s.period:
+        committee = s.current
+    case signaturePeriod == s.period+1 && s.next != nil:
+        committee = s.next
+    default:
+        return fmt.Errorf("signed in sync committee period %d, but the committee of period %d is the latest known",
+            signaturePeriod, s.period)
+    }
+    if err := s.verifySignature(committee, attested, update); err != nil {
+        return err
+    }
+
+    finalityDepth, committeeDepth := s.branchDepths(attested)
+    branch, err := parseBranch(update.FinalityBranch, finalityDepth)
+    if err != nil {
+        return fmt.Errorf("finality proof: %w", err)
+    }
+    if !ssz.VerifyBranch(finalized.Root(), branch, finalizedRootIndex, attested.StateRoot) {
+        return fmt.Errorf("finalized header at slot %d is not the attested state's finalized checkpoint", finalized.Slot)
+    }
+
+    if update.NextSyncCommittee != nil && len(update.NextSyncCommitteeBranch) > 0 && syncPeriod(attested.Slot) == s.period {
+        next, nextRoot, err := parseCommittee(update.NextSyncCommittee)
+        if err != nil {
+            return fmt.Errorf("next sync committee: %w", err)
+        }
+        branch, err := parseBranch(update.NextSyncCommitteeBranch, committeeDepth)
+        if err != nil {
+            return fmt.Errorf("next sync committee proof: %w", err)
+        }
+        if !ssz.VerifyBranch(nextRoot, branch, nextSyncCommitteeIndex, attested.StateRoot) {
+            return fmt.Errorf("next sync committee is not in the attested state")
+        }
+        if s.next != nil && nextRoot != s.nextRoot {
+            return fmt.Errorf("next sync committee differs from the one already verified")
+        }
+        s.next, s.nextRoot = next, nextRoot
+    }
+
+    if finalized.Slot > s.finalized.Slot {
+        s.finalized = finalized
+    }
+    if syncPeriod(s.finalized.Slot) == s.period+1 && s.next != nil {
+        s.current, s.next, s.nextRoot = s.next, nil, [32]byte{}
+        s.period++
+    }
+    return nil
+}
+
+// verifySignature checks that a supermajority of committee signed attested.
+// The protocol accepts a single signer, but a supermajority is what makes a
+// header as hard to forge as the chain's own finality.
+func (s *store) verifySignature(committee []*blsu.Pubkey, attested Header, update *beacon.LightClientUpdate) error {
+    bits, err := parseHex(update.SyncAggregate.SyncCommitteeBits, (len(committee)+7)/8)
+    if err != nil {
+        return fmt.Errorf("sync committee bits: %w", err)
+    }
+    var signers []*blsu.Pubkey
+    for i, key := range committee {
+        if bits[i/8]>>(i%8)&1 == 1 {
+            signers = append(signers, key)
+        }
+    }
+    if len(signers)*3 < len(committee)*2 {
+        return fmt.Errorf("only %d of %d sync committee members signed the header at slot %d",
+            len(signers), len(committee), attested.Slot)
+    }
+
+    raw, err := parseHex(update.SyncAggregate.SyncCommitteeSignature, 96)
+    if err != nil {
+        return fmt.Errorf("sync committee signature: %w", err)
+    }
+    var signature blsu.Signature
+    var encoded [96]byte
+    copy(encoded[:], raw)
+    if err := signature.Deserialize(&encoded); err != nil {
+        return fmt.Errorf("sync committee signature: %w", err)
+    }
+
+    signingRoot := s.signingRoot(attested, update.SignatureSlot)
+    if !blsu.FastAggregateVerify(signers, signingRoot[:], &signature) {
+        return fmt.Errorf("sync committee signature over the header at slot %d is invalid", attested.Slot)
+    }
+    return nil
+}
+
+// signingRoot is the message a sync committee signs for header: its root in
+// the domain of the fork at the slot before signatureSlot
+func (s *store) signingRoot(header Header, signatureSlot uint64) [32]byte {
+    epoch := uint64(0)
+    if signatureSlot > 0 {
+        epoch = (signatureSlot - 1) / config.SLOTS_PER_EPOCH
+    }
+    var version [32]byte
+    forkVersion := s.forkAt(epoch).version
+    copy(version[:], forkVersion[:])
+    forkDataRoot := ssz.HashPair(version, s.genesisValidatorsRoot)
+
+    var domain [32]byte
+    copy(domain[:4], domainSyncCommittee[:])
+    copy(domain[4:], forkDataRoot[:28])
+    return ssz.HashPair(header.Root(), domain)
+}
+
+// forkAt returns the fork in effect at epoch
+func (s *store) forkAt(epoch uint64) fork {
+    current := s.forks[0]
+    for _, f := range s.forks {
+        if f.epoch <= epoch {
+            current = f
+        }
+    }
+    return current
+}
+
+// branchDepths returns the depths of the finality and sync committee proofs
+// into the state of header
+func (s *store) branchDepths(header Header) (finality, committee int) {
+    if isElectraOrLater(s.forkAt(header.Epoch()).name) {
+        return 7, 6
+    }
+    return 6, 5
+}
+
+// isElectraOrLater reports whether fork name is Electra or a later fork
+func isElectraOrLater(name string) bool {
+    for _, f := range forkNames {
+        if f == "electra" {
+            return true
+        }
+        if f == name {
+            return false
+        }
+    }
+    return true
+}
+
+// syncPeriod returns the sync committee period of slot
+func syncPeriod(slot uint64) uint64 {
+    return slot / config.SLOTS_PER_EPOCH / config.EPOCHS_PER_SYNC_COMMITTEE_PERIOD
+}
+
+// parseCommittee decodes a sync committee's keys and computes its root
+func parseCommittee(keys *beacon.SyncCommitteeKeys) ([]*blsu.Pubkey, [32]byte, error) {
+    if len(keys.Pubkeys) != int(config.SYNC_COMMITTEE_SIZE) {
+        return nil, [32]byte{}, fmt.Errorf("%d keys, expected %d", len(keys.Pubkeys), config.SYNC_COMMITTEE_SIZE)
+    }
+
+    data := make([]byte, 0, 48*(len(keys.Pubkeys)+1))
+    committee := make([]*blsu.Pubkey, len(keys.Pubkeys))
+    for i, key := range keys.Pubkeys {
+        raw, err := parseHex(key, 48)
+        if err != nil {
+            return nil, [32]byte{}, fmt.Errorf("key %d: %w", i, err)
+        }
+        var encoded [48]byte
+        copy(encoded[:], raw)
+        committee[i] = new(blsu.Pubkey)
+        if err := committee[i].Deserialize(&encoded); err != nil {
+            return nil, [32]byte{}, fmt.Errorf("key %d: %w", i, err)
+        }
+        data = append(data, raw...)
+    }
+    aggregate, err := parseHex(keys.AggregatePubkey, 48)
+    if err != nil {
+        return nil, [32]byte{}, fmt.Errorf("aggregate key: %w", err)
+    }
+    data = append(data, aggregate...)
+
+    root, err := ssz.SyncCommittee().Root(data)
+    return committee, root, err
+}
+
+// parseHeader decodes a header from the REST API
+func parseHeader(h beacon.BeaconBlockHeader) (Header, error) {
+    header := Header{Slot: h.Slot, ProposerIndex: h.ProposerIndex}
+    var err error
+    if header.ParentRoot, err = parseRoot(h.ParentRoot); err != nil {
+        return header, fmt.Errorf("parent root: %w", err)
+    }
+    if header.StateRoot, err = parseRoot(h.StateRoot); err != nil {
+        return header, fmt.Errorf("state root: %w", err)
+    }
+    if header.BodyRoot, err = parseRoot(h.BodyRoot); err != nil {
+        return header, fmt.Errorf("body root: %w", err)
+    }
+    return header, nil
+}
+
+// parseBranch decodes a Merkle proof of depth roots
+func parseBranch(branch []string, depth int) ([][32]byte, error) {
+    if len(branch) != depth {
+        return nil, fmt.Errorf("%d roots, expected %d", len(branch), depth)
+    }
+    roots := make([][32]byte, len(branch))
+    for i, node := range branch {
+        var err error
+        if roots[i], err = parseRoot(node); err != nil {
+            return nil, err
+        }
+    }
+    return roots, nil
+}
+
+// ParseRoot decodes a 0x-prefixed 32-byte hex root, such as a checkpoint
+func ParseRoot(s string) ([32]byte, error) {
+    return parseRoot(s)
+}
+
+func parseRoot(s string) ([32]byte, error) {
+    var root [32]byte
+    raw, err := parseHex(s, 32)
+    copy(root[:], raw)
+    return root, err
+}
+
+// parseHex decodes 0x-prefixed hex of exactly size bytes
+func parseHex(s string, size int) ([]byte, error) {
+    raw, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+    if err != nil {
+        return nil, fmt.Errorf("invalid hex %q", s)
+    }
+    if len(raw) != size {
+        return nil, fmt.Errorf("%d bytes, expected %d", len(raw), size)
+    }
+    return raw, nil
+}
+
+func hexRoot(root [32]byte) string {
+    return "0x" + hex.EncodeToString(root[:])
+}
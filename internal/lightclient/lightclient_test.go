@@ -0,0 +1,432 @@
+package lightclient
+
+import (
+    "encoding/binary"
+    "encoding/hex"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/eth-rewards-calculator/internal/beacon"
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/ssz"
+
+    blsu "github.com/protolambda/bls12-381-util"
+)
+
+// useMinimalPreset switches to the minimal preset, whose 32-member sync
+// committees and 64-slot periods keep the signing cheap, until the test ends
+func useMinimalPreset(t *testing.T) {
+    saved := config.Save()
+    t.Cleanup(saved.Restore)
+    if err := config.ApplyPreset("minimal"); err != nil {
+        t.Fatal(err)
+    }
+}
+
+// testCommittee is a sync committee whose secret keys the test holds
+type testCommittee struct {
+    secrets []*blsu.SecretKey
+    keys    beacon.SyncCommitteeKeys
+    data    []byte // SSZ encoding
+}
+
+func newCommittee(t *testing.T, seed byte) testCommittee {
+    t.Helper()
+    var c testCommittee
+    var pubkeys []*blsu.Pubkey
+    for i := 0; i < int(config.SYNC_COMMITTEE_SIZE); i++ {
+        var raw [32]byte
+        raw[30], raw[31] = seed, byte(i+1)
+        secret := new(blsu.SecretKey)
+        if err := secret.Deserialize(&raw); err != nil {
+            t.Fatal(err)
+        }
+        pubkey, err := blsu.SkToPk(secret)
+        if err != nil {
+            t.Fatal(err)
+        }
+        encoded := pubkey.Serialize()
+        c.secrets = append(c.secrets, secret)
+        c.keys.Pubkeys = append(c.keys.Pubkeys, "0x"+hex.EncodeToString(encoded[:]))
+        c.data = append(c.data, encoded[:]...)
+        pubkeys = append(pubkeys, pubkey)
+    }
+    aggregate, err := blsu.AggregatePubkeys(pubkeys)
+    if err != nil {
+        t.Fatal(err)
+    }
+    encoded := aggregate.Serialize()
+    c.keys.AggregatePubkey = "0x" + hex.EncodeToString(encoded[:])
+    c.data = append(c.data, encoded[:]...)
+    return c
+}
+
+// testChain is a minimal-preset chain whose forks up to stateFork all
+// activated at genesis
+type testChain struct {
+    t                     *testing.T
+    stateFork             string
+    schedule              []beacon.ForkScheduleEntry
+    genesisValidatorsRoot [32]byte
+}
+
+func newChain(t *testing.T, stateFork string) *testChain {
+    c := &testChain{t: t, stateFork: stateFork}
+    c.genesisValidatorsRoot[0] = 0x4b
+    for i, name := range forkNames {
+        c.schedule = append(c.schedule, beacon.ForkScheduleEntry{CurrentVersion: config.ForkVersion(name)})
+        if name == stateFork {
+            break
+        }
+        if i == len(forkNames)-1 {
+            t.Fatalf("unknown fork %s", stateFork)
+        }
+    }
+    return c
+}
+
+// store is the store a bootstrap into period 0 with committee leaves
+func (c *testChain) store(committee testCommittee) *store {
+    s := &store{genesisValidatorsRoot: c.genesisValidatorsRoot}
+    for i, entry := range c.schedule {
+        version, err := parseHex(entry.CurrentVersion, 4)
+        if err != nil {
+            c.t.Fatal(err)
+        }
+        f := fork{name: forkNames[i], epoch: entry.Epoch}
+        copy(f.version[:], version)
+        s.forks = append(s.forks, f)
+    }
+    committees, _, err := parseCommittee(&committee.keys)
+    if err != nil {
+        c.t.Fatal(err)
+    }
+    s.current = committees
+    return s
+}
+
+// testState is a beacon state with the given finalized header and sync
+// committees, and the proofs a node serves for them
+type testState struct {
+    root           [32]byte
+    finalityBranch []string
+    currentBranch  []string
+    nextBranch     []string
+}
+
+func (c *testChain) state(finalized Header, current, next testCommittee) testState {
+    layout, err := ssz.BeaconState(c.stateFork)
+    if err != nil {
+        c.t.Fatal(err)
+    }
+    fields, err := layout.Fields(ssz.Zero(layout))
+    if err != nil {
+        c.t.Fatal(err)
+    }
+    finalizedRoot := finalized.Root()
+    fields[layout.Index("finalized_checkpoint")] = append(binary.LittleEndian.AppendUint64(nil, finalized.Epoch()), finalizedRoot[:]...)
+    fields[layout.Index("current_sync_committee")] = current.data
+    fields[layout.Index("next_sync_committee")] = next.data
+    data, err := layout.Encode(fields)
+    if err != nil {
+        c.t.Fatal(err)
+    }
+
+    var state testState
+    if state.root, err = layout.Root(data); err != nil {
+        c.t.Fatal(err)
+    }
+    leaves := make([][32]byte, len(layout))
+    for i, field := range layout {
+        if leaves[i], err = field.Type.Root(fields[i]); err != nil {
+            c.t.Fatal(err)
+        }
+    }
+    var epoch [32]byte
+    binary.LittleEndian.PutUint64(epoch[:], finalized.Epoch())
+    state.finalityBranch = append([]string{hexRoot(epoch)}, proof(leaves, layout.Index("finalized_checkpoint"))...)
+    state.currentBranch = proof(leaves, layout.Index("current_sync_committee"))
+    state.nextBranch = proof(leaves, layout.Index("next_sync_committee"))
+    return state
+}
+
+// proof returns the Merkle proof of leaves[index] in the smallest tree that
+// holds them all
+func proof(leaves [][32]byte, index int) []string {
+    layer := append([][32]byte{}, leaves...)
+    var branch []string
+    var empty [32]byte // root of an empty subtree at this level
+    for level := 0; len(layer) > 1 || level == 0; level++ {
+        if len(layer)%2 == 1 {
+            layer = append(layer, empty)
+        }
+        empty = ssz.HashPair(empty, empty)
+        branch = append(branch, hexRoot(layer[index^1]))
+        next := make([][32]byte, len(layer)/2)
+        for i := range next {
+            next[i] = ssz.HashPair(layer[2*i], layer[2*i+1])
+        }
+        layer, index = next, index/2
+    }
+    return branch
+}
+
+// sign has the first signers members of committee sign attested at
+// signatureSlot under version, computing the signing root as the spec does
+func (c *testChain) sign(committee testCommittee, signers int, attested Header, signatureSlot uint64, version string) beacon.SyncAggregate {
+    var forkVersion [32]byte
+    raw, _ := parseHex(version, 4)
+    copy(forkVersion[:], raw)
+    forkDataRoot := ssz.HashPair(forkVersion, c.genesisValidatorsRoot) // hash_tree_root(ForkData)
+    var domain [32]byte
+    copy(domain[:], []byte{0x07, 0x00, 0x00, 0x00})
+    copy(domain[4:], forkDataRoot[:28])
+    signingRoot := ssz.HashPair(attested.Root(), domain) // hash_tree_root(SigningData)
+
+    bits := make([]byte, (len(committee.secrets)+7)/8)
+    var signatures []*blsu.Signature
+    for i := 0; i < signers; i++ {
+        signatures = append(signatures, blsu.Sign(committee.secrets[i], signingRoot[:]))
+        bits[i/8] |= 1 << (i % 8)
+    }
+    aggregate, err := blsu.Aggregate(signatures)
+    if err != nil {
+        c.t.Fatal(err)
+    }
+    encoded := aggregate.Serialize()
+    return beacon.SyncAggregate{
+        SyncCommitteeBits:      "0x" + hex.EncodeToString(bits),
+        SyncCommitteeSignature: "0x" + hex.EncodeToString(encoded[:]),
+    }
+}
+
+// update is an update signed by every member of signer over an attested
+// header at attestedSlot, finalizing a header at finalizedSlot and carrying
+// next as the next sync committee
+func (c *testChain) update(signer, current, next testCommittee, attestedSlot, finalizedSlot uint64) beacon.LightClientUpdate {
+    finalized := Header{Slot: finalizedSlot, BodyRoot: [32]byte{byte(finalizedSlot)}}
+    state := c.state(finalized, current, next)
+    attested := Header{Slot: attestedSlot, StateRoot: state.root}
+    version := c.schedule[len(c.schedule)-1].CurrentVersion
+    return beacon.LightClientUpdate{
+        AttestedHeader:          beacon.LightClientHeader{Beacon: restHeader(attested)},
+        NextSyncCommittee:       &next.keys,
+        NextSyncCommitteeBranch: state.nextBranch,
+        FinalizedHeader:         beacon.LightClientHeader{Beacon: restHeader(finalized)},
+        FinalityBranch:          state.finalityBranch,
+        SyncAggregate:           c.sign(signer, len(signer.secrets), attested, attestedSlot+1, version),
+        SignatureSlot:           attestedSlot + 1,
+    }
+}
+
+// restHeader is a header as the REST API sends it
+func restHeader(h Header) beacon.BeaconBlockHeader {
+    return beacon.BeaconBlockHeader{
+        Slot:          h.Slot,
+        ProposerIndex: h.ProposerIndex,
+        ParentRoot:    hexRoot(h.ParentRoot),
+        StateRoot:     hexRoot(h.StateRoot),
+        BodyRoot:      hexRoot(h.BodyRoot),
+    }
+}
+
+func TestProofIndices(t *testing.T) {
+    for _, name := range []string{"altair", "capella", "electra", "fulu"} {
+        layout, err := ssz.BeaconState(name)
+        if err != nil {
+            t.Fatal(err)
+        }
+        depth := 0
+        for 1<<depth < len(layout) {
+            depth++
+        }
+        if got := layout.Index("finalized_checkpoint")*2 + 1; got != finalizedRootIndex {
+            t.Errorf("%s: finalized root at %d, want %d", name, got, finalizedRootIndex)
+        }
+        if layout.Index("current_sync_committee") != currentSyncCommitteeIndex || layout.Index("next_sync_committee") != nextSyncCommitteeIndex {
+            t.Errorf("%s: sync committees at %d and %d", name, layout.Index("current_sync_committee"), layout.Index("next_sync_committee"))
+        }
+        s := &store{forks: []fork{{name: name}}}
+        if finality, committee := s.branchDepths(Header{}); finality != depth+1 || committee != depth {
+            t.Errorf("%s: branch depths %d and %d, want %d and %d", name, finality, committee, depth+1, depth)
+        }
+    }
+}
+
+func TestApplyUpdates(t *testing.T) {
+    useMinimalPreset(t)
+    first, second := newCommittee(t, 1), newCommittee(t, 2)
+    slotsPerPeriod := config.SLOTS_PER_EPOCH * config.EPOCHS_PER_SYNC_COMMITTEE_PERIOD
+
+    for _, stateFork := range []string{"capella", "electra"} {
+        t.Run(stateFork, func(t *testing.T) {
+            chain := newChain(t, stateFork)
+            s := chain.store(first)
+
+            // The first committee signs in its period and names the second
+            update := chain.update(first, first, second, slotsPerPeriod-8, slotsPerPeriod-16)
+            if err := s.apply(&update); err != nil {
+                t.Fatal(err)
+            }
+            if s.finalized.Slot != slotsPerPeriod-16 || s.next == nil || s.period != 0 {
+                t.Fatalf("after the first update: finalized slot %d, period %d, next known %v", s.finalized.Slot, s.period, s.next != nil)
+            }
+
+            // The second signs once the chain finalizes into its period
+            update = chain.update(second, second, first, slotsPerPeriod+16, slotsPerPeriod+8)
+            if err := s.apply(&update); err != nil {
+                t.Fatal(err)
+            }
+            if s.finalized.Slot != slotsPerPeriod+8 || s.period != 1 || s.next != nil {
+                t.Fatalf("after the hand-over: finalized slot %d, period %d, next known %v", s.finalized.Slot, s.period, s.next != nil)
+            }
+        })
+    }
+}
+
+func TestRejectedUpdates(t *testing.T) {
+    useMinimalPreset(t)
+    first, second := newCommittee(t, 1), newCommittee(t, 2)
+    chain := newChain(t, "capella")
+    slotsPerPeriod := config.SLOTS_PER_EPOCH * config.EPOCHS_PER_SYNC_COMMITTEE_PERIOD
+
+    tests := []struct {
+        name   string
+        change func(u *beacon.LightClientUpdate)
+        want   string
+    }{
+        {"bad finality branch", func(u *beacon.LightClientUpdate) {
+            u.FinalityBranch[2] = hexRoot([32]byte{1})
+        }, "is not the attested state's finalized checkpoint"},
+        {"short finality branch", func(u *beacon.LightClientUpdate) {
+            u.FinalityBranch = u.FinalityBranch[1:]
+        }, "finality proof"},
+        {"other finalized header", func(u *beacon.LightClientUpdate) {
+            u.FinalizedHeader.Beacon.Slot--
+        }, "is not the attested state's finalized checkpoint"},
+        {"bad next committee branch", func(u *beacon.LightClientUpdate) {
+            u.NextSyncCommitteeBranch[0] = hexRoot([32]byte{1})
+        }, "next sync committee is not in the attested state"},
+        {"fewer than two thirds signed", func(u *beacon.LightClientUpdate) {
+            attested, _ := parseHeader(u.AttestedHeader.Beacon)
+            u.SyncAggregate = chain.sign(first, 21, attested, u.SignatureSlot, chain.schedule[len(chain.schedule)-1].CurrentVersion)
+        }, "only 21 of 32 sync committee members signed"},
+        {"wrong fork version", func(u *beacon.LightClientUpdate) {
+            attested, _ := parseHeader(u.AttestedHeader.Beacon)
+            u.SyncAggregate = chain.sign(first, 32, attested, u.SignatureSlot, chain.schedule[1].CurrentVersion)
+        }, "signature over the header at slot 56 is invalid"},
+        {"wrong committee", func(u *beacon.LightClientUpdate) {
+            attested, _ := parseHeader(u.AttestedHeader.Beacon)
+            u.SyncAggregate = chain.sign(second, 32, attested, u.SignatureSlot, chain.schedule[len(chain.schedule)-1].CurrentVersion)
+        }, "is invalid"},
+        {"other attested header", func(u *beacon.LightClientUpdate) {
+            u.AttestedHeader.Beacon.ProposerIndex = 7
+        }, "is invalid"},
+        {"signed in an unknown period", func(u *beacon.LightClientUpdate) {
+            u.SignatureSlot = 2 * slotsPerPeriod
+        }, "signed in sync committee period 2"},
+        {"slots out of order", func(u *beacon.LightClientUpdate) {
+            u.SignatureSlot = u.AttestedHeader.Beacon.Slot
+        }, "slots out of order"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            s := chain.store(first)
+            update := chain.update(first, first, second, slotsPerPeriod-8, slotsPerPeriod-16)
+            tt.change(&update)
+            err := s.apply(&update)
+            if err == nil || !strings.Contains(err.Error(), tt.want) {
+                t.Fatalf("apply error = %v, want %q", err, tt.want)
+            }
+            if s.finalized.Slot != 0 || s.next != nil {
+                t.Errorf("rejected update changed the store")
+            }
+        })
+    }
+}
+
+func TestFinalized(t *testing.T) {
+    useMinimalPreset(t)
+    first, second := newCommittee(t, 1), newCommittee(t, 2)
+    chain := newChain(t, "electra")
+    slotsPerPeriod := config.SLOTS_PER_EPOCH * config.EPOCHS_PER_SYNC_COMMITTEE_PERIOD
+
+    checkpointState := chain.state(Header{}, first, second)
+    checkpoint := Header{Slot: 8, StateRoot: checkpointState.root}
+    updates := []beacon.LightClientUpdate{chain.update(first, first, second, slotsPerPeriod-8, slotsPerPeriod-16)}
+    finality := chain.update(second, second, first, slotsPerPeriod+16, slotsPerPeriod+8)
+    finality.NextSyncCommittee, finality.NextSyncCommitteeBranch = nil, nil
+
+    bootstrapBranch := checkpointState.currentBranch
+    mux := http.NewServeMux()
+    respond := func(w http.ResponseWriter, v interface{}) {
+        json.NewEncoder(w).Encode(map[string]interface{}{"data": v})
+    }
+    mux.HandleFunc("/eth/v1/beacon/genesis", func(w http.ResponseWriter, r *http.Request) {
+        respond(w, map[string]string{"genesis_validators_root": hexRoot(chain.genesisValidatorsRoot)})
+    })
+    mux.HandleFunc("/eth/v1/config/fork_schedule", func(w http.ResponseWriter, r *http.Request) {
+        respond(w, chain.schedule)
+    })
+    mux.HandleFunc("/eth/v1/beacon/light_client/bootstrap/", func(w http.ResponseWriter, r *http.Request) {
+        respond(w, beacon.LightClientBootstrap{
+            Header:                     beacon.LightClientHeader{Beacon: restHeader(checkpoint)},
+            CurrentSyncCommittee:       first.keys,
+            CurrentSyncCommitteeBranch: bootstrapBranch,
+        })
+    })
+    mux.HandleFunc("/eth/v1/beacon/light_client/updates", func(w http.ResponseWriter, r *http.Request) {
+        var envelopes []map[string]interface{}
+        if r.URL.Query().Get("start_period") == "0" {
+            for _, update := range updates {
+                envelopes = append(envelopes, map[string]interface{}{"data": update})
+            }
+        }
+        json.NewEncoder(w).Encode(envelopes)
+    })
+    mux.HandleFunc("/eth/v1/beacon/light_client/finality_update", func(w http.ResponseWriter, r *http.Request) {
+        respond(w, finality)
+    })
+    server := httptest.NewServer(mux)
+    defer server.Close()
+
+    header, err := New(beacon.NewClient(server.URL), checkpoint.Root()).Finalized()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if header.Slot != slotsPerPeriod+8 {
+        t.Errorf("finalized slot %d, want %d", header.Slot, slotsPerPeriod+8)
+    }
+
+    // A checkpoint the bootstrap header does not match
+    if _, err := New(beacon.NewClient(server.URL), [32]byte{1}).Finalized(); err == nil || !strings.Contains(err.Error(), "not the checkpoint") {
+        t.Errorf("wrong checkpoint: error = %v", err)
+    }
+
+    // A committee that is not in the checkpoint's state
+    bootstrapBranch = checkpointState.nextBranch
+    if _, err := New(beacon.NewClient(server.URL), checkpoint.Root()).Finalized(); err == nil || !strings.Contains(err.Error(), "not in the checkpoint's state") {
+        t.Errorf("bad bootstrap branch: error = %v", err)
+    }
+}
+
+func TestSupermajority(t *testing.T) {
+    useMinimalPreset(t)
+    first, second := newCommittee(t, 1), newCommittee(t, 2)
+    chain := newChain(t, "capella")
+    version := chain.schedule[len(chain.schedule)-1].CurrentVersion
+
+    // 22 of 32 is the smallest two-thirds majority
+    for signers, accepted := range map[int]bool{21: false, 22: true} {
+        s := chain.store(first)
+        update := chain.update(first, first, second, 40, 32)
+        attested, _ := parseHeader(update.AttestedHeader.Beacon)
+        update.SyncAggregate = chain.sign(first, signers, attested, update.SignatureSlot, version)
+        if err := s.apply(&update); (err == nil) != accepted {
+            t.Errorf("%d signers: error = %v, want accepted %v", signers, err, accepted)
+        }
+    }
+}
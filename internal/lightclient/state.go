@@ -0,0 +1,121 @@
+package lightclient
+
+import (
+    "encoding/binary"
+    "fmt"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/ssz"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// Snapshot summarizes the validators of the latest finalized state. The node
+// sends the whole state, which is only accepted if its root is the verified
+// finalized header's state root, so every figure is as trustworthy as the
+// checkpoint. Like the state itself, the download is large on mainnet.
+func (c *Client) Snapshot() (*types.NetworkSnapshot, error) {
+    _, layout, data, err := c.verifiedState()
+    if err != nil {
+        return nil, err
+    }
+    return summarize(layout, data)
+}
+
+// State captures the active validators of the latest finalized state as a
+// NetworkState, with their participation flags and inactivity scores and the
+// slashings vector, all verified as Snapshot's figures are
+func (c *Client) State() (*types.NetworkState, error) {
+    _, _, data, err := c.verifiedState()
+    if err != nil {
+        return nil, err
+    }
+    state := &types.NetworkState{}
+    if err := state.UnmarshalSSZ(data); err != nil {
+        return nil, err
+    }
+    return state, nil
+}
+
+// verifiedState downloads the latest finalized state and checks it against
+// the verified state root, returning its fork, layout, and SSZ encoding
+func (c *Client) verifiedState() (string, ssz.Container, []byte, error) {
+    header, err := c.Finalized()
+    if err != nil {
+        return "", nil, nil, err
+    }
+
+    fork, data, err := c.node.GetStateSSZ(hexRoot(header.StateRoot))
+    if err != nil {
+        return "", nil, nil, err
+    }
+    layout, err := ssz.BeaconState(fork)
+    if err != nil {
+        return "", nil, nil, err
+    }
+    root, err := layout.Root(data)
+    if err != nil {
+        return "", nil, nil, fmt.Errorf("hashing beacon state: %w", err)
+    }
+    if root != header.StateRoot {
+        return "", nil, nil, fmt.Errorf("beacon state has root %s, not the verified %s", hexRoot(root), hexRoot(header.StateRoot))
+    }
+    return fork, layout, data, nil
+}
+
+// summarize counts a state's active and queued validators and the share of
+// the previous epoch's active validators that attested to the right target
+func summarize(layout ssz.Container, data []byte) (*types.NetworkSnapshot, error) {
+    fields, err := layout.Fields(data)
+    if err != nil {
+        return nil, err
+    }
+    epoch := binary.LittleEndian.Uint64(fields[layout.Index("slot")]) / config.SLOTS_PER_EPOCH
+    previous := epoch
+    if previous > 0 {
+        previous--
+    }
+
+    validators, err := ssz.Elements(ssz.Validator, fields[layout.Index("validators")])
+    if err != nil {
+        return nil, fmt.Errorf("validators: %w", err)
+    }
+    flags := fields[layout.Index("previous_epoch_participation")]
+    if len(flags) != len(validators) {
+        return nil, fmt.Errorf("%d participation flags for %d validators", len(flags), len(validators))
+    }
+
+    snapshot := &types.NetworkSnapshot{Epoch: epoch}
+    attesters, timely := 0, 0
+    for i, raw := range validators {
+        validator, err := ssz.Validator.Fields(raw)
+        if err != nil {
+            return nil, fmt.Errorf("validator %d: %w", i, err)
+        }
+        slashed := validator[ssz.Validator.Index("slashed")][0] == 1
+        eligibility := binary.LittleEndian.Uint64(validator[ssz.Validator.Index("activation_eligibility_epoch")])
+        activation := binary.LittleEndian.Uint64(validator[ssz.Validator.Index("activation_epoch")])
+        exit := binary.LittleEndian.Uint64(validator[ssz.Validator.Index("exit_epoch")])
+
+        // The same statuses the beacon API reports
+        switch {
+        case activation <= epoch && epoch < exit:
+            snapshot.ActiveValidators++
+            if exit != ssz.FarFutureEpoch && !slashed {
+                snapshot.ExitQueue++
+            }
+        case eligibility != ssz.FarFutureEpoch && activation > epoch:
+            snapshot.ActivationQueue++
+        }
+
+        if activation <= previous && previous < exit && !slashed {
+            attesters++
+            if flags[i]>>config.TIMELY_TARGET_FLAG_INDEX&1 == 1 {
+                timely++
+            }
+        }
+    }
+    if attesters > 0 {
+        snapshot.Participation = float64(timely) / float64(attesters)
+    }
+    return snapshot, nil
+}
@@ -0,0 +1,265 @@
+package monitor
+
+import (
+    "encoding/json"
+    "fmt"
+    "regexp"
+    "strconv"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+var (
+    // lighthouseMessage matches the validator monitor's attestation logs, both
+    // the per-validator ones and the (s) forms that list several validators
+    lighthouseMessage = regexp.MustCompile(`^Previous epoch attestation(?:\(s\))? (success|missing|failed to match (head|target|source))$`)
+
+    // fieldStart finds where a terminal log line's message ends and its
+    // "key: value" fields begin
+    fieldStart = regexp.MustCompile(`\s+[A-Za-z_]+: `)
+
+    // colorCode matches the ANSI colors of logs captured from a terminal
+    colorCode = regexp.MustCompile("\x1b\\[[0-9;]*m")
+)
+
+// logReader collects the attestations one client's logs report. A later
+// report of a validator's epoch replaces an earlier one, as after a restart.
+type logReader struct {
+    records    map[string]*attestation
+    order      []string
+    mismatches [][2]string // record key and the vote that failed to match
+}
+
+// record returns the attestation of a validator's epoch, creating it as a
+// timely, included one
+func (r *logReader) record(validator, epoch string) *attestation {
+    if r.records == nil {
+        r.records = make(map[string]*attestation)
+    }
+    key := validator + "/" + epoch
+    if a, ok := r.records[key]; ok {
+        return a
+    }
+    a := &attestation{validator: validator, epoch: epoch, included: true, source: true, target: true, head: true}
+    r.records[key] = a
+    r.order = append(r.order, key)
+    return a
+}
+
+// add reads a Lighthouse validator monitor log, reporting whether it was one
+func (r *logReader) add(msg string, fields map[string]string) bool {
+    match := lighthouseMessage.FindStringSubmatch(msg)
+    if match == nil {
+        return false
+    }
+
+    validators := parseList(fields["validators"])
+    if v := fields["validator"]; v != "" {
+        validators = append(validators, v)
+    }
+    for _, validator := range validators {
+        switch match[1] {
+        case "success":
+            a := r.record(validator, fields["epoch"])
+            *a = attestation{validator: validator, epoch: fields["epoch"], included: true,
+                source: fields["matched_source"] != "false", target: fields["matched_target"] != "false",
+                head: fields["matched_head"] != "false"}
+            a.distance, _ = strconv.ParseUint(fields["inclusion_delay"], 10, 64)
+        case "missing":
+            *r.record(validator, fields["epoch"]) = attestation{validator: validator, epoch: fields["epoch"]}
+        default:
+            r.mismatches = append(r.mismatches, [2]string{validator + "/" + fields["epoch"], match[2]})
+        }
+    }
+    return true
+}
+
+// addPrysm reads a Prysm validator client's voting summary log. Prysm
+// reports the timely votes of an attestation that was not included as false.
+func (r *logReader) addPrysm(msg string, fields map[string]string) {
+    if msg != "Previous epoch voting summary" {
+        return
+    }
+
+    validator := fields["pubKey"]
+    if validator == "" {
+        validator = fields["pubkey"]
+    }
+    a := r.record(validator, fields["epoch"])
+    *a = attestation{validator: validator, epoch: fields["epoch"],
+        source: fields["correctlyVotedSource"] == "true", target: fields["correctlyVotedTarget"] == "true",
+        head: fields["correctlyVotedHead"] == "true"}
+    a.distance, _ = strconv.ParseUint(fields["inclusionDistance"], 10, 64)
+    a.included = a.source || a.target || a.head || a.distance > 0
+}
+
+// summary totals the attestations read
+func (r *logReader) summary(client string) *types.MonitorSummary {
+    for _, m := range r.mismatches {
+        if a, ok := r.records[m[0]]; ok && !a.included {
+            continue // a missing attestation matched nothing anyway
+        }
+        validator, epoch, _ := strings.Cut(m[0], "/")
+        a := r.record(validator, epoch)
+        switch m[1] {
+        case "source":
+            a.source = false
+        case "target":
+            a.target = false
+        case "head":
+            a.head = false
+        }
+    }
+
+    summary := &types.MonitorSummary{Client: client, Format: "log"}
+    validators := make(map[string]bool)
+    var distance, distances uint64
+    for _, key := range r.order {
+        a := r.records[key]
+        validators[a.validator] = true
+        summary.Attestations++
+        if !a.included {
+            continue
+        }
+        summary.Included++
+        if a.source {
+            summary.TimelySource++
+        }
+        if a.target {
+            summary.TimelyTarget++
+        }
+        if a.head {
+            summary.TimelyHead++
+        }
+        if a.distance > 0 {
+            distance += a.distance
+            distances++
+        }
+    }
+    summary.Validators = len(validators)
+    if distances > 0 {
+        summary.AverageInclusionDistance = float64(distance) / float64(distances)
+    }
+    return summary
+}
+
+// logFields splits a log line into its message and fields. It reads JSON
+// logs, logrus text logs (key=value, as Prysm writes them), and terminal logs
+// with "key: value" fields (as Lighthouse writes them).
+func logFields(line string) (string, map[string]string, bool) {
+    line = strings.TrimSpace(colorCode.ReplaceAllString(line, ""))
+
+    if strings.HasPrefix(line, "{") {
+        var entry map[string]interface{}
+        if err := json.Unmarshal([]byte(line), &entry); err != nil {
+            return "", nil, false
+        }
+        msg, _ := entry["msg"].(string)
+        fields := make(map[string]string, len(entry))
+        for key, value := range entry {
+            if list, ok := value.([]interface{}); ok {
+                parts := make([]string, len(list))
+                for i, item := range list {
+                    parts[i] = fmt.Sprint(item)
+                }
+                fields[key] = strings.Join(parts, ",")
+            } else {
+                fields[key] = fmt.Sprint(value)
+            }
+        }
+        return msg, fields, msg != ""
+    }
+
+    if strings.Contains(line, " msg=") || strings.HasPrefix(line, "msg=") {
+        fields := logrusFields(line)
+        return fields["msg"], fields, fields["msg"] != ""
+    }
+
+    start := strings.Index(line, "Previous epoch ")
+    if start < 0 {
+        return "", nil, false
+    }
+    rest := line[start:]
+    loc := fieldStart.FindStringIndex(rest)
+    if loc == nil {
+        return strings.TrimSpace(rest), map[string]string{}, true
+    }
+    fields := make(map[string]string)
+    for _, part := range splitTopLevel(rest[loc[0]:]) {
+        key, value, found := strings.Cut(strings.TrimSpace(part), ": ")
+        if found {
+            fields[key] = strings.TrimSpace(value)
+        }
+    }
+    return strings.TrimSpace(rest[:loc[0]]), fields, true
+}
+
+// logrusFields reads the key=value pairs of a logrus text log line, where
+// values with spaces are quoted
+func logrusFields(line string) map[string]string {
+    fields := make(map[string]string)
+    for line != "" {
+        line = strings.TrimLeft(line, " ")
+        eq := strings.IndexByte(line, '=')
+        if eq < 0 {
+            break
+        }
+        key := line[:eq]
+        line = line[eq+1:]
+
+        var value string
+        if strings.HasPrefix(line, `"`) {
+            end := 1
+            for end < len(line) && (line[end] != '"' || line[end-1] == '\\') {
+                end++
+            }
+            if end >= len(line) {
+                end = len(line) - 1
+            }
+            quoted := line[:end+1]
+            if unquoted, err := strconv.Unquote(quoted); err == nil {
+                value = unquoted
+            } else {
+                value = strings.Trim(quoted, `"`)
+            }
+            line = line[end+1:]
+        } else {
+            value, line, _ = strings.Cut(line, " ")
+        }
+        fields[key] = value
+    }
+    return fields
+}
+
+// splitTopLevel splits fields on the commas outside brackets, so a list value
+// stays whole
+func splitTopLevel(s string) []string {
+    var parts []string
+    depth, start := 0, 0
+    for i, c := range s {
+        switch c {
+        case '[':
+            depth++
+        case ']':
+            depth--
+        case ',':
+            if depth == 0 {
+                parts = append(parts, s[start:i])
+                start = i + 1
+            }
+        }
+    }
+    return append(parts, s[start:])
+}
+
+// parseList reads a list of validators such as [1, 2], ["1", "2"], or 1,2
+func parseList(s string) []string {
+    var items []string
+    for _, item := range strings.FieldsFunc(strings.Trim(s, "[]"), func(r rune) bool { return r == ',' || r == ' ' }) {
+        if item = strings.Trim(item, `"`); item != "" {
+            items = append(items, item)
+        }
+    }
+    return items
+}
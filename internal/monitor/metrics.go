@@ -0,0 +1,210 @@
+package monitor
+
+import (
+    "bufio"
+    "bytes"
+    "strconv"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// sample is one line of Prometheus text exposition
+type sample struct {
+    name   string
+    labels map[string]string
+    value  float64
+}
+
+// Lighthouse's validator monitor counts, per validator, the previous epoch
+// attestations that were included or missed and the votes that were timely
+const lighthousePrefix = "validator_monitor_prev_epoch_on_chain_"
+
+// lighthouseMetrics summarizes a Lighthouse beacon node's validator monitor
+// metrics, or returns nil if there are none. The counters run from the node's
+// start, so they cover every epoch it has monitored.
+func lighthouseMetrics(samples []sample) *types.MonitorSummary {
+    totals := make(map[string]float64)
+    validators := make(map[string]bool)
+    var distance float64
+    var distances int
+    for _, s := range samples {
+        if !strings.HasPrefix(s.name, lighthousePrefix) {
+            continue
+        }
+        metric := strings.TrimSuffix(strings.TrimPrefix(s.name, lighthousePrefix), "_total")
+        validators[s.labels["validator"]] = true
+        if metric == "inclusion_distance" {
+            if s.value > 0 {
+                distance += s.value
+                distances++
+            }
+            continue
+        }
+        totals[metric] += s.value
+    }
+    if len(validators) == 0 {
+        return nil
+    }
+
+    summary := &types.MonitorSummary{
+        Client:       "lighthouse",
+        Format:       "metrics",
+        Validators:   len(validators),
+        Attestations: uint64(totals["attester_hit"] + totals["attester_miss"]),
+        Included:     uint64(totals["attester_hit"]),
+        TimelySource: uint64(totals["source_attester_hit"]),
+        TimelyTarget: uint64(totals["target_attester_hit"]),
+        TimelyHead:   uint64(totals["head_attester_hit"]),
+    }
+    if distances > 0 {
+        summary.AverageInclusionDistance = distance / float64(distances)
+    }
+    return summary
+}
+
+// prysmMetrics summarizes a Prysm validator client's per-validator vote
+// gauges, or returns nil if there are none. The gauges hold only the latest
+// epoch, so each validator counts one attestation.
+func prysmMetrics(samples []sample) *types.MonitorSummary {
+    type votes struct{ source, target, head, distance float64 }
+    byKey := make(map[string]*votes)
+    var order []string
+    for _, s := range samples {
+        var field *float64
+        pubkey := s.labels["pubkey"]
+        v, ok := byKey[pubkey]
+        if !ok {
+            v = &votes{}
+        }
+        switch s.name {
+        case "validator_correctly_voted_source":
+            field = &v.source
+        case "validator_correctly_voted_target":
+            field = &v.target
+        case "validator_correctly_voted_head":
+            field = &v.head
+        case "validator_inclusion_distance":
+            field = &v.distance
+        default:
+            continue
+        }
+        if !ok {
+            byKey[pubkey] = v
+            order = append(order, pubkey)
+        }
+        *field = s.value
+    }
+    if len(order) == 0 {
+        return nil
+    }
+
+    summary := &types.MonitorSummary{Client: "prysm", Format: "metrics", Validators: len(order)}
+    var distance float64
+    for _, pubkey := range order {
+        v := byKey[pubkey]
+        summary.Attestations++
+        if v.source == 0 && v.target == 0 && v.head == 0 && v.distance == 0 {
+            continue
+        }
+        summary.Included++
+        if v.source > 0 {
+            summary.TimelySource++
+        }
+        if v.target > 0 {
+            summary.TimelyTarget++
+        }
+        if v.head > 0 {
+            summary.TimelyHead++
+        }
+        distance += v.distance
+    }
+    if summary.Included > 0 && distance > 0 {
+        summary.AverageInclusionDistance = distance / float64(summary.Included)
+    }
+    return summary
+}
+
+// parseMetrics reads the samples of Prometheus text exposition, skipping
+// comments and anything that is not a sample line
+func parseMetrics(data []byte) []sample {
+    var samples []sample
+    scanner := bufio.NewScanner(bytes.NewReader(data))
+    scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+    for scanner.Scan() {
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+
+        s := sample{labels: make(map[string]string)}
+        rest := line
+        if open := strings.IndexByte(line, '{'); open > 0 {
+            end := strings.LastIndexByte(line, '}')
+            if end < open {
+                continue
+            }
+            s.name = line[:open]
+            for _, pair := range splitLabels(line[open+1 : end]) {
+                key, value, found := strings.Cut(pair, "=")
+                if unquoted, err := strconv.Unquote(strings.TrimSpace(value)); found && err == nil {
+                    s.labels[strings.TrimSpace(key)] = unquoted
+                }
+            }
+            rest = line[end+1:]
+        } else {
+            name, after, found := strings.Cut(line, " ")
+            if !found {
+                continue
+            }
+            s.name, rest = name, after
+        }
+
+        fields := strings.Fields(rest)
+        if len(fields) == 0 || !validMetricName(s.name) {
+            continue
+        }
+        value, err := strconv.ParseFloat(fields[0], 64)
+        if err != nil {
+            continue
+        }
+        s.value = value
+        samples = append(samples, s)
+    }
+    return samples
+}
+
+// splitLabels splits label pairs on the commas outside quoted values
+func splitLabels(s string) []string {
+    var pairs []string
+    quoted, start := false, 0
+    for i := 0; i < len(s); i++ {
+        switch s[i] {
+        case '\\':
+            i++ // skip the escaped character
+        case '"':
+            quoted = !quoted
+        case ',':
+            if !quoted {
+                pairs = append(pairs, s[start:i])
+                start = i + 1
+            }
+        }
+    }
+    if strings.TrimSpace(s[start:]) != "" {
+        pairs = append(pairs, s[start:])
+    }
+    return pairs
+}
+
+// validMetricName reports whether name is a Prometheus metric name, so log
+// lines are not taken for samples
+func validMetricName(name string) bool {
+    for i, c := range name {
+        letter := c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+        if !letter && (i == 0 || c < '0' || c > '9') {
+            return false
+        }
+    }
+    return name != ""
+}
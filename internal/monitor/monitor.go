@@ -0,0 +1,121 @@
+// Package monitor reads the attestation performance reported by Lighthouse's
+// validator monitor and Prysm's validator client, from their logs or
+// Prometheus metrics, so the effectiveness of the user's own validators can
+// be measured instead of guessed.
+package monitor
+
+import (
+    "bufio"
+    "bytes"
+    "fmt"
+    "io"
+    "net/http"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// attestation is one validator's attestation for one epoch as a log reported it
+type attestation struct {
+    validator string
+    epoch     string // empty if the log does not say
+    included  bool
+    source    bool
+    target    bool
+    head      bool
+    distance  uint64 // 0 if not reported
+}
+
+// Read summarizes the monitoring output at path: a log or metrics file, the
+// http(s) URL of a metrics endpoint, or - for stdin
+func Read(path string) (*types.MonitorSummary, error) {
+    var data []byte
+    var err error
+    switch {
+    case path == "-":
+        data, err = io.ReadAll(os.Stdin)
+    case strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://"):
+        data, err = fetch(path)
+    default:
+        data, err = os.ReadFile(path)
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    summary, err := Parse(data)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", path, err)
+    }
+    return summary, nil
+}
+
+// fetch reads a metrics endpoint
+func fetch(url string) ([]byte, error) {
+    client := &http.Client{Timeout: 30 * time.Second}
+    resp, err := client.Get(url)
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("%s returned %s", url, resp.Status)
+    }
+    return io.ReadAll(resp.Body)
+}
+
+// Parse detects whether data is Lighthouse or Prysm monitoring output, as
+// logs or Prometheus metrics, and summarizes it
+func Parse(data []byte) (*types.MonitorSummary, error) {
+    samples := parseMetrics(data)
+    if summary := lighthouseMetrics(samples); summary != nil {
+        return finish(summary)
+    }
+    if summary := prysmMetrics(samples); summary != nil {
+        return finish(summary)
+    }
+
+    var lighthouse, prysm logReader
+    scanner := bufio.NewScanner(bytes.NewReader(data))
+    scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+    for scanner.Scan() {
+        msg, fields, ok := logFields(scanner.Text())
+        if !ok {
+            continue
+        }
+        if !lighthouse.add(msg, fields) {
+            prysm.addPrysm(msg, fields)
+        }
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, err
+    }
+
+    switch {
+    case len(lighthouse.records) > 0 && len(prysm.records) > 0:
+        return nil, fmt.Errorf("both Lighthouse and Prysm attestation logs found; import one client at a time")
+    case len(lighthouse.records) > 0:
+        return finish(lighthouse.summary("lighthouse"))
+    case len(prysm.records) > 0:
+        return finish(prysm.summary("prysm"))
+    }
+    return nil, fmt.Errorf("no Lighthouse or Prysm attestation performance found")
+}
+
+// finish computes the effectiveness of a summary, rejecting one with no
+// attestations
+func finish(summary *types.MonitorSummary) (*types.MonitorSummary, error) {
+    if summary.Attestations == 0 {
+        return nil, fmt.Errorf("the %s %s report no attestations yet", summary.Client, summary.Format)
+    }
+
+    weight := float64(config.TIMELY_SOURCE_WEIGHT + config.TIMELY_TARGET_WEIGHT + config.TIMELY_HEAD_WEIGHT)
+    earned := float64(summary.TimelySource)*float64(config.TIMELY_SOURCE_WEIGHT) +
+        float64(summary.TimelyTarget)*float64(config.TIMELY_TARGET_WEIGHT) +
+        float64(summary.TimelyHead)*float64(config.TIMELY_HEAD_WEIGHT)
+    summary.Effectiveness = earned / (weight * float64(summary.Attestations))
+    return summary, nil
+}
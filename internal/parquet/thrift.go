@@ -0,0 +1,102 @@
+package parquet
+
+import (
+    "bytes"
+    "encoding/binary"
+)
+
+// Thrift compact protocol type codes
+const (
+    compactI32    = 5
+    compactI64    = 6
+    compactBinary = 8
+    compactList   = 9
+    compactStruct = 12
+)
+
+// thriftEncoder writes the Thrift compact protocol used by Parquet metadata.
+// The zero value is ready to encode a top-level struct.
+type thriftEncoder struct {
+    buf     bytes.Buffer
+    lastIDs []int16 // last field id written in each open struct
+}
+
+func (e *thriftEncoder) fieldHeader(id int16, typ byte) {
+    if len(e.lastIDs) == 0 {
+        e.lastIDs = append(e.lastIDs, 0)
+    }
+    last := &e.lastIDs[len(e.lastIDs)-1]
+
+    if delta := id - *last; delta > 0 && delta <= 15 {
+        e.buf.WriteByte(byte(delta)<<4 | typ)
+    } else {
+        e.buf.WriteByte(typ)
+        e.i32(int32(id))
+    }
+    *last = id
+}
+
+func (e *thriftEncoder) varint(v uint64) {
+    var tmp [binary.MaxVarintLen64]byte
+    e.buf.Write(tmp[:binary.PutUvarint(tmp[:], v)])
+}
+
+func (e *thriftEncoder) i32(v int32) {
+    e.varint(uint64(uint32((v << 1) ^ (v >> 31))))
+}
+
+func (e *thriftEncoder) i64(v int64) {
+    e.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (e *thriftEncoder) binary(s string) {
+    e.varint(uint64(len(s)))
+    e.buf.WriteString(s)
+}
+
+func (e *thriftEncoder) i32Field(id int16, v int32) {
+    e.fieldHeader(id, compactI32)
+    e.i32(v)
+}
+
+func (e *thriftEncoder) i64Field(id int16, v int64) {
+    e.fieldHeader(id, compactI64)
+    e.i64(v)
+}
+
+func (e *thriftEncoder) stringField(id int16, s string) {
+    e.fieldHeader(id, compactBinary)
+    e.binary(s)
+}
+
+// listField starts a list field; the caller then writes size elements
+func (e *thriftEncoder) listField(id int16, elemType byte, size int) {
+    e.fieldHeader(id, compactList)
+    if size < 15 {
+        e.buf.WriteByte(byte(size)<<4 | elemType)
+    } else {
+        e.buf.WriteByte(0xF0 | elemType)
+        e.varint(uint64(size))
+    }
+}
+
+// structField starts a nested struct field, closed with endStruct
+func (e *thriftEncoder) structField(id int16) {
+    e.fieldHeader(id, compactStruct)
+    e.beginStruct()
+}
+
+// beginStruct starts a struct that is a list element
+func (e *thriftEncoder) beginStruct() {
+    if len(e.lastIDs) == 0 {
+        e.lastIDs = append(e.lastIDs, 0)
+    }
+    e.lastIDs = append(e.lastIDs, 0)
+}
+
+func (e *thriftEncoder) endStruct() {
+    e.buf.WriteByte(0)
+    if len(e.lastIDs) > 0 {
+        e.lastIDs = e.lastIDs[:len(e.lastIDs)-1]
+    }
+}
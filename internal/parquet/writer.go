@@ -0,0 +1,228 @@
+// Package parquet writes flat result tables as Parquet files.
+//
+// Only what the calculator's sweep output needs is supported: a single row
+// group of required INT64, DOUBLE, BOOLEAN, and UTF8 string columns, PLAIN
+// encoded and uncompressed. Columns come from struct fields tagged
+// `parquet:"column_name"`.
+package parquet
+
+import (
+    "bytes"
+    "encoding/binary"
+    "fmt"
+    "io"
+    "math"
+    "reflect"
+)
+
+// Parquet physical types, encodings, and other enum values from parquet.thrift
+const (
+    typeBoolean   = 0
+    typeInt64     = 2
+    typeDouble    = 5
+    typeByteArray = 6
+
+    convertedUTF8 = 0
+    repRequired   = 0
+    encodingPlain = 0
+    encodingRLE   = 3
+    codecNone     = 0
+    pageData      = 0
+)
+
+var magic = []byte("PAR1")
+
+// column is one output column and its PLAIN-encoded values
+type column struct {
+    name  string
+    field int
+    typ   int32
+    data  bytes.Buffer
+}
+
+// Write encodes rows, a slice of structs, as a Parquet file
+func Write(w io.Writer, rows interface{}) error {
+    value := reflect.ValueOf(rows)
+    if value.Kind() != reflect.Slice || value.Type().Elem().Kind() != reflect.Struct {
+        return fmt.Errorf("parquet: rows must be a slice of structs, got %T", rows)
+    }
+
+    columns, err := schemaColumns(value.Type().Elem())
+    if err != nil {
+        return err
+    }
+
+    numRows := value.Len()
+    for _, col := range columns {
+        encodeColumn(col, value)
+    }
+
+    var file bytes.Buffer
+    file.Write(magic)
+
+    chunks := make([]chunkMeta, len(columns))
+    for i, col := range columns {
+        header := pageHeader(col.data.Len(), numRows)
+        offset := int64(file.Len())
+        file.Write(header)
+        file.Write(col.data.Bytes())
+        chunks[i] = chunkMeta{
+            offset: offset,
+            size:   int64(len(header) + col.data.Len()),
+        }
+    }
+
+    footer := fileMetaData(columns, chunks, numRows)
+    file.Write(footer)
+    binary.Write(&file, binary.LittleEndian, uint32(len(footer)))
+    file.Write(magic)
+
+    _, err = w.Write(file.Bytes())
+    return err
+}
+
+// schemaColumns maps the tagged fields of a struct type to columns
+func schemaColumns(rowType reflect.Type) ([]*column, error) {
+    var columns []*column
+    for i := 0; i < rowType.NumField(); i++ {
+        field := rowType.Field(i)
+        name := field.Tag.Get("parquet")
+        if name == "" || name == "-" {
+            continue
+        }
+
+        col := &column{name: name, field: i}
+        switch field.Type.Kind() {
+        case reflect.Int, reflect.Int64, reflect.Uint64:
+            col.typ = typeInt64
+        case reflect.Float64:
+            col.typ = typeDouble
+        case reflect.Bool:
+            col.typ = typeBoolean
+        case reflect.String:
+            col.typ = typeByteArray
+        default:
+            return nil, fmt.Errorf("parquet: unsupported type %s for column %s", field.Type, name)
+        }
+        columns = append(columns, col)
+    }
+
+    if len(columns) == 0 {
+        return nil, fmt.Errorf("parquet: %s has no tagged fields", rowType)
+    }
+    return columns, nil
+}
+
+// encodeColumn PLAIN-encodes one column of every row
+func encodeColumn(col *column, rows reflect.Value) {
+    var bits, nbits byte
+    for i := 0; i < rows.Len(); i++ {
+        field := rows.Index(i).Field(col.field)
+        switch col.typ {
+        case typeInt64:
+            var v int64
+            if field.Kind() == reflect.Uint64 {
+                v = int64(field.Uint())
+            } else {
+                v = field.Int()
+            }
+            binary.Write(&col.data, binary.LittleEndian, v)
+        case typeDouble:
+            binary.Write(&col.data, binary.LittleEndian, math.Float64bits(field.Float()))
+        case typeBoolean:
+            // Booleans are bit-packed, least significant bit first
+            if field.Bool() {
+                bits |= 1 << nbits
+            }
+            nbits++
+            if nbits == 8 {
+                col.data.WriteByte(bits)
+                bits, nbits = 0, 0
+            }
+        case typeByteArray:
+            binary.Write(&col.data, binary.LittleEndian, uint32(field.Len()))
+            col.data.WriteString(field.String())
+        }
+    }
+    if nbits > 0 {
+        col.data.WriteByte(bits)
+    }
+}
+
+// chunkMeta records where a column chunk landed in the file
+type chunkMeta struct {
+    offset int64
+    size   int64
+}
+
+func pageHeader(dataSize, numRows int) []byte {
+    var e thriftEncoder
+    e.i32Field(1, pageData)
+    e.i32Field(2, int32(dataSize))
+    e.i32Field(3, int32(dataSize))
+    e.structField(5)
+    e.i32Field(1, int32(numRows))
+    e.i32Field(2, encodingPlain)
+    e.i32Field(3, encodingRLE)
+    e.i32Field(4, encodingRLE)
+    e.endStruct()
+    e.endStruct()
+    return e.buf.Bytes()
+}
+
+func fileMetaData(columns []*column, chunks []chunkMeta, numRows int) []byte {
+    var e thriftEncoder
+    e.i32Field(1, 1)
+
+    // Schema: a root element followed by one leaf per column
+    e.listField(2, compactStruct, len(columns)+1)
+    e.beginStruct()
+    e.stringField(4, "schema")
+    e.i32Field(5, int32(len(columns)))
+    e.endStruct()
+    for _, col := range columns {
+        e.beginStruct()
+        e.i32Field(1, col.typ)
+        e.i32Field(3, repRequired)
+        e.stringField(4, col.name)
+        if col.typ == typeByteArray {
+            e.i32Field(6, convertedUTF8)
+        }
+        e.endStruct()
+    }
+
+    e.i64Field(3, int64(numRows))
+
+    var totalSize int64
+    for _, chunk := range chunks {
+        totalSize += chunk.size
+    }
+
+    e.listField(4, compactStruct, 1)
+    e.beginStruct()
+    e.listField(1, compactStruct, len(columns))
+    for i, col := range columns {
+        e.beginStruct()
+        e.i64Field(2, chunks[i].offset)
+        e.structField(3)
+        e.i32Field(1, col.typ)
+        e.listField(2, compactI32, 1)
+        e.i32(encodingPlain)
+        e.listField(3, compactBinary, 1)
+        e.binary(col.name)
+        e.i32Field(4, codecNone)
+        e.i64Field(5, int64(numRows))
+        e.i64Field(6, chunks[i].size)
+        e.i64Field(7, chunks[i].size)
+        e.i64Field(9, chunks[i].offset)
+        e.endStruct()
+        e.endStruct()
+    }
+    e.i64Field(2, totalSize)
+    e.i64Field(3, int64(numRows))
+    e.endStruct()
+
+    e.stringField(6, "eth-rewards-calculator")
+    e.endStruct()
+    return e.buf.Bytes()
+}
@@ -0,0 +1,66 @@
+package portfolio
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// depositData is one entry of a staking-deposit-cli deposit_data.json file
+type depositData struct {
+    Pubkey                string `json:"pubkey"`
+    WithdrawalCredentials string `json:"withdrawal_credentials"`
+    Amount                uint64 `json:"amount"`
+}
+
+// ReadDepositData reads the deposits in a staking-deposit-cli deposit_data.json
+// file, in file order. Deposits to the same pubkey are combined into one
+// validator, and a validator must receive enough to activate.
+func ReadDepositData(path string) ([]types.PortfolioValidator, error) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+
+    var deposits []depositData
+    if err := json.Unmarshal(data, &deposits); err != nil {
+        return nil, fmt.Errorf("%s: %v", path, err)
+    }
+    if len(deposits) == 0 {
+        return nil, fmt.Errorf("%s: no deposits found", path)
+    }
+
+    var validators []types.PortfolioValidator
+    positions := make(map[string]int)
+    for i, deposit := range deposits {
+        pubkey := normalizeHex(deposit.Pubkey)
+        if pubkey == "" {
+            return nil, fmt.Errorf("%s: deposit %d has no pubkey", path, i+1)
+        }
+        if deposit.Amount == 0 {
+            return nil, fmt.Errorf("%s: deposit %d has no amount", path, i+1)
+        }
+
+        if pos, ok := positions[pubkey]; ok {
+            validators[pos].Amount += deposit.Amount
+            continue
+        }
+        positions[pubkey] = len(validators)
+        validators = append(validators, types.PortfolioValidator{
+            Pubkey:                pubkey,
+            WithdrawalCredentials: normalizeHex(deposit.WithdrawalCredentials),
+            Amount:                deposit.Amount,
+        })
+    }
+
+    for _, v := range validators {
+        if v.Amount < config.MAX_EFFECTIVE_BALANCE {
+            return nil, fmt.Errorf("%s: validator %s receives %g ETH, below the %g ETH needed to activate",
+                path, v.Pubkey, float64(v.Amount)/1e9, float64(config.MAX_EFFECTIVE_BALANCE)/1e9)
+        }
+    }
+    return validators, nil
+}
@@ -0,0 +1,75 @@
+package portfolio
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "strings"
+    "testing"
+
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// writeFiles writes each file under dir, creating its directories
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+    t.Helper()
+    for name, content := range files {
+        path := filepath.Join(dir, name)
+        if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+            t.Fatal(err)
+        }
+        if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+            t.Fatal(err)
+        }
+    }
+}
+
+func TestReadDepositData(t *testing.T) {
+    tests := []struct {
+        name    string
+        data    string
+        want    []types.PortfolioValidator
+        wantErr string
+    }{
+        {"staking-deposit-cli output", `[
+            {"pubkey": "AA01", "withdrawal_credentials": "0x01BB", "amount": 32000000000, "deposit_cli_version": "2.7.0"},
+            {"pubkey": "0xaa02", "withdrawal_credentials": "00cc", "amount": 32000000000}
+        ]`, []types.PortfolioValidator{
+            {Pubkey: "0xaa01", WithdrawalCredentials: "0x01bb", Amount: 32_000_000_000},
+            {Pubkey: "0xaa02", WithdrawalCredentials: "0x00cc", Amount: 32_000_000_000},
+        }, ""},
+        {"top-ups combine", `[
+            {"pubkey": "0xaa01", "amount": 16000000000},
+            {"pubkey": "0xaa02", "amount": 2048000000000},
+            {"pubkey": "0xAA01", "amount": 16000000000}
+        ]`, []types.PortfolioValidator{
+            {Pubkey: "0xaa01", Amount: 32_000_000_000},
+            {Pubkey: "0xaa02", Amount: 2_048_000_000_000},
+        }, ""},
+        {"below activation", `[{"pubkey": "0xaa01", "amount": 31000000000}]`, nil, "below the 32 ETH needed to activate"},
+        {"no pubkey", `[{"pubkey": " ", "amount": 32000000000}]`, nil, "deposit 1 has no pubkey"},
+        {"no amount", `[{"pubkey": "0xaa01", "amount": 32000000000}, {"pubkey": "0xaa02"}]`, nil, "deposit 2 has no amount"},
+        {"empty", `[]`, nil, "no deposits found"},
+        {"not a list", `{"pubkey": "0xaa01"}`, nil, "cannot unmarshal"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            path := filepath.Join(t.TempDir(), "deposit_data.json")
+            writeFiles(t, filepath.Dir(path), map[string]string{"deposit_data.json": tt.data})
+            got, err := ReadDepositData(path)
+            if tt.wantErr != "" {
+                if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+                    t.Errorf("error %v, want one mentioning %q", err, tt.wantErr)
+                }
+                return
+            }
+            if err != nil || !reflect.DeepEqual(got, tt.want) {
+                t.Errorf("ReadDepositData = %+v, %v; want %+v", got, err, tt.want)
+            }
+        })
+    }
+
+    if _, err := ReadDepositData(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+        t.Error("missing file read")
+    }
+}
@@ -0,0 +1,77 @@
+package portfolio
+
+import (
+    "encoding/json"
+    "fmt"
+    "io/fs"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// keystore holds the fields that identify an EIP-2335 keystore file
+type keystore struct {
+    Version int             `json:"version"`
+    Pubkey  string          `json:"pubkey"`
+    Crypto  json.RawMessage `json:"crypto"`
+}
+
+// ScanKeystores finds the EIP-2335 validator keystores in dir and its
+// subdirectories, so both staking-deposit-cli's validator_keys layout and
+// clients that keep one directory per key are covered. Other JSON files, such
+// as deposit data, are skipped, and copies of a keystore with the same pubkey
+// count once. Each validator is assumed to hold a full effective balance.
+func ScanKeystores(dir string) ([]types.PortfolioValidator, error) {
+    var validators []types.PortfolioValidator
+    seen := make(map[string]bool)
+
+    err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+        if err != nil {
+            return err
+        }
+        if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+            return nil
+        }
+
+        data, err := os.ReadFile(path)
+        if err != nil {
+            return err
+        }
+        var ks keystore
+        if json.Unmarshal(data, &ks) != nil || ks.Version != 4 || len(ks.Crypto) == 0 {
+            return nil
+        }
+
+        pubkey := normalizeHex(ks.Pubkey)
+        if pubkey != "" {
+            if seen[pubkey] {
+                return nil
+            }
+            seen[pubkey] = true
+        }
+        validators = append(validators, types.PortfolioValidator{
+            Pubkey: pubkey,
+            Amount: config.MAX_EFFECTIVE_BALANCE,
+        })
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    if len(validators) == 0 {
+        return nil, fmt.Errorf("no EIP-2335 keystores found in %s", dir)
+    }
+    return validators, nil
+}
+
+// normalizeHex lowercases a hex value such as a pubkey and adds the 0x prefix
+func normalizeHex(value string) string {
+    value = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(value), "0x"))
+    if value == "" {
+        return ""
+    }
+    return "0x" + value
+}
@@ -0,0 +1,49 @@
+package portfolio
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestScanKeystores(t *testing.T) {
+    keystore := func(pubkey string) string {
+        return `{"version": 4, "pubkey": "` + pubkey + `", "crypto": {"kdf": {}, "checksum": {}, "cipher": {}}, "path": "m/12381/3600/0/0/0"}`
+    }
+    dir := t.TempDir()
+    writeFiles(t, dir, map[string]string{
+        // staking-deposit-cli's layout, beside its deposit data
+        "validator_keys/keystore-m_12381_3600_0_0_0-1700000000.json": keystore("aa01"),
+        "validator_keys/keystore-m_12381_3600_1_0_0-1700000000.json": keystore("0xAA02"),
+        "validator_keys/deposit_data-1700000000.json":                `[{"pubkey": "aa01", "amount": 32000000000}]`,
+        // a client's one directory per key, with a copy of a key above
+        "keys/0xaa02/voting-keystore.JSON": keystore("aa02"),
+        "keys/0xaa03/voting-keystore.json": keystore("aa03"),
+        // not keystores
+        "keys/0xaa03/slashing_protection.json": `{"metadata": {}, "data": []}`,
+        "keys/v3.json":                         `{"version": 3, "pubkey": "aa04", "crypto": {}}`,
+        "keys/broken.json":                     `{"version": 4,`,
+        "keys/notes.txt":                       keystore("aa05"),
+    })
+
+    got, err := ScanKeystores(dir)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var pubkeys []string
+    for _, v := range got {
+        if v.Amount != 32_000_000_000 {
+            t.Errorf("%s holds %d Gwei", v.Pubkey, v.Amount)
+        }
+        pubkeys = append(pubkeys, v.Pubkey)
+    }
+    if want := []string{"0xaa02", "0xaa03", "0xaa01"}; !reflect.DeepEqual(pubkeys, want) {
+        t.Errorf("found %v, want %v", pubkeys, want)
+    }
+
+    empty := t.TempDir()
+    writeFiles(t, empty, map[string]string{"deposit_data.json": `[]`})
+    if _, err := ScanKeystores(empty); err == nil || !strings.Contains(err.Error(), "no EIP-2335 keystores") {
+        t.Errorf("directory without keystores: %v", err)
+    }
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+    "sync"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// Cached wraps a StateProvider and reuses its answers for a TTL, so watch
+// loops, the server, and bots that ask for the same conditions many times
+// reach the source once per TTL. Errors are not cached. It is safe for
+// concurrent use; concurrent misses wait for a single request to the source.
+type Cached struct {
+    source StateProvider
+    ttl    time.Duration
+
+    mu         sync.Mutex
+    count      int
+    countAt    time.Time
+    snapshot   *types.NetworkSnapshot
+    snapshotAt time.Time
+}
+
+// NewCached caches source's answers for ttl
+func NewCached(source StateProvider, ttl time.Duration) *Cached {
+    return &Cached{source: source, ttl: ttl}
+}
+
+// ActiveValidators returns the cached count, or the count from a fresh
+// snapshot, while either is younger than the TTL
+func (c *Cached) ActiveValidators() (int, error) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    now := time.Now()
+    if c.snapshot != nil && now.Sub(c.snapshotAt) < c.ttl && !c.snapshotAt.Before(c.countAt) {
+        return c.snapshot.ActiveValidators, nil
+    }
+    if !c.countAt.IsZero() && now.Sub(c.countAt) < c.ttl {
+        return c.count, nil
+    }
+
+    count, err := c.source.ActiveValidators()
+    if err != nil {
+        return 0, err
+    }
+    c.count, c.countAt = count, now
+    return count, nil
+}
+
+// Snapshot returns the cached snapshot while it is younger than the TTL
+func (c *Cached) Snapshot() (*types.NetworkSnapshot, error) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    now := time.Now()
+    if c.snapshot == nil || now.Sub(c.snapshotAt) >= c.ttl {
+        snapshot, err := c.source.Snapshot()
+        if err != nil {
+            return nil, err
+        }
+        c.snapshot, c.snapshotAt = snapshot, now
+    }
+
+    // Callers may fill in fields such as the APY, so each gets its own copy
+    snapshot := *c.snapshot
+    return &snapshot, nil
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/url"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/beacon"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// Explorer reads the conditions from a block explorer with the beaconcha.in
+// v1 API, for users without a beacon node of their own
+type Explorer struct {
+    BaseURL    string
+    APIKey     string
+    HTTPClient *http.Client
+}
+
+// NewExplorer creates a provider for the explorer at baseURL (e.g.
+// https://beaconcha.in). apiKey may be empty for anonymous, rate-limited use.
+func NewExplorer(baseURL, apiKey string) *Explorer {
+    return &Explorer{
+        BaseURL:    strings.TrimRight(baseURL, "/"),
+        APIKey:     apiKey,
+        HTTPClient: &http.Client{Timeout: beacon.DefaultTimeout},
+    }
+}
+
+// explorerEpoch is the part of /api/v1/epoch/{epoch} the calculator uses
+type explorerEpoch struct {
+    Epoch                   uint64  `json:"epoch"`
+    ValidatorsCount         int     `json:"validatorscount"`
+    GlobalParticipationRate float64 `json:"globalparticipationrate"`
+}
+
+// explorerQueue is the response of /api/v1/validators/queue
+type explorerQueue struct {
+    Entering int `json:"beaconchain_entering"`
+    Exiting  int `json:"beaconchain_exiting"`
+}
+
+// ActiveValidators returns the validator count of the latest finalized epoch
+func (e *Explorer) ActiveValidators() (int, error) {
+    var epoch explorerEpoch
+    if err := e.get("/api/v1/epoch/finalized", &epoch); err != nil {
+        return 0, err
+    }
+    return epoch.ValidatorsCount, nil
+}
+
+// Snapshot returns the latest finalized epoch's validator count and
+// participation, and the current queue lengths
+func (e *Explorer) Snapshot() (*types.NetworkSnapshot, error) {
+    var epoch explorerEpoch
+    if err := e.get("/api/v1/epoch/finalized", &epoch); err != nil {
+        return nil, err
+    }
+    var queue explorerQueue
+    if err := e.get("/api/v1/validators/queue", &queue); err != nil {
+        return nil, err
+    }
+
+    return &types.NetworkSnapshot{
+        Epoch:            epoch.Epoch,
+        ActiveValidators: epoch.ValidatorsCount,
+        Participation:    epoch.GlobalParticipationRate,
+        ActivationQueue:  queue.Entering,
+        ExitQueue:        queue.Exiting,
+    }, nil
+}
+
+// get fetches path and decodes the "data" field of the response into out
+func (e *Explorer) get(path string, out interface{}) error {
+    target := e.BaseURL + path
+    if e.APIKey != "" {
+        target += "?apikey=" + url.QueryEscape(e.APIKey)
+    }
+
+    req, err := http.NewRequest(http.MethodGet, target, nil)
+    if err != nil {
+        return fmt.Errorf("creating request: %w", err)
+    }
+    req.Header.Set("Accept", "application/json")
+
+    resp, err := e.HTTPClient.Do(req)
+    if err != nil {
+        return fmt.Errorf("requesting %s: %w", path, err)
+    }
+    defer resp.Body.Close()
+
+    envelope := struct {
+        Status string      `json:"status"`
+        Data   interface{} `json:"data"`
+    }{Data: out}
+    if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+        if resp.StatusCode != http.StatusOK {
+            return fmt.Errorf("explorer returned %d for %s", resp.StatusCode, path)
+        }
+        return fmt.Errorf("decoding response from %s: %w", path, err)
+    }
+    if resp.StatusCode != http.StatusOK || envelope.Status != "OK" {
+        return fmt.Errorf("explorer returned %d for %s: %s", resp.StatusCode, path, envelope.Status)
+    }
+    return nil
+}
@@ -0,0 +1,201 @@
+package provider
+
+import (
+    "fmt"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// Endpoint is one source behind a Failover, named for messages
+type Endpoint struct {
+    Name   string
+    Source StateProvider
+}
+
+// Failover asks its endpoints in order of preference and moves on to the next
+// when one fails, so live modes keep working while an endpoint is down. A
+// failed request is retried with exponential backoff before the next endpoint
+// is tried. An endpoint that fails its retries is marked down and asked last
+// until its cooldown passes, after which the next request checks it again.
+// It is safe for concurrent use.
+type Failover struct {
+    Retries int           // extra attempts at an endpoint that is up
+    Backoff time.Duration // wait before the first retry, doubling after each
+
+    endpoints []*endpoint
+    mu        sync.Mutex
+    current   string
+}
+
+// endpoint is an Endpoint and its health
+type endpoint struct {
+    Endpoint
+    failures  int // consecutive
+    downUntil time.Time
+}
+
+// Cooldowns after an endpoint fails, doubling with each consecutive failure
+const (
+    minCooldown = 30 * time.Second
+    maxCooldown = 5 * time.Minute
+)
+
+// NewFailover creates a provider that prefers endpoints in the order given
+func NewFailover(endpoints []Endpoint, retries int, backoff time.Duration) *Failover {
+    f := &Failover{Retries: retries, Backoff: backoff}
+    for _, e := range endpoints {
+        f.endpoints = append(f.endpoints, &endpoint{Endpoint: e})
+    }
+    if len(endpoints) > 0 {
+        f.current = endpoints[0].Name
+    }
+    return f
+}
+
+// ActiveValidators returns the count from the first endpoint that answers
+func (f *Failover) ActiveValidators() (int, error) {
+    var count int
+    err := f.try(func(source StateProvider) (err error) {
+        count, err = source.ActiveValidators()
+        return err
+    })
+    return count, err
+}
+
+// Snapshot returns the snapshot from the first endpoint that answers
+func (f *Failover) Snapshot() (*types.NetworkSnapshot, error) {
+    var snapshot *types.NetworkSnapshot
+    err := f.try(func(source StateProvider) (err error) {
+        snapshot, err = source.Snapshot()
+        return err
+    })
+    return snapshot, err
+}
+
+// Current names the endpoint that answered last, or the preferred one before
+// any has
+func (f *Failover) Current() string {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    return f.current
+}
+
+// Check asks every endpoint for its validator count once, without retries,
+// and records which are up
+func (f *Failover) Check() []types.EndpointStatus {
+    statuses := make([]types.EndpointStatus, len(f.endpoints))
+    for i, e := range f.endpoints {
+        start := time.Now()
+        count, err := e.Source.ActiveValidators()
+        statuses[i] = types.EndpointStatus{
+            Name:      e.Name,
+            Healthy:   err == nil,
+            LatencyMs: float64(time.Since(start)) / float64(time.Millisecond),
+        }
+        if err != nil {
+            statuses[i].Error = err.Error()
+            f.markDown(e)
+            continue
+        }
+        statuses[i].ActiveValidators = count
+        f.markUp(e, false)
+    }
+    return statuses
+}
+
+// try calls request on each endpoint in turn until one succeeds. Endpoints
+// that are down are asked last, once each, so a request still goes through
+// when every endpoint failed recently.
+func (f *Failover) try(request func(StateProvider) error) error {
+    if len(f.endpoints) == 0 {
+        return fmt.Errorf("no endpoints configured")
+    }
+
+    var failures []string
+    var lastErr error
+    for _, e := range f.order() {
+        attempts := 1 + f.Retries
+        if f.isDown(e) {
+            attempts = 1
+        }
+
+        wait := f.Backoff
+        var err error
+        for attempt := 0; attempt < attempts; attempt++ {
+            if attempt > 0 {
+                time.Sleep(wait)
+                wait *= 2
+            }
+            if err = request(e.Source); err == nil {
+                break
+            }
+        }
+        if err == nil {
+            f.markUp(e, true)
+            return nil
+        }
+
+        f.markDown(e)
+        lastErr = err
+        failures = append(failures, fmt.Sprintf("%s: %v", e.Name, err))
+    }
+
+    // A lone endpoint's error needs no introduction
+    if len(f.endpoints) == 1 {
+        return lastErr
+    }
+    return fmt.Errorf("all %d endpoints failed (%s)", len(f.endpoints), strings.Join(failures, "; "))
+}
+
+// order lists the endpoints that are up, then those that are down, each in
+// order of preference
+func (f *Failover) order() []*endpoint {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    now := time.Now()
+    var up, down []*endpoint
+    for _, e := range f.endpoints {
+        if now.Before(e.downUntil) {
+            down = append(down, e)
+        } else {
+            up = append(up, e)
+        }
+    }
+    return append(up, down...)
+}
+
+func (f *Failover) isDown(e *endpoint) bool {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    return time.Now().Before(e.downUntil)
+}
+
+// markUp clears e's failures and, if it answered a request, makes it current
+func (f *Failover) markUp(e *endpoint, answered bool) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    e.failures = 0
+    e.downUntil = time.Time{}
+    if answered {
+        f.current = e.Name
+    }
+}
+
+// markDown rests e for a cooldown that doubles with each consecutive failure
+func (f *Failover) markDown(e *endpoint) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    e.failures++
+    cooldown := minCooldown
+    for i := 1; i < e.failures && cooldown < maxCooldown; i++ {
+        cooldown *= 2
+    }
+    if cooldown > maxCooldown {
+        cooldown = maxCooldown
+    }
+    e.downUntil = time.Now().Add(cooldown)
+}
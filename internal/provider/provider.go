@@ -0,0 +1,59 @@
+// Package provider supplies the live network conditions calculations start
+// from. The command line and the server ask a StateProvider rather than a
+// particular data source, so new sources plug in without touching the math.
+package provider
+
+import (
+    "github.com/eth-rewards-calculator/internal/beacon"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// StateProvider yields snapshots of a network's conditions. The snapshot's
+// validator count and participation become the NetworkState that rewards are
+// projected from.
+type StateProvider interface {
+    // ActiveValidators returns the current number of active validators. It may
+    // be cheaper than a full Snapshot.
+    ActiveValidators() (int, error)
+
+    // Snapshot returns the conditions at the latest finalized epoch
+    Snapshot() (*types.NetworkSnapshot, error)
+}
+
+// Static is a fixed snapshot, such as one given on the command line
+type Static struct {
+    State types.NetworkSnapshot
+}
+
+// ActiveValidators returns the snapshot's validator count
+func (s *Static) ActiveValidators() (int, error) {
+    return s.State.ActiveValidators, nil
+}
+
+// Snapshot returns a copy of the snapshot
+func (s *Static) Snapshot() (*types.NetworkSnapshot, error) {
+    snapshot := s.State
+    return &snapshot, nil
+}
+
+// BeaconNode reads the conditions from a beacon node's standard REST API
+type BeaconNode struct {
+    Client *beacon.Client
+}
+
+// NewBeaconNode creates a provider for the beacon node at baseURL
+func NewBeaconNode(baseURL string) *BeaconNode {
+    return &BeaconNode{Client: beacon.NewClient(baseURL)}
+}
+
+// ActiveValidators counts the active validators at the head
+func (b *BeaconNode) ActiveValidators() (int, error) {
+    return b.Client.GetActiveValidatorCount("head")
+}
+
+// Snapshot collects the conditions at the latest finalized epoch. Counting
+// participation fetches every validator's attestation rewards, so it is a
+// large request on mainnet.
+func (b *BeaconNode) Snapshot() (*types.NetworkSnapshot, error) {
+    return b.Client.GetNetworkSnapshot()
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+    "github.com/eth-rewards-calculator/internal/beacon"
+    "github.com/eth-rewards-calculator/internal/lightclient"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// Verified reads the conditions from a beacon node it does not trust,
+// checking them with the light-client protocol against a trusted checkpoint
+type Verified struct {
+    Client *lightclient.Client
+}
+
+// NewVerified creates a provider for the beacon node at baseURL that trusts
+// the block with root checkpoint
+func NewVerified(baseURL string, checkpoint [32]byte) *Verified {
+    return &Verified{Client: lightclient.New(beacon.NewClient(baseURL), checkpoint)}
+}
+
+// ActiveValidators counts the active validators in the verified finalized
+// state. It costs as much as a Snapshot.
+func (v *Verified) ActiveValidators() (int, error) {
+    snapshot, err := v.Client.Snapshot()
+    if err != nil {
+        return 0, err
+    }
+    return snapshot.ActiveValidators, nil
+}
+
+// Snapshot summarizes the verified finalized state
+func (v *Verified) Snapshot() (*types.NetworkSnapshot, error) {
+    return v.Client.Snapshot()
+}
@@ -0,0 +1,331 @@
+package publish
+
+import (
+    "bytes"
+    "encoding/binary"
+    "fmt"
+    "hash/crc32"
+    "io"
+    "net"
+    "strconv"
+    "time"
+)
+
+// Kafka API keys and the versions used: Metadata v4 and Produce v3 (the
+// first with record batches) are served by every broker since Kafka 1.0
+const (
+    kafkaProduce         = 0
+    kafkaMetadata        = 3
+    kafkaProduceVersion  = 3
+    kafkaMetadataVersion = 4
+)
+
+// Kafka error codes the publisher recovers from by asking for metadata again
+const (
+    kafkaUnknownTopic       = 3
+    kafkaLeaderNotAvailable = 5
+    kafkaNotLeader          = 6
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// kafkaPublisher produces each message to partition 0 of its topic, so a
+// network's results stay in order. Topics are created on first use if the
+// brokers allow it. The leader of each topic's partition is looked up from
+// the bootstrap brokers and kept until it fails.
+type kafkaPublisher struct {
+    brokers     []string
+    correlation int32
+    leaders     map[string]string   // topic to leader address
+    conns       map[string]net.Conn // by broker address
+}
+
+func (p *kafkaPublisher) Publish(topic string, message []byte) error {
+    var err error
+    for attempt := 0; attempt < 3; attempt++ {
+        if attempt > 0 {
+            time.Sleep(time.Duration(attempt) * 500 * time.Millisecond)
+        }
+        var leader string
+        if leader, err = p.leader(topic); err != nil {
+            continue
+        }
+        if err = p.produce(leader, topic, message); err == nil {
+            return nil
+        }
+        delete(p.leaders, topic)
+    }
+    return fmt.Errorf("publishing to Kafka topic %s: %w", topic, err)
+}
+
+func (p *kafkaPublisher) Close() error {
+    for address, conn := range p.conns {
+        conn.Close()
+        delete(p.conns, address)
+    }
+    return nil
+}
+
+// leader returns the address of the broker leading the topic's partition 0
+func (p *kafkaPublisher) leader(topic string) (string, error) {
+    if address, ok := p.leaders[topic]; ok {
+        return address, nil
+    }
+
+    var body kafkaEncoder
+    body.int32(1)
+    body.string(topic)
+    body.int8(1) // allow_auto_topic_creation
+
+    var err error
+    for _, broker := range p.brokers {
+        var response []byte
+        if response, err = p.roundTrip(broker, kafkaMetadata, kafkaMetadataVersion, body.Bytes()); err != nil {
+            continue
+        }
+        var address string
+        if address, err = parseMetadata(response, topic); err != nil {
+            continue
+        }
+        if p.leaders == nil {
+            p.leaders = make(map[string]string)
+        }
+        p.leaders[topic] = address
+        return address, nil
+    }
+    return "", err
+}
+
+// parseMetadata finds the leader of the topic's partition 0 in a Metadata v4
+// response
+func parseMetadata(response []byte, topic string) (string, error) {
+    d := kafkaDecoder{data: response}
+    d.int32() // throttle_time_ms
+    brokers := make(map[int32]string)
+    for i := d.int32(); i > 0 && d.err == nil; i-- {
+        id, host, port := d.int32(), d.string(), d.int32()
+        d.string() // rack
+        brokers[id] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+    }
+    d.string() // cluster_id
+    d.int32()  // controller_id
+
+    for i := d.int32(); i > 0 && d.err == nil; i-- {
+        code, name := d.int16(), d.string()
+        d.int8() // is_internal
+        leader, found := int32(-1), false
+        for j := d.int32(); j > 0 && d.err == nil; j-- {
+            partitionCode, index, partitionLeader := d.int16(), d.int32(), d.int32()
+            d.skipInt32Array() // replica_nodes
+            d.skipInt32Array() // isr_nodes
+            if index == 0 && partitionCode == 0 {
+                leader, found = partitionLeader, true
+            }
+        }
+        if d.err != nil || name != topic {
+            continue
+        }
+        if code != 0 {
+            return "", fmt.Errorf("metadata for %s: %s", topic, kafkaError(code))
+        }
+        if address, ok := brokers[leader]; found && ok {
+            return address, nil
+        }
+        return "", fmt.Errorf("metadata for %s: %s", topic, kafkaError(kafkaLeaderNotAvailable))
+    }
+    if d.err != nil {
+        return "", fmt.Errorf("metadata for %s: %w", topic, d.err)
+    }
+    return "", fmt.Errorf("metadata for %s: %s", topic, kafkaError(kafkaUnknownTopic))
+}
+
+// produce sends the message to the leader with acks from all in-sync
+// replicas and checks the partition's error code
+func (p *kafkaPublisher) produce(leader, topic string, message []byte) error {
+    batch := recordBatch(message, time.Now())
+
+    var body kafkaEncoder
+    body.int16(-1) // transactional_id: null
+    body.int16(-1) // acks: all in-sync replicas
+    body.int32(int32(timeout / time.Millisecond))
+    body.int32(1)
+    body.string(topic)
+    body.int32(1)
+    body.int32(0) // partition
+    body.int32(int32(len(batch)))
+    body.Write(batch)
+
+    response, err := p.roundTrip(leader, kafkaProduce, kafkaProduceVersion, body.Bytes())
+    if err != nil {
+        return err
+    }
+    d := kafkaDecoder{data: response}
+    for i := d.int32(); i > 0 && d.err == nil; i-- {
+        d.string() // name
+        for j := d.int32(); j > 0 && d.err == nil; j-- {
+            d.int32() // partition index
+            if code := d.int16(); code != 0 && d.err == nil {
+                return fmt.Errorf("%s", kafkaError(code))
+            }
+            d.int64() // base_offset
+            d.int64() // log_append_time_ms
+        }
+    }
+    return d.err
+}
+
+// recordBatch encodes one message as a record batch (magic 2) with no key
+func recordBatch(message []byte, at time.Time) []byte {
+    var record kafkaEncoder
+    record.int8(0)    // attributes
+    record.varint(0)  // timestamp delta
+    record.varint(0)  // offset delta
+    record.varint(-1) // key: null
+    record.varint(int64(len(message)))
+    record.Write(message)
+    record.varint(0) // headers
+
+    // Everything after the CRC, which covers it
+    var tail kafkaEncoder
+    tail.int16(0) // attributes: no compression
+    tail.int32(0) // last offset delta
+    tail.int64(at.UnixMilli())
+    tail.int64(at.UnixMilli())
+    tail.int64(-1) // producer id
+    tail.int16(-1) // producer epoch
+    tail.int32(-1) // base sequence
+    tail.int32(1)  // records
+    tail.varint(int64(record.Len()))
+    tail.Write(record.Bytes())
+
+    var batch kafkaEncoder
+    batch.int64(0)                             // base offset
+    batch.int32(int32(4 + 1 + 4 + tail.Len())) // length after this field
+    batch.int32(-1)                            // partition leader epoch
+    batch.int8(2)                              // magic
+    batch.int32(int32(crc32.Checksum(tail.Bytes(), castagnoli)))
+    batch.Write(tail.Bytes())
+    return batch.Bytes()
+}
+
+// roundTrip sends a request to a broker and returns the response body after
+// its correlation id, dropping the connection on any failure
+func (p *kafkaPublisher) roundTrip(address string, apiKey, version int16, body []byte) ([]byte, error) {
+    conn, ok := p.conns[address]
+    if !ok {
+        var err error
+        if conn, err = dial("tcp", address, timeout); err != nil {
+            return nil, err
+        }
+        if p.conns == nil {
+            p.conns = make(map[string]net.Conn)
+        }
+        p.conns[address] = conn
+    }
+    fail := func(err error) ([]byte, error) {
+        conn.Close()
+        delete(p.conns, address)
+        return nil, fmt.Errorf("%s: %w", address, err)
+    }
+
+    p.correlation++
+    var request kafkaEncoder
+    request.int16(apiKey)
+    request.int16(version)
+    request.int32(p.correlation)
+    request.string("eth-rewards") // client_id
+    request.Write(body)
+
+    conn.SetDeadline(time.Now().Add(timeout))
+    var size [4]byte
+    binary.BigEndian.PutUint32(size[:], uint32(request.Len()))
+    if _, err := conn.Write(append(size[:], request.Bytes()...)); err != nil {
+        return fail(err)
+    }
+    if _, err := io.ReadFull(conn, size[:]); err != nil {
+        return fail(err)
+    }
+    response := make([]byte, binary.BigEndian.Uint32(size[:]))
+    if _, err := io.ReadFull(conn, response); err != nil {
+        return fail(err)
+    }
+    if len(response) < 4 || int32(binary.BigEndian.Uint32(response)) != p.correlation {
+        return fail(fmt.Errorf("response out of order"))
+    }
+    return response[4:], nil
+}
+
+// kafkaError describes a Kafka error code
+func kafkaError(code int16) string {
+    switch code {
+    case kafkaUnknownTopic:
+        return "unknown topic (create it, or let the brokers auto-create topics)"
+    case kafkaLeaderNotAvailable:
+        return "leader not available"
+    case kafkaNotLeader:
+        return "broker is not the partition leader"
+    case 10:
+        return "message too large"
+    case 29:
+        return "not authorized for the topic"
+    }
+    return fmt.Sprintf("error code %d", code)
+}
+
+// kafkaEncoder writes the big-endian primitives of the Kafka protocol
+type kafkaEncoder struct {
+    bytes.Buffer
+}
+
+func (e *kafkaEncoder) int8(v int8)   { e.WriteByte(byte(v)) }
+func (e *kafkaEncoder) int16(v int16) { binary.Write(e, binary.BigEndian, v) }
+func (e *kafkaEncoder) int32(v int32) { binary.Write(e, binary.BigEndian, v) }
+func (e *kafkaEncoder) int64(v int64) { binary.Write(e, binary.BigEndian, v) }
+
+func (e *kafkaEncoder) string(s string) {
+    e.int16(int16(len(s)))
+    e.WriteString(s)
+}
+
+// varint writes a zigzag varint, as records use
+func (e *kafkaEncoder) varint(v int64) {
+    var tmp [binary.MaxVarintLen64]byte
+    e.Write(tmp[:binary.PutVarint(tmp[:], v)])
+}
+
+// kafkaDecoder reads a response, remembering the first error so callers can
+// check once at the end
+type kafkaDecoder struct {
+    data []byte
+    err  error
+}
+
+func (d *kafkaDecoder) next(n int) []byte {
+    if d.err != nil || n < 0 || n > len(d.data) {
+        d.err = io.ErrUnexpectedEOF
+        return make([]byte, 8) // enough for any integer read
+    }
+    b := d.data[:n]
+    d.data = d.data[n:]
+    return b
+}
+
+func (d *kafkaDecoder) int8() int8   { return int8(d.next(1)[0]) }
+func (d *kafkaDecoder) int16() int16 { return int16(binary.BigEndian.Uint16(d.next(2))) }
+func (d *kafkaDecoder) int32() int32 { return int32(binary.BigEndian.Uint32(d.next(4))) }
+func (d *kafkaDecoder) int64() int64 { return int64(binary.BigEndian.Uint64(d.next(8))) }
+
+// string reads a string, with a null one as ""
+func (d *kafkaDecoder) string() string {
+    n := d.int16()
+    if n < 0 {
+        return ""
+    }
+    return string(d.next(int(n)))
+}
+
+func (d *kafkaDecoder) skipInt32Array() {
+    if n := d.int32(); n > 0 {
+        d.next(4 * int(n))
+    }
+}
@@ -0,0 +1,164 @@
+package publish
+
+import (
+    "bytes"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "hash/crc32"
+    "io"
+    "strings"
+    "testing"
+    "time"
+)
+
+// hexBytes decodes hex laid out in parts, one per protocol field
+func hexBytes(t *testing.T, parts ...string) []byte {
+    t.Helper()
+    b, err := hex.DecodeString(strings.Join(parts, ""))
+    if err != nil {
+        t.Fatal(err)
+    }
+    return b
+}
+
+func TestCastagnoli(t *testing.T) {
+    // The CRC-32C check value; IEEE's table gives cbf43926
+    if got := crc32.Checksum([]byte("123456789"), castagnoli); got != 0xe3069283 {
+        t.Errorf("CRC-32C of 123456789 = %08x, want e3069283", got)
+    }
+}
+
+func TestRecordBatch(t *testing.T) {
+    // A record batch of one keyless record, field by field as the Kafka
+    // protocol guide lays it out
+    want := hexBytes(t,
+        "0000000000000000", // base offset
+        "0000003d",         // batch length: 61 bytes follow
+        "ffffffff",         // partition leader epoch
+        "02",               // magic
+        "e641a44b",         // CRC-32C of everything below
+        "0000",             // attributes
+        "00000000",         // last offset delta
+        "0000018bcfe56800", // first timestamp: 1700000000000 ms
+        "0000018bcfe56800", // max timestamp
+        "ffffffffffffffff", // producer id
+        "ffff",             // producer epoch
+        "ffffffff",         // base sequence
+        "00000001",         // records
+        "16",               // record length 11, zigzag
+        "00",               // attributes
+        "00",               // timestamp delta
+        "00",               // offset delta
+        "01",               // key length -1 (null), zigzag
+        "0a",               // value length 5, zigzag
+        "68656c6c6f",       // value: hello
+        "00",               // headers
+    )
+    got := recordBatch([]byte("hello"), time.UnixMilli(1_700_000_000_000))
+    if !bytes.Equal(got, want) {
+        t.Errorf("recordBatch =\n%x\nwant\n%x", got, want)
+    }
+
+    // The CRC covers the attributes onward
+    if crc := crc32.Checksum(got[21:], castagnoli); crc != 0xe641a44b {
+        t.Errorf("CRC of the batch tail = %08x", crc)
+    }
+
+    // The lengths follow the message
+    long := recordBatch(bytes.Repeat([]byte{'x'}, 200), time.UnixMilli(0))
+    d := kafkaDecoder{data: long}
+    d.int64()
+    if length := d.int32(); int(length) != len(long)-12 {
+        t.Errorf("batch length %d, want %d", length, len(long)-12)
+    }
+}
+
+func TestParseMetadata(t *testing.T) {
+    // A Metadata v4 response: two brokers, then a topic other than the one
+    // asked for and results.mainnet, whose partition 0 is led by broker 2
+    brokers := []string{
+        "00000000",           // throttle_time_ms
+        "00000002",           // brokers
+        "00000001",           // node_id
+        "00076b61666b612d31", // host: kafka-1
+        "00002384",           // port: 9092
+        "ffff",               // rack: null
+        "00000002",           // node_id
+        "00076b61666b612d32", // host: kafka-2
+        "00002384",           // port: 9092
+        "00027231",           // rack: r1
+        "000163",             // cluster_id: c
+        "00000001",           // controller_id
+    }
+    other := []string{
+        "0000",             // error_code
+        "00056f74686572",   // name: other
+        "00",               // is_internal
+        "00000001",         // partitions
+        "0000",             // error_code
+        "00000000",         // partition_index
+        "00000001",         // leader_id
+        "0000000100000001", // replica_nodes: [1]
+        "0000000100000001", // isr_nodes: [1]
+    }
+    results := []string{
+        "0000",                               // error_code
+        "000f726573756c74732e6d61696e6e6574", // name: results.mainnet
+        "00",                                 // is_internal
+        "00000002",                           // partitions
+        "0000",                               // error_code
+        "00000001",                           // partition_index
+        "00000001",                           // leader_id
+        "00000000",                           // replica_nodes: []
+        "00000000",                           // isr_nodes: []
+        "0000",                               // error_code
+        "00000000",                           // partition_index
+        "00000002",                           // leader_id
+        "000000020000000100000002",           // replica_nodes: [1, 2]
+        "0000000100000002",                   // isr_nodes: [2]
+    }
+    response := func(topics ...[]string) []byte {
+        parts := append([]string{}, brokers...)
+        parts = append(parts, fmt.Sprintf("%08x", len(topics)))
+        for _, topic := range topics {
+            parts = append(parts, topic...)
+        }
+        return hexBytes(t, parts...)
+    }
+    full := response(other, results)
+
+    // results.mainnet with a topic error, and with partition 0 leaderless
+    unknown := response(append([]string{"0003"}, results[1:]...))
+    leaderless := response(append(append([]string{}, results[:3]...),
+        "00000001", "0005", "00000000", "ffffffff", "00000000", "00000000"))
+
+    tests := []struct {
+        name     string
+        response []byte
+        topic    string
+        want     string
+        wantErr  string
+    }{
+        {"leader of partition 0", full, "results.mainnet", "kafka-2:9092", ""},
+        {"first topic", full, "other", "kafka-1:9092", ""},
+        {"topic not in the response", full, "missing", "", "unknown topic"},
+        {"topic error", unknown, "results.mainnet", "", "unknown topic"},
+        {"partition 0 without a leader", leaderless, "results.mainnet", "", "leader not available"},
+        {"no topics", response(), "results.mainnet", "", "unknown topic"},
+        {"truncated", full[:len(full)-6], "results.mainnet", "", io.ErrUnexpectedEOF.Error()},
+        {"empty", nil, "results.mainnet", "", io.ErrUnexpectedEOF.Error()},
+    }
+    for _, tt := range tests {
+        got, err := parseMetadata(tt.response, tt.topic)
+        switch {
+        case tt.wantErr == "" && (err != nil || got != tt.want):
+            t.Errorf("%s: parseMetadata = %q, %v; want %q", tt.name, got, err, tt.want)
+        case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+            t.Errorf("%s: error %v, want one mentioning %q", tt.name, err, tt.wantErr)
+        }
+    }
+    if _, err := parseMetadata(full[:10], "results.mainnet"); !errors.Is(err, io.ErrUnexpectedEOF) {
+        t.Errorf("truncated response: error %v does not wrap io.ErrUnexpectedEOF", err)
+    }
+}
@@ -0,0 +1,117 @@
+package publish
+
+import (
+    "bufio"
+    "encoding/json"
+    "fmt"
+    "net"
+    "net/url"
+    "strings"
+    "time"
+)
+
+// natsPublisher publishes over the NATS client protocol. Each message is
+// followed by a PING, and the server's PONG confirms it processed the PUB.
+type natsPublisher struct {
+    address string
+    user    *url.Userinfo // credentials, or a token as the user name alone
+
+    conn   net.Conn
+    reader *bufio.Reader
+}
+
+func (p *natsPublisher) Publish(topic string, message []byte) error {
+    if strings.ContainsAny(topic, " \t\r\n") {
+        return fmt.Errorf("invalid NATS subject %q", topic)
+    }
+    if p.conn == nil {
+        if err := p.connect(); err != nil {
+            return err
+        }
+    }
+
+    p.conn.SetDeadline(time.Now().Add(timeout))
+    _, err := fmt.Fprintf(p.conn, "PUB %s %d\r\n%s\r\nPING\r\n", topic, len(message), message)
+    if err == nil {
+        err = p.awaitPong()
+    }
+    if err != nil {
+        p.Close()
+        return fmt.Errorf("publishing to NATS at %s: %w", p.address, err)
+    }
+    return nil
+}
+
+// connect dials the server, reads its INFO, and sends CONNECT, confirming the
+// server accepted it
+func (p *natsPublisher) connect() error {
+    conn, err := dial("tcp", p.address, timeout)
+    if err != nil {
+        return fmt.Errorf("connecting to NATS: %w", err)
+    }
+    conn.SetDeadline(time.Now().Add(timeout))
+    p.conn, p.reader = conn, bufio.NewReader(conn)
+
+    line, err := p.reader.ReadString('\n')
+    if err != nil || !strings.HasPrefix(line, "INFO ") {
+        p.Close()
+        return fmt.Errorf("connecting to NATS at %s: no INFO from server", p.address)
+    }
+    var info struct {
+        TLSRequired bool `json:"tls_required"`
+    }
+    json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), &info)
+    if info.TLSRequired {
+        p.Close()
+        return fmt.Errorf("NATS server at %s requires TLS, which is not supported", p.address)
+    }
+
+    options := map[string]interface{}{"verbose": false, "pedantic": false, "name": "eth-rewards", "lang": "go"}
+    if p.user != nil {
+        if password, ok := p.user.Password(); ok {
+            options["user"], options["pass"] = p.user.Username(), password
+        } else {
+            options["auth_token"] = p.user.Username()
+        }
+    }
+    connect, _ := json.Marshal(options)
+    if _, err = fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", connect); err == nil {
+        err = p.awaitPong()
+    }
+    if err != nil {
+        p.Close()
+        return fmt.Errorf("connecting to NATS at %s: %w", p.address, err)
+    }
+    return nil
+}
+
+// awaitPong reads until the server's PONG, answering its PINGs and failing on
+// an -ERR
+func (p *natsPublisher) awaitPong() error {
+    for {
+        line, err := p.reader.ReadString('\n')
+        if err != nil {
+            return err
+        }
+        line = strings.TrimSpace(line)
+        switch {
+        case line == "PONG":
+            return nil
+        case line == "PING":
+            if _, err := p.conn.Write([]byte("PONG\r\n")); err != nil {
+                return err
+            }
+        case strings.HasPrefix(line, "-ERR"):
+            return fmt.Errorf("server error: %s", strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")), "'"))
+        }
+    }
+}
+
+func (p *natsPublisher) Close() error {
+    if p.conn == nil {
+        return nil
+    }
+    err := p.conn.Close()
+    p.conn, p.reader = nil, nil
+    return err
+}
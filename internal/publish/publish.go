@@ -0,0 +1,94 @@
+// Package publish sends calculation results to a message bus, NATS or Kafka,
+// so scheduled runs can feed an operator's own event pipelines. Each speaks
+// just enough of its protocol to publish a message and wait for the broker to
+// take it.
+package publish
+
+import (
+    "fmt"
+    "net"
+    "net/url"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// timeout bounds connecting to the broker and each exchange with it
+const timeout = 10 * time.Second
+
+// dial connects to a broker; tests replace it to talk to a fake one
+var dial = net.DialTimeout
+
+// Publisher sends messages to topics (NATS subjects or Kafka topics). A
+// message is published once Publish returns nil. After a failure the next
+// Publish connects again, so a long-running schedule survives broker restarts.
+type Publisher interface {
+    Publish(topic string, message []byte) error
+    Close() error
+}
+
+// Open returns a publisher for a bus URL: nats://[user:pass@]host[:port] or
+// kafka://host[:port][,host[:port]...]. Nothing is dialed until the first
+// Publish.
+func Open(busURL string) (Publisher, error) {
+    scheme, rest, ok := strings.Cut(busURL, "://")
+    if !ok || rest == "" {
+        return nil, fmt.Errorf("invalid bus URL %q (use nats://host:4222 or kafka://host:9092)", busURL)
+    }
+
+    switch scheme {
+    case "nats":
+        parsed, err := url.Parse(busURL)
+        if err != nil {
+            return nil, fmt.Errorf("invalid bus URL %q: %w", busURL, err)
+        }
+        if parsed.Host == "" {
+            return nil, fmt.Errorf("invalid bus URL %q (use nats://host:4222)", busURL)
+        }
+        return &natsPublisher{address: withPort(parsed.Host, "4222"), user: parsed.User}, nil
+    case "kafka":
+        // A URL has one host, so each broker of the list is parsed on its own
+        var brokers []string
+        for _, host := range strings.Split(strings.TrimSuffix(rest, "/"), ",") {
+            broker, err := kafkaBroker(host)
+            if err != nil {
+                return nil, fmt.Errorf("invalid bus URL %q: %w", busURL, err)
+            }
+            brokers = append(brokers, broker)
+        }
+        return &kafkaPublisher{brokers: brokers}, nil
+    }
+    return nil, fmt.Errorf("unknown bus %q (use nats:// or kafka://)", scheme)
+}
+
+// kafkaBroker checks one host[:port] of a Kafka broker list and returns its
+// address, with the default port if it has none
+func kafkaBroker(host string) (string, error) {
+    parsed, err := url.Parse("kafka://" + host)
+    if err != nil || parsed.Host == "" || parsed.Host != host {
+        return "", fmt.Errorf("broker %q is not host[:port]", host)
+    }
+    address := withPort(host, "9092")
+    _, port, err := net.SplitHostPort(address)
+    if err != nil {
+        return "", fmt.Errorf("broker %q: %w", host, err)
+    }
+    if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+        return "", fmt.Errorf("broker %q: invalid port %q", host, port)
+    }
+    return address, nil
+}
+
+// Topic names the topic for a network's results: the prefix and the network,
+// joined by a dot, which both NATS subjects and Kafka topic names allow
+func Topic(prefix, network string) string {
+    return prefix + "." + network
+}
+
+// withPort adds the default port to a host that has none
+func withPort(host, port string) string {
+    if _, _, err := net.SplitHostPort(host); err == nil {
+        return host
+    }
+    return net.JoinHostPort(strings.Trim(host, "[]"), port)
+}
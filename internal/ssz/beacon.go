@@ -0,0 +1,216 @@
+package ssz
+
+import (
+    "fmt"
+
+    "github.com/eth-rewards-calculator/internal/config"
+)
+
+// Beacon state list limits shared by the mainnet and minimal presets. The
+// sizes that differ come from the config when a layout is built, so a state
+// is encoded for the preset in use.
+const (
+    historicalRootsLimit   = 1 << 24
+    validatorRegistryLimit = 1 << 40
+    pendingDepositsLimit   = 1 << 27
+)
+
+// FarFutureEpoch marks an epoch that has not been scheduled
+const FarFutureEpoch = ^uint64(0)
+
+var (
+    Fork = Container{
+        {"previous_version", ByteVector(4)},
+        {"current_version", ByteVector(4)},
+        {"epoch", Uint64},
+    }
+
+    BeaconBlockHeader = Container{
+        {"slot", Uint64},
+        {"proposer_index", Uint64},
+        {"parent_root", Bytes32},
+        {"state_root", Bytes32},
+        {"body_root", Bytes32},
+    }
+
+    Eth1Data = Container{
+        {"deposit_root", Bytes32},
+        {"deposit_count", Uint64},
+        {"block_hash", Bytes32},
+    }
+
+    Validator = Container{
+        {"pubkey", ByteVector(48)},
+        {"withdrawal_credentials", Bytes32},
+        {"effective_balance", Uint64},
+        {"slashed", Boolean},
+        {"activation_eligibility_epoch", Uint64},
+        {"activation_epoch", Uint64},
+        {"exit_epoch", Uint64},
+        {"withdrawable_epoch", Uint64},
+    }
+
+    Checkpoint = Container{
+        {"epoch", Uint64},
+        {"root", Bytes32},
+    }
+
+    HistoricalSummary = Container{
+        {"block_summary_root", Bytes32},
+        {"state_summary_root", Bytes32},
+    }
+
+    PendingDeposit = Container{
+        {"pubkey", ByteVector(48)},
+        {"withdrawal_credentials", Bytes32},
+        {"amount", Uint64},
+        {"signature", ByteVector(96)},
+        {"slot", Uint64},
+    }
+
+    PendingPartialWithdrawal = Container{
+        {"validator_index", Uint64},
+        {"amount", Uint64},
+        {"withdrawable_epoch", Uint64},
+    }
+
+    PendingConsolidation = Container{
+        {"source_index", Uint64},
+        {"target_index", Uint64},
+    }
+)
+
+// SyncCommittee returns the sync committee layout, whose size depends on the
+// preset
+func SyncCommittee() Container {
+    return Container{
+        {"pubkeys", Vector{ByteVector(48), int(config.SYNC_COMMITTEE_SIZE)}},
+        {"aggregate_pubkey", ByteVector(48)},
+    }
+}
+
+// executionPayloadHeader is the header of the latest execution payload as of
+// fork, which grew a field or two with each fork after Bellatrix
+func executionPayloadHeader(fork string) Container {
+    header := Container{
+        {"parent_hash", Bytes32},
+        {"fee_recipient", ByteVector(20)},
+        {"state_root", Bytes32},
+        {"receipts_root", Bytes32},
+        {"logs_bloom", ByteVector(256)},
+        {"prev_randao", Bytes32},
+        {"block_number", Uint64},
+        {"gas_limit", Uint64},
+        {"gas_used", Uint64},
+        {"timestamp", Uint64},
+        {"extra_data", ByteList{32}},
+        {"base_fee_per_gas", Uint256},
+        {"block_hash", Bytes32},
+        {"transactions_root", Bytes32},
+    }
+    if forkIndex(fork) >= forkIndex("capella") {
+        header = append(header, Field{"withdrawals_root", Bytes32})
+    }
+    if forkIndex(fork) >= forkIndex("deneb") {
+        header = append(header, Field{"blob_gas_used", Uint64}, Field{"excess_blob_gas", Uint64})
+    }
+    return header
+}
+
+// stateForks are the forks BeaconState supports, oldest first
+var stateForks = []string{"altair", "bellatrix", "capella", "deneb", "electra", "fulu"}
+
+func forkIndex(fork string) int {
+    for i, f := range stateForks {
+        if f == fork {
+            return i
+        }
+    }
+    return -1
+}
+
+// BeaconState returns the beacon state layout as of fork, which must be
+// altair or later, with the vector sizes of the active preset
+func BeaconState(fork string) (Container, error) {
+    if forkIndex(fork) < 0 {
+        return nil, fmt.Errorf("no beacon state layout for fork %q (known: altair to fulu)", fork)
+    }
+
+    slotsPerHistoricalRoot := int(config.SLOTS_PER_HISTORICAL_ROOT)
+    eth1VotesLimit := int(config.EPOCHS_PER_ETH1_VOTING_PERIOD * config.SLOTS_PER_EPOCH)
+    syncCommittee := SyncCommittee()
+
+    state := Container{
+        {"genesis_time", Uint64},
+        {"genesis_validators_root", Bytes32},
+        {"slot", Uint64},
+        {"fork", Fork},
+        {"latest_block_header", BeaconBlockHeader},
+        {"block_roots", Vector{Bytes32, slotsPerHistoricalRoot}},
+        {"state_roots", Vector{Bytes32, slotsPerHistoricalRoot}},
+        {"historical_roots", List{Bytes32, historicalRootsLimit}},
+        {"eth1_data", Eth1Data},
+        {"eth1_data_votes", List{Eth1Data, eth1VotesLimit}},
+        {"eth1_deposit_index", Uint64},
+        {"validators", List{Validator, validatorRegistryLimit}},
+        {"balances", List{Uint64, validatorRegistryLimit}},
+        {"randao_mixes", Vector{Bytes32, int(config.EPOCHS_PER_HISTORICAL_VECTOR)}},
+        {"slashings", Vector{Uint64, int(config.EPOCHS_PER_SLASHINGS_VECTOR)}},
+        {"previous_epoch_participation", List{Uint8, validatorRegistryLimit}},
+        {"current_epoch_participation", List{Uint8, validatorRegistryLimit}},
+        {"justification_bits", Bitvector(4)},
+        {"previous_justified_checkpoint", Checkpoint},
+        {"current_justified_checkpoint", Checkpoint},
+        {"finalized_checkpoint", Checkpoint},
+        {"inactivity_scores", List{Uint64, validatorRegistryLimit}},
+        {"current_sync_committee", syncCommittee},
+        {"next_sync_committee", syncCommittee},
+    }
+    if forkIndex(fork) >= forkIndex("bellatrix") {
+        state = append(state, Field{"latest_execution_payload_header", executionPayloadHeader(fork)})
+    }
+    if forkIndex(fork) >= forkIndex("capella") {
+        state = append(state,
+            Field{"next_withdrawal_index", Uint64},
+            Field{"next_withdrawal_validator_index", Uint64},
+            Field{"historical_summaries", List{HistoricalSummary, historicalRootsLimit}},
+        )
+    }
+    if forkIndex(fork) >= forkIndex("electra") {
+        state = append(state,
+            Field{"deposit_requests_start_index", Uint64},
+            Field{"deposit_balance_to_consume", Uint64},
+            Field{"exit_balance_to_consume", Uint64},
+            Field{"earliest_exit_epoch", Uint64},
+            Field{"consolidation_balance_to_consume", Uint64},
+            Field{"earliest_consolidation_epoch", Uint64},
+            Field{"pending_deposits", List{PendingDeposit, pendingDepositsLimit}},
+            Field{"pending_partial_withdrawals", List{PendingPartialWithdrawal, int(config.PENDING_PARTIAL_WITHDRAWALS_LIMIT)}},
+            Field{"pending_consolidations", List{PendingConsolidation, int(config.PENDING_CONSOLIDATIONS_LIMIT)}},
+        )
+    }
+    if forkIndex(fork) >= forkIndex("fulu") {
+        state = append(state, Field{"proposer_lookahead", Vector{Uint64, int((config.MIN_SEED_LOOKAHEAD + 1) * config.SLOTS_PER_EPOCH)}})
+    }
+    return state, nil
+}
+
+// StateFork names the fork whose beacon state layout data has, the latest
+// if several fit. The fixed part of the state differs between forks, except
+// Capella and Deneb, whose execution payload headers differ instead.
+func StateFork(data []byte) (string, error) {
+    for i := len(stateForks) - 1; i >= 0; i-- {
+        state, _ := BeaconState(stateForks[i])
+        fields, err := state.Fields(data)
+        if err != nil {
+            continue
+        }
+        if j := state.Index("latest_execution_payload_header"); j >= 0 {
+            if _, err := executionPayloadHeader(stateForks[i]).Fields(fields[j]); err != nil {
+                continue
+            }
+        }
+        return stateForks[i], nil
+    }
+    return "", fmt.Errorf("%d bytes are not a beacon state of any fork from altair to fulu", len(data))
+}
@@ -0,0 +1,389 @@
+// Package ssz computes SimpleSerialize hash tree roots, the commitments that
+// beacon chain signatures and Merkle proofs are made over. Types describe a
+// serialized value so its root can be computed, and its fields read or
+// written, without decoding it into Go structs.
+package ssz
+
+import (
+    "crypto/sha256"
+    "encoding/binary"
+    "fmt"
+)
+
+// Type is an SSZ type
+type Type interface {
+    // FixedSize is the serialized size of every value, or 0 if it varies
+    FixedSize() int
+
+    // Root returns the hash tree root of a serialized value
+    Root(data []byte) ([32]byte, error)
+}
+
+// zeroHashes[i] is the root of a subtree of depth i holding only zero chunks
+var zeroHashes [65][32]byte
+
+func init() {
+    for i := 1; i < len(zeroHashes); i++ {
+        zeroHashes[i] = HashPair(zeroHashes[i-1], zeroHashes[i-1])
+    }
+}
+
+// HashPair hashes two roots into their parent, as in a Merkle tree
+func HashPair(a, b [32]byte) [32]byte {
+    var pair [64]byte
+    copy(pair[:32], a[:])
+    copy(pair[32:], b[:])
+    return sha256.Sum256(pair[:])
+}
+
+// Merkleize returns the root of a tree with room for limit chunks, of which
+// the first are chunks and the rest zero
+func Merkleize(chunks [][32]byte, limit int) ([32]byte, error) {
+    if len(chunks) > limit {
+        return [32]byte{}, fmt.Errorf("%d chunks exceed the limit of %d", len(chunks), limit)
+    }
+    depth := 0
+    for 1<<depth < limit {
+        depth++
+    }
+    if len(chunks) == 0 {
+        return zeroHashes[depth], nil
+    }
+
+    layer := make([][32]byte, len(chunks))
+    copy(layer, chunks)
+    for level := 0; level < depth; level++ {
+        if len(layer)%2 == 1 {
+            layer = append(layer, zeroHashes[level])
+        }
+        next := layer[:len(layer)/2]
+        for i := range next {
+            next[i] = HashPair(layer[2*i], layer[2*i+1])
+        }
+        layer = next
+    }
+    return layer[0], nil
+}
+
+// MixInLength combines a list's root with its length
+func MixInLength(root [32]byte, length int) [32]byte {
+    var chunk [32]byte
+    binary.LittleEndian.PutUint64(chunk[:], uint64(length))
+    return HashPair(root, chunk)
+}
+
+// pack splits data into chunks, zero-padding the last
+func pack(data []byte) [][32]byte {
+    chunks := make([][32]byte, (len(data)+31)/32)
+    for i := range chunks {
+        copy(chunks[i][:], data[i*32:])
+    }
+    return chunks
+}
+
+// VerifyBranch checks a Merkle proof that leaf sits at index among the
+// 2^len(branch) leaves of the tree with the given root
+func VerifyBranch(leaf [32]byte, branch [][32]byte, index uint64, root [32]byte) bool {
+    node := leaf
+    for i, sibling := range branch {
+        if index>>uint(i)&1 == 1 {
+            node = HashPair(sibling, node)
+        } else {
+            node = HashPair(node, sibling)
+        }
+    }
+    return node == root
+}
+
+// Uint is an unsigned integer of Size bytes
+type Uint struct {
+    Size int
+}
+
+var (
+    Uint8   = Uint{1}
+    Uint64  = Uint{8}
+    Uint256 = Uint{32}
+)
+
+func (u Uint) FixedSize() int { return u.Size }
+
+func (u Uint) Root(data []byte) ([32]byte, error) {
+    var chunk [32]byte
+    if len(data) != u.Size {
+        return chunk, fmt.Errorf("uint%d is %d bytes, not %d", u.Size*8, u.Size, len(data))
+    }
+    copy(chunk[:], data)
+    return chunk, nil
+}
+
+// Boolean is a single byte, 0 or 1
+var Boolean = Uint8
+
+// ByteVector is a fixed number of bytes, such as a root or a public key
+type ByteVector int
+
+// Bytes32 is a root or hash
+const Bytes32 ByteVector = 32
+
+func (v ByteVector) FixedSize() int { return int(v) }
+
+func (v ByteVector) Root(data []byte) ([32]byte, error) {
+    if len(data) != int(v) {
+        return [32]byte{}, fmt.Errorf("expected %d bytes, got %d", int(v), len(data))
+    }
+    return Merkleize(pack(data), (int(v)+31)/32)
+}
+
+// Bitvector is a fixed number of bits
+type Bitvector int
+
+func (v Bitvector) FixedSize() int { return (int(v) + 7) / 8 }
+
+func (v Bitvector) Root(data []byte) ([32]byte, error) {
+    if len(data) != v.FixedSize() {
+        return [32]byte{}, fmt.Errorf("bitvector of %d bits is %d bytes, not %d", int(v), v.FixedSize(), len(data))
+    }
+    return Merkleize(pack(data), (int(v)+255)/256)
+}
+
+// ByteList is up to Limit bytes
+type ByteList struct {
+    Limit int
+}
+
+func (l ByteList) FixedSize() int { return 0 }
+
+func (l ByteList) Root(data []byte) ([32]byte, error) {
+    if len(data) > l.Limit {
+        return [32]byte{}, fmt.Errorf("%d bytes exceed the limit of %d", len(data), l.Limit)
+    }
+    root, err := Merkleize(pack(data), (l.Limit+31)/32)
+    if err != nil {
+        return root, err
+    }
+    return MixInLength(root, len(data)), nil
+}
+
+// Vector is Length elements of a fixed-size type
+type Vector struct {
+    Elem   Type
+    Length int
+}
+
+func (v Vector) FixedSize() int { return v.Elem.FixedSize() * v.Length }
+
+func (v Vector) Root(data []byte) ([32]byte, error) {
+    if v.Elem.FixedSize() == 0 {
+        return [32]byte{}, fmt.Errorf("vectors of variable-size elements are not supported")
+    }
+    if len(data) != v.FixedSize() {
+        return [32]byte{}, fmt.Errorf("vector of %d elements is %d bytes, not %d", v.Length, v.FixedSize(), len(data))
+    }
+    return elementsRoot(v.Elem, data, v.Length)
+}
+
+// List is up to Limit elements of a fixed-size type
+type List struct {
+    Elem  Type
+    Limit int
+}
+
+func (l List) FixedSize() int { return 0 }
+
+func (l List) Root(data []byte) ([32]byte, error) {
+    size := l.Elem.FixedSize()
+    if size == 0 {
+        return [32]byte{}, fmt.Errorf("lists of variable-size elements are not supported")
+    }
+    if len(data)%size != 0 {
+        return [32]byte{}, fmt.Errorf("list of %d-byte elements has %d bytes", size, len(data))
+    }
+    length := len(data) / size
+    if length > l.Limit {
+        return [32]byte{}, fmt.Errorf("%d elements exceed the limit of %d", length, l.Limit)
+    }
+    root, err := elementsRoot(l.Elem, data, l.Limit)
+    if err != nil {
+        return root, err
+    }
+    return MixInLength(root, length), nil
+}
+
+// Elements splits a serialized list or vector into its elements
+func Elements(elem Type, data []byte) ([][]byte, error) {
+    size := elem.FixedSize()
+    if size == 0 || len(data)%size != 0 {
+        return nil, fmt.Errorf("cannot split %d bytes into %d-byte elements", len(data), size)
+    }
+    elements := make([][]byte, len(data)/size)
+    for i := range elements {
+        elements[i] = data[i*size : (i+1)*size]
+    }
+    return elements, nil
+}
+
+// elementsRoot merkleizes the elements of a list or vector with room for
+// limit of them. Integers are packed into chunks; other elements contribute
+// their roots.
+func elementsRoot(elem Type, data []byte, limit int) ([32]byte, error) {
+    if u, ok := elem.(Uint); ok {
+        return Merkleize(pack(data), (limit*u.Size+31)/32)
+    }
+
+    elements, err := Elements(elem, data)
+    if err != nil {
+        return [32]byte{}, err
+    }
+    roots := make([][32]byte, len(elements))
+    for i, element := range elements {
+        if roots[i], err = elem.Root(element); err != nil {
+            return roots[i], err
+        }
+    }
+    return Merkleize(roots, limit)
+}
+
+// Field is a named member of a Container
+type Field struct {
+    Name string
+    Type Type
+}
+
+// Container is a sequence of fields
+type Container []Field
+
+func (c Container) FixedSize() int {
+    size := 0
+    for _, field := range c {
+        if field.Type.FixedSize() == 0 {
+            return 0
+        }
+        size += field.Type.FixedSize()
+    }
+    return size
+}
+
+func (c Container) Root(data []byte) ([32]byte, error) {
+    fields, err := c.Fields(data)
+    if err != nil {
+        return [32]byte{}, err
+    }
+    roots := make([][32]byte, len(c))
+    for i, field := range c {
+        if roots[i], err = field.Type.Root(fields[i]); err != nil {
+            return roots[i], fmt.Errorf("%s: %w", field.Name, err)
+        }
+    }
+    return Merkleize(roots, len(c))
+}
+
+// Fields splits a serialized container into its fields' serialized values
+func (c Container) Fields(data []byte) ([][]byte, error) {
+    // Fixed-size fields sit in place; variable-size fields leave a 4-byte
+    // offset to their data, which follows the fixed part in field order
+    fields := make([][]byte, len(c))
+    var offsets []int
+    var variable []int
+    pos := 0
+    for i, field := range c {
+        size := field.Type.FixedSize()
+        if size == 0 {
+            size = 4
+        }
+        if pos+size > len(data) {
+            return nil, fmt.Errorf("%s: data ends at byte %d", field.Name, len(data))
+        }
+        if field.Type.FixedSize() == 0 {
+            offsets = append(offsets, int(binary.LittleEndian.Uint32(data[pos:])))
+            variable = append(variable, i)
+        } else {
+            fields[i] = data[pos : pos+size]
+        }
+        pos += size
+    }
+
+    if len(offsets) == 0 {
+        if pos != len(data) {
+            return nil, fmt.Errorf("%d bytes after the last field", len(data)-pos)
+        }
+        return fields, nil
+    }
+    if offsets[0] != pos {
+        return nil, fmt.Errorf("%s: first offset %d does not follow the fixed part at %d", c[variable[0]].Name, offsets[0], pos)
+    }
+    for j, i := range variable {
+        end := len(data)
+        if j+1 < len(offsets) {
+            end = offsets[j+1]
+        }
+        if offsets[j] > end || end > len(data) {
+            return nil, fmt.Errorf("%s: offsets out of order", c[i].Name)
+        }
+        fields[i] = data[offsets[j]:end]
+    }
+    return fields, nil
+}
+
+// Encode serializes a container from its fields' serialized values, the
+// inverse of Fields
+func (c Container) Encode(fields [][]byte) ([]byte, error) {
+    if len(fields) != len(c) {
+        return nil, fmt.Errorf("%d values for %d fields", len(fields), len(c))
+    }
+    fixed := 0
+    for i, field := range c {
+        size := field.Type.FixedSize()
+        if size == 0 {
+            size = 4
+        } else if len(fields[i]) != size {
+            return nil, fmt.Errorf("%s: %d bytes, not %d", field.Name, len(fields[i]), size)
+        }
+        fixed += size
+    }
+
+    data := make([]byte, 0, fixed)
+    offset := fixed
+    for i, field := range c {
+        if field.Type.FixedSize() == 0 {
+            data = binary.LittleEndian.AppendUint32(data, uint32(offset))
+            offset += len(fields[i])
+        } else {
+            data = append(data, fields[i]...)
+        }
+    }
+    for i, field := range c {
+        if field.Type.FixedSize() == 0 {
+            data = append(data, fields[i]...)
+        }
+    }
+    return data, nil
+}
+
+// Zero returns the serialized zero value of t: zero bytes for a fixed-size
+// type, and empty lists
+func Zero(t Type) []byte {
+    if size := t.FixedSize(); size > 0 {
+        return make([]byte, size)
+    }
+    c, ok := t.(Container)
+    if !ok {
+        return nil
+    }
+    fields := make([][]byte, len(c))
+    for i, field := range c {
+        fields[i] = Zero(field.Type)
+    }
+    data, _ := c.Encode(fields) // zero values always fit
+    return data
+}
+
+// Index returns the position of the named field, or -1
+func (c Container) Index(name string) int {
+    for i, field := range c {
+        if field.Name == name {
+            return i
+        }
+    }
+    return -1
+}
@@ -0,0 +1,252 @@
+package ssz
+
+import (
+    "bytes"
+    "encoding/binary"
+    "encoding/hex"
+    "testing"
+
+    "github.com/eth-rewards-calculator/internal/config"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+    t.Helper()
+    b, err := hex.DecodeString(s)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return b
+}
+
+func TestZeroHashes(t *testing.T) {
+    // The roots of empty subtrees, as the deposit contract and every client
+    // hard-code them
+    want := []string{
+        "0000000000000000000000000000000000000000000000000000000000000000",
+        "f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a92759fb4b",
+        "db56114e00fdd4c1f85c892bf35ac9a89289aaecb1ebd0a96cde606a748b5d71",
+        "c78009fdf07fc56a11f122370658a353aaa542ed63e44c4bc15ff4cd105ab33c",
+        "536d98837f2dd165a55d5eeae91485954472d56f246df256bf3cae19352a123c",
+    }
+    for i, root := range want {
+        if got := hex.EncodeToString(zeroHashes[i][:]); got != root {
+            t.Errorf("zeroHashes[%d] = %s, want %s", i, got, root)
+        }
+    }
+}
+
+func TestRoots(t *testing.T) {
+    // Mainnet's genesis block: a zero phase0 body over the genesis state root.
+    // The variable-size lists are empty, so stand-ins with the same limits
+    // have the same roots.
+    body := Container{
+        {"randao_reveal", ByteVector(96)},
+        {"eth1_data", Eth1Data},
+        {"graffiti", Bytes32},
+        {"proposer_slashings", List{Bytes32, 16}},
+        {"attester_slashings", List{Bytes32, 2}},
+        {"attestations", List{Bytes32, 128}},
+        {"deposits", List{Bytes32, 16}},
+        {"voluntary_exits", List{Bytes32, 16}},
+    }
+    bodyRoot, err := body.Root(Zero(body))
+    if err != nil {
+        t.Fatal(err)
+    }
+    header := make([]byte, 16+32)
+    header = append(header, mustHex(t, "7e76880eb67bbdc86250aa578958e9d0675e64e714337855204fb5abaaf82c2b")...)
+    header = append(header, bodyRoot[:]...)
+
+    tests := []struct {
+        name string
+        typ  Type
+        data []byte
+        want string
+    }{
+        {"mainnet genesis block", BeaconBlockHeader, header,
+            "4d611d5b93fdab69013a7f0a2f961caca0c853f87cfe9595fe50038163079360"},
+        {"empty deposit tree", List{Bytes32, 1 << 32}, nil,
+            "d70a234731285c6804c2a4f56711ddb8c82c99740f207854891028af34e27e5e"},
+        {"uint64", Uint64, []byte{1, 0, 0, 0, 0, 0, 0, 0},
+            "0100000000000000000000000000000000000000000000000000000000000000"},
+        {"empty byte list", ByteList{32}, nil,
+            "f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a92759fb4b"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            root, err := tt.typ.Root(tt.data)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got := hex.EncodeToString(root[:]); got != tt.want {
+                t.Errorf("root = %s, want %s", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestSyncCommitteeRoot(t *testing.T) {
+    saved := config.Save()
+    defer saved.Restore()
+    if err := config.ApplyPreset("minimal"); err != nil {
+        t.Fatal(err)
+    }
+
+    // hash_tree_root(SyncCommittee): the keys' vector root beside the
+    // aggregate key's root, each key two chunks with the second zero-padded
+    keyRoot := func(key []byte) [32]byte {
+        var second [32]byte
+        copy(second[:], key[32:])
+        return HashPair([32]byte(key[:32]), second)
+    }
+    var data []byte
+    var keyRoots [][32]byte
+    for i := 0; i < int(config.SYNC_COMMITTEE_SIZE)+1; i++ {
+        key := bytes.Repeat([]byte{byte(i + 1)}, 48)
+        data = append(data, key...)
+        keyRoots = append(keyRoots, keyRoot(key))
+    }
+    layer := keyRoots[:config.SYNC_COMMITTEE_SIZE]
+    for len(layer) > 1 {
+        next := make([][32]byte, len(layer)/2)
+        for i := range next {
+            next[i] = HashPair(layer[2*i], layer[2*i+1])
+        }
+        layer = next
+    }
+    want := HashPair(layer[0], keyRoots[config.SYNC_COMMITTEE_SIZE])
+
+    got, err := SyncCommittee().Root(data)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got != want {
+        t.Errorf("sync committee root = %x, want %x", got, want)
+    }
+    if _, err := SyncCommittee().Root(data[48:]); err == nil {
+        t.Error("a committee one key short was accepted")
+    }
+}
+
+func TestBeaconStateLayouts(t *testing.T) {
+    for _, fork := range stateForks {
+        t.Run(fork, func(t *testing.T) {
+            layout, err := BeaconState(fork)
+            if err != nil {
+                t.Fatal(err)
+            }
+            data := Zero(layout)
+            if detected, err := StateFork(data); err != nil || detected != fork {
+                t.Errorf("StateFork = %q, %v", detected, err)
+            }
+
+            // The root is the merkleized roots of the fields
+            fields, err := layout.Fields(data)
+            if err != nil {
+                t.Fatal(err)
+            }
+            roots := make([][32]byte, len(layout))
+            for i, field := range layout {
+                if roots[i], err = field.Type.Root(fields[i]); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            want, _ := Merkleize(roots, len(layout))
+            got, err := layout.Root(data)
+            if err != nil || got != want {
+                t.Errorf("root = %x, %v; want %x", got, err, want)
+            }
+
+            encoded, err := layout.Encode(fields)
+            if err != nil || !bytes.Equal(encoded, data) {
+                t.Errorf("Encode does not invert Fields: %v", err)
+            }
+        })
+    }
+    if _, err := BeaconState("phase0"); err == nil {
+        t.Error("phase0 has a layout")
+    }
+}
+
+func TestBeaconStatePreset(t *testing.T) {
+    saved := config.Save()
+    defer saved.Restore()
+
+    sizes := func() (slashings, blockRoots, committee int) {
+        layout, _ := BeaconState("electra")
+        return layout[layout.Index("slashings")].Type.FixedSize() / 8,
+            layout[layout.Index("block_roots")].Type.FixedSize() / 32,
+            layout[layout.Index("current_sync_committee")].Type.FixedSize()/48 - 1
+    }
+    if s, b, c := sizes(); s != 8192 || b != 8192 || c != 512 {
+        t.Errorf("mainnet sizes %d, %d, %d; want 8192, 8192, 512", s, b, c)
+    }
+    if err := config.ApplyPreset("minimal"); err != nil {
+        t.Fatal(err)
+    }
+    if s, b, c := sizes(); s != 64 || b != 64 || c != 32 {
+        t.Errorf("minimal sizes %d, %d, %d; want 64, 64, 32", s, b, c)
+    }
+}
+
+func TestVerifyBranch(t *testing.T) {
+    var leaves [][32]byte
+    for i := 0; i < 8; i++ {
+        var leaf [32]byte
+        binary.LittleEndian.PutUint64(leaf[:], uint64(i+1))
+        leaves = append(leaves, leaf)
+    }
+    root, _ := Merkleize(leaves, 8)
+
+    // The proof of leaf 5: its sibling 4, then the pair (6, 7), then 0-3
+    branch := [][32]byte{
+        leaves[4],
+        HashPair(leaves[6], leaves[7]),
+        HashPair(HashPair(leaves[0], leaves[1]), HashPair(leaves[2], leaves[3])),
+    }
+    if !VerifyBranch(leaves[5], branch, 5, root) {
+        t.Error("valid branch rejected")
+    }
+    if VerifyBranch(leaves[5], branch, 4, root) {
+        t.Error("branch accepted at the wrong index")
+    }
+    if VerifyBranch(leaves[4], branch, 5, root) {
+        t.Error("branch accepted for the wrong leaf")
+    }
+    branch[1][0] ^= 1
+    if VerifyBranch(leaves[5], branch, 5, root) {
+        t.Error("tampered branch accepted")
+    }
+}
+
+func TestFieldsErrors(t *testing.T) {
+    c := Container{{"a", Uint64}, {"b", ByteList{8}}, {"c", ByteList{8}}}
+    valid, err := c.Encode([][]byte{make([]byte, 8), {1, 2}, {3}})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if fields, err := c.Fields(valid); err != nil || !bytes.Equal(fields[1], []byte{1, 2}) || !bytes.Equal(fields[2], []byte{3}) {
+        t.Fatalf("Fields = %v, %v", fields, err)
+    }
+
+    tests := []struct {
+        name string
+        data []byte
+    }{
+        {"truncated", valid[:10]},
+        {"first offset past the fixed part", append(append(append([]byte{}, valid[:8]...), 17, 0, 0, 0), valid[12:]...)},
+        {"offsets out of order", append(append(append([]byte{}, valid[:12]...), 15, 0, 0, 0), valid[16:]...)},
+        {"offset past the end", append(append(append([]byte{}, valid[:12]...), 99, 0, 0, 0), valid[16:]...)},
+    }
+    for _, tt := range tests {
+        if _, err := c.Fields(tt.data); err == nil {
+            t.Errorf("%s: no error", tt.name)
+        }
+    }
+    if _, err := (Container{{"a", Uint64}}).Fields(make([]byte, 9)); err == nil {
+        t.Error("trailing byte accepted")
+    }
+    if _, err := (List{Uint64, 2}).Root(make([]byte, 24)); err == nil {
+        t.Error("list over its limit accepted")
+    }
+}
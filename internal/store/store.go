@@ -0,0 +1,134 @@
+// Package store records calculator runs in a SQLite database so projections
+// can be tracked over time as the network evolves.
+package store
+
+import (
+    "database/sql"
+    "encoding/json"
+    "fmt"
+    "time"
+
+    _ "github.com/mattn/go-sqlite3"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at TEXT NOT NULL,
+    command    TEXT NOT NULL,
+    args       TEXT NOT NULL,
+    inputs     TEXT NOT NULL,
+    results    TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS runs_command_created_at ON runs (command, created_at);
+`
+
+// Store is a SQLite database of recorded runs
+type Store struct {
+    db *sql.DB
+}
+
+// Open opens or creates the database at path
+func Open(path string) (*Store, error) {
+    db, err := sql.Open("sqlite3", path)
+    if err != nil {
+        return nil, fmt.Errorf("opening %s: %w", path, err)
+    }
+    if _, err := db.Exec(schema); err != nil {
+        db.Close()
+        return nil, fmt.Errorf("creating schema in %s: %w", path, err)
+    }
+    return &Store{db: db}, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+    return s.db.Close()
+}
+
+// Record saves a run's command, arguments, input values, and results.
+// Results are stored as JSON.
+func (s *Store) Record(command string, args []string, inputs map[string]string, results interface{}) (int64, error) {
+    argsJSON, err := json.Marshal(args)
+    if err != nil {
+        return 0, err
+    }
+    inputsJSON, err := json.Marshal(inputs)
+    if err != nil {
+        return 0, err
+    }
+    resultsJSON, err := json.Marshal(results)
+    if err != nil {
+        return 0, fmt.Errorf("encoding results: %w", err)
+    }
+
+    res, err := s.db.Exec(
+        `INSERT INTO runs (created_at, command, args, inputs, results) VALUES (?, ?, ?, ?, ?)`,
+        time.Now().UTC().Format(time.RFC3339Nano), command,
+        string(argsJSON), string(inputsJSON), string(resultsJSON))
+    if err != nil {
+        return 0, fmt.Errorf("recording run: %w", err)
+    }
+    return res.LastInsertId()
+}
+
+// Run is a single recorded invocation
+type Run struct {
+    ID        int64             `json:"id"`
+    CreatedAt time.Time         `json:"created_at"`
+    Command   string            `json:"command"`
+    Args      []string          `json:"args"`
+    Inputs    map[string]string `json:"inputs"`
+    Results   json.RawMessage   `json:"results"`
+}
+
+// Runs returns every recorded run, oldest first
+func (s *Store) Runs() ([]Run, error) {
+    rows, err := s.db.Query(`SELECT id, created_at, command, args, inputs, results FROM runs ORDER BY id`)
+    if err != nil {
+        return nil, fmt.Errorf("listing runs: %w", err)
+    }
+    defer rows.Close()
+
+    var runs []Run
+    for rows.Next() {
+        run, err := scanRun(rows)
+        if err != nil {
+            return nil, err
+        }
+        runs = append(runs, *run)
+    }
+    return runs, rows.Err()
+}
+
+// Run returns the run with the given ID
+func (s *Store) Run(id int64) (*Run, error) {
+    row := s.db.QueryRow(`SELECT id, created_at, command, args, inputs, results FROM runs WHERE id = ?`, id)
+    run, err := scanRun(row)
+    if err == sql.ErrNoRows {
+        return nil, fmt.Errorf("run %d not found", id)
+    }
+    return run, err
+}
+
+// scanRun decodes one row of the runs table
+func scanRun(row interface{ Scan(...interface{}) error }) (*Run, error) {
+    var run Run
+    var createdAt, args, inputs, results string
+    if err := row.Scan(&run.ID, &createdAt, &run.Command, &args, &inputs, &results); err != nil {
+        return nil, err
+    }
+
+    var err error
+    if run.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+        return nil, fmt.Errorf("run %d: %w", run.ID, err)
+    }
+    if err := json.Unmarshal([]byte(args), &run.Args); err != nil {
+        return nil, fmt.Errorf("run %d: %w", run.ID, err)
+    }
+    if err := json.Unmarshal([]byte(inputs), &run.Inputs); err != nil {
+        return nil, fmt.Errorf("run %d: %w", run.ID, err)
+    }
+    run.Results = json.RawMessage(results)
+    return &run, nil
+}
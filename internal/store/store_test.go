@@ -0,0 +1,80 @@
+package store
+
+import (
+    "encoding/json"
+    "path/filepath"
+    "reflect"
+    "testing"
+    "time"
+)
+
+func TestRecordAndRuns(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "runs.db")
+    s, err := Open(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    before := time.Now().UTC()
+    recorded := []struct {
+        command string
+        args    []string
+        inputs  map[string]string
+        results interface{}
+    }{
+        {"calculate", []string{"-v", "1000000"}, map[string]string{"validators": "1000000"}, map[string]float64{"apy": 2.84}},
+        {"forecast", []string{"forecast", "--months", "12"}, map[string]string{"months": "12"}, []int{1, 2, 3}},
+    }
+    for i, r := range recorded {
+        id, err := s.Record(r.command, r.args, r.inputs, r.results)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if id != int64(i+1) {
+            t.Errorf("run %d recorded with id %d", i+1, id)
+        }
+    }
+    if _, err := s.Record("calculate", nil, nil, func() {}); err == nil {
+        t.Error("results that cannot be encoded were recorded")
+    }
+    s.Close()
+
+    // The runs survive reopening the database
+    if s, err = Open(path); err != nil {
+        t.Fatal(err)
+    }
+    defer s.Close()
+    runs, err := s.Runs()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(runs) != len(recorded) {
+        t.Fatalf("%d runs, want %d", len(runs), len(recorded))
+    }
+    for i, run := range runs {
+        want := recorded[i]
+        results, _ := json.Marshal(want.results)
+        if run.ID != int64(i+1) || run.Command != want.command || !reflect.DeepEqual(run.Args, want.args) ||
+            !reflect.DeepEqual(run.Inputs, want.inputs) || string(run.Results) != string(results) {
+            t.Errorf("run %d = %+v, want %+v", i+1, run, want)
+        }
+        if run.CreatedAt.Before(before.Add(-time.Second)) || run.CreatedAt.After(time.Now().Add(time.Second)) {
+            t.Errorf("run %d created at %s", i+1, run.CreatedAt)
+        }
+    }
+
+    run, err := s.Run(2)
+    if err != nil || !reflect.DeepEqual(*run, runs[1]) {
+        t.Errorf("Run(2) = %+v, %v; want %+v", run, err, runs[1])
+    }
+    if _, err := s.Run(3); err == nil || err.Error() != "run 3 not found" {
+        t.Errorf("Run(3): %v", err)
+    }
+}
+
+func TestOpenErrors(t *testing.T) {
+    // A directory is not a database
+    if _, err := Open(t.TempDir()); err == nil {
+        t.Error("opened a directory")
+    }
+}
@@ -0,0 +1,84 @@
+// Package tracker follows a validator's duty outcomes epoch by epoch and
+// compares the rewards it realized with the ideal the calculator expects.
+package tracker
+
+import (
+    "fmt"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// Tracker accumulates duty outcomes for a single validator
+type Tracker struct {
+    summary types.PerformanceSummary
+
+    // Ideal rewards (Gwei) for a validator that performs every duty
+    idealAttestationPerEpoch int64
+    idealPerProposal         int64
+    idealSyncPerEpoch        int64
+}
+
+// New creates a tracker for validatorIndex. The ideal rewards come from the
+// calculator's model of state at full participation.
+func New(validatorIndex uint64, state *types.NetworkState) *Tracker {
+    results := calculator.CalculateRewards(state, 1.0)
+    syncPerSlot := calculator.CalculateSyncCommitteeReward(state, 1)
+
+    return &Tracker{
+        summary:                  types.PerformanceSummary{ValidatorIndex: validatorIndex},
+        idealAttestationPerEpoch: int64(results.AttestationRewardPerEpochGwei),
+        idealPerProposal:         int64(results.AvgProposerRewardPerBlockGwei),
+        idealSyncPerEpoch:        int64(syncPerSlot * config.SLOTS_PER_EPOCH),
+    }
+}
+
+// Add ingests the outcomes for a range of epochs, usually a single one, and
+// returns the updated summary. Ranges must arrive in epoch order.
+func (t *Tracker) Add(outcome *types.RealizedRewards) (types.PerformanceSummary, error) {
+    s := &t.summary
+    if outcome.ValidatorIndex != s.ValidatorIndex {
+        return *s, fmt.Errorf("outcome is for validator %d, tracking validator %d",
+            outcome.ValidatorIndex, s.ValidatorIndex)
+    }
+    if outcome.ToEpoch < outcome.FromEpoch {
+        return *s, fmt.Errorf("end epoch %d is before start epoch %d", outcome.ToEpoch, outcome.FromEpoch)
+    }
+    if s.Epochs > 0 && outcome.FromEpoch <= s.ToEpoch {
+        return *s, fmt.Errorf("epoch %d was already tracked", outcome.FromEpoch)
+    }
+
+    epochs := outcome.ToEpoch - outcome.FromEpoch + 1
+    if s.Epochs == 0 {
+        s.FromEpoch = outcome.FromEpoch
+    }
+    s.ToEpoch = outcome.ToEpoch
+    s.Epochs += epochs
+
+    s.RealizedAttestationGwei += outcome.AttestationRewardsGwei
+    s.IdealAttestationGwei += t.idealAttestationPerEpoch * int64(epochs)
+
+    // A missed proposal forfeits the whole expected block reward
+    s.ProposalsScheduled += outcome.ProposalsScheduled
+    s.ProposalsMade += outcome.ProposalsMade
+    s.RealizedProposalsGwei += outcome.BlockRewardsGwei
+    s.IdealProposalsGwei += t.idealPerProposal * int64(outcome.ProposalsScheduled)
+
+    s.SyncCommitteeEpochs += outcome.SyncCommitteeEpochs
+    s.RealizedSyncGwei += outcome.SyncCommitteeRewardsGwei
+    s.IdealSyncGwei += t.idealSyncPerEpoch * int64(outcome.SyncCommitteeEpochs)
+
+    s.RealizedTotalGwei = s.RealizedAttestationGwei + s.RealizedProposalsGwei + s.RealizedSyncGwei
+    s.IdealTotalGwei = s.IdealAttestationGwei + s.IdealProposalsGwei + s.IdealSyncGwei
+    if s.IdealTotalGwei > 0 {
+        s.Efficiency = float64(s.RealizedTotalGwei) / float64(s.IdealTotalGwei) * 100
+    }
+
+    return *s, nil
+}
+
+// Summary returns the performance over every epoch tracked so far
+func (t *Tracker) Summary() types.PerformanceSummary {
+    return t.summary
+}
@@ -0,0 +1,219 @@
+package types
+
+import (
+    "encoding/binary"
+    "encoding/hex"
+    "fmt"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/ssz"
+)
+
+// MarshalSSZ serializes the validator as the consensus Validator container.
+// An exit epoch of 0, which the calculator takes as no exit, becomes the far
+// future epoch, as does the withdrawable epoch with it. The inactivity score
+// is kept in the beacon state, not the validator record, so it is left out.
+func (v *Validator) MarshalSSZ() ([]byte, error) {
+    exit, withdrawable := v.ExitEpoch, v.WithdrawableEpoch
+    if exit == 0 {
+        exit = ssz.FarFutureEpoch
+        if withdrawable == 0 {
+            withdrawable = ssz.FarFutureEpoch
+        }
+    }
+
+    data := make([]byte, 0, ssz.Validator.FixedSize())
+    data = append(data, v.Pubkey[:]...)
+    data = append(data, v.WithdrawalCredentials[:]...)
+    data = binary.LittleEndian.AppendUint64(data, v.EffectiveBalance)
+    if v.Slashed {
+        data = append(data, 1)
+    } else {
+        data = append(data, 0)
+    }
+    data = binary.LittleEndian.AppendUint64(data, v.ActivationEligibilityEpoch)
+    data = binary.LittleEndian.AppendUint64(data, v.ActivationEpoch)
+    data = binary.LittleEndian.AppendUint64(data, exit)
+    data = binary.LittleEndian.AppendUint64(data, withdrawable)
+    return data, nil
+}
+
+// UnmarshalSSZ reads a consensus Validator container, leaving the inactivity
+// score zero
+func (v *Validator) UnmarshalSSZ(data []byte) error {
+    fields, err := ssz.Validator.Fields(data)
+    if err != nil {
+        return err
+    }
+    field := func(name string) []byte { return fields[ssz.Validator.Index(name)] }
+    if slashed := field("slashed")[0]; slashed > 1 {
+        return fmt.Errorf("slashed flag is %d, not 0 or 1", slashed)
+    }
+
+    *v = Validator{
+        EffectiveBalance:           binary.LittleEndian.Uint64(field("effective_balance")),
+        Slashed:                    field("slashed")[0] == 1,
+        ActivationEligibilityEpoch: binary.LittleEndian.Uint64(field("activation_eligibility_epoch")),
+        ActivationEpoch:            binary.LittleEndian.Uint64(field("activation_epoch")),
+        ExitEpoch:                  binary.LittleEndian.Uint64(field("exit_epoch")),
+        WithdrawableEpoch:          binary.LittleEndian.Uint64(field("withdrawable_epoch")),
+    }
+    copy(v.Pubkey[:], field("pubkey"))
+    copy(v.WithdrawalCredentials[:], field("withdrawal_credentials"))
+    return nil
+}
+
+// HashTreeRoot returns the root of the validator's Validator container, as
+// the beacon state commits to it
+func (v *Validator) HashTreeRoot() ([32]byte, error) {
+    data, err := v.MarshalSSZ()
+    if err != nil {
+        return [32]byte{}, err
+    }
+    return ssz.Validator.Root(data)
+}
+
+// MarshalSSZ serializes the state as a BeaconState of its fork (Electra if
+// unset), so consensus tooling can read it. Only what the calculator models
+// is filled in: the validators as the whole registry, their effective
+// balances as balances, their inactivity scores and participation flags, the
+// slashings, and the epochs. Roots, committees, and everything else are zero,
+// so no node would accept the result as a real chain's state.
+func (s *NetworkState) MarshalSSZ() ([]byte, error) {
+    fork := s.CurrentFork
+    switch fork {
+    case "":
+        fork = "electra"
+    case "merge":
+        fork = "bellatrix"
+    }
+    layout, err := ssz.BeaconState(fork)
+    if err != nil {
+        return nil, err
+    }
+    version, err := hex.DecodeString(strings.TrimPrefix(config.ForkVersion(fork), "0x"))
+    if err != nil || len(version) != 4 {
+        return nil, fmt.Errorf("no fork version for %s", fork)
+    }
+
+    var validators, balances, scores []byte
+    for i := range s.Validators {
+        data, err := s.Validators[i].MarshalSSZ()
+        if err != nil {
+            return nil, fmt.Errorf("validator %d: %w", i, err)
+        }
+        validators = append(validators, data...)
+        balances = binary.LittleEndian.AppendUint64(balances, s.Validators[i].EffectiveBalance)
+        scores = binary.LittleEndian.AppendUint64(scores, s.Validators[i].InactivityScore)
+    }
+
+    // Nil participation means every validator was timely
+    previous := s.PreviousEpochParticipation
+    if previous == nil {
+        timely := uint8(1<<config.TIMELY_SOURCE_FLAG_INDEX | 1<<config.TIMELY_TARGET_FLAG_INDEX | 1<<config.TIMELY_HEAD_FLAG_INDEX)
+        previous = make([]uint8, len(s.Validators))
+        for i := range previous {
+            previous[i] = timely
+        }
+    }
+    slashings := ssz.Zero(layout[layout.Index("slashings")].Type)
+    if s.SlashingsPerEpoch != nil {
+        slashings = nil
+        for _, amount := range s.SlashingsPerEpoch {
+            slashings = binary.LittleEndian.AppendUint64(slashings, amount)
+        }
+    }
+    checkpoint := func(epoch uint64) []byte {
+        return append(binary.LittleEndian.AppendUint64(nil, epoch), make([]byte, 32)...) // zero root
+    }
+
+    values := map[string][]byte{
+        "slot":                         binary.LittleEndian.AppendUint64(nil, s.CurrentEpoch*config.SLOTS_PER_EPOCH),
+        "fork":                         append(append(append([]byte{}, version...), version...), make([]byte, 8)...),
+        "validators":                   validators,
+        "balances":                     balances,
+        "slashings":                    slashings,
+        "previous_epoch_participation": previous,
+        "current_epoch_participation":  make([]byte, len(s.Validators)),
+        "current_justified_checkpoint": checkpoint(s.JustifiedEpoch),
+        "finalized_checkpoint":         checkpoint(s.FinalizedEpoch),
+        "inactivity_scores":            scores,
+    }
+    fields := make([][]byte, len(layout))
+    for i, field := range layout {
+        if value, ok := values[field.Name]; ok {
+            fields[i] = value
+        } else {
+            fields[i] = ssz.Zero(field.Type)
+        }
+    }
+    return layout.Encode(fields)
+}
+
+// UnmarshalSSZ loads a BeaconState of any fork from Altair through Fulu, as
+// a node's debug API or consensus tooling dumps it. Like the calculator's
+// other states, it keeps only the validators active at the state's epoch,
+// with their previous epoch participation flags and inactivity scores. Forks
+// after Electra kept its reward and penalty parameters, so they load as
+// Electra.
+func (s *NetworkState) UnmarshalSSZ(data []byte) error {
+    fork, err := ssz.StateFork(data)
+    if err != nil {
+        return err
+    }
+    layout, _ := ssz.BeaconState(fork)
+    fields, err := layout.Fields(data)
+    if err != nil {
+        return err
+    }
+    field := func(name string) []byte { return fields[layout.Index(name)] }
+
+    epoch := binary.LittleEndian.Uint64(field("slot")) / config.SLOTS_PER_EPOCH
+    state := NetworkState{
+        CurrentEpoch:   epoch,
+        FinalizedEpoch: binary.LittleEndian.Uint64(field("finalized_checkpoint")),
+        JustifiedEpoch: binary.LittleEndian.Uint64(field("current_justified_checkpoint")),
+        CurrentFork:    fork,
+    }
+    if !config.IsKnownFork(fork) {
+        state.CurrentFork = "electra"
+    }
+
+    slashings, err := ssz.Elements(ssz.Uint64, field("slashings"))
+    if err != nil {
+        return fmt.Errorf("slashings: %w", err)
+    }
+    for _, amount := range slashings {
+        state.SlashingsPerEpoch = append(state.SlashingsPerEpoch, binary.LittleEndian.Uint64(amount))
+    }
+
+    validators, err := ssz.Elements(ssz.Validator, field("validators"))
+    if err != nil {
+        return fmt.Errorf("validators: %w", err)
+    }
+    flags := field("previous_epoch_participation")
+    scores, err := ssz.Elements(ssz.Uint64, field("inactivity_scores"))
+    if err != nil || len(flags) != len(validators) || len(scores) != len(validators) {
+        return fmt.Errorf("participation flags and inactivity scores do not match the %d validators", len(validators))
+    }
+
+    for i, raw := range validators {
+        var validator Validator
+        if err := validator.UnmarshalSSZ(raw); err != nil {
+            return fmt.Errorf("validator %d: %w", i, err)
+        }
+        if validator.ActivationEpoch > epoch || epoch >= validator.ExitEpoch {
+            continue
+        }
+        validator.InactivityScore = binary.LittleEndian.Uint64(scores[i])
+        state.Validators = append(state.Validators, validator)
+        state.PreviousEpochParticipation = append(state.PreviousEpochParticipation, flags[i])
+        state.TotalActiveBalance += validator.EffectiveBalance
+    }
+    if len(state.Validators) == 0 {
+        return fmt.Errorf("no active validators in the beacon state")
+    }
+    *s = state
+    return nil
+}
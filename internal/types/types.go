@@ -1,5 +1,10 @@
 package types
 
+import (
+    "strings"
+    "time"
+)
+
 // Validator represents a single validator in the network
 type Validator struct {
     // Core fields
@@ -32,48 +37,112 @@ type NetworkState struct {
     // Fork information
     CurrentFork        string      `json:"current_fork"`
     
-    // Slashing tracking
+    // Slashing tracking: the balance slashed in each epoch, indexed by epoch
+    // modulo EPOCHS_PER_SLASHINGS_VECTOR as in the beacon state
     SlashingsPerEpoch  []uint64    `json:"slashings_per_epoch,omitempty"`
+    
+    // Participation flags per validator for the previous epoch (bit i is set
+    // when flag index i was earned). Nil means every validator was timely.
+    PreviousEpochParticipation []uint8 `json:"previous_epoch_participation,omitempty"`
+}
+
+// StateFile is a NetworkState saved by state export, with where and when it
+// was captured so a later analysis can be reproduced
+type StateFile struct {
+    Version    int          `json:"version"`
+    CapturedAt time.Time    `json:"captured_at"`
+    Source     string       `json:"source"`
+    State      NetworkState `json:"state"`
+}
+
+// EpochDelta is one validator's balance change from an epoch transition's
+// rewards and penalties (Gwei, negative for penalties)
+type EpochDelta struct {
+    ValidatorIndex int   `json:"validator_index"`
+    Source         int64 `json:"source"`
+    Target         int64 `json:"target"`
+    Head           int64 `json:"head"`
+    Inactivity     int64 `json:"inactivity"`
 }
 
-// RewardResults contains all calculated reward information
+// Net returns the validator's total balance change
+func (d EpochDelta) Net() int64 {
+    return d.Source + d.Target + d.Head + d.Inactivity
+}
+
+// RewardResults contains all calculated reward information. Amounts end in
+// Gwei, both in Go and in JSON; rates and probabilities are fractions
+// (0.0-1.0), and APYs and yields are percentages.
 type RewardResults struct {
     // Input parameters
-    ValidatorCount     int         `json:"validator_count"`
-    TotalStaked       uint64      `json:"total_staked_gwei"`
+    ValidatorCount    int         `json:"validator_count"`
+    TotalStakedGwei   uint64      `json:"total_staked_gwei"`
     ParticipationRate float64     `json:"participation_rate"`
     
     // Base calculations
-    SqrtTotalBalance   uint64      `json:"sqrt_total_balance"`
-    BaseRewardPerEpoch uint64      `json:"base_reward_per_epoch"`
+    SqrtTotalBalance       uint64  `json:"sqrt_total_balance"` // square root of the Gwei total
+    BaseRewardPerIncrementGwei uint64 `json:"base_reward_per_increment_gwei"`
+    BaseRewardPerEpochGwei uint64  `json:"base_reward_per_epoch_gwei"`
     
     // Component rewards (per epoch)
-    SourceReward       uint64      `json:"source_reward"`
-    TargetReward       uint64      `json:"target_reward"`
-    HeadReward         uint64      `json:"head_reward"`
-    AttestationRewardPerEpoch uint64 `json:"attestation_reward_per_epoch"`
+    SourceRewardGwei              uint64 `json:"source_reward_gwei"`
+    TargetRewardGwei              uint64 `json:"target_reward_gwei"`
+    HeadRewardGwei                uint64 `json:"head_reward_gwei"`
+    AttestationRewardPerEpochGwei uint64 `json:"attestation_reward_per_epoch_gwei"`
     
     // Proposer calculations
-    ProposerProbability       float64 `json:"proposer_probability"`
-    ExpectedProposalsPerYear  float64 `json:"expected_proposals_per_year"`
-    AvgProposerRewardPerBlock float64 `json:"avg_proposer_reward_per_block"`
-    ProposerRewardPerEpoch    float64 `json:"proposer_reward_per_epoch"`
+    ProposerProbability           float64 `json:"proposer_probability"`
+    ExpectedProposalsPerYear      float64 `json:"expected_proposals_per_year"`
+    AvgProposerRewardPerBlockGwei float64 `json:"avg_proposer_reward_per_block_gwei"`
+    ProposerRewardPerEpochGwei    float64 `json:"proposer_reward_per_epoch_gwei"`
+    FirstProposal                 FirstProposalEstimate `json:"first_proposal"`
+    
+    // Sync committee calculations (one period is EPOCHS_PER_SYNC_COMMITTEE_PERIOD epochs)
+    SyncCommitteeProbability         float64 `json:"sync_committee_selection_probability"`
+    SyncCommitteePeriodsPerYear      float64 `json:"expected_sync_committee_periods_per_year"`
+    SyncCommitteeIncomePerPeriodGwei float64 `json:"sync_committee_income_per_period_gwei"`
     
     // Attestation inclusion details
-    EstimatedAttestationsPerBlock float64 `json:"estimated_attestations_per_block"`
-    AttestationInclusionReward    uint64  `json:"attestation_inclusion_reward_per_block"`
-    InclusionEffectivenessRate    float64 `json:"inclusion_effectiveness_rate"`
+    EstimatedAttestationsPerBlock  float64 `json:"estimated_attestations_per_block"`
+    AttestationInclusionRewardGwei uint64  `json:"attestation_inclusion_reward_per_block_gwei"`
+    InclusionEffectivenessRate     float64 `json:"inclusion_effectiveness_rate"`
+    
+    // Annual projections: consensus layer
+    AttestationRewardsAnnualGwei   float64 `json:"attestation_rewards_annual_gwei"`
+    ProposerRewardsAnnualGwei      float64 `json:"proposer_rewards_annual_gwei"`
+    SyncCommitteeRewardsAnnualGwei float64 `json:"sync_committee_rewards_annual_gwei"`
+    ConsensusRewardsAnnualGwei     float64 `json:"consensus_rewards_annual_gwei"`
+    
+    // Annual yield of each consensus duty as a percentage of stake; together
+    // they make up the APY before execution layer income
+    SourceAPR   float64 `json:"source_apr_percentage"`
+    TargetAPR   float64 `json:"target_apr_percentage"`
+    HeadAPR     float64 `json:"head_apr_percentage"`
+    ProposerAPR float64 `json:"proposer_apr_percentage"`
+    SyncAPR     float64 `json:"sync_committee_apr_percentage"`
     
-    // Annual projections
-    AttestationRewardsAnnual  float64 `json:"attestation_rewards_annual"`
-    ProposerRewardsAnnual     float64 `json:"proposer_rewards_annual"`
-    TotalAnnualRewards        float64 `json:"total_annual_rewards"`
-    APY                       float64 `json:"apy_percentage"`
+    // Annual projections: execution layer
+    PriorityFeesPerBlockGwei   float64 `json:"priority_fees_per_block_gwei"`
+    MEVPerBlockGwei            float64 `json:"mev_per_block_gwei"`
+    PriorityFeesAnnualGwei     float64 `json:"priority_fees_annual_gwei"`
+    MEVRewardsAnnualGwei       float64 `json:"mev_rewards_annual_gwei"`
+    ExecutionRewardsAnnualGwei float64 `json:"execution_rewards_annual_gwei"`
+    
+    // Annual projections: total
+    TotalAnnualRewardsGwei float64 `json:"total_annual_rewards_gwei"`
+    APY                    float64 `json:"apy_percentage"`
+    
+    // Year-to-year spread from proposal and sync committee luck (one standard deviation)
+    ProposerRewardsStdDevGwei      float64 `json:"proposer_rewards_annual_std_dev_gwei"`
+    SyncCommitteeRewardsStdDevGwei float64 `json:"sync_committee_rewards_annual_std_dev_gwei"`
+    AnnualRewardsStdDevGwei        float64 `json:"total_annual_rewards_std_dev_gwei"`
+    APYStdDev                      float64 `json:"apy_std_dev_percentage"`
     
     // Time-based projections
-    DailyRewards   float64 `json:"daily_rewards"`
-    WeeklyRewards  float64 `json:"weekly_rewards"`
-    MonthlyRewards float64 `json:"monthly_rewards"`
+    DailyRewardsGwei   float64     `json:"daily_rewards_gwei"`
+    WeeklyRewardsGwei  float64     `json:"weekly_rewards_gwei"`
+    MonthlyRewardsGwei float64     `json:"monthly_rewards_gwei"`
+    Periods            []PeriodRow `json:"periods"` // rewards and expected losses over each period
     
     // Participation economics
     ParticipationMultiplier float64 `json:"participation_multiplier"`
@@ -81,45 +150,159 @@ type RewardResults struct {
     EffectiveAPY            float64 `json:"effective_apy_with_boost"`
     InactivityLeakActive    bool    `json:"inactivity_leak_active"`
     NetworkHealthWarning    string  `json:"network_health_warning,omitempty"`
+    
+    // Risk adjustment (annual amounts)
+    MissRate                  float64 `json:"miss_rate"`
+    SyncMissRate              float64 `json:"sync_miss_rate"` // while on the sync committee
+    SlashingProbability       float64 `json:"slashing_probability"`
+    ExpectedMissedRewardsGwei float64 `json:"expected_missed_rewards_annual_gwei"`
+    ExpectedPenaltiesGwei     float64 `json:"expected_penalties_annual_gwei"` // including sync committee penalties
+    ExpectedSyncPenaltiesGwei float64 `json:"expected_sync_penalties_annual_gwei"`
+    ExpectedSlashingLossGwei  float64 `json:"expected_slashing_loss_annual_gwei"`
+    RiskAdjustedAPY           float64 `json:"risk_adjusted_apy_percentage"`
+    
+    // Exit delay cost of capital
+    AlternativeYield    float64 `json:"alternative_yield_percentage"`
+    ExitQueueDays       float64 `json:"exit_queue_days"`
+    WithdrawalDelayDays float64 `json:"withdrawal_delay_days"`
+    ExitDelayCostGwei   float64 `json:"exit_delay_cost_gwei"`
+    
+    // After-tax returns
+    TaxTreatment string      `json:"tax_treatment,omitempty"`
+    AfterTaxAPY  float64     `json:"after_tax_apy_percentage,omitempty"`
+    TaxResults   []TaxResult `json:"tax_results,omitempty"`
+    
+    // The operator's own validators, when given
+    Portfolio *PortfolioProjection `json:"portfolio,omitempty"`
+}
+
+// TaxResult is the return left after tax under one tax treatment
+type TaxResult struct {
+    Treatment     string  `json:"treatment"`
+    TaxRate       float64 `json:"tax_rate_percentage"`
+    AnnualTaxGwei float64 `json:"annual_tax_gwei"`
+    AfterTaxAPY   float64 `json:"after_tax_apy_percentage"`
+}
+
+// FirstProposalEstimate describes how long a validator waits for its first block
+// proposal. Each slot is an independent draw, so the wait is geometric.
+type FirstProposalEstimate struct {
+    ExpectedEpochs float64              `json:"expected_epochs"`
+    ExpectedDays   float64              `json:"expected_days"`
+    Percentiles    []ProposalPercentile `json:"percentiles"`
+}
+
+// ProposalPercentile is the wait within which a share of validators have proposed
+type ProposalPercentile struct {
+    Percentile float64 `json:"percentile"`
+    Epochs     float64 `json:"epochs"`
+    Days       float64 `json:"days"`
 }
 
 // PenaltyResults contains penalty calculations
 type PenaltyResults struct {
     // Attestation penalties
-    SourcePenalty           uint64 `json:"source_penalty"`
-    TargetPenalty           uint64 `json:"target_penalty"`
-    HeadPenalty             uint64 `json:"head_penalty"`
-    TotalAttestationPenalty uint64 `json:"total_attestation_penalty"`
+    SourcePenaltyGwei           uint64 `json:"source_penalty_gwei"`
+    TargetPenaltyGwei           uint64 `json:"target_penalty_gwei"`
+    HeadPenaltyGwei             uint64 `json:"head_penalty_gwei"`
+    TotalAttestationPenaltyGwei uint64 `json:"total_attestation_penalty_gwei"`
     
     // Inactivity penalties
-    InactivityScore   uint64 `json:"inactivity_score"`
-    InactivityPenalty uint64 `json:"inactivity_penalty"`
+    InactivityScore       uint64 `json:"inactivity_score"`
+    InactivityPenaltyGwei uint64 `json:"inactivity_penalty_gwei"`
     
     // Daily projections
     DailyAttestationPenalty float64 `json:"daily_attestation_penalty_eth"`
     DailyInactivityPenalty  float64 `json:"daily_inactivity_penalty_eth"`
+    
+    // The penalties over each period
+    Periods []PeriodRow `json:"periods"`
+}
+
+// PeriodAmounts is an amount over each standard period, in Gwei. The periods
+// are whole numbers of epochs, so they follow the chain's slot time.
+type PeriodAmounts struct {
+    PerEpoch float64 `json:"per_epoch_gwei"`
+    Daily    float64 `json:"daily_gwei"`
+    Weekly   float64 `json:"weekly_gwei"`
+    Monthly  float64 `json:"monthly_gwei"`
+    Annual   float64 `json:"annual_gwei"`
+}
+
+// PeriodRow is one line of a period summary
+type PeriodRow struct {
+    Name string `json:"name"`
+    PeriodAmounts
 }
 
 // SlashingResults contains slashing penalty calculations
 type SlashingResults struct {
-    InitialPenalty       uint64  `json:"initial_penalty"`
-    ProportionalPenalty  uint64  `json:"proportional_penalty"`
-    TotalPenalty         uint64  `json:"total_penalty"`
-    PercentageOfStake    float64 `json:"percentage_of_stake"`
-    WhistleblowerReward  uint64  `json:"whistleblower_reward"`
-    ProposerReward       uint64  `json:"proposer_reward"`
+    InitialPenaltyGwei      uint64  `json:"initial_penalty_gwei"`
+    ProportionalPenaltyGwei uint64  `json:"proportional_penalty_gwei"`
+    TotalPenaltyGwei        uint64  `json:"total_penalty_gwei"`
+    PercentageOfStake       float64 `json:"percentage_of_stake"`
+    WhistleblowerRewardGwei uint64  `json:"whistleblower_reward_gwei"`
+    ProposerRewardGwei      uint64  `json:"proposer_reward_gwei"`
+}
+
+// SlashingEvent is effective balance slashed in one epoch (Gwei)
+type SlashingEvent struct {
+    Epoch       uint64 `json:"epoch"`
+    BalanceGwei uint64 `json:"balance_gwei"`
+}
+
+// SlashingPenalty is the penalty one slashed validator pays (amounts in ETH)
+type SlashingPenalty struct {
+    Initial      float64 `json:"initial_eth"`
+    Proportional float64 `json:"proportional_eth"`
+    Total        float64 `json:"total_eth"`
+    Percentage   float64 `json:"percentage"`
+}
+
+// SlashingNetworkImpact is what a correlated slashing costs the network
+// (amounts in ETH)
+type SlashingNetworkImpact struct {
+    TotalPenalties float64 `json:"total_penalties_eth"`
+    ReducedStaking float64 `json:"reduced_staking_eth"`
+    SecurityImpact string  `json:"security_impact"`
+}
+
+// SlashingImpact compares a lone slashing with a correlated one of
+// SlashedValidatorCount validators under one fork's rules
+type SlashingImpact struct {
+    Fork                   string                `json:"fork"`
+    SlashedValidatorCount  int                   `json:"slashed_validator_count"`
+    SlashedBalance         float64               `json:"slashed_balance_eth"`
+    NetworkPercentage      float64               `json:"network_percentage"`
+    SingleValidatorPenalty SlashingPenalty       `json:"single_validator_penalty"`
+    CorrelatedPenalty      SlashingPenalty       `json:"correlated_penalty"`
+    NetworkImpact          SlashingNetworkImpact `json:"network_impact"`
 }
 
 // ComparisonResult for comparing different validator counts
 type ComparisonResult struct {
     ValidatorCount int     `json:"validator_count"`
     TotalStaked    uint64  `json:"total_staked_eth"`
-    BaseReward     uint64  `json:"base_reward_gwei"`
+    BaseRewardGwei uint64  `json:"base_reward_gwei"`
     AnnualRewards  float64 `json:"annual_rewards_eth"`
     APY            float64 `json:"apy_percentage"`
     DailyRewards   float64 `json:"daily_rewards_eth"`
 }
 
+// ParticipationResult is one row of the participation rate comparison
+// (rewards in ETH)
+type ParticipationResult struct {
+    ParticipationRate       float64 `json:"participation_rate"`
+    ParticipationMultiplier float64 `json:"participation_multiplier"`
+    BaseAPY                 float64 `json:"base_apy_percentage"`
+    EffectiveAPY            float64 `json:"effective_apy_percentage"`
+    ConsensusRewards        float64 `json:"consensus_rewards_eth"`
+    ExecutionRewards        float64 `json:"execution_rewards_eth"`
+    AnnualRewards           float64 `json:"annual_rewards_eth"`
+    InactivityLeakActive    bool    `json:"inactivity_leak_active"`
+    NetworkStatus           string  `json:"network_status"`
+}
+
 // DetailedBreakdown provides comprehensive reward breakdown
 type DetailedBreakdown struct {
     RewardResults    *RewardResults    `json:"reward_results"`
@@ -155,4 +338,636 @@ type ValidatorPerformance struct {
     TotalRewards         uint64  `json:"total_rewards"`
     TotalPenalties       uint64  `json:"total_penalties"`
     NetEarnings          int64   `json:"net_earnings"`
-}
\ No newline at end of file
+}
+
+// ForecastPoint is a single month of a validator-set growth projection
+type ForecastPoint struct {
+    Month          int     `json:"month"`
+    ValidatorCount int     `json:"validator_count"`
+    TotalStaked    uint64  `json:"total_staked_eth"`
+    APY            float64 `json:"apy_percentage"`
+    APYChange      float64 `json:"apy_change_percentage"`
+}
+
+// GrowthForecast contains APY projections under a validator-set growth model
+type GrowthForecast struct {
+    Model  string          `json:"model"`
+    Points []ForecastPoint `json:"points"`
+}
+
+// IssuanceResult contains APY and inflation under an issuance policy
+type IssuanceResult struct {
+    Policy         string  `json:"policy"`
+    ValidatorCount int     `json:"validator_count"`
+    TotalStaked    uint64  `json:"total_staked_eth"`
+    BaseAPY        float64 `json:"base_apy_percentage"`
+    EffectiveAPY   float64 `json:"effective_apy_percentage"`
+    APYChange      float64 `json:"apy_change_vs_sqrt_percentage"`
+    AnnualIssuance float64 `json:"annual_issuance_eth"`
+    InflationRate  float64 `json:"inflation_rate_percentage"`
+}
+
+// EquilibriumResult describes the validator count at which staking APY equals an alternative yield
+type EquilibriumResult struct {
+    TargetYield    float64 `json:"target_yield_percentage"`
+    ValidatorCount int     `json:"validator_count"`
+    TotalStaked    uint64  `json:"total_staked_eth"`
+    StakingRatio   float64 `json:"staking_ratio_percentage"`
+    APY            float64 `json:"apy_percentage"`
+    ExceedsSupply  bool    `json:"exceeds_supply"`
+}
+
+// TargetIncome is the stake needed to earn a target net income per month, as
+// 32 ETH validators or as compounding (0x02) validators (amounts in ETH)
+type TargetIncome struct {
+    MonthlyTarget         float64 `json:"monthly_target_eth"`
+    MonthlyTargetUSD      float64 `json:"monthly_target_usd,omitempty"`
+    ETHPrice              float64 `json:"eth_price_usd,omitempty"`
+    NetAPY                float64 `json:"net_apy"`
+    Validators            int     `json:"validators"`
+    ValidatorStake        float64 `json:"validator_stake_eth"`
+    CompoundingStake      float64 `json:"compounding_stake_eth"`
+    CompoundingValidators int     `json:"compounding_validators"`
+}
+
+// APYThreshold is where the risk-adjusted APY falls to a target, for setting
+// monitoring alerts. Effectiveness and participation are fractions (0.0-1.0)
+// and only meaningful when found.
+type APYThreshold struct {
+    TargetAPY          float64 `json:"target_apy"`
+    CurrentAPY         float64 `json:"current_apy"`
+    Effectiveness      float64 `json:"effectiveness"`
+    EffectivenessFound bool    `json:"effectiveness_found"`
+    Participation      float64 `json:"participation"`
+    ParticipationFound bool    `json:"participation_found"`
+}
+
+// SensitivityGrid is the risk-adjusted APY over every combination of two
+// parameters: APY[i][j] is for Rows[i] and Columns[j]
+type SensitivityGrid struct {
+    RowParameter    string      `json:"row_parameter"`
+    ColumnParameter string      `json:"column_parameter"`
+    Rows            []float64   `json:"rows"`
+    Columns         []float64   `json:"columns"`
+    APY             [][]float64 `json:"apy"`
+}
+
+// NetworkSnapshot is the live state of the network at a finalized epoch
+type NetworkSnapshot struct {
+    Epoch            uint64  `json:"epoch"`
+    ActiveValidators int     `json:"active_validators"`
+    Participation    float64 `json:"participation"`
+    ActivationQueue  int     `json:"activation_queue"`
+    ExitQueue        int     `json:"exit_queue"`
+    APY              float64 `json:"apy"` // projected from the snapshot
+}
+
+// EndpointStatus is the result of checking one beacon node or explorer
+type EndpointStatus struct {
+    Name             string  `json:"name"`
+    Healthy          bool    `json:"healthy"`
+    ActiveValidators int     `json:"active_validators,omitempty"`
+    LatencyMs        float64 `json:"latency_ms"`
+    Error            string  `json:"error,omitempty"`
+}
+
+// NetworkReport is one network's live conditions and projected rewards in a
+// multi-network report. Amounts are in the network's own staking token.
+type NetworkReport struct {
+    Network           string           `json:"network"`
+    BeaconURL         string           `json:"beacon_url"`
+    Error             string           `json:"error,omitempty"`
+    Snapshot          *NetworkSnapshot `json:"snapshot,omitempty"`
+    SecondsPerSlot    uint64           `json:"seconds_per_slot"`
+    StakePerValidator float64          `json:"stake_per_validator"`
+    AnnualRewards     float64          `json:"annual_rewards_per_validator"`
+}
+
+// MultiNetworkReport combines the reports of several networks
+type MultiNetworkReport struct {
+    Networks         []NetworkReport `json:"networks"`
+    ActiveValidators int             `json:"active_validators"`
+    AverageAPY       float64         `json:"average_apy"` // weighted by active validators
+}
+
+// Alert reports a network metric crossing its configured threshold
+type Alert struct {
+    Metric    string          `json:"metric"`
+    Value     float64         `json:"value"`
+    Threshold float64         `json:"threshold"`
+    Message   string          `json:"message"`
+    Snapshot  NetworkSnapshot `json:"snapshot"`
+}
+
+// LuckResult compares a validator's block proposals over a period with the
+// number expected from the network size
+type LuckResult struct {
+    Proposals          int     `json:"proposals"`
+    Epochs             uint64  `json:"epochs"`
+    Days               float64 `json:"days"`
+    ExpectedProposals  float64 `json:"expected_proposals"`
+    Luck               float64 `json:"luck_percentage"`
+    Percentile         float64 `json:"luck_percentile"`
+    ProbabilityAtMost  float64 `json:"probability_at_most"`
+    ProbabilityAtLeast float64 `json:"probability_at_least"`
+}
+
+// SimulationResult summarizes Monte Carlo trials of one validator's annual rewards (ETH)
+type SimulationResult struct {
+    Trials      int                    `json:"trials"`
+    Seed        int64                  `json:"seed"`
+    Mean        float64                `json:"mean_eth"`
+    StdDev      float64                `json:"std_dev_eth"`
+    Min         float64                `json:"min_eth"`
+    Max         float64                `json:"max_eth"`
+    Percentiles []SimulationPercentile `json:"percentiles"`
+    Histogram   []HistogramBin         `json:"histogram"`
+}
+
+// SimulationPercentile is the annual reward below which a share of trials fell
+type SimulationPercentile struct {
+    Percentile float64 `json:"percentile"`
+    Rewards    float64 `json:"rewards_eth"`
+}
+
+// SimulationTrial is one simulated year: the proposals drawn, the sync
+// committee periods served, and the resulting annual rewards
+type SimulationTrial struct {
+    Trial       int     `json:"trial"`
+    Proposals   int     `json:"proposals"`
+    SyncPeriods int     `json:"sync_committee_periods"`
+    Rewards     float64 `json:"annual_rewards_eth"`
+}
+
+// OutageResult is what one validator loses by being offline for Slots slots
+// from StartSlot (amounts in ETH). Costs and duty counts are expected values;
+// the percentiles and maximum come from Monte Carlo trials.
+type OutageResult struct {
+    StartSlot  uint64  `json:"start_slot"`
+    Slots      uint64  `json:"slots"`
+    Seconds    uint64  `json:"seconds"`
+    FirstEpoch uint64  `json:"first_epoch"`
+    LastEpoch  uint64  `json:"last_epoch"`
+    Trials     int     `json:"trials"`
+    Seed       int64   `json:"seed"`
+
+    // Expected number of each duty that falls in the outage
+    MissedAttestations   float64 `json:"missed_attestations"`
+    MissedProposals      float64 `json:"missed_proposals"`
+    MissedSyncDuties     float64 `json:"missed_sync_duties"`
+    ProposalProbability  float64 `json:"proposal_probability"`  // of missing at least one
+    SyncCommitteeProbability float64 `json:"sync_committee_probability"` // of being on the committee during it
+
+    AttestationCost float64                `json:"attestation_cost_eth"` // rewards forgone and penalties
+    ProposalCost    float64                `json:"proposal_cost_eth"`
+    SyncCost        float64                `json:"sync_committee_cost_eth"`
+    Cost            float64                `json:"cost_eth"`
+    Max             float64                `json:"max_cost_eth"` // in any trial
+    Percentiles     []OutagePercentile     `json:"percentiles"`
+    RecoveryHours   float64                `json:"recovery_hours"` // at the expected reward rate
+}
+
+// OutagePercentile is the outage cost that a share of trials stayed within
+type OutagePercentile struct {
+    Percentile float64 `json:"percentile"`
+    Cost       float64 `json:"cost_eth"`
+}
+
+// HistogramBin counts the trials whose annual rewards fell in [Lower, Upper)
+type HistogramBin struct {
+    Lower float64 `json:"lower_eth"`
+    Upper float64 `json:"upper_eth"`
+    Count int     `json:"count"`
+    Share float64 `json:"share"`
+}
+
+// ForkComparison is one validator's economics under a fork's reward and penalty
+// rules (amounts in Gwei)
+type ForkComparison struct {
+    Fork                           string  `json:"fork"`
+    BaseRewardGwei                 uint64  `json:"base_reward_gwei"`
+    AttestationRewardPerEpochGwei  uint64  `json:"attestation_reward_per_epoch_gwei"`
+    ProposerRewardPerBlockGwei     float64 `json:"proposer_reward_per_block_gwei"`
+    AttestationRewardsAnnualGwei   float64 `json:"attestation_rewards_annual_gwei"`
+    ProposerRewardsAnnualGwei      float64 `json:"proposer_rewards_annual_gwei"`
+    SyncCommitteeRewardsAnnualGwei float64 `json:"sync_committee_rewards_annual_gwei"`
+    TotalAnnualRewardsGwei         float64 `json:"total_annual_rewards_gwei"`
+    APY                            float64 `json:"apy_percentage"`
+    InactivityPenaltyQuotient      uint64  `json:"inactivity_penalty_quotient"`
+    InactivityLeakLossGwei         float64 `json:"inactivity_leak_loss_gwei"`
+    SlashingPenaltyGwei            uint64  `json:"slashing_penalty_gwei"`
+}
+
+// ExitPlan is the timeline of an exit requested from the withdrawal credentials'
+// execution address (EIP-7002)
+type ExitPlan struct {
+    ExcessRequests     uint64          `json:"excess_requests"`
+    PendingRequests    uint64          `json:"pending_requests"`
+    RequestFeeWei      string          `json:"request_fee_wei"`
+    RequestFee         float64         `json:"request_fee_eth"`
+    BlocksToMinimumFee uint64          `json:"blocks_to_minimum_fee"`
+    Milestones         []ExitMilestone `json:"milestones"`
+}
+
+// ExitMilestone is a stage of an exit, timed from when the request is submitted
+type ExitMilestone struct {
+    Stage  string  `json:"stage"`
+    Epochs float64 `json:"epochs"`
+    Days   float64 `json:"days"`
+}
+
+// ExitSchedule staggers a fleet's voluntary exits so every balance is withdrawn
+// by a deadline while the validators keep earning as long as possible (amounts
+// in ETH)
+type ExitSchedule struct {
+    Validators      int         `json:"validators"`
+    Deadline        string      `json:"deadline"`
+    QueueAhead      int         `json:"queue_ahead"`
+    ChurnLimit      uint64      `json:"churn_limit_per_epoch"`
+    WithdrawalDays  float64     `json:"withdrawal_days"`
+    Feasible        bool        `json:"feasible"`
+    CompletionDate  string      `json:"completion_date"`
+    Batches         []ExitBatch `json:"batches"`
+    ExpectedIncome  float64     `json:"expected_income"`
+    ImmediateIncome float64     `json:"immediate_income"` // if every exit were submitted today
+}
+
+// ExitBatch is the exits to submit on one day
+type ExitBatch struct {
+    Date       string  `json:"date"`
+    Validators int     `json:"validators"`
+    Cumulative int     `json:"cumulative"`
+    ExitDate   string  `json:"last_exit_date"`
+    Income     float64 `json:"income"` // earned by the batch's validators until they exit
+}
+
+// Alternative is somewhere else capital could earn a yield (in percent)
+type Alternative struct {
+    Name  string  `json:"name"`
+    Yield float64 `json:"yield_percentage"`
+}
+
+// AlternativeReturn is what an alternative earns over the horizon, set
+// against staking (amounts in ETH)
+type AlternativeReturn struct {
+    Name          string  `json:"name"`
+    Yield         float64 `json:"yield_percentage"`
+    Return        float64 `json:"return"`
+    Advantage     float64 `json:"staking_advantage"` // negative when the alternative earns more
+    StakingBetter bool    `json:"staking_better"`
+}
+
+// OpportunityComparison sets staking against alternative yields over a
+// horizon, counting the days the stake cannot be withdrawn (amounts in ETH)
+type OpportunityComparison struct {
+    Capital        float64             `json:"capital"`
+    HorizonDays    float64             `json:"horizon_days"`
+    StakingAPY     float64             `json:"staking_apy_percentage"` // risk-adjusted
+    ActivationDays float64             `json:"activation_queue_days"`
+    ExitQueueDays  float64             `json:"exit_queue_days"`
+    WithdrawalDays float64             `json:"withdrawal_delay_days"`
+    IlliquidDays   float64             `json:"illiquid_days"` // from requesting the exit to withdrawal
+    StakingReturn  float64             `json:"staking_return"`
+    EffectiveYield float64             `json:"effective_staking_yield_percentage"`
+    Alternatives   []AlternativeReturn `json:"alternatives"`
+}
+
+// QueueDirection is the wait through one direction of the validator queue
+type QueueDirection struct {
+    Pending    int     `json:"pending"`
+    ChurnLimit uint64  `json:"churn_limit_per_epoch"`
+    Capped     bool    `json:"capped"` // churn held at the activation cap (EIP-7514)
+    Epochs     float64 `json:"epochs"`
+    Days       float64 `json:"days"`
+}
+
+// ValidatorQueue is the wait to enter and to leave the active validator set.
+// The directions have separate churn limits, so their waits differ.
+type ValidatorQueue struct {
+    ActiveValidators int            `json:"active_validators"`
+    Activation       QueueDirection `json:"activation"`
+    Exit             QueueDirection `json:"exit"`
+    NetChangePerDay  float64        `json:"net_validator_change_per_day"` // while both queues stay full
+}
+
+// ConsolidationPlan times a batch of consolidation requests (EIP-7251), each
+// merging a 32 ETH source validator into a compounding target, and what the
+// sources stop earning while their balances are in transit (amounts in ETH)
+type ConsolidationPlan struct {
+    Requests          int     `json:"requests"`
+    QueueAhead        int     `json:"queue_ahead"`
+    ChurnLimit        float64 `json:"churn_limit_eth_per_epoch"`
+    PerEpoch          float64 `json:"consolidations_per_epoch"`
+    Disabled          bool    `json:"disabled"` // churn too low for consolidations to be processed
+    FirstDays         float64 `json:"first_complete_days"`
+    LastEpochs        float64 `json:"last_complete_epochs"`
+    LastDays          float64 `json:"last_complete_days"`
+    IdleEpochs        float64 `json:"idle_epochs_per_source"`
+    RewardsKept       float64 `json:"rewards_without_consolidating"`
+    RewardsEarned     float64 `json:"rewards_while_consolidating"`
+    RewardsDifference float64 `json:"rewards_difference"`
+}
+
+// SkimProjection forecasts the partial withdrawals a 0x01 validator receives as
+// the withdrawal sweep skims its balance above 32 ETH (amounts in ETH)
+type SkimProjection struct {
+    CycleDays     float64           `json:"sweep_cycle_days"`
+    SkimsPerYear  float64           `json:"skims_per_year"`
+    AmountPerSkim float64           `json:"amount_per_skim_eth"`
+    AnnualSkimmed float64           `json:"annual_skimmed_eth"`
+    Balance       BalanceTrajectory `json:"balance"`
+    Calendar      []SkimEvent       `json:"calendar"`
+}
+
+// BalanceTrajectory summarizes a 0x01 validator's balance as it grows above
+// 32 ETH between skims and is reset by each one (amounts in ETH)
+type BalanceTrajectory struct {
+    PeakBalance    float64 `json:"peak_balance_eth"`
+    AverageBalance float64 `json:"average_balance_eth"`
+    NaiveAPY       float64 `json:"naive_apy"`     // on a constant 32 ETH
+    EffectiveAPY   float64 `json:"effective_apy"` // on the average balance
+    APYDifference  float64 `json:"apy_difference"`
+}
+
+// SkimEvent is one expected partial withdrawal
+type SkimEvent struct {
+    Number     int     `json:"number"`
+    Date       string  `json:"date"`
+    Days       float64 `json:"days"`
+    Amount     float64 `json:"amount_eth"`
+    Cumulative float64 `json:"cumulative_eth"`
+}
+
+// Reward event layers
+const (
+    ConsensusLayer = "consensus"
+    ExecutionLayer = "execution"
+)
+
+// RewardEvent is one receipt of staking income, projected or imported
+type RewardEvent struct {
+    Time        time.Time `json:"time"`
+    Layer       string    `json:"layer"`
+    Amount      float64   `json:"amount_eth"`
+    Description string    `json:"description,omitempty"`
+}
+
+// InsuranceResult compares a slashing insurance premium against modeled losses
+type InsuranceResult struct {
+    PremiumBps           float64 `json:"premium_bps"`
+    AnnualPremium        float64 `json:"annual_premium_eth"`
+    SlashingProbability  float64 `json:"slashing_probability"`
+    SinglePenalty        float64 `json:"single_slashing_penalty_eth"`
+    CorrelatedCount      int     `json:"correlated_slashing_count"`
+    CorrelatedPenalty    float64 `json:"correlated_slashing_penalty_eth"`
+    ExpectedLoss         float64 `json:"expected_loss_eth"`
+    BreakEvenProbability float64 `json:"break_even_probability"`
+    CoverageRational     bool    `json:"coverage_rational"`
+}
+
+// RealizedRewards contains the rewards a validator actually earned over a range of epochs,
+// as reported by a beacon node (all amounts in Gwei, negative for net penalties)
+type RealizedRewards struct {
+    ValidatorIndex uint64 `json:"validator_index"`
+    FromEpoch      uint64 `json:"from_epoch"`
+    ToEpoch        uint64 `json:"to_epoch"`
+    
+    // Attestation rewards
+    SourceRewardsGwei       int64 `json:"source_rewards_gwei"`
+    TargetRewardsGwei       int64 `json:"target_rewards_gwei"`
+    HeadRewardsGwei         int64 `json:"head_rewards_gwei"`
+    InactivityPenaltiesGwei int64 `json:"inactivity_penalties_gwei"`
+    AttestationRewardsGwei  int64 `json:"attestation_rewards_gwei"`
+    
+    // Block proposals
+    ProposalsScheduled int   `json:"proposals_scheduled"`
+    ProposalsMade      int   `json:"proposals_made"`
+    BlockRewardsGwei   int64 `json:"block_rewards_gwei"`
+    
+    // Sync committee
+    SyncCommitteeEpochs      int   `json:"sync_committee_epochs"`
+    SyncCommitteeRewardsGwei int64 `json:"sync_committee_rewards_gwei"`
+    
+    TotalRewardsGwei int64 `json:"total_rewards_gwei"`
+}
+
+// PerformanceSummary compares the rewards a validator realized with the ideal the
+// calculator expects from a validator that performs every duty (amounts in Gwei)
+type PerformanceSummary struct {
+    ValidatorIndex uint64 `json:"validator_index"`
+    FromEpoch      uint64 `json:"from_epoch"`
+    ToEpoch        uint64 `json:"to_epoch"`
+    Epochs         uint64 `json:"epochs"`
+    
+    // Attestations
+    RealizedAttestationGwei int64 `json:"realized_attestation_rewards_gwei"`
+    IdealAttestationGwei    int64 `json:"ideal_attestation_rewards_gwei"`
+    
+    // Block proposals
+    ProposalsScheduled    int   `json:"proposals_scheduled"`
+    ProposalsMade         int   `json:"proposals_made"`
+    RealizedProposalsGwei int64 `json:"realized_block_rewards_gwei"`
+    IdealProposalsGwei    int64 `json:"ideal_block_rewards_gwei"`
+    
+    // Sync committee
+    SyncCommitteeEpochs int   `json:"sync_committee_epochs"`
+    RealizedSyncGwei    int64 `json:"realized_sync_committee_rewards_gwei"`
+    IdealSyncGwei       int64 `json:"ideal_sync_committee_rewards_gwei"`
+    
+    RealizedTotalGwei int64   `json:"realized_total_gwei"`
+    IdealTotalGwei    int64   `json:"ideal_total_gwei"`
+    Efficiency        float64 `json:"efficiency_percentage"`
+}
+
+// MonitorSummary is the attestation performance a validator client or
+// beacon node's validator monitor reported. Effectiveness is the share of the
+// attestation reward weight earned (0.0-1.0), counting each timely source,
+// target, and head vote by its weight.
+type MonitorSummary struct {
+    Client                   string  `json:"client"`
+    Format                   string  `json:"format"` // log or metrics
+    Validators               int     `json:"validators"`
+    Attestations             uint64  `json:"attestations"`
+    Included                 uint64  `json:"included"`
+    TimelySource             uint64  `json:"timely_source"`
+    TimelyTarget             uint64  `json:"timely_target"`
+    TimelyHead               uint64  `json:"timely_head"`
+    AverageInclusionDistance float64 `json:"average_inclusion_distance,omitempty"`
+    Effectiveness            float64 `json:"effectiveness"`
+}
+
+// StakingSetup describes the yearly economics of one way of staking a fixed amount of ETH
+type StakingSetup struct {
+    Name            string  `json:"name"`
+    Validators      int     `json:"validators"`
+    CapitalDeployed float64 `json:"capital_deployed_eth"`
+    IdleCapital     float64 `json:"idle_capital_eth"`
+    GrossRewards    float64 `json:"gross_rewards_eth"`
+    Penalties       float64 `json:"penalties_eth"`
+    Fees            float64 `json:"fees_eth"`
+    NetRewards      float64 `json:"net_rewards_eth"`
+    ReturnOnCapital float64 `json:"return_on_capital_percentage"`
+}
+
+// CompoundingProjection follows an operator who funds new validators from
+// accumulated rewards (amounts in ETH)
+type CompoundingProjection struct {
+    Capital           float64            `json:"capital_eth"`
+    InitialValidators int                `json:"initial_validators"`
+    FinalValidators   int                `json:"final_validators"`
+    FinalValue        float64            `json:"final_value_eth"`
+    WithdrawnValue    float64            `json:"withdrawn_value_eth"` // capital plus rewards if never restaked
+    CompoundedAPY     float64            `json:"compounded_apy"`
+    WithdrawnAPY      float64            `json:"withdrawn_apy"`
+    Timeline          []CompoundingMonth `json:"timeline"`
+}
+
+// CompoundingMonth is the operator's position at the end of one month
+type CompoundingMonth struct {
+    Month         int     `json:"month"`
+    Validators    int     `json:"validators"`
+    NewValidators int     `json:"new_validators"`
+    Rewards       float64 `json:"rewards_eth"`
+    Pool          float64 `json:"pool_eth"` // rewards waiting for the next 32 ETH
+    TotalValue    float64 `json:"total_value_eth"`
+}
+
+// SetupComparison compares staking setups for the same amount of ETH
+type SetupComparison struct {
+    Capital float64        `json:"capital_eth"`
+    Setups  []StakingSetup `json:"setups"`
+}
+
+// PortfolioValidator is one of the operator's own validators (Amount in Gwei)
+type PortfolioValidator struct {
+    Pubkey                string `json:"pubkey,omitempty"`
+    WithdrawalCredentials string `json:"withdrawal_credentials,omitempty"`
+    Amount                uint64 `json:"amount"`
+}
+
+// Compounding reports whether the validator has 0x02 withdrawal credentials.
+// Validators without known credentials are treated as 0x01.
+func (v PortfolioValidator) Compounding() bool {
+    return strings.HasPrefix(strings.ToLower(v.WithdrawalCredentials), "0x02")
+}
+
+// CredentialGroup is the projected rewards of the portfolio's validators with
+// one kind of withdrawal credentials (amounts in Gwei)
+type CredentialGroup struct {
+    Credentials   string  `json:"credentials"`
+    Validators    int     `json:"validators"`
+    TotalStaked   uint64  `json:"total_staked_gwei"`
+    AnnualRewards float64 `json:"annual_rewards"`
+    APY           float64 `json:"apy_percentage"`
+}
+
+// PortfolioProjection is the projected rewards of the operator's own validators
+// (amounts in Gwei)
+type PortfolioProjection struct {
+    Source         string  `json:"source"`
+    Validators     int     `json:"validators"`
+    TotalStaked    uint64  `json:"total_staked_gwei"`
+    AnnualRewards  float64 `json:"annual_rewards"`
+    MonthlyRewards float64 `json:"monthly_rewards"`
+    DailyRewards   float64 `json:"daily_rewards"`
+    APY            float64 `json:"apy_percentage"` // blended across credential groups
+    Groups         []CredentialGroup `json:"credential_groups"`
+    
+    // Pending deposits only: the wait to activation and the first year's
+    // rewards after it
+    ActivationQueue  int                 `json:"activation_queue,omitempty"`
+    ActivationDays   float64             `json:"activation_days,omitempty"`
+    FirstYearRewards float64             `json:"first_year_rewards,omitempty"`
+    Deposits         []DepositProjection `json:"deposits,omitempty"`
+}
+
+// DepositProjection is one pending deposit's wait to activation and the
+// rewards it earns in the year after the deposit (Amount and rewards in Gwei)
+type DepositProjection struct {
+    Pubkey           string  `json:"pubkey"`
+    Amount           uint64  `json:"amount"`
+    ActivationEpochs float64 `json:"activation_epochs"`
+    ActivationDays   float64 `json:"activation_days"`
+    FirstYearRewards float64 `json:"first_year_rewards"`
+}
+
+// LeakCohort is the outcome of an inactivity leak for the validators that
+// stayed online or went offline (amounts in Gwei)
+type LeakCohort struct {
+    Name          string  `json:"name"`
+    Validators    int     `json:"validators"`
+    StartBalance  uint64  `json:"start_balance"`
+    EndBalance    uint64  `json:"end_balance"` // per validator
+    TotalChange   float64 `json:"total_change"`
+    EjectedEpoch  uint64  `json:"ejected_epoch,omitempty"` // epochs into the leak
+}
+
+// LeakPoint is the state of both cohorts some epochs into the non-finality
+// period (balances per validator in Gwei)
+type LeakPoint struct {
+    Epoch          uint64  `json:"epoch"`
+    Days           float64 `json:"days"`
+    OnlineBalance  uint64  `json:"online_balance"`
+    OfflineBalance uint64  `json:"offline_balance"`
+    OfflineScore   uint64  `json:"offline_inactivity_score"`
+    OnlineShare    float64 `json:"online_stake_percentage"`
+}
+
+// LeakAnalysis follows the online and offline cohorts through a period of
+// non-finality, until finality resumes or the period ends (amounts in Gwei)
+type LeakAnalysis struct {
+    Validators         int          `json:"validators"`
+    Participation      float64      `json:"participation_rate"`
+    Epochs             uint64       `json:"epochs"`
+    FinalityRestored   bool         `json:"finality_restored"`
+    Online             LeakCohort   `json:"online"`
+    Offline            LeakCohort   `json:"offline"`
+    StartOnlineShare   float64      `json:"start_online_stake_percentage"`
+    EndOnlineShare     float64      `json:"end_online_stake_percentage"`
+    Burned             float64      `json:"burned"`
+    Trajectory         []LeakPoint  `json:"trajectory"`
+}
+
+// EffectiveBalanceRate is what a validator earns at one effective balance
+// (amounts in ETH)
+type EffectiveBalanceRate struct {
+    EffectiveBalance float64 `json:"effective_balance"`
+    MinBalance       float64 `json:"min_balance"`           // below this the effective balance drops
+    MaxBalance       float64 `json:"max_balance,omitempty"` // above this it rises; 0 at the maximum
+    Compounding      bool    `json:"compounding"`           // only reachable with 0x02 credentials
+    DailyRewards     float64 `json:"daily_rewards"`
+    AnnualRewards    float64 `json:"annual_rewards"`
+    APY              float64 `json:"apy_percentage"`
+    RelativeRewards  float64 `json:"relative_rewards_percentage"` // of a 32 ETH validator's rewards
+    DaysToNextStep   float64 `json:"days_to_next_step,omitempty"` // for consensus rewards to lift the effective balance
+}
+
+// CrossCheck compares the attestation rewards the calculator computes for
+// each validator in one epoch with those a consensus client reports (amounts
+// in Gwei)
+type CrossCheck struct {
+    Epoch      uint64           `json:"epoch"`
+    Client     string           `json:"client"`
+    Fork       string           `json:"fork"`
+    Validators int              `json:"validators"` // compared
+    Components []ComponentCheck `json:"components"`
+    Mismatches []RewardMismatch `json:"mismatches"`
+}
+
+// ComponentCheck totals one reward component over the compared validators
+type ComponentCheck struct {
+    Component   string `json:"component"`
+    Calculated  int64  `json:"calculated_total"`
+    Reference   int64  `json:"reference_total"`
+    Mismatches  int    `json:"mismatches"`
+    MaxAbsDelta int64  `json:"max_abs_delta"`
+}
+
+// RewardMismatch is one validator's reward component on which the calculator
+// and the client disagree
+type RewardMismatch struct {
+    ValidatorIndex uint64 `json:"validator_index"`
+    Component      string `json:"component"`
+    Calculated     int64  `json:"calculated"`
+    Reference      int64  `json:"reference"`
+}
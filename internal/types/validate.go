@@ -0,0 +1,49 @@
+package types
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+
+    "github.com/eth-rewards-calculator/internal/config"
+)
+
+// Validate checks that the state is consistent enough for reward calculations.
+// An empty CurrentFork is allowed and selects the default fork configuration.
+func (s *NetworkState) Validate() error {
+    if s == nil {
+        return errors.New("network state is nil")
+    }
+    if len(s.Validators) == 0 {
+        return errors.New("network state has no validators")
+    }
+    if s.TotalActiveBalance == 0 {
+        return errors.New("total active balance must be greater than zero")
+    }
+    if s.FinalizedEpoch > s.CurrentEpoch {
+        return fmt.Errorf("finalized epoch %d is after current epoch %d", s.FinalizedEpoch, s.CurrentEpoch)
+    }
+    if s.JustifiedEpoch > s.CurrentEpoch {
+        return fmt.Errorf("justified epoch %d is after current epoch %d", s.JustifiedEpoch, s.CurrentEpoch)
+    }
+    if s.PreviousEpochParticipation != nil && len(s.PreviousEpochParticipation) != len(s.Validators) {
+        return fmt.Errorf("participation has %d entries for %d validators",
+            len(s.PreviousEpochParticipation), len(s.Validators))
+    }
+    if s.SlashingsPerEpoch != nil && uint64(len(s.SlashingsPerEpoch)) != config.EPOCHS_PER_SLASHINGS_VECTOR {
+        return fmt.Errorf("slashings vector has %d entries, want %d",
+            len(s.SlashingsPerEpoch), config.EPOCHS_PER_SLASHINGS_VECTOR)
+    }
+    if s.CurrentFork != "" && !config.IsKnownFork(s.CurrentFork) {
+        return fmt.Errorf("unknown fork %q (known forks: %s)", s.CurrentFork, strings.Join(config.KnownForks, ", "))
+    }
+    return nil
+}
+
+// ValidateIndex checks that index refers to a validator in the state
+func (s *NetworkState) ValidateIndex(index int) error {
+    if index < 0 || index >= len(s.Validators) {
+        return fmt.Errorf("validator index %d out of range [0, %d)", index, len(s.Validators))
+    }
+    return nil
+}
@@ -0,0 +1,149 @@
+package rewards
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "sync"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/config"
+    "github.com/eth-rewards-calculator/internal/types"
+)
+
+// Calculator runs the package's calculations under its own chain parameters
+// and fork, leaving those of other Calculators and the package-level
+// functions alone. The zero value and the package-level functions use the
+// process's chain parameters, mainnet unless the command line changed them.
+type Calculator struct {
+    fork string
+    spec map[string]string
+}
+
+// Option configures a Calculator
+type Option func(*Calculator) error
+
+// Weights are the Altair reward weights. WEIGHT_DENOMINATOR becomes their sum.
+type Weights struct {
+    Source   uint64 `json:"source"`
+    Target   uint64 `json:"target"`
+    Head     uint64 `json:"head"`
+    Sync     uint64 `json:"sync"`
+    Proposer uint64 `json:"proposer"`
+}
+
+// chainParams guards the chain parameters in internal/config, which the internal
+// calculator reads directly. Calculations under the process's parameters
+// share it; a Calculator with its own swaps them in under the write lock.
+var chainParams sync.RWMutex
+
+// defaultCalculator backs the package-level functions
+var defaultCalculator = &Calculator{}
+
+// NewCalculator returns a Calculator configured by opts, applied in order so
+// later options override earlier ones
+func NewCalculator(opts ...Option) (*Calculator, error) {
+    c := &Calculator{}
+    for _, opt := range opts {
+        if err := opt(c); err != nil {
+            return nil, err
+        }
+    }
+
+    // Reject a bad spec now rather than on the first calculation
+    if len(c.spec) > 0 {
+        chainParams.Lock()
+        defer chainParams.Unlock()
+        saved := config.Save()
+        defer saved.Restore()
+        if _, err := config.ApplySpec(c.spec); err != nil {
+            return nil, err
+        }
+    }
+    return c, nil
+}
+
+// WithFork selects the fork whose penalty and slashing quotients apply, such as
+// phase0, altair, or bellatrix
+func WithFork(fork string) Option {
+    return func(c *Calculator) error {
+        if !config.IsKnownFork(fork) {
+            return fmt.Errorf("unknown fork %q (known forks: %s)", fork, strings.Join(config.KnownForks, ", "))
+        }
+        c.fork = fork
+        return nil
+    }
+}
+
+// WithWeights replaces the reward weights
+func WithWeights(w Weights) Option {
+    return func(c *Calculator) error {
+        if w.Source+w.Target+w.Head+w.Sync+w.Proposer == 0 {
+            return fmt.Errorf("reward weights must not all be zero")
+        }
+        return WithSpec(map[string]string{
+            "TIMELY_SOURCE_WEIGHT": strconv.FormatUint(w.Source, 10),
+            "TIMELY_TARGET_WEIGHT": strconv.FormatUint(w.Target, 10),
+            "TIMELY_HEAD_WEIGHT":   strconv.FormatUint(w.Head, 10),
+            "SYNC_REWARD_WEIGHT":   strconv.FormatUint(w.Sync, 10),
+            "PROPOSER_WEIGHT":      strconv.FormatUint(w.Proposer, 10),
+            "WEIGHT_DENOMINATOR":   strconv.FormatUint(w.Source+w.Target+w.Head+w.Sync+w.Proposer, 10),
+        })(c)
+    }
+}
+
+// WithSpec sets chain parameters from chain spec values, keyed as in a beacon
+// node's /eth/v1/config/spec. Keys the calculator does not use are ignored.
+func WithSpec(spec map[string]string) Option {
+    return func(c *Calculator) error {
+        if c.spec == nil {
+            c.spec = make(map[string]string)
+        }
+        for key, value := range spec {
+            c.spec[key] = value
+        }
+        return nil
+    }
+}
+
+// WithNetworkPreset sets the chain parameters a consensus spec preset changes
+// from mainnet. The minimal preset changes the slot and epoch lengths, sync
+// committees, and penalties; mainnet sets them back to mainnet's values.
+func WithNetworkPreset(name string) Option {
+    return func(c *Calculator) error {
+        preset, ok := config.Presets[name]
+        if !ok {
+            return fmt.Errorf("unknown preset %q", name)
+        }
+        return WithSpec(preset)(c)
+    }
+}
+
+// run calls f with the Calculator's chain parameters in effect
+func (c *Calculator) run(f func()) {
+    if len(c.spec) == 0 {
+        chainParams.RLock()
+        defer chainParams.RUnlock()
+        f()
+        return
+    }
+
+    chainParams.Lock()
+    defer chainParams.Unlock()
+    saved := config.Save()
+    defer saved.Restore()
+    config.ApplySpec(c.spec) // checked by NewCalculator
+    f()
+}
+
+// newState builds the network calculations run on, under the Calculator's
+// fork. One template validator stands in for all validators, so it costs the
+// same at any size, up to the validators the ETH supply could fund.
+func (c *Calculator) newState(validators int) (*types.NetworkState, error) {
+    state, err := calculator.NewTemplateState(validators)
+    if err != nil {
+        return nil, err
+    }
+    state.CurrentFork = c.fork
+    return state, nil
+}
@@ -0,0 +1,188 @@
+package rewards
+
+import (
+    "fmt"
+    "math"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/config"
+)
+
+// PenaltyResult is what one validator loses for missed attestation duties
+// (amounts in ETH)
+type PenaltyResult struct {
+    Validators    int     `json:"validators"`
+    SourcePenalty float64 `json:"source_penalty_eth"` // per epoch
+    TargetPenalty float64 `json:"target_penalty_eth"` // per epoch
+    HeadPenalty   float64 `json:"head_penalty_eth"`   // per epoch
+    TotalPenalty  float64 `json:"total_penalty_eth"`  // per epoch
+    DailyPenalty  float64 `json:"daily_penalty_eth"`
+}
+
+// Penalties computes the attestation penalties of a validator that misses the
+// given duties every epoch on a finalizing network of validators validators
+func Penalties(validators int, missedSource, missedTarget, missedHead bool) (PenaltyResult, error) {
+    return defaultCalculator.Penalties(validators, missedSource, missedTarget, missedHead)
+}
+
+// Penalties is the package-level Penalties under the Calculator's parameters
+func (c *Calculator) Penalties(validators int, missedSource, missedTarget, missedHead bool) (result PenaltyResult, err error) {
+    c.run(func() { result, err = c.penalties(validators, missedSource, missedTarget, missedHead) })
+    return
+}
+
+// penalties is Penalties with the chain parameters already in effect
+func (c *Calculator) penalties(validators int, missedSource, missedTarget, missedHead bool) (PenaltyResult, error) {
+    state, err := c.newState(validators)
+    if err != nil {
+        return PenaltyResult{}, err
+    }
+
+    penalties, err := calculator.CalculatePenalties(state, 0, !missedSource, !missedTarget, !missedHead)
+    if err != nil {
+        return PenaltyResult{}, err
+    }
+    return PenaltyResult{
+        Validators:    validators,
+        SourcePenalty: float64(penalties.SourcePenaltyGwei) / 1e9,
+        TargetPenalty: float64(penalties.TargetPenaltyGwei) / 1e9,
+        HeadPenalty:   float64(penalties.HeadPenaltyGwei) / 1e9,
+        TotalPenalty:  float64(penalties.TotalAttestationPenaltyGwei) / 1e9,
+        DailyPenalty:  penalties.DailyAttestationPenalty,
+    }, nil
+}
+
+// LeakResult is how a period of non-finality ends for the validators that
+// stay online and those that go offline (balances per validator in ETH)
+type LeakResult struct {
+    Validators       int     `json:"validators"`
+    Participation    float64 `json:"participation"`
+    Epochs           int     `json:"epochs"`
+    FinalityRestored bool    `json:"finality_restored"`
+    OnlineBalance    float64 `json:"online_balance_eth"`
+    OfflineBalance   float64 `json:"offline_balance_eth"`
+    OfflineEjected   bool    `json:"offline_ejected"`
+    OfflineLoss      float64 `json:"offline_loss_eth"`
+    Burned           float64 `json:"burned_eth"` // across the network
+}
+
+// InactivityLeak follows a network of validators validators, of which the
+// fraction participation stays online, through at most epochs epochs without
+// finality (a year if 0). participation must be below 2/3 for the chain to
+// stop finalizing.
+func InactivityLeak(validators int, participation float64, epochs int) (LeakResult, error) {
+    return defaultCalculator.InactivityLeak(validators, participation, epochs)
+}
+
+// InactivityLeak is the package-level InactivityLeak under the Calculator's parameters
+func (c *Calculator) InactivityLeak(validators int, participation float64, epochs int) (result LeakResult, err error) {
+    c.run(func() { result, err = c.inactivityLeak(validators, participation, epochs) })
+    return
+}
+
+// inactivityLeak is InactivityLeak with the chain parameters already in effect
+func (c *Calculator) inactivityLeak(validators int, participation float64, epochs int) (LeakResult, error) {
+    if validators <= 0 || validators > calculator.MaxValidators() {
+        return LeakResult{}, fmt.Errorf("validators must be between 1 and %d", calculator.MaxValidators())
+    }
+    if participation < 0 || participation*3 >= 2 {
+        return LeakResult{}, fmt.Errorf("participation must be between 0.0 and 2/3 for the chain to stop finalizing")
+    }
+    if epochs < 0 {
+        return LeakResult{}, fmt.Errorf("epochs must not be negative")
+    }
+
+    analysis := calculator.SimulateLeak(validators, participation, uint64(epochs))
+    return LeakResult{
+        Validators:       validators,
+        Participation:    participation,
+        Epochs:           int(analysis.Epochs),
+        FinalityRestored: analysis.FinalityRestored,
+        OnlineBalance:    float64(analysis.Online.EndBalance) / 1e9,
+        OfflineBalance:   float64(analysis.Offline.EndBalance) / 1e9,
+        OfflineEjected:   analysis.Offline.EjectedEpoch > 0,
+        OfflineLoss:      float64(analysis.Offline.StartBalance-analysis.Offline.EndBalance) / 1e9,
+        Burned:           analysis.Burned / 1e9,
+    }, nil
+}
+
+// SyncCommitteeResult is one validator's chance of sync committee duty and
+// what a period on the committee pays (amounts in ETH)
+type SyncCommitteeResult struct {
+    Validators           int     `json:"validators"`
+    SelectionProbability float64 `json:"selection_probability"` // per period
+    PeriodsPerYear       float64 `json:"expected_periods_per_year"`
+    PeriodDays           float64 `json:"period_days"`
+    IncomePerPeriod      float64 `json:"income_per_period_eth"`
+    AnnualRewards        float64 `json:"annual_rewards_eth"` // expected
+}
+
+// SyncCommittee computes the sync committee odds and income of one validator
+// on a network of validators validators
+func SyncCommittee(validators int, participation float64) (SyncCommitteeResult, error) {
+    return defaultCalculator.SyncCommittee(validators, participation)
+}
+
+// SyncCommittee is the package-level SyncCommittee under the Calculator's parameters
+func (c *Calculator) SyncCommittee(validators int, participation float64) (result SyncCommitteeResult, err error) {
+    c.run(func() { result, err = c.syncCommittee(validators, participation) })
+    return
+}
+
+// syncCommittee is SyncCommittee with the chain parameters already in effect
+func (c *Calculator) syncCommittee(validators int, participation float64) (SyncCommitteeResult, error) {
+    if participation <= 0 || participation > 1 {
+        return SyncCommitteeResult{}, fmt.Errorf("participation must be between 0.0 and 1.0")
+    }
+    state, err := c.newState(validators)
+    if err != nil {
+        return SyncCommitteeResult{}, err
+    }
+
+    results := calculator.ProjectTemplateRewards(state, validators, participation, calculator.Adjustments{})
+    return SyncCommitteeResult{
+        Validators:           validators,
+        SelectionProbability: results.SyncCommitteeProbability,
+        PeriodsPerYear:       results.SyncCommitteePeriodsPerYear,
+        PeriodDays:           float64(config.EPOCHS_PER_SYNC_COMMITTEE_PERIOD) / float64(config.EPOCHS_PER_DAY),
+        IncomePerPeriod:      results.SyncCommitteeIncomePerPeriodGwei / 1e9,
+        AnnualRewards:        results.SyncCommitteeRewardsAnnualGwei / 1e9,
+    }, nil
+}
+
+// WhistleblowerResult is the reward for reporting a slashable offense (amounts
+// in ETH). When the proposer including the report is not the whistleblower, it
+// keeps ProposerReward and the whistleblower the rest.
+type WhistleblowerResult struct {
+    SlashedBalance float64 `json:"slashed_balance_eth"`
+    Reward         float64 `json:"whistleblower_reward_eth"`
+    ProposerReward float64 `json:"proposer_reward_eth"` // the proposer's share of Reward
+}
+
+// Whistleblower computes the reward for reporting a validator with an
+// effective balance of slashedBalance ETH
+func Whistleblower(slashedBalance float64) (WhistleblowerResult, error) {
+    return defaultCalculator.Whistleblower(slashedBalance)
+}
+
+// Whistleblower is the package-level Whistleblower under the Calculator's parameters
+func (c *Calculator) Whistleblower(slashedBalance float64) (result WhistleblowerResult, err error) {
+    c.run(func() { result, err = c.whistleblower(slashedBalance) })
+    return
+}
+
+// whistleblower is Whistleblower with the chain parameters already in effect
+func (c *Calculator) whistleblower(slashedBalance float64) (WhistleblowerResult, error) {
+    if slashedBalance <= 0 || slashedBalance > float64(config.MAX_EFFECTIVE_BALANCE_ELECTRA)/1e9 {
+        return WhistleblowerResult{}, fmt.Errorf("slashed effective balance must be between 0 and %g ETH",
+            float64(config.MAX_EFFECTIVE_BALANCE_ELECTRA)/1e9)
+    }
+
+    balance := uint64(math.Round(slashedBalance * 1e9))
+    reward, proposerReward := calculator.CalculateWhistleblowerReward(balance)
+    return WhistleblowerResult{
+        SlashedBalance: slashedBalance,
+        Reward:         float64(reward) / 1e9,
+        ProposerReward: float64(proposerReward) / 1e9,
+    }, nil
+}
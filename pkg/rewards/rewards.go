@@ -0,0 +1,209 @@
+// Package rewards is the public API of the calculator: the same reward math as
+// the command line behind plain inputs and outputs. It does no I/O, so it also
+// builds for js/wasm (see cmd/wasm) and gives identical results in a browser.
+package rewards
+
+import (
+    "fmt"
+    "runtime"
+    "sync"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+    "github.com/eth-rewards-calculator/internal/config"
+)
+
+// Params are the inputs to Calculate. Amounts are in ETH and rates are
+// fractions (0.0-1.0), as on the command line. Validators must be between 1
+// and MaxValidators.
+type Params struct {
+    Validators          int     `json:"validators"`
+    Participation       float64 `json:"participation"`
+    PriorityFees        float64 `json:"priority_fees"` // per proposed block
+    MEV                 float64 `json:"mev"`           // per proposed block
+    MissRate            float64 `json:"miss_rate"`
+    SyncUptime          float64 `json:"sync_uptime,omitempty"` // on the sync committee; 0 takes 1 - MissRate
+    SlashingProbability float64 `json:"slashing_probability"`
+}
+
+// Result is one validator's projected annual return. Amounts are in ETH and
+// APYs in percent; only numbers and strings are used so it maps directly onto
+// a JavaScript object.
+type Result struct {
+    Validators           int     `json:"validators"`
+    TotalStaked          float64 `json:"total_staked_eth"`
+    Participation        float64 `json:"participation"`
+    APY                  float64 `json:"apy"`
+    BaseAPY              float64 `json:"base_apy"`
+    RiskAdjustedAPY      float64 `json:"risk_adjusted_apy"`
+    AttestationRewards   float64 `json:"attestation_rewards_eth"`
+    ProposerRewards      float64 `json:"proposer_rewards_eth"`
+    SyncCommitteeRewards float64 `json:"sync_committee_rewards_eth"`
+    ConsensusRewards     float64 `json:"consensus_rewards_eth"`
+    ExecutionRewards     float64 `json:"execution_rewards_eth"`
+    TotalRewards         float64 `json:"total_rewards_eth"`
+    ExpectedLosses       float64 `json:"expected_losses_eth"`
+    NetworkHealthWarning string  `json:"network_health_warning,omitempty"`
+}
+
+// MaxValidators is the most validators the calculations accept: as many 32
+// ETH validators as the ETH supply could fund
+func MaxValidators() int {
+    chainParams.RLock()
+    defer chainParams.RUnlock()
+    return calculator.MaxValidators()
+}
+
+// DefaultParams returns the command-line defaults for the given validator count
+func DefaultParams(validators int) Params {
+    return Params{Validators: validators, Participation: 0.95}
+}
+
+// Calculate projects the annual rewards of one validator on a network of
+// p.Validators validators
+func Calculate(p Params) (Result, error) {
+    return defaultCalculator.Calculate(p)
+}
+
+// Calculate is the package-level Calculate under the Calculator's parameters
+func (c *Calculator) Calculate(p Params) (result Result, err error) {
+    c.run(func() { result, err = c.calculate(p) })
+    return
+}
+
+// calculate is Calculate with the chain parameters already in effect
+func (c *Calculator) calculate(p Params) (Result, error) {
+    if p.Participation <= 0 || p.Participation > 1 {
+        return Result{}, fmt.Errorf("participation must be between 0.0 and 1.0")
+    }
+    if p.MissRate < 0 || p.MissRate > 1 || p.SlashingProbability < 0 || p.SlashingProbability > 1 {
+        return Result{}, fmt.Errorf("miss rate and slashing probability must be between 0.0 and 1.0")
+    }
+    if p.SyncUptime < 0 || p.SyncUptime > 1 {
+        return Result{}, fmt.Errorf("sync uptime must be between 0.0 and 1.0")
+    }
+    if p.PriorityFees < 0 || p.MEV < 0 {
+        return Result{}, fmt.Errorf("priority fees and MEV must not be negative")
+    }
+
+    state, err := c.newState(p.Validators)
+    if err != nil {
+        return Result{}, err
+    }
+
+    results := calculator.ProjectTemplateRewards(state, p.Validators, p.Participation, calculator.Adjustments{
+        PriorityFees:        p.PriorityFees,
+        MEV:                 p.MEV,
+        MissRate:            p.MissRate,
+        SyncUptime:          p.SyncUptime,
+        SlashingProbability: p.SlashingProbability,
+    })
+
+    return Result{
+        Validators:           results.ValidatorCount,
+        TotalStaked:          float64(results.TotalStakedGwei) / 1e9,
+        Participation:        results.ParticipationRate,
+        APY:                  results.APY,
+        BaseAPY:              results.BaseAPY,
+        RiskAdjustedAPY:      results.RiskAdjustedAPY,
+        AttestationRewards:   results.AttestationRewardsAnnualGwei / 1e9,
+        ProposerRewards:      results.ProposerRewardsAnnualGwei / 1e9,
+        SyncCommitteeRewards: results.SyncCommitteeRewardsAnnualGwei / 1e9,
+        ConsensusRewards:     results.ConsensusRewardsAnnualGwei / 1e9,
+        ExecutionRewards:     results.ExecutionRewardsAnnualGwei / 1e9,
+        TotalRewards:         results.TotalAnnualRewardsGwei / 1e9,
+        ExpectedLosses: (results.ExpectedMissedRewardsGwei + results.ExpectedPenaltiesGwei +
+            results.ExpectedSlashingLossGwei) / 1e9,
+        NetworkHealthWarning: results.NetworkHealthWarning,
+    }, nil
+}
+
+// CalculateBatch runs Calculate for every scenario in params, spread across
+// GOMAXPROCS goroutines, and returns the results in the same order. If any
+// scenario is invalid it returns the error of the first one instead.
+func CalculateBatch(params []Params) ([]Result, error) {
+    return defaultCalculator.CalculateBatch(params)
+}
+
+// CalculateBatch is the package-level CalculateBatch under the Calculator's
+// parameters
+func (c *Calculator) CalculateBatch(params []Params) (results []Result, err error) {
+    c.run(func() { results, err = c.calculateBatch(params) })
+    return
+}
+
+// calculateBatch is CalculateBatch with the chain parameters already in effect
+func (c *Calculator) calculateBatch(params []Params) ([]Result, error) {
+    results := make([]Result, len(params))
+    errs := make([]error, len(params))
+
+    next := make(chan int)
+    var wg sync.WaitGroup
+    for w := 0; w < min(runtime.GOMAXPROCS(0), len(params)); w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for i := range next {
+                results[i], errs[i] = c.calculate(params[i])
+            }
+        }()
+    }
+    for i := range params {
+        next <- i
+    }
+    close(next)
+    wg.Wait()
+
+    for i, err := range errs {
+        if err != nil {
+            return nil, fmt.Errorf("scenario %d: %v", i, err)
+        }
+    }
+    return results, nil
+}
+
+// SlashingResult is the penalty for one validator slashed together with others
+// (amounts in ETH)
+type SlashingResult struct {
+    Validators          int     `json:"validators"`
+    Slashed             int     `json:"slashed"`
+    InitialPenalty      float64 `json:"initial_penalty_eth"`
+    ProportionalPenalty float64 `json:"proportional_penalty_eth"`
+    TotalPenalty        float64 `json:"total_penalty_eth"`
+    PercentageOfStake   float64 `json:"percentage_of_stake"`
+}
+
+// Slashing computes the penalty for one of slashed validators slashed together
+// on a network of validators validators
+func Slashing(validators, slashed int) (SlashingResult, error) {
+    return defaultCalculator.Slashing(validators, slashed)
+}
+
+// Slashing is the package-level Slashing under the Calculator's parameters
+func (c *Calculator) Slashing(validators, slashed int) (result SlashingResult, err error) {
+    c.run(func() { result, err = c.slashing(validators, slashed) })
+    return
+}
+
+// slashing is Slashing with the chain parameters already in effect
+func (c *Calculator) slashing(validators, slashed int) (SlashingResult, error) {
+    if slashed <= 0 || slashed > validators {
+        return SlashingResult{}, fmt.Errorf("slashed validators must be between 1 and the validator count")
+    }
+    state, err := c.newState(validators)
+    if err != nil {
+        return SlashingResult{}, err
+    }
+
+    penalties, err := calculator.CalculateSlashingPenalties(state, 0, uint64(slashed)*config.MAX_EFFECTIVE_BALANCE)
+    if err != nil {
+        return SlashingResult{}, err
+    }
+    return SlashingResult{
+        Validators:          validators,
+        Slashed:             slashed,
+        InitialPenalty:      float64(penalties.InitialPenaltyGwei) / 1e9,
+        ProportionalPenalty: float64(penalties.ProportionalPenaltyGwei) / 1e9,
+        TotalPenalty:        float64(penalties.TotalPenaltyGwei) / 1e9,
+        PercentageOfStake:   penalties.PercentageOfStake,
+    }, nil
+}
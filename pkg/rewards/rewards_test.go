@@ -0,0 +1,147 @@
+package rewards
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/eth-rewards-calculator/internal/calculator"
+)
+
+func TestCalculateBatch(t *testing.T) {
+    var params []Params
+    for _, validators := range []int{1, 10_000, 1_000_000, 3_000_000} {
+        for _, missRate := range []float64{0, 0.02} {
+            params = append(params, Params{
+                Validators:          validators,
+                Participation:       0.95,
+                PriorityFees:        0.02,
+                MEV:                 0.05,
+                MissRate:            missRate,
+                SlashingProbability: 0.001,
+            })
+        }
+    }
+
+    results, err := CalculateBatch(params)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(results) != len(params) {
+        t.Fatalf("%d results for %d scenarios", len(results), len(params))
+    }
+    for i, p := range params {
+        want, err := Calculate(p)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if results[i] != want {
+            t.Errorf("scenario %d: batch gives %+v, Calculate %+v", i, results[i], want)
+        }
+    }
+
+    params[3].Participation = 0
+    if _, err := CalculateBatch(params); err == nil || !strings.HasPrefix(err.Error(), "scenario 3:") {
+        t.Errorf("invalid scenario 3: error %v", err)
+    }
+}
+
+func TestCalculateBatchCalculator(t *testing.T) {
+    c, err := NewCalculator(WithNetworkPreset("minimal"), WithFork("electra"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    params := []Params{DefaultParams(1_000), DefaultParams(500_000)}
+    results, err := c.CalculateBatch(params)
+    if err != nil {
+        t.Fatal(err)
+    }
+    for i, p := range params {
+        want, err := c.Calculate(p)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if results[i] != want {
+            t.Errorf("scenario %d: batch gives %+v, Calculate %+v", i, results[i], want)
+        }
+        mainnet, _ := Calculate(p)
+        if results[i].APY == mainnet.APY {
+            t.Errorf("scenario %d: the minimal preset projects mainnet's APY", i)
+        }
+    }
+}
+
+func TestValidatorBound(t *testing.T) {
+    max := MaxValidators()
+    calls := map[string]func(validators int) error{
+        "Calculate":      func(n int) error { _, err := Calculate(DefaultParams(n)); return err },
+        "Slashing":       func(n int) error { _, err := Slashing(n, 1); return err },
+        "Penalties":      func(n int) error { _, err := Penalties(n, true, true, true); return err },
+        "SyncCommittee":  func(n int) error { _, err := SyncCommittee(n, 0.95); return err },
+        "InactivityLeak": func(n int) error { _, err := InactivityLeak(n, 0.5, 10); return err },
+    }
+    for name, call := range calls {
+        if err := call(max); err != nil {
+            t.Errorf("%s(%d): %v", name, max, err)
+        }
+        for _, n := range []int{0, max + 1} {
+            if err := call(n); err == nil {
+                t.Errorf("%s(%d) returned no error", name, n)
+            }
+        }
+    }
+}
+
+func TestSyncCommitteeTemplate(t *testing.T) {
+    // The template state gives what a network of real validators does
+    state, err := calculator.NewNetworkState(100_000)
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := calculator.CalculateRewards(state, 0.95)
+    got, err := SyncCommittee(100_000, 0.95)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got.SelectionProbability != want.SyncCommitteeProbability ||
+        got.AnnualRewards != want.SyncCommitteeRewardsAnnualGwei/1e9 ||
+        got.IncomePerPeriod != want.SyncCommitteeIncomePerPeriodGwei/1e9 {
+        t.Errorf("SyncCommittee = %+v, full state %g, %g, %g", got, want.SyncCommitteeProbability,
+            want.SyncCommitteeRewardsAnnualGwei/1e9, want.SyncCommitteeIncomePerPeriodGwei/1e9)
+    }
+}
+
+func TestWithWeights(t *testing.T) {
+    base, err := Calculate(DefaultParams(1_000_000))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // Mainnet's weights give mainnet's result; dropping the sync and
+    // proposer weights leaves only attestation income
+    tests := []struct {
+        name    string
+        weights Weights
+        check   func(Result) bool
+    }{
+        {"mainnet", Weights{Source: 14, Target: 26, Head: 14, Sync: 2, Proposer: 8},
+            func(r Result) bool { return r == base }},
+        {"attestations only", Weights{Source: 14, Target: 26, Head: 14},
+            func(r Result) bool { return r.ProposerRewards == 0 && r.SyncCommitteeRewards == 0 && r.AttestationRewards > 0 }},
+        {"proposer only", Weights{Proposer: 8},
+            func(r Result) bool { return r.AttestationRewards == 0 && r.SyncCommitteeRewards == 0 }},
+    }
+    for _, tt := range tests {
+        c, err := NewCalculator(WithWeights(tt.weights))
+        if err != nil {
+            t.Errorf("%s: %v", tt.name, err)
+            continue
+        }
+        result, err := c.Calculate(DefaultParams(1_000_000))
+        if err != nil || !tt.check(result) {
+            t.Errorf("%s: %+v, %v", tt.name, result, err)
+        }
+    }
+    if _, err := NewCalculator(WithWeights(Weights{})); err == nil {
+        t.Error("all-zero weights accepted")
+    }
+}